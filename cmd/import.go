@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/app"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/cache"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/config"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/importer"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/logger"
+)
+
+var importFlags struct {
+	wallet  string
+	file    string
+	preset  string
+	chain   string
+	mode    string
+	dryRun  bool
+	mapping importer.Mapping
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import-holdings",
+	Short: "Import a wallet's holdings from a CSV file",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger, err := logger.SetupLogger()
+		if err != nil {
+			return fmt.Errorf("creating logger: %w", err)
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		f, err := os.Open(importFlags.file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		redisClient, err := cache.NewRedisClient(cfg.Redis.URL)
+		if err != nil {
+			return fmt.Errorf("creating cache client: %w", err)
+		}
+
+		cacheManager, err := cache.NewRedisManager(redisClient, logger)
+		if err != nil {
+			return fmt.Errorf("creating cache manager: %w", err)
+		}
+
+		appCtx, err := app.NewAppContext(ctx, cfg, logger, cacheManager)
+		if err != nil {
+			return fmt.Errorf("creating app context: %w", err)
+		}
+		defer appCtx.Close()
+
+		// symbol rows need the coin list the server keeps loaded
+		if err := appCtx.TokenSearch.Load(ctx); err != nil {
+			return fmt.Errorf("loading token list: %w", err)
+		}
+
+		res, err := appCtx.ImportService.Import(ctx, importFlags.wallet, f, importer.Options{
+			Preset:       importFlags.preset,
+			Mapping:      importFlags.mapping,
+			DefaultChain: importFlags.chain,
+			Mode:         importer.Mode(importFlags.mode),
+			DryRun:       importFlags.dryRun,
+		})
+		if err != nil {
+			return err
+		}
+
+		out, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+
+		if len(res.Errors) > 0 {
+			return fmt.Errorf("%d rows failed, nothing was applied", len(res.Errors))
+		}
+		return nil
+	},
+}
+
+func init() {
+	f := importCmd.Flags()
+	f.StringVar(&importFlags.wallet, "wallet", "", "wallet address")
+	f.StringVar(&importFlags.file, "file", "", "CSV file to import")
+	f.StringVar(&importFlags.preset, "preset", importer.DefaultPreset, "column layout: generic, binance, kraken or coinbase")
+	f.StringVar(&importFlags.chain, "chain", "ethereum", "chain of rows without one")
+	f.StringVar(&importFlags.mode, "mode", "set", "set replaces recorded amounts, add adds to them")
+	f.BoolVar(&importFlags.dryRun, "dry-run", false, "validate and print the result without applying it")
+	f.StringVar(&importFlags.mapping.Chain, "col-chain", "", "chain column, overriding the preset")
+	f.StringVar(&importFlags.mapping.Contract, "col-contract", "", "contract address column, overriding the preset")
+	f.StringVar(&importFlags.mapping.Symbol, "col-symbol", "", "symbol column, overriding the preset")
+	f.StringVar(&importFlags.mapping.Amount, "col-amount", "", "amount column, overriding the preset")
+	f.StringVar(&importFlags.mapping.Fee, "col-fee", "", "fee column of ledger presets, overriding the preset")
+	_ = importCmd.MarkFlagRequired("wallet")
+	_ = importCmd.MarkFlagRequired("file")
+
+	rootCmd.AddCommand(importCmd)
+}
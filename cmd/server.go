@@ -59,7 +59,80 @@ var serverCmd = &cobra.Command{
 
 		portfolioHander := handlers.NewPortfolioHandler(appCtx.PortfolioService, logger)
 
-		router := httpserver.NewRouter(pricesHandler, txHandler, portfolioHander)
+		streamHandler := handlers.NewStreamHandler(appCtx.StreamHub, logger)
+
+		overridesHandler := handlers.NewPriceOverridesHandler(appCtx.PricingService, logger)
+
+		tokensHandler := handlers.NewTokensHandler(appCtx.TokenService, appCtx.TokenSearch, logger)
+
+		alertsHandler := handlers.NewAlertsHandler(appCtx.AlertService, logger)
+
+		historyHandler := handlers.NewPortfolioHistoryHandler(appCtx.SnapshotService, logger)
+
+		performanceHandler := handlers.NewPerformanceHandler(appCtx.PerformanceService, logger)
+
+		allocationHandler := handlers.NewAllocationHandler(appCtx.AllocationService, logger)
+
+		rebalanceHandler := handlers.NewRebalanceHandler(appCtx.RebalanceService, logger)
+
+		pnlHandler := handlers.NewPnLHandler(appCtx.PnLService, logger)
+
+		taxHandler := handlers.NewTaxHandler(appCtx.TaxService, logger)
+
+		exportHandler := handlers.NewExportHandler(appCtx.ExportService, logger)
+
+		importHandler := handlers.NewImportHandler(appCtx.ImportService, logger)
+
+		syncHandler := handlers.NewSyncHandler(appCtx.BalanceSync, logger)
+
+		reconcileHandler := handlers.NewReconcileHandler(appCtx.ReconcileService, logger)
+
+		suggestionsHandler := handlers.NewSuggestionsHandler(appCtx.DiscoveryService, logger)
+
+		spamHandler := handlers.NewSpamHandler(appCtx.SpamService, logger)
+
+		nftHandler := handlers.NewNFTHandler(appCtx.NFTService, logger)
+
+		router := httpserver.NewRouter(httpserver.Handlers{
+			Prices:         pricesHandler,
+			Transactions:   txHandler,
+			Portfolio:      portfolioHander,
+			Stream:         streamHandler,
+			PriceOverrides: overridesHandler,
+			Tokens:         tokensHandler,
+			Alerts:         alertsHandler,
+			History:        historyHandler,
+			Performance:    performanceHandler,
+			Allocation:     allocationHandler,
+			Rebalance:      rebalanceHandler,
+			PnL:            pnlHandler,
+			Tax:            taxHandler,
+			Export:         exportHandler,
+			Import:         importHandler,
+			Sync:           syncHandler,
+			Reconcile:      reconcileHandler,
+			Suggestions:    suggestionsHandler,
+			Spam:           spamHandler,
+			NFT:            nftHandler,
+		}, cfg.Admin.APIKey)
+
+		// single refresh loop shared by every websocket client
+		go appCtx.StreamHub.Run(ctx)
+
+		// resolves token metadata missed by portfolio and transaction lookups
+		go appCtx.TokenService.Run(ctx)
+
+		// keeps the token search list fresh
+		go appCtx.TokenSearch.Run(ctx)
+
+		// evaluates alert rules and delivers webhooks
+		go appCtx.AlertEvaluator.Run(ctx)
+
+		// records portfolio values for the history endpoint
+		go appCtx.SnapshotService.Run(ctx)
+
+		// refreshes synced holdings from on-chain balances
+		go appCtx.BalanceSync.Run(ctx)
 
 		go func() {
 			if err := http.ListenAndServe(":8080", router); err != nil {
@@ -79,6 +152,8 @@ var serverCmd = &cobra.Command{
 		defer shutdownCancel()
 
 		_ = shutdownCtx
+
+		appCtx.Close()
 	},
 }
 
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/app"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/cache"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/config"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/logger"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pnl"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tax"
+)
+
+var taxFlags struct {
+	wallet       string
+	year         int
+	chain        string
+	method       string
+	jurisdiction string
+	csvPath      string
+	summaryPath  string
+}
+
+var taxCmd = &cobra.Command{
+	Use:   "tax-report",
+	Short: "Write a wallet's tax report as CSV and a JSON summary",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		logger, err := logger.SetupLogger()
+		if err != nil {
+			return fmt.Errorf("creating logger: %w", err)
+		}
+
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		method, err := pnl.ParseMethod(taxFlags.method)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		redisClient, err := cache.NewRedisClient(cfg.Redis.URL)
+		if err != nil {
+			return fmt.Errorf("creating cache client: %w", err)
+		}
+
+		cacheManager, err := cache.NewRedisManager(redisClient, logger)
+		if err != nil {
+			return fmt.Errorf("creating cache manager: %w", err)
+		}
+
+		appCtx, err := app.NewAppContext(ctx, cfg, logger, cacheManager)
+		if err != nil {
+			return fmt.Errorf("creating app context: %w", err)
+		}
+		defer appCtx.Close()
+
+		report, err := appCtx.TaxService.Report(ctx, taxFlags.wallet, tax.Query{
+			Chain:        taxFlags.chain,
+			Method:       method,
+			Jurisdiction: taxFlags.jurisdiction,
+			Year:         taxFlags.year,
+		})
+		if err != nil {
+			return err
+		}
+
+		csvPath := taxFlags.csvPath
+		if csvPath == "" {
+			csvPath = fmt.Sprintf("tax-%s-%d.csv", taxFlags.wallet, taxFlags.year)
+		}
+		summaryPath := taxFlags.summaryPath
+		if summaryPath == "" {
+			summaryPath = fmt.Sprintf("tax-%s-%d.json", taxFlags.wallet, taxFlags.year)
+		}
+
+		if err := writeTaxCSV(csvPath, report.Rows); err != nil {
+			return err
+		}
+
+		summary, err := json.MarshalIndent(report.Summary, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(summaryPath, append(summary, '\n'), 0o644); err != nil {
+			return err
+		}
+
+		logger.Info("tax-report-written",
+			zap.String("csv", csvPath),
+			zap.String("summary", summaryPath),
+			zap.Int("rows", len(report.Rows)),
+		)
+		// rows valued at zero or a cut-off history need a look before filing
+		if len(report.Summary.Unpriced) > 0 || report.Summary.Truncated {
+			logger.Warn("tax-report-incomplete",
+				zap.Strings("unpriced", report.Summary.Unpriced),
+				zap.Bool("truncated", report.Summary.Truncated),
+			)
+		}
+		return nil
+	},
+}
+
+func writeTaxCSV(path string, rows []tax.Row) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := tax.WriteCSV(f, rows); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func init() {
+	f := taxCmd.Flags()
+	f.StringVar(&taxFlags.wallet, "wallet", "", "wallet address")
+	f.IntVar(&taxFlags.year, "year", time.Now().UTC().Year()-1, "tax year")
+	f.StringVar(&taxFlags.chain, "chain", "ethereum", "chain")
+	f.StringVar(&taxFlags.method, "method", "fifo", "lot matching method: fifo, lifo or hifo")
+	f.StringVar(&taxFlags.jurisdiction, "jurisdiction", "", "holding-period rules: us, de, au or pt (default TAX_JURISDICTION)")
+	f.StringVar(&taxFlags.csvPath, "csv", "", "CSV output path (default tax-<wallet>-<year>.csv)")
+	f.StringVar(&taxFlags.summaryPath, "summary", "", "JSON summary output path (default tax-<wallet>-<year>.json)")
+	_ = taxCmd.MarkFlagRequired("wallet")
+
+	rootCmd.AddCommand(taxCmd)
+}
@@ -22,9 +22,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/prices": {
-            "post": {
-                "description": "Fetch USD prices for tokens by chain + contract address",
+        "/admin/allocation/assignments": {
+            "put": {
+                "description": "Place a token in one of the taxonomy's categories, replacing its current category",
                 "consumes": [
                     "application/json"
                 ],
@@ -32,17 +32,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Prices"
+                    "Admin"
                 ],
-                "summary": "Get token prices",
+                "summary": "Assign token category",
                 "parameters": [
                     {
-                        "description": "Assets to price",
-                        "name": "request",
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Assignment",
+                        "name": "assignment",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.PricesRequest"
+                            "$ref": "#/definitions/handlers.AssignCategoryRequest"
                         }
                     }
                 ],
@@ -50,7 +57,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PriceAPIResponse"
+                            "$ref": "#/definitions/allocation.Assignment"
                         }
                     },
                     "400": {
@@ -59,39 +66,53 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/wallets/{wallet}/portfolio": {
-            "get": {
-                "description": "Fetch wallet portfolio with live valuation",
+            },
+            "delete": {
+                "description": "Remove a token's assigned category; it falls back to its taxonomy file default or uncategorized",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Portfolio"
+                    "Admin"
                 ],
-                "summary": "Get portfolio",
+                "summary": "Remove token category",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Wallet address",
-                        "name": "wallet",
-                        "in": "path",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain",
+                        "name": "chain",
+                        "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Contract address",
+                        "name": "contract",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "OK"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/handlers.PortfolioResponse"
+                            "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     },
                     "404": {
@@ -103,8 +124,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/wallets/{wallet}/portfolio/holdings": {
+        "/admin/prices/overrides": {
+            "get": {
+                "description": "List manual price overrides, including expired ones",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List price overrides",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/pricing.Override"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
             "put": {
+                "description": "Set a manual USD price for an asset, with optional expiry and note. It takes precedence over cached and provider prices.",
                 "consumes": [
                     "application/json"
                 ],
@@ -112,40 +170,100 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Portfolio"
+                    "Admin"
                 ],
-                "summary": "Update holding",
+                "summary": "Set price override",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Wallet address",
-                        "name": "wallet",
-                        "in": "path",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
                         "required": true
                     },
                     {
-                        "description": "Holding",
-                        "name": "holding",
+                        "description": "Override",
+                        "name": "override",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/portfolio.Holding"
+                            "$ref": "#/definitions/handlers.SetPriceOverrideRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pricing.Override"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
                     }
                 }
             },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Delete price override",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain",
+                        "name": "chain",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Contract address",
+                        "name": "contract",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/tokens/lists": {
             "post": {
+                "description": "Import a standard token list (tokenlists.org format). Imported entries take precedence over coingecko metadata.",
                 "consumes": [
                     "application/json"
                 ],
@@ -153,40 +271,72 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Portfolio"
+                    "Admin"
                 ],
-                "summary": "Add holding",
+                "summary": "Import token list",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Wallet address",
-                        "name": "wallet",
-                        "in": "path",
+                        "description": "Admin API key",
+                        "name": "X-Admin-Key",
+                        "in": "header",
                         "required": true
                     },
                     {
-                        "description": "Holding",
-                        "name": "holding",
+                        "description": "Token list",
+                        "name": "list",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/portfolio.Holding"
+                            "$ref": "#/definitions/tokens.TokenList"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ImportTokenListResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/alerts/rules": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alerts"
+                ],
+                "summary": "List alert rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/alerts.Rule"
+                            }
+                        }
                     }
                 }
             },
-            "delete": {
+            "post": {
+                "description": "Rules fire a signed webhook when price_above/price_below crosses threshold (USD) or pct_change moves by threshold percent within window_minutes (negative for drops). Wallet rules: drawdown fires when the wallet value is threshold percent below its high within window_minutes, concentration when one holding exceeds threshold percent of the wallet. A rule fires once when its condition starts to hold and not again until it clears and cooldown_minutes (default 60, 0 for none) has passed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -194,30 +344,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Portfolio"
+                    "Alerts"
                 ],
-                "summary": "Delete holding",
+                "summary": "Create alert rule",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Wallet address",
-                        "name": "wallet",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Holding",
-                        "name": "holding",
+                        "description": "Rule",
+                        "name": "rule",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/portfolio.Holding"
+                            "$ref": "#/definitions/handlers.AlertRuleRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/alerts.Rule"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -228,252 +374,3813 @@ const docTemplate = `{
                 }
             }
         },
-        "/wallets/{wallet}/transactions": {
+        "/alerts/rules/{id}": {
             "get": {
-                "description": "Fetch paginated transactions for a wallet",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Transactions"
+                    "Alerts"
                 ],
-                "summary": "List wallet transactions",
+                "summary": "Get alert rule",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Wallet address",
-                        "name": "wallet",
+                        "description": "Rule ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Blockchain (ethereum)",
-                        "name": "chain",
-                        "in": "query",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "default": 1,
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 20,
-                        "description": "Page size",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Transaction type",
-                        "name": "type",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Transaction status",
-                        "name": "status",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Token symbol",
-                        "name": "token",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Start date RFC3339",
-                        "name": "start_date",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "End date RFC3339",
-                        "name": "end_date",
-                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.TransactionListResponse"
+                            "$ref": "#/definitions/alerts.Rule"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/handlers.ErrorResponse"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "handlers.AssetRequest": {
-            "type": "object",
-            "properties": {
-                "chain": {
-                    "type": "string"
-                },
-                "contract_address": {
-                    "type": "string"
-                }
-            }
-        },
-        "handlers.ErrorResponse": {
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alerts"
+                ],
+                "summary": "Update alert rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rule",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AlertRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/alerts.Rule"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alerts"
+                ],
+                "summary": "Delete alert rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/allocation/categories": {
+            "get": {
+                "description": "The category taxonomy and every token assigned to a category",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Allocation"
+                ],
+                "summary": "List allocation categories",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/allocation.Taxonomy"
+                        }
+                    }
+                }
+            }
+        },
+        "/prices": {
+            "post": {
+                "description": "Fetch USD prices for tokens by chain + contract address, optionally with 24h change, volume, market cap and last-updated time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Get token prices",
+                "parameters": [
+                    {
+                        "description": "Assets to price",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PricesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PriceAPIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/prices/{chain}/{contract}/ohlc": {
+            "get": {
+                "description": "Fetch OHLC candles for a token, for charting",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Get OHLC candles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chain (ethereum)",
+                        "name": "chain",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token contract address",
+                        "name": "contract",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "1d",
+                        "description": "Candle interval (1h | 4h | 1d)",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "30d",
+                        "description": "Range in days, e.g. 7d (max 365d, 90d for intraday intervals)",
+                        "name": "range",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OHLCResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tax/jurisdictions": {
+            "get": {
+                "description": "The holding-period rules tax reports can be classified under",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tax"
+                ],
+                "summary": "List tax jurisdictions",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tax.Jurisdiction"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens/search": {
+            "get": {
+                "description": "Find tokens by symbol or name, for autocomplete when adding holdings. Each result is one token on one chain; exact matches come first, then by market cap rank.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tokens"
+                ],
+                "summary": "Search tokens",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Symbol or name",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Restrict results to a chain",
+                        "name": "chain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Max results",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/tokens.SearchResult"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tokens/{chain}/{contract}": {
+            "get": {
+                "description": "Symbol, name, decimals and logo of a token contract",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tokens"
+                ],
+                "summary": "Get token metadata",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chain (ethereum, polygon-pos, ...)",
+                        "name": "chain",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token contract address",
+                        "name": "contract",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tokens.Metadata"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{wallet}/alerts": {
+            "get": {
+                "description": "Alerts fired by the wallet's drawdown and concentration rules, most recent first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alerts"
+                ],
+                "summary": "Wallet alert history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Max events",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/alerts.Event"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{wallet}/pnl": {
+            "get": {
+                "description": "Replays the wallet's transfers on a chain through acquisition lots. Transfers in open lots; transfers out and swaps are disposals matched against them with the chosen method. Every leg is priced at its historical price. Gains are summed per asset and per period for disposals between from and to.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PnL"
+                ],
+                "summary": "Realized profit and loss",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "ethereum",
+                        "description": "Chain",
+                        "name": "chain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "fifo",
+                        "description": "fifo | lifo | hifo",
+                        "name": "method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "month",
+                        "description": "month | quarter | year",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Disposals from, RFC3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Disposals up to, RFC3339",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/pnl.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{wallet}/portfolio": {
+            "get": {
+                "description": "Fetch wallet portfolio with live valuation, or as it was at as_of using rebuilt holdings and historical prices. Each token holding carries its spam verdict; hide_spam leaves spam holdings out of the holdings and totals.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Get portfolio",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Past time to value the portfolio at (RFC3339)",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Leave holdings judged spam out",
+                        "name": "hide_spam",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PortfolioResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{wallet}/portfolio/allocation": {
+            "get": {
+                "description": "Portfolio value and weight by asset, by chain and by category. Each grouping adds up to total_value_usd.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Portfolio allocation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/allocation.Allocation"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{wallet}/portfolio/history": {
+            "get": {
+                "description": "Recorded portfolio snapshots (holdings, prices and total) for value-over-time charts. Downsampled series keep the last snapshot of each hour or day.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Portfolio value history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date RFC3339 (default 30 days before to)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date RFC3339 (default now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "raw",
+                        "description": "raw | hourly | daily",
+                        "name": "resolution",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/snapshots.Snapshot"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{wallet}/portfolio/holdings": {
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Update holding",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Holding",
+                        "name": "holding",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/portfolio.Holding"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Add holding",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Holding",
+                        "name": "holding",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/portfolio.Holding"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Delete holding",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Holding",
+                        "name": "holding",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/portfolio.Holding"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{wallet}/portfolio/import": {
+            "post": {
+                "description": "Reads holdings from a CSV body and applies them in one save. preset picks the column layout (generic, binance, kraken or coinbase) and col_* names override its columns. Rows without a contract address are resolved by symbol on their chain. Every row is validated first; when any row fails, or dry_run is set, nothing is applied and the result lists what would change. mode=add adds amounts to recorded holdings instead of replacing them.",
+                "consumes": [
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Import holdings from CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "generic",
+                        "description": "generic | binance | kraken | coinbase",
+                        "name": "preset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "ethereum",
+                        "description": "Chain of rows without one",
+                        "name": "chain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "set",
+                        "description": "set | add",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Validate without applying",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain column",
+                        "name": "col_chain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Contract address column",
+                        "name": "col_contract",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Symbol column",
+                        "name": "col_symbol",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Amount column",
+                        "name": "col_amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fee column, ledger presets only",
+                        "name": "col_fee",
+                        "in": "query"
+                    },
+                    {
+                        "description": "CSV",
+                        "name": "file",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/importer.Result"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{wallet}/portfolio/nfts/sync": {
+            "post": {
+                "description": "Nets the wallet's ERC-721 and ERC-1155 transfers on one chain per token and replaces its NFT holdings there with the tokens it still holds. NFTs on other chains and fungible holdings are left alone. The portfolio values NFTs at their collection's floor price.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Sync NFT holdings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "ethereum",
+                        "description": "Chain name or ID",
+                        "name": "chain",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/nft.Result"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{wallet}/portfolio/performance": {
+            "get": {
+                "description": "Time-weighted and money-weighted (XIRR) returns, volatility, max drawdown and Sharpe ratio from daily portfolio snapshots, with native transfers in and out of the wallet as cash flows, compared against a benchmark asset",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Portfolio performance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "30d",
+                        "description": "7d | 30d | 90d | 1y | ytd",
+                        "name": "period",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "ETH",
+                        "description": "ETH, BTC or chain:contract",
+                        "name": "benchmark",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/performance.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{wallet}/portfolio/rebalance": {
+            "get": {
+                "description": "Buy and sell quantities and USD amounts that bring the live portfolio back to its target allocation. Assets within the tolerance band and trades below min_trade_usd are held. With cash_only, nothing but the target's cash asset is sold and buys are scaled to the cash available.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rebalance"
+                ],
+                "summary": "Rebalancing plan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Smallest trade to include, in USD",
+                        "name": "min_trade_usd",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Fund buys from the cash asset only",
+                        "name": "cash_only",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rebalance.Plan"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{wallet}/portfolio/reconcile": {
+            "get": {
+                "description": "Compares each asset's portfolio amount, manual and synced together, with its on-chain balance and classifies it as match, missing (held on-chain only), extra (in the portfolio only), mismatch (different by more than tolerance_pct) or unknown (balance unreadable). Native assets are also compared with the net of the transaction history: transfers in, less transfers out and gas paid.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Reconcile holdings with the chain",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "default": 0.5,
+                        "description": "Relative difference left unflagged, in percent",
+                        "name": "tolerance_pct",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/reconcile.Report"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{wallet}/portfolio/reconcile/accept": {
+            "post": {
+                "description": "Sets the manual holding of each flagged asset so the portfolio matches its on-chain balance, in one save. Synced amounts are kept and the manual amount takes the difference. Without assets every missing, extra and mismatched asset is fixed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Accept on-chain balances",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Assets to fix",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AcceptOnChainRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AcceptOnChainResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{wallet}/portfolio/suggestions": {
+            "get": {
+                "description": "Scans the wallet's ERC-20 transfer history on one chain for tokens it still holds but the portfolio does not, reading each balance from the chain or, when that fails, taking the net of the transfers. Tokens judged spam and tokens worth less than min_value_usd are hidden unless asked for; tokens without a price are never counted as dust.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Suggest untracked tokens",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "ethereum",
+                        "description": "Chain name or ID",
+                        "name": "chain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include tokens classified as spam",
+                        "name": "include_spam",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include tokens worth less than min_value_usd",
+                        "name": "include_dust",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Dust threshold in USD, defaults to DISCOVERY_DUST_USD",
+                        "name": "min_value_usd",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/discovery.Result"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{wallet}/portfolio/sync": {
+            "post": {
+                "description": "Reads the wallet's native balance on every chain with an RPC endpoint, and the balance of watched tokens and tokens it already holds, then replaces its synced holdings. Manual holdings are left alone. Balances that cannot be read are listed under errors and keep their previous amount.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Portfolio"
+                ],
+                "summary": "Sync on-chain balances",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/balances.Result"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{wallet}/portfolio/targets": {
+            "get": {
+                "description": "The target weights and tolerance band the wallet is rebalanced against",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rebalance"
+                ],
+                "summary": "Get target allocation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rebalance.Target"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Store the wallet's target weights, which must add up to 100, replacing any earlier target. Drift within tolerance_pct percentage points is left alone; cash_asset funds buys in cash-only plans.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rebalance"
+                ],
+                "summary": "Set target allocation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target allocation",
+                        "name": "target",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SetTargetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/rebalance.Target"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Rebalance"
+                ],
+                "summary": "Delete target allocation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{wallet}/spam/overrides": {
+            "get": {
+                "description": "List the tokens the wallet has marked as spam or not spam",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Spam"
+                ],
+                "summary": "List spam overrides",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/spam.Override"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Record the wallet's own verdict on a token. It wins over the spam lists and heuristics wherever the wallet's transactions, portfolio and suggestions are judged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Spam"
+                ],
+                "summary": "Mark a token as spam or not",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Override",
+                        "name": "override",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SetSpamOverrideRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/spam.Override"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove the wallet's verdict on a token; it is judged by the lists and heuristics again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Spam"
+                ],
+                "summary": "Remove a spam override",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Chain",
+                        "name": "chain",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Contract address",
+                        "name": "contract",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{wallet}/tax/report": {
+            "get": {
+                "description": "Every disposal made in the tax year with its acquired and disposed dates, proceeds, cost basis, gain and short/long-term class under the jurisdiction's holding-period rule. format=json returns the summary; format=csv returns the rows in Form 8949 column order.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Tax"
+                ],
+                "summary": "Tax report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tax year, defaults to last year",
+                        "name": "year",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "us | de | au | pt, defaults to TAX_JURISDICTION",
+                        "name": "jurisdiction",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "ethereum",
+                        "description": "Chain",
+                        "name": "chain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "fifo",
+                        "description": "fifo | lifo | hifo",
+                        "name": "method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "json",
+                        "description": "json | csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tax.Summary"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{wallet}/transactions": {
+            "get": {
+                "description": "Fetch paginated transactions for a wallet",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "List wallet transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Blockchain (ethereum)",
+                        "name": "chain",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction type: send, receive, swap, stake, or nft to list ERC-721 and ERC-1155 transfers instead",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token symbol",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date RFC3339",
+                        "name": "start_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date RFC3339",
+                        "name": "end_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Drop transactions judged spam: spam tokens and zero-value transfers the wallet did not send",
+                        "name": "hide_spam",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.TransactionListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallets/{wallet}/transactions/export": {
+            "get": {
+                "description": "Streams the wallet's full transaction history on a chain as CSV, newest first, with token, direction, fee and USD values at the time of each transaction. format picks a generic layout or the import template of Koinly or CoinTracker.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "Transactions"
+                ],
+                "summary": "Export wallet transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Wallet address",
+                        "name": "wallet",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "default": "ethereum",
+                        "description": "Chain",
+                        "name": "chain",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "csv",
+                        "description": "csv | koinly | cointracker",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date RFC3339",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date RFC3339",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/ws": {
+            "get": {
+                "description": "Upgrades to a WebSocket. Send {\"action\":\"subscribe\",\"assets\":[{\"chain\":\"ethereum\",\"contract_address\":\"0x..\"}]} or {\"action\":\"subscribe\",\"wallet\":\"0x..\"} to receive price and portfolio updates on every refresh.",
+                "tags": [
+                    "Streaming"
+                ],
+                "summary": "Stream live prices and portfolios",
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols"
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "alerts.Event": {
+            "type": "object",
+            "properties": {
+                "asset": {
+                    "description": "for concentration, the largest holding",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/pricing.AssetRef"
+                        }
+                    ]
+                },
+                "change_pct": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "$ref": "#/definitions/alerts.RuleKind"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "peak_value_usd": {
+                    "description": "drawdown only",
+                    "type": "number"
+                },
+                "price_usd": {
+                    "type": "number"
+                },
+                "rule_id": {
+                    "type": "string"
+                },
+                "share_pct": {
+                    "description": "concentration only",
+                    "type": "number"
+                },
+                "threshold": {
+                    "type": "number"
+                },
+                "triggered_at": {
+                    "type": "string"
+                },
+                "value_usd": {
+                    "description": "wallet total value",
+                    "type": "number"
+                },
+                "wallet": {
+                    "type": "string"
+                }
+            }
+        },
+        "alerts.Rule": {
+            "type": "object",
+            "properties": {
+                "asset": {
+                    "description": "price rules",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/pricing.AssetRef"
+                        }
+                    ]
+                },
+                "cooldown_minutes": {
+                    "description": "defaults to 60, 0 never waits",
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "firing": {
+                    "description": "evaluation state, a rule fires when its condition starts to hold and\nnot again until it has cleared and the cooldown has passed",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "$ref": "#/definitions/alerts.RuleKind"
+                },
+                "last_triggered_at": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "description": "USD for price_above/below, percent otherwise",
+                    "type": "number"
+                },
+                "wallet": {
+                    "description": "wallet rules",
+                    "type": "string"
+                },
+                "webhook_url": {
+                    "type": "string"
+                },
+                "window_minutes": {
+                    "description": "pct_change and drawdown",
+                    "type": "integer"
+                }
+            }
+        },
+        "alerts.RuleKind": {
+            "type": "string",
+            "enum": [
+                "price_above",
+                "price_below",
+                "pct_change",
+                "drawdown",
+                "concentration"
+            ],
+            "x-enum-varnames": [
+                "KindPriceAbove",
+                "KindPriceBelow",
+                "KindPercentChange",
+                "KindDrawdown",
+                "KindConcentration"
+            ]
+        },
+        "allocation.Allocation": {
+            "type": "object",
+            "properties": {
+                "by_asset": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/allocation.Weight"
+                    }
+                },
+                "by_category": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/allocation.Weight"
+                    }
+                },
+                "by_chain": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/allocation.Weight"
+                    }
+                },
+                "total_value_usd": {
+                    "type": "number"
+                },
+                "wallet": {
+                    "type": "string"
+                }
+            }
+        },
+        "allocation.Assignment": {
+            "type": "object",
+            "properties": {
+                "asset": {
+                    "$ref": "#/definitions/pricing.AssetRef"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "allocation.Category": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "allocation.Taxonomy": {
+            "type": "object",
+            "properties": {
+                "assignments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/allocation.Assignment"
+                    }
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/allocation.Category"
+                    }
+                }
+            }
+        },
+        "allocation.Weight": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "value_usd": {
+                    "type": "number"
+                },
+                "weight_pct": {
+                    "type": "number"
+                }
+            }
+        },
+        "balances.AssetError": {
+            "type": "object",
+            "properties": {
+                "chain": {
+                    "type": "string"
+                },
+                "contract_address": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "balances.Balance": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "chain": {
+                    "type": "string"
+                },
+                "contract_address": {
+                    "type": "string"
+                },
+                "previous": {
+                    "type": "number"
+                }
+            }
+        },
+        "balances.Result": {
+            "type": "object",
+            "properties": {
+                "balances": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/balances.Balance"
+                    }
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/balances.AssetError"
+                    }
+                },
+                "synced_at": {
+                    "type": "string"
+                },
+                "wallet": {
+                    "type": "string"
+                }
+            }
+        },
+        "discovery.BalanceSource": {
+            "type": "string",
+            "enum": [
+                "chain",
+                "transfers"
+            ],
+            "x-enum-comments": {
+                "BalanceSourceTransfers": "net of transfers, when the chain could not be read"
+            },
+            "x-enum-descriptions": [
+                "",
+                "net of transfers, when the chain could not be read"
+            ],
+            "x-enum-varnames": [
+                "BalanceSourceChain",
+                "BalanceSourceTransfers"
+            ]
+        },
+        "discovery.Result": {
+            "type": "object",
+            "properties": {
+                "chain": {
+                    "type": "string"
+                },
+                "hidden_dust": {
+                    "type": "integer"
+                },
+                "hidden_spam": {
+                    "type": "integer"
+                },
+                "scanned_at": {
+                    "type": "string"
+                },
+                "suggestions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/discovery.Suggestion"
+                    }
+                },
+                "truncated": {
+                    "description": "history longer than the scan covers",
+                    "type": "boolean"
+                },
+                "wallet": {
+                    "type": "string"
+                }
+            }
+        },
+        "discovery.Suggestion": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "number"
+                },
+                "balance_source": {
+                    "$ref": "#/definitions/discovery.BalanceSource"
+                },
+                "chain": {
+                    "type": "string"
+                },
+                "contract_address": {
+                    "type": "string"
+                },
+                "dust": {
+                    "type": "boolean"
+                },
+                "first_seen": {
+                    "type": "string"
+                },
+                "last_seen": {
+                    "type": "string"
+                },
+                "price_usd": {
+                    "type": "number"
+                },
+                "spam": {
+                    "$ref": "#/definitions/spam.Verdict"
+                },
+                "token": {
+                    "$ref": "#/definitions/tokens.Metadata"
+                },
+                "transfers": {
+                    "type": "integer"
+                },
+                "value_usd": {
+                    "type": "number"
+                }
+            }
+        },
+        "handlers.AcceptOnChainRequest": {
+            "type": "object",
+            "properties": {
+                "assets": {
+                    "description": "every flagged asset when empty",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.AssetRequest"
+                    }
+                },
+                "tolerance_pct": {
+                    "type": "number"
+                }
+            }
+        },
+        "handlers.AcceptOnChainResponse": {
+            "type": "object",
+            "properties": {
+                "fixed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/reconcile.Line"
+                    }
+                }
+            }
+        },
+        "handlers.AlertRuleRequest": {
+            "type": "object",
+            "properties": {
+                "chain": {
+                    "type": "string"
+                },
+                "contract_address": {
+                    "type": "string"
+                },
+                "cooldown_minutes": {
+                    "description": "defaults to 60, 0 never waits",
+                    "type": "integer"
+                },
+                "enabled": {
+                    "description": "defaults to true",
+                    "type": "boolean"
+                },
+                "kind": {
+                    "$ref": "#/definitions/alerts.RuleKind"
+                },
+                "threshold": {
+                    "type": "number"
+                },
+                "wallet": {
+                    "description": "drawdown and concentration rules",
+                    "type": "string"
+                },
+                "webhook_url": {
+                    "type": "string"
+                },
+                "window_minutes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.AssetRequest": {
+            "type": "object",
+            "properties": {
+                "chain": {
+                    "type": "string"
+                },
+                "contract_address": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.AssignCategoryRequest": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "chain": {
+                    "type": "string"
+                },
+                "contract_address": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ImportTokenListResponse": {
+            "type": "object",
+            "properties": {
+                "imported": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.OHLCResponse": {
+            "type": "object",
+            "properties": {
+                "candles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pricing.Candle"
+                    }
+                },
+                "chain": {
+                    "type": "string"
+                },
+                "contract_address": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "string"
+                },
+                "range": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.PortfolioResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/portfolio.PortfolioView"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "message": {
+                    "description": "human-readable",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "success | error",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.PriceAPIResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handlers.PricesResponse"
+                },
+                "error": {
+                    "$ref": "#/definitions/handlers.ErrorResponse"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.PricesRequest": {
+            "type": "object",
+            "properties": {
+                "assets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.AssetRequest"
+                    }
+                },
+                "include_market_data": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.PricesResponse": {
+            "type": "object",
+            "properties": {
+                "market_data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/pricing.MarketData"
+                    }
+                },
+                "prices": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number",
+                        "format": "float64"
+                    }
+                }
+            }
+        },
+        "handlers.SetPriceOverrideRequest": {
+            "type": "object",
+            "properties": {
+                "chain": {
+                    "type": "string"
+                },
+                "contract_address": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "price_usd": {
+                    "type": "number"
+                }
+            }
+        },
+        "handlers.SetSpamOverrideRequest": {
+            "type": "object",
+            "properties": {
+                "chain": {
+                    "type": "string"
+                },
+                "contract_address": {
+                    "type": "string"
+                },
+                "spam": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.SetTargetRequest": {
+            "type": "object",
+            "properties": {
+                "cash_asset": {
+                    "$ref": "#/definitions/handlers.AssetRequest"
+                },
+                "tolerance_pct": {
+                    "type": "number"
+                },
+                "weights": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.TargetWeightRequest"
+                    }
+                }
+            }
+        },
+        "handlers.TargetWeightRequest": {
+            "type": "object",
+            "properties": {
+                "chain": {
+                    "type": "string"
+                },
+                "contract_address": {
+                    "type": "string"
+                },
+                "weight_pct": {
+                    "type": "number"
+                }
+            }
+        },
+        "handlers.TransactionListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "properties": {
+                        "items": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/transactions.Transaction"
+                            }
+                        },
+                        "limit": {
+                            "type": "integer"
+                        },
+                        "page": {
+                            "type": "integer"
+                        }
+                    }
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "importer.Action": {
+            "type": "string",
+            "enum": [
+                "add",
+                "update",
+                "remove",
+                "unchanged"
+            ],
+            "x-enum-varnames": [
+                "ActionAdd",
+                "ActionUpdate",
+                "ActionRemove",
+                "ActionUnchanged"
+            ]
+        },
+        "importer.Kind": {
+            "type": "string",
+            "enum": [
+                "balance",
+                "ledger"
+            ],
+            "x-enum-comments": {
+                "KindBalance": "one row per asset holding its amount",
+                "KindLedger": "signed changes summed per asset"
+            },
+            "x-enum-descriptions": [
+                "one row per asset holding its amount",
+                "signed changes summed per asset"
+            ],
+            "x-enum-varnames": [
+                "KindBalance",
+                "KindLedger"
+            ]
+        },
+        "importer.Line": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/importer.Action"
+                },
+                "amount": {
+                    "description": "amount once applied",
+                    "type": "number"
+                },
+                "chain": {
+                    "type": "string"
+                },
+                "contract_address": {
+                    "type": "string"
+                },
+                "imported": {
+                    "description": "amount read from the file",
+                    "type": "number"
+                },
+                "previous": {
+                    "description": "amount recorded before the import",
+                    "type": "number"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "importer.Mode": {
+            "type": "string",
+            "enum": [
+                "set",
+                "add"
+            ],
+            "x-enum-comments": {
+                "ModeAdd": "imported amounts are added to recorded ones",
+                "ModeSet": "imported amounts replace recorded ones"
+            },
+            "x-enum-descriptions": [
+                "imported amounts replace recorded ones",
+                "imported amounts are added to recorded ones"
+            ],
+            "x-enum-varnames": [
+                "ModeSet",
+                "ModeAdd"
+            ]
+        },
+        "importer.Result": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "type": "boolean"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/importer.RowError"
+                    }
+                },
+                "holdings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/importer.Line"
+                    }
+                },
+                "kind": {
+                    "$ref": "#/definitions/importer.Kind"
+                },
+                "mode": {
+                    "$ref": "#/definitions/importer.Mode"
+                },
+                "preset": {
+                    "type": "string"
+                },
+                "rows": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/importer.Skip"
+                    }
+                },
+                "wallet": {
+                    "type": "string"
+                }
+            }
+        },
+        "importer.RowError": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                }
+            }
+        },
+        "importer.Skip": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "row": {
+                    "type": "integer"
+                }
+            }
+        },
+        "nft.Result": {
+            "type": "object",
+            "properties": {
+                "chain": {
+                    "type": "string"
+                },
+                "synced_at": {
+                    "type": "string"
+                },
+                "tokens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/nft.Token"
+                    }
+                },
+                "truncated": {
+                    "description": "the history is longer than etherscan returns",
+                    "type": "boolean"
+                },
+                "wallet": {
+                    "type": "string"
+                }
+            }
+        },
+        "nft.Token": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "collection": {
+                    "type": "string"
+                },
+                "collection_name": {
+                    "type": "string"
+                },
+                "last_received": {
+                    "type": "string"
+                },
+                "standard": {
+                    "$ref": "#/definitions/transactions.TokenStandard"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "token_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "performance.Benchmark": {
+            "type": "object",
+            "properties": {
+                "annualized_return_pct": {
+                    "type": "number"
+                },
+                "asset": {
+                    "$ref": "#/definitions/pricing.AssetRef"
+                },
+                "excess_return_pct": {
+                    "description": "portfolio TWR minus benchmark return",
+                    "type": "number"
+                },
+                "max_drawdown_pct": {
+                    "type": "number"
+                },
+                "money_weighted_return_pct": {
+                    "description": "annualized XIRR",
+                    "type": "number"
+                },
+                "sharpe_ratio": {
+                    "type": "number"
+                },
+                "time_weighted_return_pct": {
+                    "type": "number"
+                },
+                "volatility_pct": {
+                    "description": "annualized",
+                    "type": "number"
+                }
+            }
+        },
+        "performance.CashFlow": {
+            "type": "object",
+            "properties": {
+                "amount_usd": {
+                    "type": "number"
+                },
+                "chain": {
+                    "type": "string"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "performance.Period": {
+            "type": "string",
+            "enum": [
+                "7d",
+                "30d",
+                "90d",
+                "1y",
+                "ytd"
+            ],
+            "x-enum-varnames": [
+                "Period7d",
+                "Period30d",
+                "Period90d",
+                "Period1y",
+                "PeriodYTD"
+            ]
+        },
+        "performance.Report": {
+            "type": "object",
+            "properties": {
+                "annualized_return_pct": {
+                    "type": "number"
+                },
+                "benchmark": {
+                    "description": "nil when the benchmark could not be priced",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/performance.Benchmark"
+                        }
+                    ]
+                },
+                "cash_flows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/performance.CashFlow"
+                    }
+                },
+                "data_points": {
+                    "type": "integer"
+                },
+                "end_value_usd": {
+                    "type": "number"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "max_drawdown_pct": {
+                    "type": "number"
+                },
+                "money_weighted_return_pct": {
+                    "description": "annualized XIRR",
+                    "type": "number"
+                },
+                "net_flows_usd": {
+                    "description": "deposits minus withdrawals",
+                    "type": "number"
+                },
+                "period": {
+                    "$ref": "#/definitions/performance.Period"
+                },
+                "risk_free_pct": {
+                    "type": "number"
+                },
+                "sharpe_ratio": {
+                    "type": "number"
+                },
+                "start_value_usd": {
+                    "type": "number"
+                },
+                "time_weighted_return_pct": {
+                    "type": "number"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "truncated": {
+                    "description": "the period's history was longer than could be read",
+                    "type": "boolean"
+                },
+                "volatility_pct": {
+                    "description": "annualized",
+                    "type": "number"
+                },
+                "wallet": {
+                    "type": "string"
+                }
+            }
+        },
+        "pnl.AssetTotals": {
+            "type": "object",
+            "properties": {
+                "asset": {
+                    "$ref": "#/definitions/pricing.AssetRef"
+                },
+                "cost_basis_usd": {
+                    "type": "number"
+                },
+                "disposals": {
+                    "type": "integer"
+                },
+                "gain_usd": {
+                    "type": "number"
+                },
+                "proceeds_usd": {
+                    "type": "number"
+                },
+                "quantity": {
+                    "type": "number"
+                }
+            }
+        },
+        "pnl.Grouping": {
+            "type": "string",
+            "enum": [
+                "month",
+                "quarter",
+                "year"
+            ],
+            "x-enum-varnames": [
+                "GroupMonth",
+                "GroupQuarter",
+                "GroupYear"
+            ]
+        },
+        "pnl.Lot": {
+            "type": "object",
+            "properties": {
+                "acquire_hash": {
+                    "type": "string"
+                },
+                "acquired_at": {
+                    "type": "string"
+                },
+                "asset": {
+                    "$ref": "#/definitions/pricing.AssetRef"
+                },
+                "cost_usd": {
+                    "description": "per unit",
+                    "type": "number"
+                },
+                "quantity": {
+                    "type": "number"
+                }
+            }
+        },
+        "pnl.Method": {
+            "type": "string",
+            "enum": [
+                "fifo",
+                "lifo",
+                "hifo"
+            ],
+            "x-enum-comments": {
+                "MethodFIFO": "oldest lots first",
+                "MethodHIFO": "most expensive lots first",
+                "MethodLIFO": "newest lots first"
+            },
+            "x-enum-descriptions": [
+                "oldest lots first",
+                "newest lots first",
+                "most expensive lots first"
+            ],
+            "x-enum-varnames": [
+                "MethodFIFO",
+                "MethodLIFO",
+                "MethodHIFO"
+            ]
+        },
+        "pnl.PeriodTotals": {
+            "type": "object",
+            "properties": {
+                "cost_basis_usd": {
+                    "type": "number"
+                },
+                "disposals": {
+                    "type": "integer"
+                },
+                "gain_usd": {
+                    "type": "number"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "proceeds_usd": {
+                    "type": "number"
+                }
+            }
+        },
+        "pnl.Realization": {
+            "type": "object",
+            "properties": {
+                "acquire_hash": {
+                    "type": "string"
+                },
+                "acquired_at": {
+                    "type": "string"
+                },
+                "asset": {
+                    "$ref": "#/definitions/pricing.AssetRef"
+                },
+                "cost_basis_usd": {
+                    "type": "number"
+                },
+                "dispose_hash": {
+                    "type": "string"
+                },
+                "disposed_at": {
+                    "type": "string"
+                },
+                "gain_usd": {
+                    "type": "number"
+                },
+                "proceeds_usd": {
+                    "type": "number"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "unmatched": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "pnl.Report": {
+            "type": "object",
+            "properties": {
+                "by_asset": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pnl.AssetTotals"
+                    }
+                },
+                "by_period": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pnl.PeriodTotals"
+                    }
+                },
+                "chain": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "group_by": {
+                    "$ref": "#/definitions/pnl.Grouping"
+                },
+                "method": {
+                    "$ref": "#/definitions/pnl.Method"
+                },
+                "open_lots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pnl.Lot"
+                    }
+                },
+                "realizations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pnl.Realization"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                },
+                "totals": {
+                    "$ref": "#/definitions/pnl.Totals"
+                },
+                "truncated": {
+                    "description": "the history was longer than could be read",
+                    "type": "boolean"
+                },
+                "unpriced": {
+                    "description": "hashes of transfers with no historical price, valued at zero",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "wallet": {
+                    "type": "string"
+                }
+            }
+        },
+        "pnl.Totals": {
+            "type": "object",
+            "properties": {
+                "cost_basis_usd": {
+                    "type": "number"
+                },
+                "disposals": {
+                    "type": "integer"
+                },
+                "gain_usd": {
+                    "type": "number"
+                },
+                "proceeds_usd": {
+                    "type": "number"
+                }
+            }
+        },
+        "portfolio.Holding": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "the amount owned",
+                    "type": "number",
+                    "format": "float64"
+                },
+                "chain": {
+                    "description": "ethereum, polygon, etc",
+                    "type": "string"
+                },
+                "contractAddress": {
+                    "description": "empty for native asset",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "set on holdings returned by Service.Holdings",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/portfolio.HoldingSource"
+                        }
+                    ]
+                }
+            }
+        },
+        "portfolio.HoldingSource": {
+            "type": "string",
+            "enum": [
+                "manual",
+                "sync"
+            ],
+            "x-enum-varnames": [
+                "HoldingSourceManual",
+                "HoldingSourceSync"
+            ]
+        },
+        "portfolio.HoldingView": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "chain": {
+                    "type": "string"
+                },
+                "change24hPct": {
+                    "description": "price change over the last 24h",
+                    "type": "number",
+                    "format": "float64"
+                },
+                "contractAddress": {
+                    "type": "string"
+                },
+                "priceDerivation": {
+                    "$ref": "#/definitions/pricing.Derivation"
+                },
+                "priceNote": {
+                    "description": "note attached to an override",
+                    "type": "string"
+                },
+                "priceOverridden": {
+                    "description": "true when PriceUSD is a manual override, not a market price",
+                    "type": "boolean"
+                },
+                "priceSource": {
+                    "$ref": "#/definitions/pricing.PriceSource"
+                },
+                "priceTime": {
+                    "description": "when a historical price was observed",
+                    "type": "string"
+                },
+                "priceUSD": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "quantitySource": {
+                    "$ref": "#/definitions/portfolio.QuantitySource"
+                },
+                "spam": {
+                    "description": "nil when no spam classifier is set",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/spam.Verdict"
+                        }
+                    ]
+                },
+                "syncedAmount": {
+                    "description": "part of Amount read from on-chain balances",
+                    "type": "number",
+                    "format": "float64"
+                },
+                "token": {
+                    "description": "nil until the token has been resolved",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/tokens.Metadata"
+                        }
+                    ]
+                },
+                "valueChange24hUSD": {
+                    "description": "ValueUSD minus the same amount valued 24h ago",
+                    "type": "number",
+                    "format": "float64"
+                },
+                "valueUSD": {
+                    "type": "number",
+                    "format": "float64"
+                }
+            }
+        },
+        "portfolio.NFTHoldingView": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "chain": {
+                    "description": "ethereum, polygon, etc",
+                    "type": "string"
+                },
+                "collection": {
+                    "description": "the collection's contract address",
+                    "type": "string"
+                },
+                "collectionName": {
+                    "type": "string"
+                },
+                "floorPriceUSD": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "standard": {
+                    "$ref": "#/definitions/transactions.TokenStandard"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "tokenID": {
+                    "type": "string"
+                },
+                "valueUSD": {
+                    "type": "number",
+                    "format": "float64"
+                }
+            }
+        },
+        "portfolio.PortfolioView": {
+            "type": "object",
+            "properties": {
+                "asOf": {
+                    "description": "set when the portfolio was valued at a past time",
+                    "type": "string"
+                },
+                "hiddenSpam": {
+                    "description": "holdings left out as spam, when asked to",
+                    "type": "integer"
+                },
+                "holdings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/portfolio.HoldingView"
+                    }
+                },
+                "nfts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/portfolio.NFTHoldingView"
+                    }
+                },
+                "nftvalueUSD": {
+                    "description": "floor value of the NFTs, not part of TotalValueUSD",
+                    "type": "number",
+                    "format": "float64"
+                },
+                "pnL24hPct": {
+                    "description": "PnL24hUSD relative to the portfolio value 24h ago",
+                    "type": "number",
+                    "format": "float64"
+                },
+                "pnL24hUSD": {
+                    "description": "sum of the holdings' ValueChange24hUSD",
+                    "type": "number",
+                    "format": "float64"
+                },
+                "syncedAt": {
+                    "description": "last on-chain balance sync, nil when never synced",
+                    "type": "string"
+                },
+                "totalValueUSD": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "wallet": {
+                    "type": "string"
+                }
+            }
+        },
+        "portfolio.QuantitySource": {
+            "type": "string",
+            "enum": [
+                "current",
+                "holding_history",
+                "transactions"
+            ],
+            "x-enum-comments": {
+                "QuantitySourceCurrent": "the holding as it is now",
+                "QuantitySourceHoldingHistory": "rebuilt from recorded holding changes",
+                "QuantitySourceTransactions": "rebuilt by replaying transactions"
+            },
+            "x-enum-descriptions": [
+                "the holding as it is now",
+                "rebuilt from recorded holding changes",
+                "rebuilt by replaying transactions"
+            ],
+            "x-enum-varnames": [
+                "QuantitySourceCurrent",
+                "QuantitySourceHoldingHistory",
+                "QuantitySourceTransactions"
+            ]
+        },
+        "pricing.AssetRef": {
+            "type": "object",
+            "properties": {
+                "chain": {
+                    "description": "e.g. \"ethereum\", \"polygon\"",
+                    "type": "string"
+                },
+                "contract_address": {
+                    "description": "lowercase hex",
+                    "type": "string"
+                }
+            }
+        },
+        "pricing.Candle": {
+            "type": "object",
+            "properties": {
+                "close": {
+                    "type": "number"
+                },
+                "high": {
+                    "type": "number"
+                },
+                "low": {
+                    "type": "number"
+                },
+                "open": {
+                    "type": "number"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "pricing.Derivation": {
+            "type": "object",
+            "properties": {
+                "factor": {
+                    "description": "multiplier or exchange rate applied",
+                    "type": "number"
+                },
+                "kind": {
+                    "$ref": "#/definitions/pricing.RuleKind"
+                },
+                "underlying": {
+                    "$ref": "#/definitions/pricing.AssetRef"
+                },
+                "underlying_price_usd": {
+                    "type": "number"
+                },
+                "underlying_source": {
+                    "$ref": "#/definitions/pricing.PriceSource"
+                }
+            }
+        },
+        "pricing.MarketData": {
+            "type": "object",
+            "properties": {
+                "change_24h_pct": {
+                    "type": "number"
+                },
+                "derivation": {
+                    "description": "set when Source is derived",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/pricing.Derivation"
+                        }
+                    ]
+                },
+                "last_updated": {
+                    "type": "string"
+                },
+                "market_cap_usd": {
+                    "type": "number"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "price_usd": {
+                    "type": "number"
+                },
+                "source": {
+                    "$ref": "#/definitions/pricing.PriceSource"
+                },
+                "volume_24h_usd": {
+                    "type": "number"
+                }
+            }
+        },
+        "pricing.Override": {
+            "type": "object",
+            "properties": {
+                "asset": {
+                    "$ref": "#/definitions/pricing.AssetRef"
+                },
+                "expires_at": {
+                    "description": "nil never expires",
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "price_usd": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "pricing.PriceSource": {
+            "type": "string",
+            "enum": [
+                "market",
+                "override",
+                "derived",
+                "historical"
+            ],
+            "x-enum-comments": {
+                "PriceSourceMarket": "a price provider, possibly via cache",
+                "PriceSourceOverride": "a manual admin override"
+            },
+            "x-enum-descriptions": [
+                "a price provider, possibly via cache",
+                "a manual admin override",
+                "",
+                ""
+            ],
+            "x-enum-varnames": [
+                "PriceSourceMarket",
+                "PriceSourceOverride",
+                "PriceSourceDerived",
+                "PriceSourceHistorical"
+            ]
+        },
+        "pricing.RuleKind": {
+            "type": "string",
+            "enum": [
+                "peg",
+                "multiple",
+                "exchange_rate"
+            ],
+            "x-enum-comments": {
+                "RuleExchangeRate": "on-chain rate x underlying, e.g. wstETH, rETH",
+                "RuleMultiple": "Factor x underlying, e.g. WBTC on another chain",
+                "RulePeg": "fixed USD price, e.g. bridged USDC"
+            },
+            "x-enum-descriptions": [
+                "fixed USD price, e.g. bridged USDC",
+                "Factor x underlying, e.g. WBTC on another chain",
+                "on-chain rate x underlying, e.g. wstETH, rETH"
+            ],
+            "x-enum-varnames": [
+                "RulePeg",
+                "RuleMultiple",
+                "RuleExchangeRate"
+            ]
+        },
+        "rebalance.Action": {
+            "type": "string",
+            "enum": [
+                "buy",
+                "sell",
+                "hold"
+            ],
+            "x-enum-varnames": [
+                "ActionBuy",
+                "ActionSell",
+                "ActionHold"
+            ]
+        },
+        "rebalance.Line": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/rebalance.Action"
+                },
+                "amount_usd": {
+                    "description": "value to buy or sell, 0 for hold",
+                    "type": "number"
+                },
+                "asset": {
+                    "$ref": "#/definitions/pricing.AssetRef"
+                },
+                "current_value_usd": {
+                    "type": "number"
+                },
+                "current_weight_pct": {
+                    "type": "number"
+                },
+                "post_weight_pct": {
+                    "description": "weight once the plan is executed",
+                    "type": "number"
+                },
+                "price_usd": {
+                    "type": "number"
+                },
+                "quantity": {
+                    "description": "units to buy or sell, 0 for hold",
+                    "type": "number"
+                },
+                "reason": {
+                    "description": "why a drifted asset is held",
+                    "type": "string"
+                },
+                "target_weight_pct": {
+                    "type": "number"
+                }
+            }
+        },
+        "rebalance.Plan": {
+            "type": "object",
+            "properties": {
+                "cash_only": {
+                    "type": "boolean"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rebalance.Line"
+                    }
+                },
+                "min_trade_usd": {
+                    "type": "number"
+                },
+                "tolerance_pct": {
+                    "type": "number"
+                },
+                "total_buy_usd": {
+                    "type": "number"
+                },
+                "total_sell_usd": {
+                    "type": "number"
+                },
+                "total_value_usd": {
+                    "type": "number"
+                },
+                "unpriced": {
+                    "description": "targets that could not be priced and are left out",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/pricing.AssetRef"
+                    }
+                },
+                "wallet": {
+                    "type": "string"
+                }
+            }
+        },
+        "rebalance.Target": {
+            "type": "object",
+            "properties": {
+                "cash_asset": {
+                    "description": "funds buys in cash-only plans",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/pricing.AssetRef"
+                        }
+                    ]
+                },
+                "tolerance_pct": {
+                    "description": "drift in percentage points left alone",
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "wallet": {
+                    "type": "string"
+                },
+                "weights": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rebalance.TargetWeight"
+                    }
+                }
+            }
+        },
+        "rebalance.TargetWeight": {
+            "type": "object",
+            "properties": {
+                "asset": {
+                    "$ref": "#/definitions/pricing.AssetRef"
+                },
+                "weight_pct": {
+                    "type": "number"
+                }
+            }
+        },
+        "reconcile.Line": {
             "type": "object",
             "properties": {
-                "code": {
+                "chain": {
                     "type": "string"
                 },
-                "message": {
+                "contract_address": {
+                    "type": "string"
+                },
+                "diff": {
+                    "description": "on-chain minus holding",
+                    "type": "number"
+                },
+                "error": {
                     "type": "string"
+                },
+                "holding": {
+                    "description": "manual and synced amounts together",
+                    "type": "number"
+                },
+                "manual": {
+                    "type": "number"
+                },
+                "on_chain": {
+                    "type": "number"
+                },
+                "status": {
+                    "$ref": "#/definitions/reconcile.Status"
+                },
+                "transactions": {
+                    "description": "Transactions is the sum of transfers in less transfers out, and for\nnative assets gas paid; nil when the history cannot be read",
+                    "type": "number"
+                },
+                "transactions_status": {
+                    "$ref": "#/definitions/reconcile.Status"
                 }
             }
         },
-        "handlers.PortfolioResponse": {
+        "reconcile.Report": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/portfolio.PortfolioView"
-                },
-                "error": {
+                "checked_at": {
                     "type": "string"
                 },
-                "message": {
-                    "description": "human-readable",
-                    "type": "string"
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/reconcile.Line"
+                    }
                 },
-                "status": {
-                    "description": "success | error",
+                "summary": {
+                    "$ref": "#/definitions/reconcile.Summary"
+                },
+                "tolerance_pct": {
+                    "type": "number"
+                },
+                "truncated": {
+                    "description": "Truncated is set when a chain's history is longer than the 10,000\nrecords etherscan returns, so the transaction sums are partial",
+                    "type": "boolean"
+                },
+                "wallet": {
                     "type": "string"
                 }
             }
         },
-        "handlers.PriceAPIResponse": {
+        "reconcile.Status": {
+            "type": "string",
+            "enum": [
+                "match",
+                "missing",
+                "extra",
+                "mismatch",
+                "unknown"
+            ],
+            "x-enum-comments": {
+                "StatusExtra": "in the portfolio, not held on-chain",
+                "StatusMatch": "the amounts agree within the tolerance",
+                "StatusMismatch": "both hold it in different amounts",
+                "StatusMissing": "held on-chain, not in the portfolio",
+                "StatusUnknown": "the on-chain balance could not be read"
+            },
+            "x-enum-descriptions": [
+                "the amounts agree within the tolerance",
+                "held on-chain, not in the portfolio",
+                "in the portfolio, not held on-chain",
+                "both hold it in different amounts",
+                "the on-chain balance could not be read"
+            ],
+            "x-enum-varnames": [
+                "StatusMatch",
+                "StatusMissing",
+                "StatusExtra",
+                "StatusMismatch",
+                "StatusUnknown"
+            ]
+        },
+        "reconcile.Summary": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/handlers.PricesResponse"
+                "extra": {
+                    "type": "integer"
                 },
-                "error": {
-                    "$ref": "#/definitions/handlers.ErrorResponse"
+                "match": {
+                    "type": "integer"
                 },
-                "success": {
-                    "type": "boolean"
+                "mismatch": {
+                    "type": "integer"
+                },
+                "missing": {
+                    "type": "integer"
+                },
+                "unknown": {
+                    "type": "integer"
                 }
             }
         },
-        "handlers.PricesRequest": {
+        "snapshots.Snapshot": {
             "type": "object",
             "properties": {
-                "assets": {
+                "holdings": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handlers.AssetRequest"
+                        "$ref": "#/definitions/snapshots.SnapshotHolding"
                     }
+                },
+                "taken_at": {
+                    "type": "string"
+                },
+                "total_value_usd": {
+                    "type": "number"
+                },
+                "wallet": {
+                    "type": "string"
                 }
             }
         },
-        "handlers.PricesResponse": {
+        "snapshots.SnapshotHolding": {
             "type": "object",
             "properties": {
-                "prices": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "number",
-                        "format": "float64"
-                    }
+                "amount": {
+                    "type": "number"
+                },
+                "chain": {
+                    "type": "string"
+                },
+                "contract_address": {
+                    "type": "string"
+                },
+                "price_usd": {
+                    "type": "number"
+                },
+                "value_usd": {
+                    "type": "number"
                 }
             }
         },
-        "handlers.TransactionListResponse": {
+        "spam.Override": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "object",
-                    "properties": {
-                        "items": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/transactions.Transaction"
-                            }
-                        },
-                        "limit": {
-                            "type": "integer"
-                        },
-                        "page": {
-                            "type": "integer"
-                        }
+                "asset": {
+                    "$ref": "#/definitions/pricing.AssetRef"
+                },
+                "spam": {
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "wallet": {
+                    "type": "string"
+                }
+            }
+        },
+        "spam.Reason": {
+            "type": "string",
+            "enum": [
+                "override",
+                "allow_list",
+                "deny_list",
+                "known_token",
+                "lookalike_symbol",
+                "no_price",
+                "zero_value_transfer"
+            ],
+            "x-enum-comments": {
+                "ReasonAllowList": "configured never-spam list",
+                "ReasonDenyList": "configured always-spam list",
+                "ReasonLookalike": "poses as a well-known token",
+                "ReasonNoPrice": "no market prices it",
+                "ReasonOverride": "the wallet's own call",
+                "ReasonZeroValue": "moved nothing, address-poisoning style"
+            },
+            "x-enum-descriptions": [
+                "the wallet's own call",
+                "configured never-spam list",
+                "configured always-spam list",
+                "",
+                "poses as a well-known token",
+                "no market prices it",
+                "moved nothing, address-poisoning style"
+            ],
+            "x-enum-varnames": [
+                "ReasonOverride",
+                "ReasonAllowList",
+                "ReasonDenyList",
+                "ReasonKnown",
+                "ReasonLookalike",
+                "ReasonNoPrice",
+                "ReasonZeroValue"
+            ]
+        },
+        "spam.Verdict": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "$ref": "#/definitions/spam.Reason"
+                },
+                "signals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/spam.Reason"
                     }
                 },
-                "success": {
+                "spam": {
                     "type": "boolean"
                 }
             }
         },
-        "portfolio.Holding": {
+        "tax.Jurisdiction": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "description": "the amount owned",
-                    "type": "number",
-                    "format": "float64"
+                "code": {
+                    "type": "string"
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "inclusive": {
+                    "description": "Inclusive makes a disposal on the anniversary long-term; otherwise\nthe asset must be held for more than the period",
+                    "type": "boolean"
+                },
+                "months": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
                 },
+                "note": {
+                    "type": "string"
+                },
+                "years": {
+                    "type": "integer"
+                }
+            }
+        },
+        "tax.Summary": {
+            "type": "object",
+            "properties": {
                 "chain": {
-                    "description": "ethereum, polygon, etc",
                     "type": "string"
                 },
-                "contractAddress": {
-                    "description": "empty for native asset",
+                "jurisdiction": {
+                    "$ref": "#/definitions/tax.Jurisdiction"
+                },
+                "long_term": {
+                    "$ref": "#/definitions/pnl.Totals"
+                },
+                "method": {
+                    "$ref": "#/definitions/pnl.Method"
+                },
+                "short_term": {
+                    "$ref": "#/definitions/pnl.Totals"
+                },
+                "total": {
+                    "$ref": "#/definitions/pnl.Totals"
+                },
+                "truncated": {
+                    "type": "boolean"
+                },
+                "unmatched": {
+                    "description": "rows with no acquisition on record",
+                    "type": "integer"
+                },
+                "unpriced": {
+                    "description": "transfers valued at zero",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "wallet": {
                     "type": "string"
+                },
+                "year": {
+                    "type": "integer"
                 }
             }
         },
-        "portfolio.HoldingView": {
+        "tokens.Metadata": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "type": "number",
-                    "format": "float64"
+                "chain": {
+                    "type": "string"
+                },
+                "contract_address": {
+                    "description": "empty for the native asset",
+                    "type": "string"
+                },
+                "decimals": {
+                    "type": "integer"
+                },
+                "logo_url": {
+                    "type": "string"
                 },
+                "name": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "tokens.SearchResult": {
+            "type": "object",
+            "properties": {
                 "chain": {
                     "type": "string"
                 },
-                "contractAddress": {
+                "coin_id": {
                     "type": "string"
                 },
-                "priceUSD": {
-                    "type": "number",
-                    "format": "float64"
+                "contract_address": {
+                    "description": "empty for the native asset",
+                    "type": "string"
                 },
-                "valueUSD": {
-                    "type": "number",
-                    "format": "float64"
+                "market_cap_rank": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
                 }
             }
         },
-        "portfolio.PortfolioView": {
+        "tokens.TokenList": {
             "type": "object",
             "properties": {
-                "holdings": {
+                "name": {
+                    "type": "string"
+                },
+                "tokens": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/portfolio.HoldingView"
+                        "$ref": "#/definitions/tokens.TokenListItem"
                     }
+                }
+            }
+        },
+        "tokens.TokenListItem": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
                 },
-                "totalValueUSD": {
-                    "type": "number",
-                    "format": "float64"
+                "chainId": {
+                    "type": "integer"
                 },
-                "wallet": {
+                "decimals": {
+                    "type": "integer"
+                },
+                "logoURI": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "symbol": {
                     "type": "string"
                 }
             }
@@ -489,6 +4196,50 @@ const docTemplate = `{
                 "DirectionOut"
             ]
         },
+        "transactions.SwapLeg": {
+            "type": "object",
+            "properties": {
+                "amountIn": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "amountOut": {
+                    "type": "number",
+                    "format": "float64"
+                },
+                "dex": {
+                    "description": "protocols whose swap events the logs hold, e.g. uniswap-v2+uniswap-v3",
+                    "type": "string"
+                },
+                "router": {
+                    "description": "the contract the wallet called",
+                    "type": "string"
+                },
+                "symbolIn": {
+                    "type": "string"
+                },
+                "symbolOut": {
+                    "type": "string"
+                },
+                "tokenIn": {
+                    "type": "string"
+                },
+                "tokenOut": {
+                    "type": "string"
+                }
+            }
+        },
+        "transactions.TokenStandard": {
+            "type": "string",
+            "enum": [
+                "erc721",
+                "erc1155"
+            ],
+            "x-enum-varnames": [
+                "StandardERC721",
+                "StandardERC1155"
+            ]
+        },
         "transactions.Transaction": {
             "type": "object",
             "properties": {
@@ -503,6 +4254,11 @@ const docTemplate = `{
                 "direction": {
                     "$ref": "#/definitions/transactions.Direction"
                 },
+                "fee": {
+                    "description": "gas paid by From, in the chain's native asset",
+                    "type": "number",
+                    "format": "float64"
+                },
                 "from": {
                     "type": "string"
                 },
@@ -514,6 +4270,32 @@ const docTemplate = `{
                     "description": "internal ID (can be tx hash)",
                     "type": "string"
                 },
+                "legs": {
+                    "description": "set on swaps decoded from their receipt logs",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/transactions.SwapLeg"
+                    }
+                },
+                "method": {
+                    "description": "contract function called, empty for plain transfers",
+                    "type": "string"
+                },
+                "parentHash": {
+                    "description": "ParentHash is set on internal transactions, value moved by a\ncontract call, to the transaction that made the call",
+                    "type": "string"
+                },
+                "spam": {
+                    "description": "set when a spam classifier has judged it",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/spam.Verdict"
+                        }
+                    ]
+                },
+                "standard": {
+                    "$ref": "#/definitions/transactions.TokenStandard"
+                },
                 "status": {
                     "$ref": "#/definitions/transactions.TransactionStatus"
                 },
@@ -531,6 +4313,13 @@ const docTemplate = `{
                     "description": "empty for native ETH",
                     "type": "string"
                 },
+                "tokenID": {
+                    "description": "set on NFT transfers, where TokenAddr is the collection and Amount\nthe number of tokens moved",
+                    "type": "string"
+                },
+                "tokenMeta": {
+                    "$ref": "#/definitions/tokens.Metadata"
+                },
                 "type": {
                     "$ref": "#/definitions/transactions.TransactionType"
                 }
@@ -553,13 +4342,25 @@ const docTemplate = `{
                 "send",
                 "receive",
                 "swap",
-                "stake"
+                "stake",
+                "nft"
+            ],
+            "x-enum-comments": {
+                "TypeNFT": "an ERC-721 or ERC-1155 transfer"
+            },
+            "x-enum-descriptions": [
+                "",
+                "",
+                "",
+                "",
+                "an ERC-721 or ERC-1155 transfer"
             ],
             "x-enum-varnames": [
                 "TypeSend",
                 "TypeReceive",
                 "TypeSwap",
-                "TypeStake"
+                "TypeStake",
+                "TypeNFT"
             ]
         }
     }
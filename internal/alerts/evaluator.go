@@ -0,0 +1,356 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+// Evaluator periodically checks every enabled rule: price rules against the
+// same pricing service as the API, wallet rules against the portfolio service
+type Evaluator struct {
+	rules     RuleRepository
+	history   HistoryRepository
+	pricing   pricing.ServiceAPI
+	portfolio portfolio.Service
+	notifier  Notifier
+	interval  time.Duration
+	logger    *zap.Logger
+
+	mu      sync.Mutex
+	prices  map[pricing.AssetRef][]sample // within MaxWindow, oldest first
+	wallets map[string][]sample           // within MaxWalletWindow, oldest first
+}
+
+type sample struct {
+	time  time.Time
+	value float64
+}
+
+// firing is a rule that fired this round and the event to deliver
+type firing struct {
+	rule  Rule
+	event Event
+}
+
+func NewEvaluator(
+	rules RuleRepository,
+	history HistoryRepository,
+	prices pricing.ServiceAPI,
+	wallets portfolio.Service,
+	notifier Notifier,
+	interval time.Duration,
+	logger *zap.Logger,
+) *Evaluator {
+	return &Evaluator{
+		rules:     rules,
+		history:   history,
+		pricing:   prices,
+		portfolio: wallets,
+		notifier:  notifier,
+		interval:  interval,
+		logger:    logger.With(zap.String("service", "alerts")),
+		prices:    make(map[pricing.AssetRef][]sample),
+		wallets:   make(map[string][]sample),
+	}
+}
+
+// Run evaluates every rule each interval. It returns at once when the
+// schedule is off.
+func (e *Evaluator) Run(ctx context.Context) {
+	if e.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.Evaluate(ctx, time.Now().UTC())
+		}
+	}
+}
+
+// Evaluate checks every enabled rule once at now. It returns when all
+// webhooks of this round have been delivered or given up on.
+func (e *Evaluator) Evaluate(ctx context.Context, now time.Time) {
+	all, err := e.rules.List(ctx)
+	if err != nil {
+		e.logger.Error("list-alert-rules-failed", zap.Error(err))
+		return
+	}
+
+	priceRules := make([]Rule, 0)
+	walletRules := make([]Rule, 0)
+	for _, r := range all {
+		switch {
+		case !r.Enabled:
+		case r.WalletRule():
+			walletRules = append(walletRules, r)
+		case r.Asset != nil:
+			priceRules = append(priceRules, r)
+		}
+	}
+
+	fired := e.evaluatePrices(ctx, priceRules, now)
+	fired = append(fired, e.evaluateWallets(ctx, walletRules, now)...)
+
+	var wg sync.WaitGroup
+	for _, f := range fired {
+		wg.Add(1)
+		go func(f firing) {
+			defer wg.Done()
+			e.deliver(ctx, f.rule, f.event)
+		}(f)
+	}
+	wg.Wait()
+}
+
+func (e *Evaluator) evaluatePrices(ctx context.Context, rules []Rule, now time.Time) []firing {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	unique := make(map[pricing.AssetRef]struct{})
+	for _, r := range rules {
+		unique[*r.Asset] = struct{}{}
+	}
+	assets := make([]pricing.AssetRef, 0, len(unique))
+	for a := range unique {
+		assets = append(assets, a)
+	}
+
+	market, err := e.pricing.GetMarketData(ctx, assets)
+	if err != nil {
+		e.logger.Error("alert-pricing-failed", zap.Error(err))
+		return nil
+	}
+
+	e.mu.Lock()
+	for asset, md := range market {
+		e.prices[asset] = appendSample(e.prices[asset], sample{now, md.PriceUSD}, now.Add(-MaxWindow))
+	}
+	e.mu.Unlock()
+
+	fired := make([]firing, 0)
+	for _, r := range rules {
+		md, ok := market[*r.Asset]
+		if !ok || md.PriceUSD <= 0 {
+			continue
+		}
+
+		event, holds := e.checkPrice(r, md, now)
+		if e.transition(ctx, r, holds, now) {
+			fired = append(fired, firing{rule: r, event: event})
+		}
+	}
+	return fired
+}
+
+func (e *Evaluator) evaluateWallets(ctx context.Context, rules []Rule, now time.Time) []firing {
+	if len(rules) == 0 || e.portfolio == nil {
+		return nil
+	}
+
+	// each wallet is valued once per round however many rules watch it
+	views := make(map[string]*portfolio.PortfolioView)
+	for _, r := range rules {
+		if _, ok := views[r.Wallet]; ok {
+			continue
+		}
+
+		view, err := e.portfolio.Get(ctx, r.Wallet)
+		if err != nil {
+			e.logger.Warn("alert-portfolio-failed", zap.String("wallet", r.Wallet), zap.Error(err))
+			views[r.Wallet] = nil
+			continue
+		}
+		views[r.Wallet] = view
+
+		e.mu.Lock()
+		e.wallets[r.Wallet] = appendSample(e.wallets[r.Wallet], sample{now, view.TotalValueUSD}, now.Add(-MaxWalletWindow))
+		e.mu.Unlock()
+	}
+
+	fired := make([]firing, 0)
+	for _, r := range rules {
+		view := views[r.Wallet]
+		if view == nil || view.TotalValueUSD <= 0 {
+			continue
+		}
+
+		event, holds := e.checkWallet(r, view, now)
+		if e.transition(ctx, r, holds, now) {
+			fired = append(fired, firing{rule: r, event: event})
+		}
+	}
+	return fired
+}
+
+// transition stores the rule's new state and reports whether it should fire:
+// only when the condition starts to hold and the rule is not cooling down
+func (e *Evaluator) transition(ctx context.Context, r Rule, holds bool, now time.Time) bool {
+	var err error
+	fire := false
+
+	switch {
+	case holds && !r.Firing && !r.CoolingDown(now):
+		fire = true
+		err = e.rules.UpdateState(ctx, r.ID, true, &now)
+	case !holds && r.Firing:
+		err = e.rules.UpdateState(ctx, r.ID, false, r.LastTriggeredAt)
+	}
+
+	if err != nil {
+		// without the state stored the rule would fire again next round
+		e.logger.Error("update-alert-state-failed", zap.String("rule", r.ID), zap.Error(err))
+		return false
+	}
+	return fire
+}
+
+func (e *Evaluator) checkPrice(r Rule, md pricing.MarketData, now time.Time) (Event, bool) {
+	event := newEvent(r, now)
+	event.PriceUSD = md.PriceUSD
+
+	switch r.Kind {
+	case KindPriceAbove:
+		event.Message = fmt.Sprintf("%s price %.6g is above %.6g", r.Asset, md.PriceUSD, r.Threshold)
+		return event, md.PriceUSD > r.Threshold
+
+	case KindPriceBelow:
+		event.Message = fmt.Sprintf("%s price %.6g is below %.6g", r.Asset, md.PriceUSD, r.Threshold)
+		return event, md.PriceUSD < r.Threshold
+
+	case KindPercentChange:
+		change, ok := e.priceChange(*r.Asset, md.PriceUSD, r.Window(), now)
+		if !ok {
+			return event, false
+		}
+		event.ChangePct = change
+		event.Message = fmt.Sprintf("%s moved %.2f%% in %dm", r.Asset, change, r.WindowMinutes)
+		if r.Threshold > 0 {
+			return event, change >= r.Threshold
+		}
+		return event, change <= r.Threshold
+	}
+
+	return event, false
+}
+
+func (e *Evaluator) checkWallet(r Rule, view *portfolio.PortfolioView, now time.Time) (Event, bool) {
+	event := newEvent(r, now)
+	event.ValueUSD = view.TotalValueUSD
+
+	switch r.Kind {
+	case KindDrawdown:
+		peak := e.walletPeak(r.Wallet, r.Window(), now)
+		if peak <= 0 {
+			return event, false
+		}
+		drawdown := (peak - view.TotalValueUSD) / peak * 100
+		event.PeakValueUSD = peak
+		event.ChangePct = -drawdown
+		event.Message = fmt.Sprintf("wallet %s is down %.2f%% from its %dm high of %.2f USD", r.Wallet, drawdown, r.WindowMinutes, peak)
+		return event, drawdown >= r.Threshold
+
+	case KindConcentration:
+		var largest portfolio.HoldingView
+		for _, h := range view.Holdings {
+			if h.ValueUSD > largest.ValueUSD {
+				largest = h
+			}
+		}
+		share := largest.ValueUSD / view.TotalValueUSD * 100
+		asset := pricing.AssetRef{Chain: largest.Chain, ContractAddress: largest.ContractAddress}
+		event.Asset = &asset
+		event.SharePct = share
+		event.Message = fmt.Sprintf("%s is %.2f%% of wallet %s", asset, share, r.Wallet)
+		return event, share > r.Threshold
+	}
+
+	return event, false
+}
+
+// priceChange compares price with the oldest sample inside the window
+func (e *Evaluator) priceChange(asset pricing.AssetRef, price float64, window time.Duration, now time.Time) (float64, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	from := now.Add(-window)
+	for _, s := range e.prices[asset] {
+		if s.time.Before(from) {
+			continue
+		}
+		if !s.time.Before(now) || s.value <= 0 {
+			return 0, false
+		}
+		return (price - s.value) / s.value * 100, true
+	}
+	return 0, false
+}
+
+// walletPeak is the highest recorded value of a wallet inside the window
+func (e *Evaluator) walletPeak(wallet string, window time.Duration, now time.Time) float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	from := now.Add(-window)
+	var peak float64
+	for _, s := range e.wallets[wallet] {
+		if !s.time.Before(from) && s.value > peak {
+			peak = s.value
+		}
+	}
+	return peak
+}
+
+func (e *Evaluator) deliver(ctx context.Context, r Rule, event Event) {
+	e.logger.Info("alert-fired",
+		zap.String("rule", r.ID),
+		zap.String("kind", string(r.Kind)),
+		zap.String("message", event.Message),
+	)
+
+	if err := e.history.Add(ctx, event); err != nil {
+		e.logger.Error("record-alert-failed", zap.String("rule", r.ID), zap.Error(err))
+	}
+
+	if err := e.notifier.Notify(ctx, r.WebhookURL, event); err != nil {
+		e.logger.Warn("alert-webhook-failed",
+			zap.String("rule", r.ID),
+			zap.Error(err),
+		)
+	}
+}
+
+func newEvent(r Rule, now time.Time) Event {
+	return Event{
+		ID:          newID(),
+		RuleID:      r.ID,
+		Kind:        r.Kind,
+		Asset:       r.Asset,
+		Wallet:      r.Wallet,
+		Threshold:   r.Threshold,
+		TriggeredAt: now,
+	}
+}
+
+// appendSample adds s and drops samples older than cutoff
+func appendSample(samples []sample, s sample, cutoff time.Time) []sample {
+	i := 0
+	for i < len(samples) && samples[i].time.Before(cutoff) {
+		i++
+	}
+	return append(samples[i:], s)
+}
@@ -0,0 +1,180 @@
+package alerts
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+var eth = pricing.AssetRef{Chain: "ethereum"}
+
+type fakePricing struct {
+	prices map[pricing.AssetRef]float64
+}
+
+func (f *fakePricing) GetPrices(ctx context.Context, assets []pricing.AssetRef) (map[pricing.AssetRef]float64, error) {
+	return f.prices, nil
+}
+
+func (f *fakePricing) GetMarketData(ctx context.Context, assets []pricing.AssetRef) (map[pricing.AssetRef]pricing.MarketData, error) {
+	out := make(map[pricing.AssetRef]pricing.MarketData)
+	for a, p := range f.prices {
+		out[a] = pricing.MarketData{PriceUSD: p}
+	}
+	return out, nil
+}
+
+func (f *fakePricing) GetCandles(ctx context.Context, asset pricing.AssetRef, interval pricing.Interval, days int) ([]pricing.Candle, error) {
+	return nil, nil
+}
+
+func (f *fakePricing) GetHistoricalPrices(ctx context.Context, assets []pricing.AssetRef, at time.Time) (map[pricing.AssetRef]pricing.HistoricalPrice, error) {
+	return nil, nil
+}
+
+func minutes(n int) *int {
+	return &n
+}
+
+type fakeNotifier struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, url string, e Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, e)
+	return nil
+}
+
+type fakePortfolio struct {
+	portfolio.Service
+	views map[string]*portfolio.PortfolioView
+}
+
+func (f *fakePortfolio) Get(ctx context.Context, wallet string) (*portfolio.PortfolioView, error) {
+	return f.views[wallet], nil
+}
+
+func newTestEvaluator(t *testing.T, rule Rule) (*Evaluator, *fakePricing, *fakeNotifier) {
+	ev, prices, _, notifier := newWalletTestEvaluator(t, rule)
+	return ev, prices, notifier
+}
+
+func newWalletTestEvaluator(t *testing.T, rule Rule) (*Evaluator, *fakePricing, *fakePortfolio, *fakeNotifier) {
+	repo := NewMemoryRuleRepository()
+	history := NewMemoryHistoryRepository()
+	svc := NewService(repo, history, zap.NewNop())
+
+	rule.Enabled = true
+	rule.WebhookURL = "https://hooks.example.com"
+	_, err := svc.CreateRule(context.Background(), rule)
+	require.NoError(t, err)
+
+	prices := &fakePricing{prices: map[pricing.AssetRef]float64{}}
+	wallets := &fakePortfolio{views: map[string]*portfolio.PortfolioView{}}
+	notifier := &fakeNotifier{}
+	return NewEvaluator(repo, history, prices, wallets, notifier, time.Minute, zap.NewNop()), prices, wallets, notifier
+}
+
+func TestEvaluator_FiresOnceWhileConditionHolds(t *testing.T) {
+	ev, prices, notifier := newTestEvaluator(t, Rule{Kind: KindPriceAbove, Asset: &eth, Threshold: 3000})
+	now := time.Now()
+
+	prices.prices[eth] = 2900
+	ev.Evaluate(context.Background(), now)
+	require.Empty(t, notifier.events)
+
+	prices.prices[eth] = 3100
+	ev.Evaluate(context.Background(), now.Add(time.Minute))
+	ev.Evaluate(context.Background(), now.Add(2*time.Minute))
+	require.Len(t, notifier.events, 1)
+	require.Equal(t, 3100.0, notifier.events[0].PriceUSD)
+}
+
+func TestEvaluator_CooldownSuppressesFlapping(t *testing.T) {
+	ev, prices, notifier := newTestEvaluator(t, Rule{Kind: KindPriceBelow, Asset: &eth, Threshold: 3000, CooldownMinutes: minutes(30)})
+	now := time.Now()
+
+	for i, p := range []float64{2900, 3100, 2900, 3100, 2900} {
+		prices.prices[eth] = p
+		ev.Evaluate(context.Background(), now.Add(time.Duration(i)*time.Minute))
+	}
+	require.Len(t, notifier.events, 1)
+
+	// still below once the cooldown has passed, so it fires again
+	ev.Evaluate(context.Background(), now.Add(31*time.Minute))
+	require.Len(t, notifier.events, 2)
+}
+
+func TestEvaluator_NoCooldownFiresOnEveryCrossing(t *testing.T) {
+	ev, prices, notifier := newTestEvaluator(t, Rule{Kind: KindPriceBelow, Asset: &eth, Threshold: 3000, CooldownMinutes: minutes(0)})
+	now := time.Now()
+
+	for i, p := range []float64{2900, 3100, 2900} {
+		prices.prices[eth] = p
+		ev.Evaluate(context.Background(), now.Add(time.Duration(i)*time.Minute))
+	}
+	require.Len(t, notifier.events, 2)
+}
+
+func TestEvaluator_PercentChangeWithinWindow(t *testing.T) {
+	ev, prices, notifier := newTestEvaluator(t, Rule{Kind: KindPercentChange, Asset: &eth, Threshold: -10, WindowMinutes: 60})
+	now := time.Now()
+
+	prices.prices[eth] = 3000
+	ev.Evaluate(context.Background(), now)
+
+	prices.prices[eth] = 2800
+	ev.Evaluate(context.Background(), now.Add(30*time.Minute))
+	require.Empty(t, notifier.events)
+
+	prices.prices[eth] = 2600
+	ev.Evaluate(context.Background(), now.Add(50*time.Minute))
+	require.Len(t, notifier.events, 1)
+	require.InDelta(t, -13.33, notifier.events[0].ChangePct, 0.01)
+}
+
+func TestEvaluator_DrawdownFromRecentHigh(t *testing.T) {
+	ev, _, wallets, notifier := newWalletTestEvaluator(t, Rule{Kind: KindDrawdown, Wallet: "0xabc", Threshold: 20, WindowMinutes: 120})
+	now := time.Now()
+
+	for i, v := range []float64{1000, 1200, 1000, 950} {
+		wallets.views["0xabc"] = &portfolio.PortfolioView{Wallet: "0xabc", TotalValueUSD: v}
+		ev.Evaluate(context.Background(), now.Add(time.Duration(i)*time.Minute))
+	}
+
+	require.Len(t, notifier.events, 1)
+	require.Equal(t, 1200.0, notifier.events[0].PeakValueUSD)
+	require.Equal(t, "0xabc", notifier.events[0].Wallet)
+
+	history, err := ev.history.ListByWallet(context.Background(), "0xabc", 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+}
+
+func TestEvaluator_Concentration(t *testing.T) {
+	ev, _, wallets, notifier := newWalletTestEvaluator(t, Rule{Kind: KindConcentration, Wallet: "0xabc", Threshold: 60})
+	wallets.views["0xabc"] = &portfolio.PortfolioView{
+		Wallet:        "0xabc",
+		TotalValueUSD: 1000,
+		Holdings: []portfolio.HoldingView{
+			{Chain: "ethereum", ContractAddress: "0xwbtc", ValueUSD: 700},
+			{Chain: "ethereum", ContractAddress: "0xusdc", ValueUSD: 300},
+		},
+	}
+
+	ev.Evaluate(context.Background(), time.Now())
+
+	require.Len(t, notifier.events, 1)
+	require.Equal(t, "0xwbtc", notifier.events[0].Asset.ContractAddress)
+	require.InDelta(t, 70, notifier.events[0].SharePct, 0.001)
+}
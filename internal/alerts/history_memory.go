@@ -0,0 +1,42 @@
+package alerts
+
+import (
+	"context"
+	"sync"
+)
+
+// historyPerWallet bounds the events kept in memory for each wallet
+const historyPerWallet = 500
+
+type memoryHistoryRepository struct {
+	mu     sync.RWMutex
+	events map[string][]Event // wallet -> events, oldest first
+}
+
+func NewMemoryHistoryRepository() HistoryRepository {
+	return &memoryHistoryRepository{events: make(map[string][]Event)}
+}
+
+func (r *memoryHistoryRepository) Add(ctx context.Context, e Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := append(r.events[e.Wallet], e)
+	if len(events) > historyPerWallet {
+		events = events[len(events)-historyPerWallet:]
+	}
+	r.events[e.Wallet] = events
+	return nil
+}
+
+func (r *memoryHistoryRepository) ListByWallet(ctx context.Context, wallet string, limit int) ([]Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	events := r.events[wallet]
+	out := make([]Event, 0, min(limit, len(events)))
+	for i := len(events) - 1; i >= 0 && len(out) < limit; i-- {
+		out = append(out, events[i])
+	}
+	return out, nil
+}
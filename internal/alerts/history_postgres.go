@@ -0,0 +1,64 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresHistoryRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresHistoryRepository(pool *pgxpool.Pool) HistoryRepository {
+	return &postgresHistoryRepository{pool: pool}
+}
+
+func (r *postgresHistoryRepository) Add(ctx context.Context, e Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO alert_events (id, rule_id, wallet, kind, payload, triggered_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		e.ID,
+		e.RuleID,
+		e.Wallet,
+		e.Kind,
+		payload,
+		e.TriggeredAt,
+	)
+	return err
+}
+
+func (r *postgresHistoryRepository) ListByWallet(ctx context.Context, wallet string, limit int) ([]Event, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT payload FROM alert_events
+		WHERE wallet = $1
+		ORDER BY triggered_at DESC
+		LIMIT $2`,
+		wallet,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]Event, 0)
+	for rows.Next() {
+		var payload []byte
+		if err := rows.Scan(&payload); err != nil {
+			return nil, err
+		}
+		var e Event
+		if err := json.Unmarshal(payload, &e); err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,75 @@
+package alerts
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+type memoryRuleRepository struct {
+	mu   sync.RWMutex
+	data map[string]Rule
+}
+
+func NewMemoryRuleRepository() RuleRepository {
+	return &memoryRuleRepository{data: make(map[string]Rule)}
+}
+
+func (r *memoryRuleRepository) List(ctx context.Context) ([]Rule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]Rule, 0, len(r.data))
+	for _, rule := range r.data {
+		out = append(out, rule)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].CreatedAt.Before(out[j].CreatedAt)
+	})
+	return out, nil
+}
+
+func (r *memoryRuleRepository) Get(ctx context.Context, id string) (*Rule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rule, ok := r.data[id]
+	if !ok {
+		return nil, ErrRuleNotFound
+	}
+	return &rule, nil
+}
+
+func (r *memoryRuleRepository) Save(ctx context.Context, rule Rule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.data[rule.ID] = rule
+	return nil
+}
+
+func (r *memoryRuleRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.data[id]; !ok {
+		return ErrRuleNotFound
+	}
+	delete(r.data, id)
+	return nil
+}
+
+func (r *memoryRuleRepository) UpdateState(ctx context.Context, id string, firing bool, lastTriggeredAt *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rule, ok := r.data[id]
+	if !ok {
+		return ErrRuleNotFound
+	}
+	rule.Firing = firing
+	rule.LastTriggeredAt = lastTriggeredAt
+	r.data[id] = rule
+	return nil
+}
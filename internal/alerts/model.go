@@ -0,0 +1,160 @@
+package alerts
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+var (
+	ErrRuleNotFound = errors.New("alert rule not found")
+	ErrInvalidRule  = errors.New("invalid alert rule")
+)
+
+type RuleKind string
+
+const (
+	KindPriceAbove RuleKind = "price_above"
+	KindPriceBelow RuleKind = "price_below"
+	// KindPercentChange fires when the price moved by Threshold percent within
+	// the window: a positive threshold watches for a rise, a negative one for a drop
+	KindPercentChange RuleKind = "pct_change"
+
+	// KindDrawdown fires when a wallet's total value is Threshold percent or
+	// more below its high within the window
+	KindDrawdown RuleKind = "drawdown"
+	// KindConcentration fires when a single holding is more than Threshold
+	// percent of a wallet's total value
+	KindConcentration RuleKind = "concentration"
+)
+
+const (
+	DefaultCooldown = 60 * time.Minute
+	// MaxWindow bounds how much price history the evaluator keeps per asset
+	MaxWindow = 24 * time.Hour
+	// MaxWalletWindow bounds how much value history is kept per wallet
+	MaxWalletWindow = 7 * 24 * time.Hour
+)
+
+// Rule is a user defined condition that sends a webhook when it starts to hold
+type Rule struct {
+	ID              string            `json:"id"`
+	Kind            RuleKind          `json:"kind"`
+	Asset           *pricing.AssetRef `json:"asset,omitempty"`          // price rules
+	Wallet          string            `json:"wallet,omitempty"`         // wallet rules
+	Threshold       float64           `json:"threshold"`                // USD for price_above/below, percent otherwise
+	WindowMinutes   int               `json:"window_minutes,omitempty"` // pct_change and drawdown
+	WebhookURL      string            `json:"webhook_url"`
+	CooldownMinutes *int              `json:"cooldown_minutes"` // defaults to 60, 0 never waits
+	Enabled         bool              `json:"enabled"`
+	CreatedAt       time.Time         `json:"created_at"`
+
+	// evaluation state, a rule fires when its condition starts to hold and
+	// not again until it has cleared and the cooldown has passed
+	Firing          bool       `json:"firing"`
+	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty"`
+}
+
+// WalletRule reports whether the rule watches a wallet rather than an asset
+func (r Rule) WalletRule() bool {
+	return r.Kind == KindDrawdown || r.Kind == KindConcentration
+}
+
+func (r Rule) Window() time.Duration {
+	return time.Duration(r.WindowMinutes) * time.Minute
+}
+
+func (r Rule) Cooldown() time.Duration {
+	if r.CooldownMinutes == nil {
+		return DefaultCooldown
+	}
+	return time.Duration(*r.CooldownMinutes) * time.Minute
+}
+
+// CoolingDown reports whether the rule fired too recently to fire again
+func (r Rule) CoolingDown(now time.Time) bool {
+	return r.LastTriggeredAt != nil && now.Sub(*r.LastTriggeredAt) < r.Cooldown()
+}
+
+// normalize fills defaults and checks the rule is complete
+func (r *Rule) normalize() error {
+	if r.CooldownMinutes == nil {
+		minutes := int(DefaultCooldown / time.Minute)
+		r.CooldownMinutes = &minutes
+	}
+	if *r.CooldownMinutes < 0 {
+		return ErrInvalidRule
+	}
+
+	// the notifier also refuses private addresses a hostname resolves to;
+	// this only rejects the ones visible in the URL up front
+	u, err := url.Parse(r.WebhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return ErrInvalidRule
+	}
+	if host := strings.ToLower(u.Hostname()); host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrInvalidRule
+	}
+	if ip := net.ParseIP(u.Hostname()); ip != nil && !publicIP(ip) {
+		return ErrInvalidRule
+	}
+
+	switch r.Kind {
+	case KindPriceAbove, KindPriceBelow:
+		if r.Threshold <= 0 {
+			return ErrInvalidRule
+		}
+	case KindPercentChange:
+		if r.Threshold == 0 || r.WindowMinutes <= 0 || r.Window() > MaxWindow {
+			return ErrInvalidRule
+		}
+	case KindDrawdown:
+		if r.Threshold <= 0 || r.Threshold > 100 || r.WindowMinutes <= 0 || r.Window() > MaxWalletWindow {
+			return ErrInvalidRule
+		}
+	case KindConcentration:
+		if r.Threshold <= 0 || r.Threshold >= 100 {
+			return ErrInvalidRule
+		}
+	default:
+		return ErrInvalidRule
+	}
+
+	if r.WalletRule() {
+		if r.Wallet == "" || r.Asset != nil {
+			return ErrInvalidRule
+		}
+		return nil
+	}
+
+	if r.Asset == nil || r.Asset.Chain == "" || r.Wallet != "" {
+		return ErrInvalidRule
+	}
+	asset := *r.Asset
+	asset.ContractAddress = strings.ToLower(asset.ContractAddress)
+	r.Asset = &asset
+
+	return nil
+}
+
+// Event is the webhook payload sent when a rule fires, and the entry kept in
+// alert history
+type Event struct {
+	ID           string            `json:"id"`
+	RuleID       string            `json:"rule_id"`
+	Kind         RuleKind          `json:"kind"`
+	Asset        *pricing.AssetRef `json:"asset,omitempty"` // for concentration, the largest holding
+	Wallet       string            `json:"wallet,omitempty"`
+	Threshold    float64           `json:"threshold"`
+	PriceUSD     float64           `json:"price_usd,omitempty"`
+	ChangePct    float64           `json:"change_pct,omitempty"`
+	ValueUSD     float64           `json:"value_usd,omitempty"`      // wallet total value
+	PeakValueUSD float64           `json:"peak_value_usd,omitempty"` // drawdown only
+	SharePct     float64           `json:"share_pct,omitempty"`      // concentration only
+	Message      string            `json:"message"`
+	TriggeredAt  time.Time         `json:"triggered_at"`
+}
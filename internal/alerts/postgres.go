@@ -0,0 +1,144 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+type postgresRuleRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresRuleRepository(pool *pgxpool.Pool) RuleRepository {
+	return &postgresRuleRepository{pool: pool}
+}
+
+const ruleColumns = `id, kind, chain, contract_address, wallet, threshold, window_minutes,
+	webhook_url, cooldown_minutes, enabled, created_at, firing, last_triggered_at`
+
+func (r *postgresRuleRepository) List(ctx context.Context) ([]Rule, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+ruleColumns+` FROM alert_rules ORDER BY created_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]Rule, 0)
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *rule)
+	}
+	return out, rows.Err()
+}
+
+func (r *postgresRuleRepository) Get(ctx context.Context, id string) (*Rule, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+ruleColumns+` FROM alert_rules WHERE id = $1`, id)
+
+	rule, err := scanRule(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrRuleNotFound
+	}
+	return rule, err
+}
+
+func (r *postgresRuleRepository) Save(ctx context.Context, rule Rule) error {
+	var chain, contract string
+	if rule.Asset != nil {
+		chain, contract = rule.Asset.Chain, rule.Asset.ContractAddress
+	}
+
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO alert_rules (`+ruleColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (id) DO UPDATE SET
+			kind              = EXCLUDED.kind,
+			chain             = EXCLUDED.chain,
+			contract_address  = EXCLUDED.contract_address,
+			wallet            = EXCLUDED.wallet,
+			threshold         = EXCLUDED.threshold,
+			window_minutes    = EXCLUDED.window_minutes,
+			webhook_url       = EXCLUDED.webhook_url,
+			cooldown_minutes  = EXCLUDED.cooldown_minutes,
+			enabled           = EXCLUDED.enabled,
+			firing            = EXCLUDED.firing,
+			last_triggered_at = EXCLUDED.last_triggered_at`,
+		rule.ID,
+		rule.Kind,
+		chain,
+		contract,
+		rule.Wallet,
+		rule.Threshold,
+		rule.WindowMinutes,
+		rule.WebhookURL,
+		rule.CooldownMinutes,
+		rule.Enabled,
+		rule.CreatedAt,
+		rule.Firing,
+		rule.LastTriggeredAt,
+	)
+	return err
+}
+
+func (r *postgresRuleRepository) Delete(ctx context.Context, id string) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM alert_rules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRuleNotFound
+	}
+	return nil
+}
+
+func (r *postgresRuleRepository) UpdateState(ctx context.Context, id string, firing bool, lastTriggeredAt *time.Time) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE alert_rules SET firing = $2, last_triggered_at = $3 WHERE id = $1`,
+		id,
+		firing,
+		lastTriggeredAt,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrRuleNotFound
+	}
+	return nil
+}
+
+func scanRule(row pgx.Row) (*Rule, error) {
+	var (
+		rule            Rule
+		chain, contract string
+	)
+	if err := row.Scan(
+		&rule.ID,
+		&rule.Kind,
+		&chain,
+		&contract,
+		&rule.Wallet,
+		&rule.Threshold,
+		&rule.WindowMinutes,
+		&rule.WebhookURL,
+		&rule.CooldownMinutes,
+		&rule.Enabled,
+		&rule.CreatedAt,
+		&rule.Firing,
+		&rule.LastTriggeredAt,
+	); err != nil {
+		return nil, err
+	}
+	if chain != "" {
+		rule.Asset = &pricing.AssetRef{Chain: chain, ContractAddress: contract}
+	}
+	return &rule, nil
+}
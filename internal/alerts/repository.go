@@ -0,0 +1,25 @@
+package alerts
+
+import (
+	"context"
+	"time"
+)
+
+type RuleRepository interface {
+	List(ctx context.Context) ([]Rule, error)
+	Get(ctx context.Context, id string) (*Rule, error)
+	Save(ctx context.Context, r Rule) error
+	Delete(ctx context.Context, id string) error
+
+	// UpdateState stores only the evaluation state, so the evaluator never
+	// overwrites an edit made to the rule while it was being evaluated
+	UpdateState(ctx context.Context, id string, firing bool, lastTriggeredAt *time.Time) error
+}
+
+// HistoryRepository keeps every fired event
+type HistoryRepository interface {
+	Add(ctx context.Context, e Event) error
+
+	// ListByWallet returns the most recent events of a wallet first
+	ListByWallet(ctx context.Context, wallet string, limit int) ([]Event, error)
+}
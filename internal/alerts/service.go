@@ -0,0 +1,105 @@
+package alerts
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type ServiceAPI interface {
+	ListRules(ctx context.Context) ([]Rule, error)
+	GetRule(ctx context.Context, id string) (*Rule, error)
+	CreateRule(ctx context.Context, r Rule) (*Rule, error)
+	UpdateRule(ctx context.Context, id string, r Rule) (*Rule, error)
+	DeleteRule(ctx context.Context, id string) error
+
+	// History returns the most recent alerts fired for a wallet
+	History(ctx context.Context, wallet string, limit int) ([]Event, error)
+}
+
+type Service struct {
+	rules   RuleRepository
+	history HistoryRepository
+	logger  *zap.Logger
+}
+
+func NewService(rules RuleRepository, history HistoryRepository, logger *zap.Logger) *Service {
+	return &Service{
+		rules:   rules,
+		history: history,
+		logger:  logger,
+	}
+}
+
+func (s *Service) ListRules(ctx context.Context) ([]Rule, error) {
+	return s.rules.List(ctx)
+}
+
+func (s *Service) GetRule(ctx context.Context, id string) (*Rule, error) {
+	return s.rules.Get(ctx, id)
+}
+
+func (s *Service) CreateRule(ctx context.Context, r Rule) (*Rule, error) {
+	if err := r.normalize(); err != nil {
+		return nil, err
+	}
+
+	r.ID = newID()
+	r.CreatedAt = time.Now().UTC()
+	r.Firing = false
+	r.LastTriggeredAt = nil
+
+	s.logger.Info("create-alert-rule",
+		zap.String("id", r.ID),
+		zap.String("kind", string(r.Kind)),
+	)
+
+	if err := s.rules.Save(ctx, r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// UpdateRule replaces the rule definition and resets its evaluation state
+func (s *Service) UpdateRule(ctx context.Context, id string, r Rule) (*Rule, error) {
+	existing, err := s.rules.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.normalize(); err != nil {
+		return nil, err
+	}
+
+	r.ID = existing.ID
+	r.CreatedAt = existing.CreatedAt
+	r.Firing = false
+	r.LastTriggeredAt = existing.LastTriggeredAt
+
+	s.logger.Info("update-alert-rule",
+		zap.String("id", r.ID),
+		zap.String("kind", string(r.Kind)),
+	)
+
+	if err := s.rules.Save(ctx, r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+func (s *Service) DeleteRule(ctx context.Context, id string) error {
+	s.logger.Info("delete-alert-rule", zap.String("id", id))
+	return s.rules.Delete(ctx, id)
+}
+
+func (s *Service) History(ctx context.Context, wallet string, limit int) ([]Event, error) {
+	return s.history.ListByWallet(ctx, wallet, limit)
+}
+
+func newID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,72 @@
+package alerts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+func TestService_CreateRule_Validates(t *testing.T) {
+	svc := NewService(NewMemoryRuleRepository(), NewMemoryHistoryRepository(), zap.NewNop())
+
+	cases := []Rule{
+		{Kind: KindPriceAbove, Threshold: 10, WebhookURL: "https://hooks.example.com"},
+		{Kind: KindPriceAbove, Asset: &eth, Threshold: 0, WebhookURL: "https://hooks.example.com"},
+		{Kind: KindPriceAbove, Asset: &eth, Threshold: 10, WebhookURL: "ftp://hooks.example.com"},
+		{Kind: KindPercentChange, Asset: &eth, Threshold: 5, WebhookURL: "https://hooks.example.com"},
+		{Kind: KindPercentChange, Asset: &eth, Threshold: 5, WindowMinutes: 60 * 48, WebhookURL: "https://hooks.example.com"},
+		{Kind: "nope", Asset: &eth, Threshold: 5, WebhookURL: "https://hooks.example.com"},
+		{Kind: KindDrawdown, Threshold: 10, WindowMinutes: 60, WebhookURL: "https://hooks.example.com"},
+		{Kind: KindDrawdown, Wallet: "0xabc", Asset: &eth, Threshold: 10, WindowMinutes: 60, WebhookURL: "https://hooks.example.com"},
+		{Kind: KindConcentration, Wallet: "0xabc", Threshold: 100, WebhookURL: "https://hooks.example.com"},
+		{Kind: KindPriceAbove, Wallet: "0xabc", Asset: &eth, Threshold: 10, WebhookURL: "https://hooks.example.com"},
+		{Kind: KindPriceAbove, Asset: &eth, Threshold: 10, WebhookURL: "https://hooks.example.com", CooldownMinutes: minutes(-1)},
+		{Kind: KindPriceAbove, Asset: &eth, Threshold: 10, WebhookURL: "http://localhost:8080/hook"},
+		{Kind: KindPriceAbove, Asset: &eth, Threshold: 10, WebhookURL: "http://127.0.0.1/hook"},
+		{Kind: KindPriceAbove, Asset: &eth, Threshold: 10, WebhookURL: "http://10.0.0.5/hook"},
+		{Kind: KindPriceAbove, Asset: &eth, Threshold: 10, WebhookURL: "http://169.254.169.254/latest/meta-data"},
+		{Kind: KindPriceAbove, Asset: &eth, Threshold: 10, WebhookURL: "http://[::1]/hook"},
+	}
+
+	for _, c := range cases {
+		_, err := svc.CreateRule(context.Background(), c)
+		require.ErrorIs(t, err, ErrInvalidRule, "kind %s", c.Kind)
+	}
+}
+
+func TestService_CreateAndUpdateRule(t *testing.T) {
+	svc := NewService(NewMemoryRuleRepository(), NewMemoryHistoryRepository(), zap.NewNop())
+
+	created, err := svc.CreateRule(context.Background(), Rule{
+		Kind:       KindPriceBelow,
+		Asset:      &pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xA0B8"},
+		Threshold:  0.99,
+		WebhookURL: "https://hooks.example.com/a",
+		Enabled:    true,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, created.ID)
+	require.Equal(t, "0xa0b8", created.Asset.ContractAddress)
+	require.Equal(t, 60, *created.CooldownMinutes)
+
+	updated, err := svc.UpdateRule(context.Background(), created.ID, Rule{
+		Kind:       KindPriceBelow,
+		Asset:      created.Asset,
+		Threshold:  0.98,
+		WebhookURL: "https://hooks.example.com/a",
+	})
+	require.NoError(t, err)
+	require.Equal(t, created.ID, updated.ID)
+	require.Equal(t, created.CreatedAt, updated.CreatedAt)
+	require.False(t, updated.Enabled)
+
+	_, err = svc.UpdateRule(context.Background(), "missing", *created)
+	require.ErrorIs(t, err, ErrRuleNotFound)
+
+	require.NoError(t, svc.DeleteRule(context.Background(), created.ID))
+	require.ErrorIs(t, svc.DeleteRule(context.Background(), created.ID), ErrRuleNotFound)
+}
@@ -0,0 +1,127 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/utils"
+)
+
+const (
+	SignatureHeader = "X-Alert-Signature"
+	TimestampHeader = "X-Alert-Timestamp"
+)
+
+// ErrWebhookAddress is returned when a webhook resolves to an address on
+// the server's own network
+var ErrWebhookAddress = errors.New("webhook address is not public")
+
+// Notifier delivers a fired alert to its destination
+type Notifier interface {
+	Notify(ctx context.Context, url string, e Event) error
+}
+
+// WebhookNotifier POSTs events as JSON. Each request is signed with
+// HMAC-SHA256 over "<timestamp>.<body>" so receivers can verify it and
+// reject replays.
+type WebhookNotifier struct {
+	httpClient *http.Client
+	secret     []byte
+	retry      utils.RetryConfig
+}
+
+func NewWebhookNotifier(secret string) *WebhookNotifier {
+	// rules are user supplied, so the address is checked when the connection
+	// is made, after DNS, and again on every redirect
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+				return ErrWebhookAddress
+			}
+			return nil
+		},
+	}
+
+	return &WebhookNotifier{
+		httpClient: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+		},
+		secret: []byte(secret),
+		retry: utils.RetryConfig{
+			MaxRetries: 3,
+			BaseDelay:  500 * time.Millisecond,
+			MaxDelay:   4 * time.Second,
+		},
+	}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, url string, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	// retry with exponential backoff
+	return utils.Retry(ctx, n.retry, func() error {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(TimestampHeader, ts)
+		req.Header.Set(SignatureHeader, "sha256="+Sign(n.secret, ts, body))
+
+		resp, err := n.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		_, _ = io.Copy(io.Discard, resp.Body)
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("webhook returned %d", resp.StatusCode)
+		}
+		return nil
+	})
+}
+
+// publicIP reports whether ip is routable on the internet, as opposed to
+// loopback, private, link-local (cloud metadata) or unspecified
+func publicIP(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<body>"
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
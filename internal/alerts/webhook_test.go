@@ -0,0 +1,53 @@
+package alerts
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookNotifier_SignsAndRetries(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		want := "sha256=" + Sign([]byte("s3cret"), r.Header.Get(TimestampHeader), body)
+		require.Equal(t, want, r.Header.Get(SignatureHeader))
+
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	n := NewWebhookNotifier("s3cret")
+	n.retry.BaseDelay = time.Millisecond
+	// the test server listens on loopback, which the notifier refuses
+	n.httpClient = ts.Client()
+
+	err := n.Notify(context.Background(), ts.URL, Event{RuleID: "r1", Message: "fired"})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func TestWebhookNotifier_RefusesPrivateAddresses(t *testing.T) {
+	var calls atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer ts.Close()
+
+	n := NewWebhookNotifier("s3cret")
+	n.retry.MaxRetries = 0
+
+	err := n.Notify(context.Background(), ts.URL, Event{RuleID: "r1"})
+	require.ErrorIs(t, err, ErrWebhookAddress)
+	require.Zero(t, calls.Load())
+}
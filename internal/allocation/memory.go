@@ -0,0 +1,47 @@
+package allocation
+
+import (
+	"context"
+	"sync"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+type memoryAssignmentRepository struct {
+	mu   sync.RWMutex
+	data map[pricing.AssetRef]Assignment
+}
+
+func NewMemoryAssignmentRepository() AssignmentRepository {
+	return &memoryAssignmentRepository{data: make(map[pricing.AssetRef]Assignment)}
+}
+
+func (r *memoryAssignmentRepository) List(ctx context.Context) ([]Assignment, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]Assignment, 0, len(r.data))
+	for _, a := range r.data {
+		out = append(out, a)
+	}
+	return out, nil
+}
+
+func (r *memoryAssignmentRepository) Save(ctx context.Context, a Assignment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.data[a.Asset] = a
+	return nil
+}
+
+func (r *memoryAssignmentRepository) Delete(ctx context.Context, asset pricing.AssetRef) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.data[asset]; !ok {
+		return ErrAssignmentMissing
+	}
+	delete(r.data, asset)
+	return nil
+}
@@ -0,0 +1,73 @@
+package allocation
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+type postgresAssignmentRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresAssignmentRepository(pool *pgxpool.Pool) AssignmentRepository {
+	return &postgresAssignmentRepository{pool: pool}
+}
+
+func (r *postgresAssignmentRepository) List(ctx context.Context) ([]Assignment, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT chain, contract_address, category, updated_at
+		FROM token_categories
+		ORDER BY chain, contract_address`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]Assignment, 0)
+	for rows.Next() {
+		var a Assignment
+		if err := rows.Scan(
+			&a.Asset.Chain,
+			&a.Asset.ContractAddress,
+			&a.Category,
+			&a.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+func (r *postgresAssignmentRepository) Save(ctx context.Context, a Assignment) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO token_categories (chain, contract_address, category, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (chain, contract_address) DO UPDATE SET
+			category   = EXCLUDED.category,
+			updated_at = EXCLUDED.updated_at`,
+		a.Asset.Chain,
+		a.Asset.ContractAddress,
+		a.Category,
+		a.UpdatedAt,
+	)
+	return err
+}
+
+func (r *postgresAssignmentRepository) Delete(ctx context.Context, asset pricing.AssetRef) error {
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM token_categories WHERE chain = $1 AND contract_address = $2`,
+		asset.Chain,
+		asset.ContractAddress,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrAssignmentMissing
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package allocation
+
+import (
+	"context"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+// AssignmentRepository stores category assignments made through the API
+type AssignmentRepository interface {
+	List(ctx context.Context) ([]Assignment, error)
+	Save(ctx context.Context, a Assignment) error
+	Delete(ctx context.Context, asset pricing.AssetRef) error
+}
@@ -0,0 +1,214 @@
+package allocation
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+// Weight is one group's share of the portfolio value
+type Weight struct {
+	Key       string  `json:"key"`
+	Name      string  `json:"name"`
+	ValueUSD  float64 `json:"value_usd"`
+	WeightPct float64 `json:"weight_pct"`
+}
+
+// Allocation is a valued portfolio grouped three ways. The values of each
+// grouping add up to TotalValueUSD and the weights to 100.
+type Allocation struct {
+	Wallet        string   `json:"wallet"`
+	TotalValueUSD float64  `json:"total_value_usd"`
+	ByAsset       []Weight `json:"by_asset"`
+	ByChain       []Weight `json:"by_chain"`
+	ByCategory    []Weight `json:"by_category"`
+}
+
+type ServiceAPI interface {
+	Allocation(ctx context.Context, wallet string) (*Allocation, error)
+	Taxonomy(ctx context.Context) (Taxonomy, error)
+	Assign(ctx context.Context, asset pricing.AssetRef, category string) (Assignment, error)
+	Unassign(ctx context.Context, asset pricing.AssetRef) error
+}
+
+type Service struct {
+	taxonomy    Taxonomy
+	assignments AssignmentRepository
+	portfolio   portfolio.Service
+	logger      *zap.Logger
+}
+
+func NewService(
+	taxonomy Taxonomy,
+	assignments AssignmentRepository,
+	portfolio portfolio.Service,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		taxonomy:    taxonomy,
+		assignments: assignments,
+		portfolio:   portfolio,
+		logger:      logger,
+	}
+}
+
+// Allocation values the wallet and groups its holdings by asset, chain and
+// category
+func (s *Service) Allocation(ctx context.Context, wallet string) (*Allocation, error) {
+	view, err := s.portfolio.Get(ctx, wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := s.categoryOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return Build(view, s.taxonomy, categories), nil
+}
+
+// Taxonomy returns the categories and every effective assignment, defaults
+// from the taxonomy file included
+func (s *Service) Taxonomy(ctx context.Context) (Taxonomy, error) {
+	assigned, err := s.categoryOf(ctx)
+	if err != nil {
+		return Taxonomy{}, err
+	}
+
+	out := Taxonomy{
+		Categories:  s.taxonomy.Categories,
+		Assignments: make([]Assignment, 0, len(assigned)),
+	}
+	for _, a := range assigned {
+		out.Assignments = append(out.Assignments, a)
+	}
+	sort.Slice(out.Assignments, func(i, j int) bool {
+		return out.Assignments[i].Asset.String() < out.Assignments[j].Asset.String()
+	})
+	return out, nil
+}
+
+// Assign places an asset in a category, replacing any earlier assignment
+func (s *Service) Assign(ctx context.Context, asset pricing.AssetRef, category string) (Assignment, error) {
+	asset.ContractAddress = strings.ToLower(asset.ContractAddress)
+	if asset.Chain == "" {
+		return Assignment{}, ErrInvalidAssignment
+	}
+	if _, ok := s.taxonomy.category(category); !ok || category == Uncategorized {
+		return Assignment{}, ErrUnknownCategory
+	}
+
+	a := Assignment{Asset: asset, Category: category, UpdatedAt: time.Now().UTC()}
+
+	s.logger.Info("assign-category",
+		zap.String("chain", asset.Chain),
+		zap.String("contract", asset.ContractAddress),
+		zap.String("category", category),
+	)
+
+	if err := s.assignments.Save(ctx, a); err != nil {
+		return Assignment{}, err
+	}
+	return a, nil
+}
+
+// Unassign removes an assignment made through the API; the asset falls back
+// to its taxonomy file default, if any
+func (s *Service) Unassign(ctx context.Context, asset pricing.AssetRef) error {
+	asset.ContractAddress = strings.ToLower(asset.ContractAddress)
+	return s.assignments.Delete(ctx, asset)
+}
+
+// categoryOf merges file defaults with stored assignments, which win
+func (s *Service) categoryOf(ctx context.Context) (map[pricing.AssetRef]Assignment, error) {
+	stored, err := s.assignments.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[pricing.AssetRef]Assignment, len(s.taxonomy.Assignments)+len(stored))
+	for _, a := range s.taxonomy.Assignments {
+		out[a.Asset] = a
+	}
+	for _, a := range stored {
+		// categories removed from the taxonomy file leave the asset to its default
+		if _, ok := s.taxonomy.category(a.Category); !ok {
+			continue
+		}
+		out[a.Asset] = a
+	}
+	return out, nil
+}
+
+// Build groups a valued portfolio by asset, chain and category
+func Build(view *portfolio.PortfolioView, taxonomy Taxonomy, assigned map[pricing.AssetRef]Assignment) *Allocation {
+	byAsset := newGroups()
+	byChain := newGroups()
+	byCategory := newGroups()
+
+	for _, h := range view.Holdings {
+		ref := pricing.AssetRef{Chain: h.Chain, ContractAddress: h.ContractAddress}
+
+		name := ref.String()
+		if h.Token != nil && h.Token.Symbol != "" {
+			name = h.Token.Symbol
+		}
+		byAsset.add(ref.String(), name, h.ValueUSD)
+		byChain.add(h.Chain, h.Chain, h.ValueUSD)
+
+		category, _ := taxonomy.category(Uncategorized)
+		if a, ok := assigned[ref]; ok {
+			category, _ = taxonomy.category(a.Category)
+		}
+		byCategory.add(category.ID, category.Name, h.ValueUSD)
+	}
+
+	return &Allocation{
+		Wallet:        view.Wallet,
+		TotalValueUSD: view.TotalValueUSD,
+		ByAsset:       byAsset.weights(view.TotalValueUSD),
+		ByChain:       byChain.weights(view.TotalValueUSD),
+		ByCategory:    byCategory.weights(view.TotalValueUSD),
+	}
+}
+
+type groups map[string]*Weight
+
+func newGroups() groups {
+	return make(groups)
+}
+
+func (g groups) add(key, name string, value float64) {
+	w, ok := g[key]
+	if !ok {
+		w = &Weight{Key: key, Name: name}
+		g[key] = w
+	}
+	w.ValueUSD += value
+}
+
+// weights returns the groups largest first, ties by key
+func (g groups) weights(total float64) []Weight {
+	out := make([]Weight, 0, len(g))
+	for _, gw := range g {
+		w := *gw
+		if total > 0 {
+			w.WeightPct = w.ValueUSD / total * 100
+		}
+		out = append(out, w)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].ValueUSD != out[j].ValueUSD {
+			return out[i].ValueUSD > out[j].ValueUSD
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
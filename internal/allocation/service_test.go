@@ -0,0 +1,119 @@
+package allocation
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+)
+
+type fakePortfolio struct {
+	portfolio.Service
+	view *portfolio.PortfolioView
+}
+
+func (f *fakePortfolio) Get(ctx context.Context, wallet string) (*portfolio.PortfolioView, error) {
+	return f.view, nil
+}
+
+var (
+	eth  = pricing.AssetRef{Chain: "ethereum"}
+	usdc = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"}
+	arb  = pricing.AssetRef{Chain: "arbitrum-one", ContractAddress: "0x912ce59144191c1204e64559fe8253a0e49e6548"}
+	uni  = pricing.AssetRef{Chain: "arbitrum-one", ContractAddress: "0xuni"}
+)
+
+func testView() *portfolio.PortfolioView {
+	return &portfolio.PortfolioView{
+		Wallet:        "0xabc",
+		TotalValueUSD: 10_000,
+		Holdings: []portfolio.HoldingView{
+			{Chain: eth.Chain, ContractAddress: eth.ContractAddress, ValueUSD: 4000, Token: &tokens.Metadata{Symbol: "ETH"}},
+			{Chain: usdc.Chain, ContractAddress: usdc.ContractAddress, ValueUSD: 3000},
+			{Chain: arb.Chain, ContractAddress: arb.ContractAddress, ValueUSD: 2000},
+			{Chain: uni.Chain, ContractAddress: uni.ContractAddress, ValueUSD: 1000},
+		},
+	}
+}
+
+func exampleTaxonomy(t *testing.T) Taxonomy {
+	_, file, _, _ := runtime.Caller(0)
+	taxonomy, err := LoadTaxonomy(filepath.Join(filepath.Dir(file), "../../config/allocation_taxonomy.example.json"))
+	require.NoError(t, err)
+	return taxonomy
+}
+
+func TestService_AllocationReconciles(t *testing.T) {
+	svc := NewService(exampleTaxonomy(t), NewMemoryAssignmentRepository(), &fakePortfolio{view: testView()}, zap.NewNop())
+
+	alloc, err := svc.Allocation(context.Background(), "0xabc")
+	require.NoError(t, err)
+
+	for _, grouping := range [][]Weight{alloc.ByAsset, alloc.ByChain, alloc.ByCategory} {
+		var value, weight float64
+		for _, w := range grouping {
+			value += w.ValueUSD
+			weight += w.WeightPct
+		}
+		require.InDelta(t, alloc.TotalValueUSD, value, 1e-9)
+		require.InDelta(t, 100.0, weight, 1e-9)
+	}
+
+	require.Len(t, alloc.ByAsset, 4)
+	require.Equal(t, "ETH", alloc.ByAsset[0].Name)
+
+	require.Equal(t, []Weight{
+		{Key: "ethereum", Name: "ethereum", ValueUSD: 7000, WeightPct: 70},
+		{Key: "arbitrum-one", Name: "arbitrum-one", ValueUSD: 3000, WeightPct: 30},
+	}, alloc.ByChain)
+
+	require.Equal(t, []Weight{
+		{Key: "l1", Name: "Layer 1", ValueUSD: 4000, WeightPct: 40},
+		{Key: "stablecoins", Name: "Stablecoins", ValueUSD: 3000, WeightPct: 30},
+		{Key: "l2", Name: "Layer 2", ValueUSD: 2000, WeightPct: 20},
+		{Key: Uncategorized, Name: "Uncategorized", ValueUSD: 1000, WeightPct: 10},
+	}, alloc.ByCategory)
+}
+
+func TestService_AssignOverridesDefault(t *testing.T) {
+	svc := NewService(exampleTaxonomy(t), NewMemoryAssignmentRepository(), &fakePortfolio{view: testView()}, zap.NewNop())
+	ctx := context.Background()
+
+	_, err := svc.Assign(ctx, pricing.AssetRef{Chain: "arbitrum-one", ContractAddress: "0xUNI"}, "defi")
+	require.NoError(t, err)
+	_, err = svc.Assign(ctx, arb, "defi")
+	require.NoError(t, err)
+
+	_, err = svc.Assign(ctx, arb, "memecoins")
+	require.ErrorIs(t, err, ErrUnknownCategory)
+
+	alloc, err := svc.Allocation(ctx, "0xabc")
+	require.NoError(t, err)
+	require.Equal(t, Weight{Key: "defi", Name: "DeFi", ValueUSD: 3000, WeightPct: 30}, alloc.ByCategory[1])
+
+	// removing the assignment restores the taxonomy file default
+	require.NoError(t, svc.Unassign(ctx, arb))
+	require.ErrorIs(t, svc.Unassign(ctx, arb), ErrAssignmentMissing)
+
+	alloc, err = svc.Allocation(ctx, "0xabc")
+	require.NoError(t, err)
+	require.Equal(t, "l2", alloc.ByCategory[2].Key)
+}
+
+func TestLoadTaxonomy_RejectsUnknownCategory(t *testing.T) {
+	taxonomy := Taxonomy{
+		Categories:  []Category{{ID: "defi", Name: "DeFi"}},
+		Assignments: []Assignment{{Asset: eth, Category: "l1"}},
+	}
+	require.ErrorIs(t, taxonomy.validate(), ErrUnknownCategory)
+
+	taxonomy = Taxonomy{Categories: []Category{{ID: "DeFi"}}}
+	require.ErrorIs(t, taxonomy.validate(), ErrInvalidTaxonomy)
+}
@@ -0,0 +1,109 @@
+package allocation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+var (
+	ErrInvalidTaxonomy   = errors.New("invalid category taxonomy")
+	ErrUnknownCategory   = errors.New("unknown category")
+	ErrInvalidAssignment = errors.New("invalid category assignment")
+	ErrAssignmentMissing = errors.New("category assignment not found")
+)
+
+// Uncategorized holds every asset without an assignment
+const Uncategorized = "uncategorized"
+
+var categoryID = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Category is a user-defined group of assets, e.g. stablecoins or DeFi
+type Category struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Assignment places an asset in a category
+type Assignment struct {
+	Asset     pricing.AssetRef `json:"asset"`
+	Category  string           `json:"category"`
+	UpdatedAt time.Time        `json:"updated_at,omitempty"`
+}
+
+// Taxonomy is the set of categories and the default assignments loaded from
+// the taxonomy file. Assignments made through the API take precedence over
+// the defaults.
+type Taxonomy struct {
+	Categories  []Category   `json:"categories"`
+	Assignments []Assignment `json:"assignments"`
+}
+
+// DefaultTaxonomy is used when no taxonomy file is configured
+func DefaultTaxonomy() Taxonomy {
+	return Taxonomy{Categories: []Category{
+		{ID: "stablecoins", Name: "Stablecoins"},
+		{ID: "l1", Name: "Layer 1"},
+		{ID: "l2", Name: "Layer 2"},
+		{ID: "defi", Name: "DeFi"},
+	}}
+}
+
+// LoadTaxonomy reads and validates a taxonomy JSON file
+func LoadTaxonomy(path string) (Taxonomy, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return Taxonomy{}, err
+	}
+
+	var t Taxonomy
+	if err := json.Unmarshal(raw, &t); err != nil {
+		return Taxonomy{}, fmt.Errorf("%w: %v", ErrInvalidTaxonomy, err)
+	}
+	if err := t.validate(); err != nil {
+		return Taxonomy{}, err
+	}
+	return t, nil
+}
+
+func (t *Taxonomy) validate() error {
+	seen := make(map[string]bool, len(t.Categories))
+	for _, c := range t.Categories {
+		if !categoryID.MatchString(c.ID) || c.ID == Uncategorized {
+			return fmt.Errorf("%w: category id %q", ErrInvalidTaxonomy, c.ID)
+		}
+		if seen[c.ID] {
+			return fmt.Errorf("%w: duplicate category %q", ErrInvalidTaxonomy, c.ID)
+		}
+		seen[c.ID] = true
+	}
+
+	for i, a := range t.Assignments {
+		if a.Asset.Chain == "" {
+			return fmt.Errorf("%w: assignment %d has no chain", ErrInvalidTaxonomy, i)
+		}
+		if !seen[a.Category] {
+			return fmt.Errorf("%w: assignment %d: %w %q", ErrInvalidTaxonomy, i, ErrUnknownCategory, a.Category)
+		}
+		t.Assignments[i].Asset.ContractAddress = strings.ToLower(a.Asset.ContractAddress)
+	}
+	return nil
+}
+
+func (t Taxonomy) category(id string) (Category, bool) {
+	if id == Uncategorized {
+		return Category{ID: Uncategorized, Name: "Uncategorized"}, true
+	}
+	for _, c := range t.Categories {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return Category{}, false
+}
@@ -2,16 +2,36 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/alerts"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/allocation"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/balances"
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/cache"
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/config"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/database"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/discovery"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/evm"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/export"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/importer"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/nft"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/performance"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pnl"
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing/coingecko"
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing/mock"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/rebalance"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/reconcile"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/snapshots"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/spam"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/streaming"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tax"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions/etherscan"
 )
@@ -21,15 +41,65 @@ type AppContext struct {
 	Logger *zap.Logger
 
 	Cache              cache.CacheManager
+	DB                 *pgxpool.Pool // nil when DATABASE_URL is not set
 	PricingService     *pricing.Service
 	TransactionService *transactions.Service
 	PortfolioService   portfolio.Service
+	TokenService       *tokens.Service
+	TokenSearch        *tokens.SearchIndex
+	StreamHub          *streaming.Hub
+	AlertService       *alerts.Service
+	AlertEvaluator     *alerts.Evaluator
+	SnapshotService    *snapshots.Service
+	PerformanceService *performance.Service
+	AllocationService  *allocation.Service
+	RebalanceService   *rebalance.Service
+	PnLService         *pnl.Service
+	TaxService         *tax.Service
+	ExportService      *export.Service
+	ImportService      *importer.Service
+	BalanceSync        *balances.Service
+	ReconcileService   *reconcile.Service
+	SpamService        *spam.Service
+	NFTService         *nft.Service
+	DiscoveryService   *discovery.Service
 }
 
 func NewAppContext(ctx context.Context, cfg *config.Config, logger *zap.Logger, cache cache.CacheManager) (*AppContext, error) {
 	ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
 	defer cancel()
 
+	var db *pgxpool.Pool
+	if cfg.Database.URL != "" {
+		pool, err := database.NewPostgresPool(ctx, cfg.Database.URL)
+		if err != nil {
+			return nil, err
+		}
+		db = pool
+	} else {
+		logger.Warn("database-url-not-set-using-memory-stores")
+	}
+
+	overrides := pricing.NewMemoryOverrideRepository()
+	if db != nil {
+		overrides = pricing.NewPostgresOverrideRepository(db)
+	}
+
+	rpcClient := evm.NewClient(cfg.RPC.URLs)
+
+	var rules []pricing.Rule
+	if cfg.Pricing.RulesFile != "" {
+		loaded, err := pricing.LoadRules(cfg.Pricing.RulesFile)
+		if err != nil {
+			return nil, err
+		}
+		rules = loaded
+	}
+	ruleSet, err := pricing.NewRuleSet(rules)
+	if err != nil {
+		return nil, err
+	}
+
 	cgClient := coingecko.NewClient(
 		cfg.CoinGecko.APIKey,
 		cfg.CoinGecko.BaseURL,
@@ -46,11 +116,47 @@ func NewAppContext(ctx context.Context, cfg *config.Config, logger *zap.Logger,
 		fallback,
 		pricingTTL,
 		logger,
+		pricing.WithOverrides(overrides),
+		pricing.WithRules(ruleSet, rpcClient),
 	)
 
+	tokenService := tokens.NewService(cache, coingecko.NewMetadataProvider(cgClient), logger)
+	for _, path := range cfg.Tokens.ListFiles {
+		list, err := tokens.LoadTokenList(path)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tokenService.Import(ctx, list); err != nil {
+			return nil, err
+		}
+	}
+
+	searchInterval := time.Duration(cfg.Tokens.SearchRefreshHours) * time.Hour
+	tokenSearch := tokens.NewSearchIndex(cache, coingecko.NewCoinListProvider(cgClient), searchInterval, logger)
+
+	var spamList spam.List
+	if cfg.Spam.ListFile != "" {
+		loaded, err := spam.LoadList(cfg.Spam.ListFile)
+		if err != nil {
+			return nil, err
+		}
+		spamList = loaded
+	}
+	spamOverrides := spam.NewMemoryOverrideRepository()
+	if db != nil {
+		spamOverrides = spam.NewPostgresOverrideRepository(db)
+	}
+	spamService := spam.NewService(spamList, spamOverrides, pricingService, tokenSearch, logger)
+
 	etherscanClient := etherscan.NewClient(cfg.EtherScan.APIKey, cfg.EtherScan.BaseURL)
 	txRepo := etherscan.NewProvider(etherscanClient)
-	txService := transactions.NewService(txRepo, logger)
+	txService := transactions.NewService(
+		txRepo,
+		logger,
+		transactions.WithTokens(tokenService),
+		transactions.WithSpam(spamService),
+		transactions.WithSwaps(rpcClient, cache),
+	)
 
 	// Hard coded snapshot from requirement
 	initial := []*portfolio.Portfolio{
@@ -73,16 +179,164 @@ func NewAppContext(ctx context.Context, cfg *config.Config, logger *zap.Logger,
 
 	repo := portfolio.NewMemoryRepository(initial)
 
-	portfolioService := portfolio.NewService(repo, pricingService, logger)
+	portfolioOpts := []portfolio.Option{
+		portfolio.WithTokens(tokenService),
+		portfolio.WithHoldingHistory(portfolio.NewMemoryHistoryRepository()),
+		portfolio.WithTransactions(txService),
+		portfolio.WithSpam(spamService),
+	}
+	switch cfg.NFT.FloorPriceProvider {
+	case "":
+	case "mock":
+		portfolioOpts = append(portfolioOpts, portfolio.WithFloorPrices(fallback))
+	default:
+		return nil, fmt.Errorf("unknown NFT floor price provider %q", cfg.NFT.FloorPriceProvider)
+	}
+
+	portfolioService := portfolio.NewService(
+		repo,
+		pricingService,
+		logger,
+		portfolioOpts...,
+	)
+
+	streamInterval := time.Duration(cfg.Streaming.RefreshSeconds) * time.Second
+	streamHub := streaming.NewHub(pricingService, portfolioService, streamInterval, logger)
+
+	alertRules := alerts.NewMemoryRuleRepository()
+	alertHistory := alerts.NewMemoryHistoryRepository()
+	if db != nil {
+		alertRules = alerts.NewPostgresRuleRepository(db)
+		alertHistory = alerts.NewPostgresHistoryRepository(db)
+	}
+	if cfg.Alerts.WebhookSecret == "" {
+		logger.Warn("alerts-webhook-secret-not-set")
+	}
+	alertService := alerts.NewService(alertRules, alertHistory, logger)
+	alertInterval := time.Duration(cfg.Alerts.EvaluateSeconds) * time.Second
+	alertEvaluator := alerts.NewEvaluator(
+		alertRules,
+		alertHistory,
+		pricingService,
+		portfolioService,
+		alerts.NewWebhookNotifier(cfg.Alerts.WebhookSecret),
+		alertInterval,
+		logger,
+	)
+
+	snapshotRepo := snapshots.NewMemoryRepository()
+	if db != nil {
+		snapshotRepo = snapshots.NewPostgresRepository(db)
+	}
+	snapshotService := snapshots.NewService(
+		snapshotRepo,
+		portfolioService,
+		time.Duration(cfg.Snapshots.IntervalMinutes)*time.Minute,
+		snapshots.Retention{
+			Raw: time.Duration(cfg.Snapshots.RawRetentionDays) * 24 * time.Hour,
+			Max: time.Duration(cfg.Snapshots.RetentionDays) * 24 * time.Hour,
+		},
+		logger,
+	)
+
+	performanceService := performance.NewService(
+		snapshotService,
+		txService,
+		txService,
+		pricingService,
+		cfg.Performance.RiskFreeRatePct,
+		logger,
+	)
+
+	taxonomy := allocation.DefaultTaxonomy()
+	if cfg.Allocation.TaxonomyFile != "" {
+		loaded, err := allocation.LoadTaxonomy(cfg.Allocation.TaxonomyFile)
+		if err != nil {
+			return nil, err
+		}
+		taxonomy = loaded
+	}
+	assignments := allocation.NewMemoryAssignmentRepository()
+	if db != nil {
+		assignments = allocation.NewPostgresAssignmentRepository(db)
+	}
+	allocationService := allocation.NewService(taxonomy, assignments, portfolioService, logger)
+
+	targets := rebalance.NewMemoryTargetRepository()
+	if db != nil {
+		targets = rebalance.NewPostgresTargetRepository(db)
+	}
+	rebalanceService := rebalance.NewService(targets, portfolioService, pricingService, logger)
+
+	pnlService := pnl.NewService(txService, txService, pricingService, logger)
+
+	taxService := tax.NewService(pnlService, cfg.Tax.Jurisdiction, logger, tax.WithTokens(tokenService))
+
+	exportService := export.NewService(txService, txService, pricingService, logger)
+
+	importService := importer.NewService(portfolioService, tokenSearch, logger)
+
+	watchlist, err := balances.ParseWatchlist(cfg.BalanceSync.Tokens)
+	if err != nil {
+		return nil, err
+	}
+	balanceSync := balances.NewService(
+		rpcClient,
+		portfolioService,
+		watchlist,
+		time.Duration(cfg.BalanceSync.IntervalMinutes)*time.Minute,
+		logger,
+	)
+
+	reconcileService := reconcile.NewService(portfolioService, balanceSync, txService, txService, logger)
+
+	discoveryService := discovery.NewService(
+		txService,
+		balanceSync,
+		portfolioService,
+		pricingService,
+		spamService,
+		cfg.Discovery.DustUSD,
+		logger,
+		discovery.WithTokens(tokenService),
+	)
+
+	nftService := nft.NewService(txService, portfolioService, logger)
 
 	appCtx := &AppContext{
 		Config:             cfg,
 		Logger:             logger,
 		Cache:              cache,
+		DB:                 db,
 		PricingService:     pricingService,
 		TransactionService: txService,
 		PortfolioService:   portfolioService,
+		TokenService:       tokenService,
+		TokenSearch:        tokenSearch,
+		StreamHub:          streamHub,
+		AlertService:       alertService,
+		AlertEvaluator:     alertEvaluator,
+		SnapshotService:    snapshotService,
+		PerformanceService: performanceService,
+		AllocationService:  allocationService,
+		RebalanceService:   rebalanceService,
+		PnLService:         pnlService,
+		TaxService:         taxService,
+		ExportService:      exportService,
+		ImportService:      importService,
+		BalanceSync:        balanceSync,
+		ReconcileService:   reconcileService,
+		SpamService:        spamService,
+		DiscoveryService:   discoveryService,
+		NFTService:         nftService,
 	}
 
 	return appCtx, nil
 }
+
+// Close releases connections held by the app
+func (a *AppContext) Close() {
+	if a.DB != nil {
+		a.DB.Close()
+	}
+}
@@ -0,0 +1,77 @@
+package balances
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+var (
+	ErrInvalidAddress = errors.New("wallet is not an address")
+	ErrNoEndpoints    = errors.New("no rpc endpoints configured")
+)
+
+var addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// Reader reads balances over JSON-RPC; evm.Client implements it
+type Reader interface {
+	Chains() []string
+	Balance(ctx context.Context, chain, address string) (*big.Int, error)
+	TokenBalance(ctx context.Context, chain, contract, holder string) (*big.Int, error)
+	Decimals(ctx context.Context, chain, contract string) (int, error)
+}
+
+// Reading is an asset's balance as read from the chain
+type Reading struct {
+	Asset  pricing.AssetRef
+	Amount float64
+	Err    error // set when the balance could not be read
+}
+
+// Balance is an asset's on-chain amount and the synced amount it replaced
+type Balance struct {
+	Chain           string  `json:"chain"`
+	ContractAddress string  `json:"contract_address"`
+	Amount          float64 `json:"amount"`
+	Previous        float64 `json:"previous"`
+}
+
+// AssetError is a balance that could not be read. The asset keeps its
+// previously synced amount.
+type AssetError struct {
+	Chain           string `json:"chain"`
+	ContractAddress string `json:"contract_address"`
+	Message         string `json:"message"`
+}
+
+// Result is the outcome of syncing one wallet
+type Result struct {
+	Wallet   string       `json:"wallet"`
+	SyncedAt time.Time    `json:"synced_at"`
+	Balances []Balance    `json:"balances"`
+	Errors   []AssetError `json:"errors"`
+}
+
+// ParseWatchlist reads chain:contract entries naming tokens whose balance
+// is read for every wallet, held or not
+func ParseWatchlist(entries []string) ([]pricing.AssetRef, error) {
+	out := make([]pricing.AssetRef, 0, len(entries))
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		if e == "" {
+			continue
+		}
+		chain, contract, ok := strings.Cut(e, ":")
+		if !ok || chain == "" || !addressPattern.MatchString(contract) {
+			return nil, fmt.Errorf("invalid watchlist entry %q, want chain:contract", e)
+		}
+		out = append(out, pricing.AssetRef{Chain: chain, ContractAddress: strings.ToLower(contract)})
+	}
+	return out, nil
+}
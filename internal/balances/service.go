@@ -0,0 +1,299 @@
+package balances
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/evm"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+)
+
+type ServiceAPI interface {
+	Sync(ctx context.Context, wallet string) (*Result, error)
+	Read(ctx context.Context, wallet string, held []portfolio.Holding) ([]Reading, error)
+	ReadTokens(ctx context.Context, wallet string, assets []pricing.AssetRef) ([]Reading, error)
+}
+
+// Service reads a wallet's native and token balances from the chain and
+// stores them as its synced holdings, apart from those entered by hand
+type Service struct {
+	reader    Reader
+	portfolio portfolio.Service
+	watchlist []pricing.AssetRef
+	interval  time.Duration
+	logger    *zap.Logger
+
+	mu       sync.Mutex
+	decimals map[pricing.AssetRef]int
+}
+
+func NewService(
+	reader Reader,
+	portfolio portfolio.Service,
+	watchlist []pricing.AssetRef,
+	interval time.Duration,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		reader:    reader,
+		portfolio: portfolio,
+		watchlist: watchlist,
+		interval:  interval,
+		logger:    logger.With(zap.String("service", "balances")),
+		decimals:  make(map[pricing.AssetRef]int),
+	}
+}
+
+// Run syncs every wallet each interval. It returns at once when the
+// schedule is off or no chain has an RPC endpoint.
+func (s *Service) Run(ctx context.Context) {
+	if s.interval <= 0 || len(s.reader.Chains()) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.SyncAll(ctx)
+		}
+	}
+}
+
+// SyncAll syncs every wallet whose portfolio is keyed by an address. A
+// wallet that fails is skipped so the others are still synced.
+func (s *Service) SyncAll(ctx context.Context) int {
+	wallets, err := s.portfolio.Wallets(ctx)
+	if err != nil {
+		s.logger.Error("list-wallets-failed", zap.Error(err))
+		return 0
+	}
+
+	synced := 0
+	for _, wallet := range wallets {
+		if !addressPattern.MatchString(wallet) {
+			continue
+		}
+		if _, err := s.Sync(ctx, wallet); err != nil {
+			s.logger.Warn("balance-sync-failed", zap.String("wallet", wallet), zap.Error(err))
+			continue
+		}
+		synced++
+	}
+
+	s.logger.Info("balances-synced", zap.Int("wallets", synced))
+	return synced
+}
+
+// Sync reads the native balance on every chain with an RPC endpoint, and
+// the balance of every watched token and every token the wallet already
+// holds there, then replaces the wallet's synced holdings in one save
+func (s *Service) Sync(ctx context.Context, wallet string) (*Result, error) {
+	s.logger.Info("sync-balances", zap.String("wallet", wallet))
+
+	// a wallet without a portfolio gets one on save
+	held, _ := s.portfolio.Holdings(ctx, wallet)
+
+	readings, err := s.Read(ctx, wallet, held)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := make(map[pricing.AssetRef]float64)
+	for _, h := range held {
+		if h.Source == portfolio.HoldingSourceSync {
+			previous[h.AssetRef()] += h.Amount
+		}
+	}
+
+	res := &Result{
+		Wallet:   wallet,
+		SyncedAt: time.Now().UTC().Truncate(time.Second),
+		Balances: []Balance{},
+		Errors:   []AssetError{},
+	}
+	var synced []portfolio.Holding
+
+	for _, r := range readings {
+		amount := r.Amount
+		if r.Err != nil {
+			res.Errors = append(res.Errors, AssetError{
+				Chain:           r.Asset.Chain,
+				ContractAddress: r.Asset.ContractAddress,
+				Message:         r.Err.Error(),
+			})
+			amount = previous[r.Asset]
+		}
+
+		if amount == 0 && previous[r.Asset] == 0 {
+			continue
+		}
+		res.Balances = append(res.Balances, Balance{
+			Chain:           r.Asset.Chain,
+			ContractAddress: r.Asset.ContractAddress,
+			Amount:          amount,
+			Previous:        previous[r.Asset],
+		})
+		synced = append(synced, portfolio.Holding{
+			Chain:           r.Asset.Chain,
+			ContractAddress: r.Asset.ContractAddress,
+			Amount:          amount,
+		})
+	}
+
+	if err := s.portfolio.SyncHoldings(ctx, wallet, synced, res.SyncedAt); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("wallet-balances-synced",
+		zap.String("wallet", wallet),
+		zap.Int("balances", len(res.Balances)),
+		zap.Int("errors", len(res.Errors)),
+	)
+	return res, nil
+}
+
+// Read reads the on-chain balance of every asset Sync would for a wallet
+// holding held, without saving anything. A balance that cannot be read
+// has Err set.
+func (s *Service) Read(ctx context.Context, wallet string, held []portfolio.Holding) ([]Reading, error) {
+	if !addressPattern.MatchString(wallet) {
+		return nil, ErrInvalidAddress
+	}
+	chains := s.reader.Chains()
+	if len(chains) == 0 {
+		return nil, ErrNoEndpoints
+	}
+
+	return s.readAll(ctx, wallet, s.assets(chains, held)), nil
+}
+
+// ReadTokens reads the on-chain balance of the given assets only. A
+// balance that cannot be read, e.g. on a chain without an RPC endpoint,
+// has Err set.
+func (s *Service) ReadTokens(ctx context.Context, wallet string, assets []pricing.AssetRef) ([]Reading, error) {
+	if !addressPattern.MatchString(wallet) {
+		return nil, ErrInvalidAddress
+	}
+	if len(s.reader.Chains()) == 0 {
+		return nil, ErrNoEndpoints
+	}
+
+	normalized := make([]pricing.AssetRef, len(assets))
+	for i, a := range assets {
+		a.ContractAddress = strings.ToLower(a.ContractAddress)
+		normalized[i] = a
+	}
+	return s.readAll(ctx, wallet, normalized), nil
+}
+
+func (s *Service) readAll(ctx context.Context, wallet string, assets []pricing.AssetRef) []Reading {
+	out := make([]Reading, 0, len(assets))
+	for _, asset := range assets {
+		amount, err := s.read(ctx, asset, wallet)
+		if err != nil {
+			s.logger.Warn("read-balance-failed",
+				zap.String("wallet", wallet),
+				zap.String("asset", asset.String()),
+				zap.Error(err),
+			)
+		}
+		out = append(out, Reading{Asset: asset, Amount: amount, Err: err})
+	}
+	return out
+}
+
+// assets lists what to read on each chain: the native asset, watched
+// tokens and tokens already held, each once and in a stable order
+func (s *Service) assets(chains []string, held []portfolio.Holding) []pricing.AssetRef {
+	enabled := make(map[string]bool, len(chains))
+	seen := make(map[pricing.AssetRef]bool)
+	var out []pricing.AssetRef
+
+	add := func(a pricing.AssetRef) {
+		a.ContractAddress = strings.ToLower(a.ContractAddress)
+		if !enabled[a.Chain] || seen[a] {
+			return
+		}
+		seen[a] = true
+		out = append(out, a)
+	}
+
+	for _, chain := range chains {
+		enabled[chain] = true
+		add(pricing.AssetRef{Chain: chain})
+	}
+	for _, a := range s.watchlist {
+		add(a)
+	}
+	for _, h := range held {
+		add(h.AssetRef())
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Chain != out[j].Chain {
+			return out[i].Chain < out[j].Chain
+		}
+		return out[i].ContractAddress < out[j].ContractAddress
+	})
+	return out
+}
+
+func (s *Service) read(ctx context.Context, asset pricing.AssetRef, wallet string) (float64, error) {
+	if asset.ContractAddress == "" {
+		wei, err := s.reader.Balance(ctx, asset.Chain, wallet)
+		if err != nil {
+			return 0, err
+		}
+		decimals := 18
+		if m, ok := tokens.NativeMetadata(asset.Chain); ok {
+			decimals = m.Decimals
+		}
+		return evm.ScaleDecimals(wei, decimals), nil
+	}
+
+	raw, err := s.reader.TokenBalance(ctx, asset.Chain, asset.ContractAddress, wallet)
+	if err != nil {
+		return 0, err
+	}
+	if raw.Sign() == 0 {
+		return 0, nil
+	}
+
+	decimals, err := s.tokenDecimals(ctx, asset)
+	if err != nil {
+		return 0, err
+	}
+	return evm.ScaleDecimals(raw, decimals), nil
+}
+
+// tokenDecimals reads decimals() once per token; they never change
+func (s *Service) tokenDecimals(ctx context.Context, asset pricing.AssetRef) (int, error) {
+	s.mu.Lock()
+	d, ok := s.decimals[asset]
+	s.mu.Unlock()
+	if ok {
+		return d, nil
+	}
+
+	d, err := s.reader.Decimals(ctx, asset.Chain, asset.ContractAddress)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	s.decimals[asset] = d
+	s.mu.Unlock()
+	return d, nil
+}
@@ -0,0 +1,133 @@
+package balances_test
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/balances"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+const (
+	wallet = "0x00000000000000000000000000000000000000aa"
+	usdc   = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	dai    = "0x6b175474e89094c44da98b954eedeac495271d0f"
+	pepe   = "0x6982508145454ce325ddbe47a25d4ec3d2311933"
+)
+
+type fakeReader struct {
+	native   map[string]*big.Int
+	tokens   map[string]*big.Int
+	decimals map[string]int
+	failing  map[string]bool
+	calls    int
+}
+
+func (f *fakeReader) Chains() []string { return []string{"ethereum"} }
+
+func (f *fakeReader) Balance(ctx context.Context, chain, address string) (*big.Int, error) {
+	return f.native[chain], nil
+}
+
+func (f *fakeReader) TokenBalance(ctx context.Context, chain, contract, holder string) (*big.Int, error) {
+	if f.failing[contract] {
+		return nil, errors.New("execution reverted")
+	}
+	if v, ok := f.tokens[contract]; ok {
+		return v, nil
+	}
+	return new(big.Int), nil
+}
+
+func (f *fakeReader) Decimals(ctx context.Context, chain, contract string) (int, error) {
+	f.calls++
+	return f.decimals[contract], nil
+}
+
+func wei(v string) *big.Int {
+	n, _ := new(big.Int).SetString(v, 10)
+	return n
+}
+
+func TestSync(t *testing.T) {
+	reader := &fakeReader{
+		native:   map[string]*big.Int{"ethereum": wei("1500000000000000000")},
+		tokens:   map[string]*big.Int{usdc: wei("250000000"), dai: wei("0")},
+		decimals: map[string]int{usdc: 6, dai: 18},
+		failing:  map[string]bool{pepe: true},
+	}
+
+	ps := portfolio.NewService(portfolio.NewMemoryRepository([]*portfolio.Portfolio{{
+		Wallet:   wallet,
+		Holdings: []portfolio.Holding{{Chain: "ethereum", ContractAddress: dai, Amount: 10}},
+		Synced:   []portfolio.Holding{{Chain: "ethereum", ContractAddress: pepe, Amount: 1e6}},
+	}}), nil, zap.NewNop())
+
+	svc := balances.NewService(reader, ps, []pricing.AssetRef{{Chain: "ethereum", ContractAddress: usdc}}, 0, zap.NewNop())
+
+	res, err := svc.Sync(context.Background(), wallet)
+	require.NoError(t, err)
+
+	require.Equal(t, []balances.Balance{
+		{Chain: "ethereum", Amount: 1.5},
+		{Chain: "ethereum", ContractAddress: pepe, Amount: 1e6, Previous: 1e6},
+		{Chain: "ethereum", ContractAddress: usdc, Amount: 250},
+	}, res.Balances)
+	require.Len(t, res.Errors, 1)
+	require.Equal(t, pepe, res.Errors[0].ContractAddress)
+
+	holdings, err := ps.Holdings(context.Background(), wallet)
+	require.NoError(t, err)
+	require.Len(t, holdings, 4)
+	require.Equal(t, portfolio.Holding{Chain: "ethereum", ContractAddress: dai, Amount: 10, Source: portfolio.HoldingSourceManual}, holdings[0])
+
+	// decimals are read once per token
+	_, err = svc.Sync(context.Background(), wallet)
+	require.NoError(t, err)
+	require.Equal(t, 1, reader.calls)
+}
+
+func TestSyncInvalidAddress(t *testing.T) {
+	ps := portfolio.NewService(portfolio.NewMemoryRepository(nil), nil, zap.NewNop())
+	svc := balances.NewService(&fakeReader{}, ps, nil, 0, zap.NewNop())
+
+	_, err := svc.Sync(context.Background(), "wallet1")
+	require.ErrorIs(t, err, balances.ErrInvalidAddress)
+
+	require.Equal(t, 0, svc.SyncAll(context.Background()))
+}
+
+func TestReadTokens(t *testing.T) {
+	reader := &fakeReader{
+		tokens:   map[string]*big.Int{usdc: wei("4200000")},
+		decimals: map[string]int{usdc: 6},
+		failing:  map[string]bool{pepe: true},
+	}
+	ps := portfolio.NewService(portfolio.NewMemoryRepository(nil), nil, zap.NewNop())
+	svc := balances.NewService(reader, ps, nil, 0, zap.NewNop())
+
+	readings, err := svc.ReadTokens(context.Background(), wallet, []pricing.AssetRef{
+		{Chain: "ethereum", ContractAddress: "0xA0B86991C6218B36C1D19D4A2E9EB0CE3606EB48"},
+		{Chain: "ethereum", ContractAddress: pepe},
+	})
+	require.NoError(t, err)
+	require.Len(t, readings, 2)
+	require.Equal(t, pricing.AssetRef{Chain: "ethereum", ContractAddress: usdc}, readings[0].Asset)
+	require.InDelta(t, 4.2, readings[0].Amount, 1e-9)
+	require.Error(t, readings[1].Err)
+}
+
+func TestParseWatchlist(t *testing.T) {
+	refs, err := balances.ParseWatchlist([]string{"ethereum:" + "0xA0b86991c6218b36c1d19d4a2e9eb0ce3606eB48", " "})
+	require.NoError(t, err)
+	require.Equal(t, []pricing.AssetRef{{Chain: "ethereum", ContractAddress: usdc}}, refs)
+
+	_, err = balances.ParseWatchlist([]string{"ethereum"})
+	require.Error(t, err)
+}
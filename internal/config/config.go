@@ -9,10 +9,24 @@ type Config struct {
 	HTTP HTTPConfig
 	Log  LogConfig
 
-	CoinGecko CoinGeckoConfig
-	Redis     RedisConfig
-	Pricing   PricingConfig
-	EtherScan EtherScanConfig
+	CoinGecko   CoinGeckoConfig
+	Redis       RedisConfig
+	Pricing     PricingConfig
+	EtherScan   EtherScanConfig
+	Streaming   StreamingConfig
+	Database    DatabaseConfig
+	Admin       AdminConfig
+	RPC         RPCConfig
+	Tokens      TokensConfig
+	Alerts      AlertsConfig
+	Snapshots   SnapshotsConfig
+	Performance PerformanceConfig
+	Allocation  AllocationConfig
+	Tax         TaxConfig
+	BalanceSync BalanceSyncConfig
+	Spam        SpamConfig
+	Discovery   DiscoveryConfig
+	NFT         NFTConfig
 }
 
 type AppConfig struct {
@@ -38,7 +52,75 @@ type RedisConfig struct {
 }
 
 type PricingConfig struct {
-	CacheTTLSeconds int `env:"CACHE_TTL_SECONDS" envDefault:"30"`
+	CacheTTLSeconds int    `env:"CACHE_TTL_SECONDS" envDefault:"30"`
+	RulesFile       string `env:"PRICE_RULES_FILE"` // JSON array of derivation rules
+}
+
+// RPCConfig maps chains to JSON-RPC endpoints, e.g. RPC_URLS=ethereum=https://...,polygon-pos=https://...
+type RPCConfig struct {
+	URLs map[string]string `env:"RPC_URLS" envKeyValSeparator:"="`
+}
+
+// TokensConfig lists token list JSON files loaded into the registry at startup
+// and how often the token search list is refreshed
+type TokensConfig struct {
+	ListFiles          []string `env:"TOKEN_LISTS"`
+	SearchRefreshHours int      `env:"TOKEN_SEARCH_REFRESH_HOURS" envDefault:"24"`
+}
+
+// AlertsConfig sets how often rules are evaluated and the key webhooks are signed with
+type AlertsConfig struct {
+	EvaluateSeconds int    `env:"ALERTS_EVALUATE_SECONDS" envDefault:"60"`
+	WebhookSecret   string `env:"ALERTS_WEBHOOK_SECRET"`
+}
+
+// SnapshotsConfig sets how often portfolios are snapshotted and how long
+// snapshots are kept; a retention of 0 days keeps everything
+type SnapshotsConfig struct {
+	IntervalMinutes  int `env:"SNAPSHOT_INTERVAL_MINUTES" envDefault:"60"`
+	RawRetentionDays int `env:"SNAPSHOT_RAW_RETENTION_DAYS" envDefault:"30"`
+	RetentionDays    int `env:"SNAPSHOT_RETENTION_DAYS" envDefault:"0"`
+}
+
+// PerformanceConfig sets the annual risk-free rate, in percent, Sharpe ratios
+// are measured against
+type PerformanceConfig struct {
+	RiskFreeRatePct float64 `env:"PERFORMANCE_RISK_FREE_RATE" envDefault:"0"`
+}
+
+// AllocationConfig names the category taxonomy JSON file; the built-in
+// categories are used when it is not set
+type AllocationConfig struct {
+	TaxonomyFile string `env:"ALLOCATION_TAXONOMY_FILE"`
+}
+
+// TaxConfig sets the jurisdiction whose holding-period rules tax reports
+// use when none is requested
+type TaxConfig struct {
+	Jurisdiction string `env:"TAX_JURISDICTION" envDefault:"us"`
+}
+
+// BalanceSyncConfig sets how often wallets' on-chain balances are synced, 0
+// turning the schedule off, and tokens (chain:contract) read for every wallet
+type BalanceSyncConfig struct {
+	IntervalMinutes int      `env:"BALANCE_SYNC_INTERVAL_MINUTES" envDefault:"60"`
+	Tokens          []string `env:"BALANCE_SYNC_TOKENS"`
+}
+
+// SpamConfig names the JSON file of tokens always or never treated as spam
+type SpamConfig struct {
+	ListFile string `env:"SPAM_LIST_FILE"`
+}
+
+// DiscoveryConfig sets the USD value below which suggested tokens count as dust
+type DiscoveryConfig struct {
+	DustUSD float64 `env:"DISCOVERY_DUST_USD" envDefault:"1"`
+}
+
+// NFTConfig picks the provider NFT holdings are valued by; left empty, NFTs
+// are listed without a value
+type NFTConfig struct {
+	FloorPriceProvider string `env:"NFT_FLOOR_PRICE_PROVIDER"`
 }
 
 type EtherScanConfig struct {
@@ -46,6 +128,19 @@ type EtherScanConfig struct {
 	BaseURL string `env:"ETHERSCAN_BASE_URL" envDefault:"https://api.etherscan.io/v2/api"`
 }
 
+type StreamingConfig struct {
+	RefreshSeconds int `env:"STREAM_REFRESH_SECONDS" envDefault:"5"`
+}
+
+// DatabaseConfig is optional; without a URL stores fall back to memory
+type DatabaseConfig struct {
+	URL string `env:"DATABASE_URL"`
+}
+
+type AdminConfig struct {
+	APIKey string `env:"ADMIN_API_KEY"`
+}
+
 func Load() (*Config, error) {
 	cfg := &Config{}
 	if err := env.Parse(cfg); err != nil {
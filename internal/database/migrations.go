@@ -0,0 +1,83 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrations are idempotent and applied in order on every start
+var migrations = []string{
+	`CREATE TABLE IF NOT EXISTS price_overrides (
+		chain            TEXT NOT NULL,
+		contract_address TEXT NOT NULL,
+		price_usd        DOUBLE PRECISION NOT NULL,
+		note             TEXT NOT NULL DEFAULT '',
+		expires_at       TIMESTAMPTZ,
+		updated_at       TIMESTAMPTZ NOT NULL,
+		PRIMARY KEY (chain, contract_address)
+	)`,
+	`CREATE TABLE IF NOT EXISTS alert_rules (
+		id                TEXT PRIMARY KEY,
+		kind              TEXT NOT NULL,
+		chain             TEXT NOT NULL DEFAULT '',
+		contract_address  TEXT NOT NULL DEFAULT '',
+		threshold         DOUBLE PRECISION NOT NULL,
+		window_minutes    INTEGER NOT NULL DEFAULT 0,
+		webhook_url       TEXT NOT NULL,
+		cooldown_minutes  INTEGER NOT NULL,
+		enabled           BOOLEAN NOT NULL,
+		created_at        TIMESTAMPTZ NOT NULL,
+		firing            BOOLEAN NOT NULL DEFAULT FALSE,
+		last_triggered_at TIMESTAMPTZ
+	)`,
+	`ALTER TABLE alert_rules ADD COLUMN IF NOT EXISTS wallet TEXT NOT NULL DEFAULT ''`,
+	`CREATE TABLE IF NOT EXISTS alert_events (
+		id           TEXT PRIMARY KEY,
+		rule_id      TEXT NOT NULL,
+		wallet       TEXT NOT NULL DEFAULT '',
+		kind         TEXT NOT NULL,
+		payload      JSONB NOT NULL,
+		triggered_at TIMESTAMPTZ NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS alert_events_wallet_idx ON alert_events (wallet, triggered_at DESC)`,
+	`CREATE TABLE IF NOT EXISTS portfolio_snapshots (
+		wallet          TEXT NOT NULL,
+		taken_at        TIMESTAMPTZ NOT NULL,
+		total_value_usd DOUBLE PRECISION NOT NULL,
+		holdings        JSONB NOT NULL,
+		PRIMARY KEY (wallet, taken_at)
+	)`,
+	`CREATE TABLE IF NOT EXISTS token_categories (
+		chain            TEXT NOT NULL,
+		contract_address TEXT NOT NULL,
+		category         TEXT NOT NULL,
+		updated_at       TIMESTAMPTZ NOT NULL,
+		PRIMARY KEY (chain, contract_address)
+	)`,
+	`CREATE TABLE IF NOT EXISTS portfolio_targets (
+		wallet        TEXT PRIMARY KEY,
+		weights       JSONB NOT NULL,
+		tolerance_pct DOUBLE PRECISION NOT NULL,
+		cash_asset    JSONB,
+		updated_at    TIMESTAMPTZ NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS spam_overrides (
+		wallet           TEXT NOT NULL,
+		chain            TEXT NOT NULL,
+		contract_address TEXT NOT NULL,
+		spam             BOOLEAN NOT NULL,
+		updated_at       TIMESTAMPTZ NOT NULL,
+		PRIMARY KEY (wallet, chain, contract_address)
+	)`,
+}
+
+func Migrate(ctx context.Context, pool *pgxpool.Pool) error {
+	for i, m := range migrations {
+		if _, err := pool.Exec(ctx, m); err != nil {
+			return fmt.Errorf("migration %d: %w", i, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package discovery
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/spam"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+)
+
+var (
+	ErrInvalidAddress   = errors.New("wallet is not an address")
+	ErrUnsupportedChain = errors.New("unsupported chain")
+	ErrInvalidMinValue  = errors.New("min value must not be negative")
+)
+
+var addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// BalanceSource says where a suggestion's balance came from
+type BalanceSource string
+
+const (
+	BalanceSourceChain     BalanceSource = "chain"
+	BalanceSourceTransfers BalanceSource = "transfers" // net of transfers, when the chain could not be read
+)
+
+// Suggestion is a token the wallet still holds but the portfolio does not
+type Suggestion struct {
+	Chain           string           `json:"chain"`
+	ContractAddress string           `json:"contract_address"`
+	Token           *tokens.Metadata `json:"token,omitempty"`
+	Balance         float64          `json:"balance"`
+	BalanceSource   BalanceSource    `json:"balance_source"`
+	PriceUSD        *float64         `json:"price_usd,omitempty"`
+	ValueUSD        *float64         `json:"value_usd,omitempty"`
+	Transfers       int              `json:"transfers"`
+	FirstSeen       time.Time        `json:"first_seen"`
+	LastSeen        time.Time        `json:"last_seen"`
+	Spam            spam.Verdict     `json:"spam"`
+	Dust            bool             `json:"dust"`
+}
+
+// Query narrows a scan. Spam and dust are hidden unless asked for; a nil
+// MinValueUSD uses the service's dust threshold.
+type Query struct {
+	Chain       string
+	IncludeSpam bool
+	IncludeDust bool
+	MinValueUSD *float64
+}
+
+// Result is the outcome of scanning a wallet's token transfers
+type Result struct {
+	Wallet      string       `json:"wallet"`
+	Chain       string       `json:"chain"`
+	ScannedAt   time.Time    `json:"scanned_at"`
+	Suggestions []Suggestion `json:"suggestions"`
+	HiddenSpam  int          `json:"hidden_spam"`
+	HiddenDust  int          `json:"hidden_dust"`
+	Truncated   bool         `json:"truncated"` // history longer than the scan covers
+}
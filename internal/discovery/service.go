@@ -0,0 +1,326 @@
+package discovery
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/balances"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/spam"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
+)
+
+// dust is the smallest balance still worth suggesting
+const dust = 1e-9
+
+type ServiceAPI interface {
+	Suggest(ctx context.Context, wallet string, q Query) (*Result, error)
+}
+
+// Service finds tokens a wallet has received and still holds but that are
+// missing from its portfolio
+type Service struct {
+	transfers transactions.TokenTransfersAPI
+	balances  balances.ServiceAPI
+	portfolio portfolio.Service
+	pricing   pricing.ServiceAPI
+	spam      spam.ServiceAPI
+	tokens    tokens.ServiceAPI
+	dustUSD   float64
+	logger    *zap.Logger
+}
+
+// Option configures optional collaborators on the discovery service
+type Option func(*Service)
+
+// WithTokens replaces the symbol and name etherscan reports with the
+// registry's metadata
+func WithTokens(t tokens.ServiceAPI) Option {
+	return func(s *Service) {
+		s.tokens = t
+	}
+}
+
+func NewService(
+	transfers transactions.TokenTransfersAPI,
+	balances balances.ServiceAPI,
+	portfolio portfolio.Service,
+	pricing pricing.ServiceAPI,
+	spam spam.ServiceAPI,
+	dustUSD float64,
+	logger *zap.Logger,
+	opts ...Option,
+) *Service {
+	s := &Service{
+		transfers: transfers,
+		balances:  balances,
+		portfolio: portfolio,
+		pricing:   pricing,
+		spam:      spam,
+		dustUSD:   dustUSD,
+		logger:    logger.With(zap.String("service", "discovery")),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// candidate is a token seen in the wallet's transfers
+type candidate struct {
+	asset     pricing.AssetRef
+	meta      *tokens.Metadata
+	net       float64
+	transfers int
+	zero      int // transfers that moved nothing
+	firstSeen time.Time
+	lastSeen  time.Time
+}
+
+// Suggest scans the wallet's ERC-20 transfers on one chain, reads the
+// balance of every token it does not hold yet and returns those with a
+// balance left, most valuable first
+func (s *Service) Suggest(ctx context.Context, wallet string, q Query) (*Result, error) {
+	if !addressPattern.MatchString(wallet) {
+		return nil, ErrInvalidAddress
+	}
+	if q.Chain == "" {
+		q.Chain = "ethereum"
+	}
+	chain := tokens.ChainName(q.Chain)
+	chainID, ok := tokens.ChainID(chain)
+	if !ok {
+		return nil, ErrUnsupportedChain
+	}
+	minValue := s.dustUSD
+	if q.MinValueUSD != nil {
+		if *q.MinValueUSD < 0 {
+			return nil, ErrInvalidMinValue
+		}
+		minValue = *q.MinValueUSD
+	}
+
+	s.logger.Info("discover-tokens",
+		zap.String("wallet", wallet),
+		zap.String("chain", chain),
+	)
+
+	candidates, truncated, err := s.scan(ctx, chain, chainID, wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	// tokens already in the portfolio, entered by hand or synced, are not
+	// suggested again; a wallet without a portfolio holds nothing
+	held, _ := s.portfolio.Holdings(ctx, wallet)
+	for _, h := range held {
+		delete(candidates, h.AssetRef())
+	}
+
+	suggestions := s.readBalances(ctx, wallet, candidates)
+	s.price(ctx, suggestions)
+	s.describe(ctx, wallet, suggestions, candidates)
+
+	res := &Result{
+		Wallet:      wallet,
+		Chain:       chain,
+		ScannedAt:   time.Now().UTC().Truncate(time.Second),
+		Suggestions: []Suggestion{},
+		Truncated:   truncated,
+	}
+	for _, sg := range suggestions {
+		sg.Dust = sg.ValueUSD != nil && *sg.ValueUSD < minValue
+		switch {
+		case sg.Spam.Spam && !q.IncludeSpam:
+			res.HiddenSpam++
+		case sg.Dust && !q.IncludeDust:
+			res.HiddenDust++
+		default:
+			res.Suggestions = append(res.Suggestions, sg)
+		}
+	}
+
+	sort.SliceStable(res.Suggestions, func(i, j int) bool {
+		a, b := res.Suggestions[i], res.Suggestions[j]
+		if (a.ValueUSD == nil) != (b.ValueUSD == nil) {
+			return a.ValueUSD != nil
+		}
+		if a.ValueUSD != nil && *a.ValueUSD != *b.ValueUSD {
+			return *a.ValueUSD > *b.ValueUSD
+		}
+		return a.ContractAddress < b.ContractAddress
+	})
+
+	s.logger.Info("tokens-discovered",
+		zap.String("wallet", wallet),
+		zap.Int("suggestions", len(res.Suggestions)),
+		zap.Int("hidden_spam", res.HiddenSpam),
+		zap.Int("hidden_dust", res.HiddenDust),
+	)
+	return res, nil
+}
+
+// scan pages through the wallet's token transfers and groups them by
+// contract
+func (s *Service) scan(ctx context.Context, chain, chainID, wallet string) (map[pricing.AssetRef]*candidate, bool, error) {
+	wallet = strings.ToLower(wallet)
+	out := make(map[pricing.AssetRef]*candidate)
+
+	txs, truncated, err := transactions.ReadPages(func(page, limit int) ([]transactions.Transaction, error) {
+		return s.transfers.TokenTransfers(ctx, chainID, wallet, page, limit)
+	}, transactions.HistoryPageSize, transactions.HistoryMaxPages)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, tx := range txs {
+		if tx.TokenAddr == "" {
+			continue
+		}
+		asset := pricing.AssetRef{Chain: chain, ContractAddress: strings.ToLower(tx.TokenAddr)}
+		c, ok := out[asset]
+		if !ok {
+			c = &candidate{asset: asset, firstSeen: tx.Timestamp, lastSeen: tx.Timestamp}
+			out[asset] = c
+		}
+		if c.meta == nil && tx.TokenMeta != nil {
+			c.meta = tx.TokenMeta
+		}
+
+		c.transfers++
+		if tx.Amount == 0 {
+			c.zero++
+		}
+		if tx.Timestamp.Before(c.firstSeen) {
+			c.firstSeen = tx.Timestamp
+		}
+		if tx.Timestamp.After(c.lastSeen) {
+			c.lastSeen = tx.Timestamp
+		}
+
+		from := strings.ToLower(tx.From) == wallet
+		to := strings.ToLower(tx.To) == wallet
+		switch {
+		case from && !to:
+			c.net -= tx.Amount
+		case to && !from:
+			c.net += tx.Amount
+		}
+	}
+	return out, truncated, nil
+}
+
+// readBalances reads each candidate's balance from the chain, falling back
+// to the net of its transfers when that fails, and drops those left empty
+func (s *Service) readBalances(ctx context.Context, wallet string, candidates map[pricing.AssetRef]*candidate) []Suggestion {
+	assets := make([]pricing.AssetRef, 0, len(candidates))
+	for a := range candidates {
+		assets = append(assets, a)
+	}
+
+	onChain := make(map[pricing.AssetRef]float64, len(assets))
+	if len(assets) > 0 && s.balances != nil {
+		readings, err := s.balances.ReadTokens(ctx, wallet, assets)
+		if err != nil {
+			s.logger.Warn("discovery-balances-failed", zap.String("wallet", wallet), zap.Error(err))
+		}
+		for _, r := range readings {
+			if r.Err == nil {
+				onChain[r.Asset] = r.Amount
+			}
+		}
+	}
+
+	out := make([]Suggestion, 0, len(candidates))
+	for a, c := range candidates {
+		balance, source := c.net, BalanceSourceTransfers
+		if v, ok := onChain[a]; ok {
+			balance, source = v, BalanceSourceChain
+		}
+		if balance <= dust {
+			continue
+		}
+		out = append(out, Suggestion{
+			Chain:           a.Chain,
+			ContractAddress: a.ContractAddress,
+			Token:           c.meta,
+			Balance:         balance,
+			BalanceSource:   source,
+			Transfers:       c.transfers,
+			FirstSeen:       c.firstSeen,
+			LastSeen:        c.lastSeen,
+		})
+	}
+	return out
+}
+
+// price values each suggestion; a token without a price is left unvalued
+// rather than counted as dust
+func (s *Service) price(ctx context.Context, suggestions []Suggestion) {
+	if len(suggestions) == 0 {
+		return
+	}
+	refs := make([]pricing.AssetRef, len(suggestions))
+	for i, sg := range suggestions {
+		refs[i] = pricing.AssetRef{Chain: sg.Chain, ContractAddress: sg.ContractAddress}
+	}
+
+	prices, err := s.pricing.GetPrices(ctx, refs)
+	if err != nil {
+		s.logger.Warn("discovery-prices-failed", zap.Error(err))
+		return
+	}
+	for i, ref := range refs {
+		p, ok := prices[ref]
+		if !ok {
+			continue
+		}
+		value := p * suggestions[i].Balance
+		suggestions[i].PriceUSD = &p
+		suggestions[i].ValueUSD = &value
+	}
+}
+
+// describe attaches registry metadata where known and classifies each
+// token as spam or not
+func (s *Service) describe(ctx context.Context, wallet string, suggestions []Suggestion, candidates map[pricing.AssetRef]*candidate) {
+	if len(suggestions) == 0 {
+		return
+	}
+	refs := make([]pricing.AssetRef, len(suggestions))
+	for i, sg := range suggestions {
+		refs[i] = pricing.AssetRef{Chain: sg.Chain, ContractAddress: sg.ContractAddress}
+	}
+
+	if s.tokens != nil {
+		meta := s.tokens.Lookup(ctx, refs)
+		for i, ref := range refs {
+			if m, ok := meta[ref]; ok {
+				suggestions[i].Token = &m
+			}
+		}
+	}
+
+	if s.spam == nil {
+		return
+	}
+	judged := make([]spam.Token, len(suggestions))
+	for i, sg := range suggestions {
+		c := candidates[refs[i]]
+		judged[i] = spam.Token{Asset: refs[i], Transfers: c.transfers, ZeroValueTransfers: c.zero}
+		if sg.Token != nil {
+			judged[i].Symbol = sg.Token.Symbol
+			judged[i].Name = sg.Token.Name
+		}
+	}
+	verdicts := s.spam.Classify(ctx, wallet, judged)
+	for i, ref := range refs {
+		suggestions[i].Spam = verdicts[ref]
+	}
+}
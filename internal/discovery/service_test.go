@@ -0,0 +1,195 @@
+package discovery_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/balances"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/discovery"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/spam"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
+)
+
+const wallet = "0x00000000000000000000000000000000000000aa"
+
+var (
+	usdc = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xusdc"}
+	link = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xlink"}
+	uni  = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xuni"}
+	shib = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xshib"}
+	scam = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xscam"}
+	gone = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xgone"}
+	odd  = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xodd"}
+)
+
+type fakeTransfers struct {
+	txs   []transactions.Transaction
+	chain string
+}
+
+func (f *fakeTransfers) TokenTransfers(
+	ctx context.Context,
+	chain string,
+	wallet string,
+	page int,
+	limit int,
+) ([]transactions.Transaction, error) {
+	f.chain = chain
+	start := (page - 1) * limit
+	if start >= len(f.txs) {
+		return nil, nil
+	}
+	return f.txs[start:min(start+limit, len(f.txs))], nil
+}
+
+type fakeBalances struct {
+	balances.ServiceAPI
+	readings []balances.Reading
+}
+
+func (f *fakeBalances) ReadTokens(ctx context.Context, wallet string, assets []pricing.AssetRef) ([]balances.Reading, error) {
+	return f.readings, nil
+}
+
+type fakePricing struct {
+	pricing.ServiceAPI
+	prices map[pricing.AssetRef]float64
+}
+
+func (f *fakePricing) GetPrices(ctx context.Context, assets []pricing.AssetRef) (map[pricing.AssetRef]float64, error) {
+	return f.prices, nil
+}
+
+func transfer(asset pricing.AssetRef, symbol string, in bool, amount float64, day int) transactions.Transaction {
+	tx := transactions.Transaction{
+		Token:     symbol,
+		TokenAddr: asset.ContractAddress,
+		TokenMeta: &tokens.Metadata{Chain: "ethereum", ContractAddress: asset.ContractAddress, Symbol: symbol},
+		Amount:    amount,
+		Timestamp: time.Date(2024, 1, day, 0, 0, 0, 0, time.UTC),
+	}
+	if in {
+		tx.From, tx.To = "0xother", wallet
+	} else {
+		tx.From, tx.To = wallet, "0xother"
+	}
+	return tx
+}
+
+func setup() (*discovery.Service, *fakeTransfers) {
+	ps := portfolio.NewService(portfolio.NewMemoryRepository([]*portfolio.Portfolio{{
+		Wallet:   wallet,
+		Holdings: []portfolio.Holding{{Chain: "ethereum", ContractAddress: "0xusdc", Amount: 100}},
+	}}), nil, zap.NewNop())
+
+	txs := &fakeTransfers{txs: []transactions.Transaction{
+		transfer(link, "LINK", true, 10, 5),
+		transfer(link, "LINK", false, 4, 3),
+		transfer(link, "LINK", true, 1, 1),
+		transfer(usdc, "USDC", true, 100, 2),
+		transfer(uni, "UNI", true, 3, 2),
+		transfer(shib, "SHIB", true, 1000, 2),
+		transfer(scam, "U5DC", true, 5000, 2),
+		transfer(gone, "GONE", true, 5, 1),
+		transfer(gone, "GONE", false, 5, 2),
+		transfer(odd, "ODD", true, 2, 4),
+	}}
+
+	bs := &fakeBalances{readings: []balances.Reading{
+		{Asset: link, Amount: 7},
+		{Asset: uni, Err: errors.New("execution reverted")},
+		{Asset: shib, Amount: 1000},
+		{Asset: scam, Amount: 5000},
+		{Asset: gone, Amount: 0},
+		{Asset: odd, Amount: 2},
+	}}
+
+	prices := &fakePricing{prices: map[pricing.AssetRef]float64{
+		link: 15,
+		uni:  6,
+		shib: 0.00001,
+	}}
+
+	classifier := spam.NewService(spam.List{Deny: []pricing.AssetRef{scam}}, spam.NewMemoryOverrideRepository(), nil, nil, zap.NewNop())
+
+	return discovery.NewService(txs, bs, ps, prices, classifier, 1, zap.NewNop()), txs
+}
+
+func TestSuggest(t *testing.T) {
+	svc, txs := setup()
+
+	res, err := svc.Suggest(context.Background(), wallet, discovery.Query{})
+	require.NoError(t, err)
+	require.Equal(t, "1", txs.chain)
+	require.Equal(t, "ethereum", res.Chain)
+	require.False(t, res.Truncated)
+	require.Equal(t, 1, res.HiddenSpam)
+	require.Equal(t, 1, res.HiddenDust)
+
+	// held usdc and emptied gone are never suggested; priced tokens come
+	// first by value, unpriced last
+	require.Len(t, res.Suggestions, 3)
+
+	l := res.Suggestions[0]
+	require.Equal(t, "0xlink", l.ContractAddress)
+	require.Equal(t, 7.0, l.Balance)
+	require.Equal(t, discovery.BalanceSourceChain, l.BalanceSource)
+	require.Equal(t, 105.0, *l.ValueUSD)
+	require.Equal(t, 3, l.Transfers)
+	require.Equal(t, 1, l.FirstSeen.Day())
+	require.Equal(t, 5, l.LastSeen.Day())
+	require.Equal(t, "LINK", l.Token.Symbol)
+
+	u := res.Suggestions[1]
+	require.Equal(t, "0xuni", u.ContractAddress)
+	require.Equal(t, 3.0, u.Balance)
+	require.Equal(t, discovery.BalanceSourceTransfers, u.BalanceSource)
+
+	require.Equal(t, "0xodd", res.Suggestions[2].ContractAddress)
+	require.Nil(t, res.Suggestions[2].ValueUSD)
+	require.False(t, res.Suggestions[2].Dust)
+}
+
+func TestSuggest_IncludeHidden(t *testing.T) {
+	svc, _ := setup()
+	zero := 0.0
+
+	res, err := svc.Suggest(context.Background(), wallet, discovery.Query{
+		IncludeSpam: true,
+		MinValueUSD: &zero,
+	})
+	require.NoError(t, err)
+	require.Zero(t, res.HiddenSpam)
+	require.Zero(t, res.HiddenDust)
+	require.Len(t, res.Suggestions, 5)
+
+	byAddress := make(map[string]discovery.Suggestion)
+	for _, sg := range res.Suggestions {
+		byAddress[sg.ContractAddress] = sg
+	}
+	require.Equal(t, spam.Verdict{Spam: true, Reason: spam.ReasonDenyList}, byAddress["0xscam"].Spam)
+	require.False(t, byAddress["0xshib"].Dust)
+}
+
+func TestSuggest_Invalid(t *testing.T) {
+	svc, _ := setup()
+	ctx := context.Background()
+
+	_, err := svc.Suggest(ctx, "wallet1", discovery.Query{})
+	require.ErrorIs(t, err, discovery.ErrInvalidAddress)
+
+	_, err = svc.Suggest(ctx, wallet, discovery.Query{Chain: "solana"})
+	require.ErrorIs(t, err, discovery.ErrUnsupportedChain)
+
+	negative := -1.0
+	_, err = svc.Suggest(ctx, wallet, discovery.Query{MinValueUSD: &negative})
+	require.ErrorIs(t, err, discovery.ErrInvalidMinValue)
+}
@@ -0,0 +1,34 @@
+package evm
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+)
+
+var errInvalidHex = errors.New("invalid hex quantity")
+
+// DecodeUint256 parses a hex quantity or a 32-byte ABI word
+func DecodeUint256(hex string) (*big.Int, error) {
+	hex = strings.TrimPrefix(hex, "0x")
+	if hex == "" {
+		return new(big.Int), nil
+	}
+	if len(hex) > 64 {
+		// only the first word of the return data is read
+		hex = hex[:64]
+	}
+
+	v, ok := new(big.Int).SetString(hex, 16)
+	if !ok {
+		return nil, errInvalidHex
+	}
+	return v, nil
+}
+
+// ScaleDecimals converts an integer token amount into units, e.g. wei to ether
+func ScaleDecimals(v *big.Int, decimals int) float64 {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	f, _ := new(big.Rat).SetFrac(v, scale).Float64()
+	return f
+}
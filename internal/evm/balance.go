@@ -0,0 +1,77 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+const (
+	selectorBalanceOf = "0x70a08231" // balanceOf(address)
+	selectorDecimals  = "0x313ce567" // decimals()
+)
+
+// Chains lists the chains with an RPC endpoint configured
+func (c *Client) Chains() []string {
+	out := make([]string, 0, len(c.endpoints))
+	for chain := range c.endpoints {
+		out = append(out, chain)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Balance returns an address's native balance at the latest block, in wei
+func (c *Client) Balance(ctx context.Context, chain, address string) (*big.Int, error) {
+	var out string
+	if err := c.do(ctx, chain, "eth_getBalance", []any{address, "latest"}, &out); err != nil {
+		return nil, err
+	}
+	return DecodeUint256(out)
+}
+
+// TokenBalance calls balanceOf(holder) on an ERC-20 contract and returns
+// the raw amount, not scaled by the token's decimals
+func (c *Client) TokenBalance(ctx context.Context, chain, contract, holder string) (*big.Int, error) {
+	arg, err := encodeAddress(holder)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := c.Call(ctx, chain, contract, selectorBalanceOf+arg)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeUint256(raw)
+}
+
+// Decimals calls decimals() on an ERC-20 contract
+func (c *Client) Decimals(ctx context.Context, chain, contract string) (int, error) {
+	raw, err := c.Call(ctx, chain, contract, selectorDecimals)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := DecodeUint256(raw)
+	if err != nil {
+		return 0, err
+	}
+	if !v.IsInt64() || v.Int64() > 77 {
+		return 0, fmt.Errorf("invalid decimals %s", v)
+	}
+	return int(v.Int64()), nil
+}
+
+// encodeAddress left-pads an address to a 32-byte ABI word
+func encodeAddress(address string) (string, error) {
+	hex := strings.ToLower(strings.TrimPrefix(address, "0x"))
+	if len(hex) != 40 {
+		return "", fmt.Errorf("invalid address %q", address)
+	}
+	if _, ok := new(big.Int).SetString(hex, 16); !ok {
+		return "", fmt.Errorf("invalid address %q", address)
+	}
+	return strings.Repeat("0", 24) + hex, nil
+}
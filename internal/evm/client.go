@@ -0,0 +1,112 @@
+package evm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Client talks JSON-RPC to one node endpoint per chain
+type Client struct {
+	httpClient *http.Client
+	limiter    *rate.Limiter
+	endpoints  map[string]string // chain -> RPC URL
+	nextID     atomic.Int64
+}
+
+func NewClient(endpoints map[string]string) *Client {
+	return &Client{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		// rate limiting requests forwarded to public RPC nodes
+		limiter:   rate.NewLimiter(rate.Every(100*time.Millisecond), 5),
+		endpoints: endpoints,
+	}
+}
+
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      int64  `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Call performs an eth_call against the latest block and returns the hex result
+func (c *Client) Call(ctx context.Context, chain, to, data string) (string, error) {
+	var out string
+	err := c.do(ctx, chain, "eth_call", []any{
+		map[string]string{"to": to, "data": data},
+		"latest",
+	}, &out)
+	return out, err
+}
+
+func (c *Client) do(ctx context.Context, chain, method string, params []any, out any) error {
+	url, ok := c.endpoints[chain]
+	if !ok {
+		return fmt.Errorf("no rpc endpoint configured for chain %q", chain)
+	}
+
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	payload, err := json.Marshal(rpcRequest{
+		JSONRPC: "2.0",
+		ID:      c.nextID.Add(1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("rpc error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var decoded rpcResponse
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return err
+	}
+	if decoded.Error != nil {
+		return fmt.Errorf("rpc error %d: %s", decoded.Error.Code, decoded.Error.Message)
+	}
+
+	return json.Unmarshal(decoded.Result, out)
+}
@@ -0,0 +1,118 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ExchangeRate(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "eth_call", req.Method)
+
+		call := req.Params[0].(map[string]any)
+		require.Equal(t, "0xwsteth", call["to"])
+		require.Equal(t, "0x035faf82", call["data"])
+
+		// 1.15e18
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x0000000000000000000000000000000000000000000000000ff59ee833b30000"}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(map[string]string{"ethereum": ts.URL})
+
+	rate, err := client.ExchangeRate(context.Background(), "ethereum", "0xwsteth", "0x035faf82", 18)
+
+	require.NoError(t, err)
+	require.InDelta(t, 1.15, rate, 1e-12)
+}
+
+func TestClient_RPCError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"error":{"code":-32000,"message":"execution reverted"}}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(map[string]string{"ethereum": ts.URL})
+
+	_, err := client.Call(context.Background(), "ethereum", "0xabc", "0x12345678")
+	require.ErrorContains(t, err, "execution reverted")
+
+	_, err = client.Call(context.Background(), "polygon", "0xabc", "0x12345678")
+	require.Error(t, err)
+}
+
+func TestClient_Balances(t *testing.T) {
+	const holder = "0x00000000000000000000000000000000000000aa"
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		switch req.Method {
+		case "eth_getBalance":
+			require.Equal(t, []any{holder, "latest"}, req.Params)
+			// 2.5e18
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x22b1c8c1227a0000"}`))
+		case "eth_call":
+			call := req.Params[0].(map[string]any)
+			if call["data"] == "0x313ce567" {
+				w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x0000000000000000000000000000000000000000000000000000000000000006"}`))
+				return
+			}
+			require.Equal(t, "0x70a08231000000000000000000000000"+holder[2:], call["data"])
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x00000000000000000000000000000000000000000000000000000000000f4240"}`))
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient(map[string]string{"ethereum": ts.URL})
+	require.Equal(t, []string{"ethereum"}, client.Chains())
+
+	wei, err := client.Balance(context.Background(), "ethereum", holder)
+	require.NoError(t, err)
+	require.InDelta(t, 2.5, ScaleDecimals(wei, 18), 1e-12)
+
+	raw, err := client.TokenBalance(context.Background(), "ethereum", "0xusdc", holder)
+	require.NoError(t, err)
+	require.Equal(t, int64(1_000_000), raw.Int64())
+
+	decimals, err := client.Decimals(context.Background(), "ethereum", "0xusdc")
+	require.NoError(t, err)
+	require.Equal(t, 6, decimals)
+
+	_, err = client.TokenBalance(context.Background(), "ethereum", "0xusdc", "0x123")
+	require.Error(t, err)
+}
+
+func TestClient_Receipt(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpcRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Equal(t, "eth_getTransactionReceipt", req.Method)
+
+		if req.Params[0] == "0xpending" {
+			w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":null}`))
+			return
+		}
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"transactionHash":"0xswap","status":"0x1","from":"0xaa","to":"0xrouter","logs":[{"address":"0xusdc","topics":["0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"],"data":"0x01"}]}}`))
+	}))
+	defer ts.Close()
+
+	client := NewClient(map[string]string{"ethereum": ts.URL})
+
+	receipt, err := client.Receipt(context.Background(), "ethereum", "0xswap")
+	require.NoError(t, err)
+	require.Equal(t, "0x1", receipt.Status)
+	require.Len(t, receipt.Logs, 1)
+	require.Equal(t, "0xusdc", receipt.Logs[0].Address)
+
+	_, err = client.Receipt(context.Background(), "ethereum", "0xpending")
+	require.ErrorIs(t, err, ErrReceiptNotFound)
+}
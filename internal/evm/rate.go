@@ -0,0 +1,34 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ExchangeRate calls a no-argument view method on a wrapper contract, such as
+// stEthPerToken() on wstETH or getExchangeRate() on rETH, and scales the
+// returned uint256 by decimals
+func (c *Client) ExchangeRate(
+	ctx context.Context,
+	chain string,
+	contract string,
+	selector string,
+	decimals int,
+) (float64, error) {
+	if !strings.HasPrefix(selector, "0x") || len(selector) != 10 {
+		return 0, fmt.Errorf("invalid method selector %q", selector)
+	}
+
+	raw, err := c.Call(ctx, chain, contract, selector)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := DecodeUint256(raw)
+	if err != nil {
+		return 0, err
+	}
+
+	return ScaleDecimals(v, decimals), nil
+}
@@ -0,0 +1,38 @@
+package evm
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrReceiptNotFound = errors.New("transaction receipt not found")
+
+// Log is an event a contract emitted during a transaction. Topics[0] is
+// the event's signature hash; indexed arguments follow it.
+type Log struct {
+	Address string   `json:"address"`
+	Topics  []string `json:"topics"`
+	Data    string   `json:"data"`
+}
+
+// Receipt is the outcome of a mined transaction
+type Receipt struct {
+	TransactionHash string `json:"transactionHash"`
+	Status          string `json:"status"` // 0x1 on success, 0x0 when reverted
+	From            string `json:"from"`
+	To              string `json:"to"`
+	Logs            []Log  `json:"logs"`
+}
+
+// Receipt fetches a transaction's receipt and logs. A transaction not mined
+// yet, or unknown to the node, gets ErrReceiptNotFound.
+func (c *Client) Receipt(ctx context.Context, chain, hash string) (*Receipt, error) {
+	var out *Receipt
+	if err := c.do(ctx, chain, "eth_getTransactionReceipt", []any{hash}, &out); err != nil {
+		return nil, err
+	}
+	if out == nil {
+		return nil, ErrReceiptNotFound
+	}
+	return out, nil
+}
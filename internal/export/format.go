@@ -0,0 +1,193 @@
+package export
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
+)
+
+var (
+	ErrInvalidFormat    = errors.New("invalid export format")
+	ErrUnsupportedChain = errors.New("chain has no transaction history")
+)
+
+// Format is a CSV layout transactions can be exported in
+type Format string
+
+const (
+	FormatCSV         Format = "csv"         // every field, one row per transaction
+	FormatKoinly      Format = "koinly"      // Koinly universal template
+	FormatCoinTracker Format = "cointracker" // CoinTracker CSV import
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case "":
+		return FormatCSV, nil
+	case FormatCSV, FormatKoinly, FormatCoinTracker:
+		return f, nil
+	default:
+		return "", ErrInvalidFormat
+	}
+}
+
+// Row is a transaction as seen from the exporting wallet, with USD values
+// at the time it happened. Fee is only set when the wallet paid it.
+type Row struct {
+	Tx          transactions.Transaction
+	Currency    string // symbol of the transferred asset
+	FeeCurrency string // symbol of the chain's native asset
+	Fee         float64
+	PriceUSD    *float64
+	ValueUSD    *float64
+	FeeUSD      *float64
+}
+
+// layout turns rows into the records of one format. A layout may skip a
+// row by returning nil.
+type layout interface {
+	header() []string
+	record(r Row) []string
+}
+
+func layoutFor(f Format) layout {
+	switch f {
+	case FormatKoinly:
+		return koinly{}
+	case FormatCoinTracker:
+		return coinTracker{}
+	default:
+		return generic{}
+	}
+}
+
+type generic struct{}
+
+func (generic) header() []string {
+	return []string{
+		"date", "chain", "hash", "type", "status", "direction", "from", "to",
+		"token", "token_address", "amount", "fee", "fee_token", "price_usd", "value_usd", "fee_usd",
+	}
+}
+
+func (generic) record(r Row) []string {
+	return []string{
+		r.Tx.Timestamp.UTC().Format(time.RFC3339),
+		r.Tx.Chain,
+		r.Tx.Hash,
+		string(r.Tx.Type),
+		string(r.Tx.Status),
+		string(r.Tx.Direction),
+		r.Tx.From,
+		r.Tx.To,
+		r.Currency,
+		r.Tx.TokenAddr,
+		amount(r.Tx.Amount),
+		amount(r.Fee),
+		r.FeeCurrency,
+		price(r.PriceUSD),
+		optional(r.ValueUSD),
+		optional(r.FeeUSD),
+	}
+}
+
+// koinly follows the Koinly universal import template
+type koinly struct{}
+
+func (koinly) header() []string {
+	return []string{
+		"Date", "Sent Amount", "Sent Currency", "Received Amount", "Received Currency",
+		"Fee Amount", "Fee Currency", "Net Worth Amount", "Net Worth Currency",
+		"Label", "Description", "TxHash",
+	}
+}
+
+func (koinly) record(r Row) []string {
+	sent, received, ok := legs(r)
+	if !ok {
+		return nil
+	}
+
+	rec := []string{r.Tx.Timestamp.UTC().Format("2006-01-02 15:04:05") + " UTC", "", "", "", "", "", "", "", "", "", string(r.Tx.Type), r.Tx.Hash}
+	if sent {
+		rec[1], rec[2] = amount(r.Tx.Amount), r.Currency
+	}
+	if received {
+		rec[3], rec[4] = amount(r.Tx.Amount), r.Currency
+	}
+	if r.Fee > 0 {
+		rec[5], rec[6] = amount(r.Fee), r.FeeCurrency
+	}
+	if (sent || received) && r.ValueUSD != nil {
+		rec[7], rec[8] = optional(r.ValueUSD), "USD"
+	}
+	if r.Tx.Type == transactions.TypeStake && sent {
+		rec[9] = "stake"
+	}
+	return rec
+}
+
+// coinTracker follows the CoinTracker CSV import layout
+type coinTracker struct{}
+
+func (coinTracker) header() []string {
+	return []string{
+		"Date", "Received Quantity", "Received Currency", "Sent Quantity", "Sent Currency",
+		"Fee Amount", "Fee Currency", "Tag",
+	}
+}
+
+func (coinTracker) record(r Row) []string {
+	sent, received, ok := legs(r)
+	if !ok {
+		return nil
+	}
+
+	rec := []string{r.Tx.Timestamp.UTC().Format("01/02/2006 15:04:05"), "", "", "", "", "", "", ""}
+	if received {
+		rec[1], rec[2] = amount(r.Tx.Amount), r.Currency
+	}
+	if sent {
+		rec[3], rec[4] = amount(r.Tx.Amount), r.Currency
+	}
+	if r.Fee > 0 {
+		rec[5], rec[6] = amount(r.Fee), r.FeeCurrency
+	}
+	if r.Tx.Type == transactions.TypeStake && sent {
+		rec[7] = "stake"
+	}
+	return rec
+}
+
+// legs reports which side of a tax tool row a transaction fills. Failed
+// transactions move nothing but may still have cost the wallet a fee;
+// anything else with neither leg nor fee is skipped.
+func legs(r Row) (sent, received, ok bool) {
+	if r.Tx.Status == transactions.StatusSuccess && r.Tx.Amount > 0 {
+		sent = r.Tx.Direction == transactions.DirectionOut
+		received = r.Tx.Direction == transactions.DirectionIn
+	}
+	return sent, received, sent || received || r.Fee > 0
+}
+
+func amount(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// price keeps full precision for low-priced tokens
+func price(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return amount(*v)
+}
+
+func optional(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', 2, 64)
+}
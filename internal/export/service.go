@@ -0,0 +1,242 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
+)
+
+// Query selects the chain, layout and date range of an export
+type Query struct {
+	Chain  string
+	Format Format
+	From   *time.Time
+	To     *time.Time
+}
+
+type ServiceAPI interface {
+	// Export writes the wallet's transactions and ERC-20 transfers, newest
+	// first, to w as CSV. Rows are flushed before each page is read.
+	Export(ctx context.Context, w io.Writer, wallet string, q Query) error
+}
+
+type Service struct {
+	transactions transactions.ServiceAPI
+	transfers    transactions.TokenTransfersAPI
+	prices       pricing.ServiceAPI
+	logger       *zap.Logger
+}
+
+func NewService(
+	txs transactions.ServiceAPI,
+	transfers transactions.TokenTransfersAPI,
+	prices pricing.ServiceAPI,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		transactions: txs,
+		transfers:    transfers,
+		prices:       prices,
+		logger:       logger.With(zap.String("service", "export")),
+	}
+}
+
+// flusher is implemented by writers that buffer, such as
+// http.ResponseWriter
+type flusher interface {
+	Flush()
+}
+
+// Validate reports whether q can be exported, so callers can reject it
+// before anything is written
+func (q Query) Validate() error {
+	if _, ok := tokens.ChainID(q.Chain); !ok {
+		return ErrUnsupportedChain
+	}
+	if _, err := ParseFormat(string(q.Format)); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *Service) Export(ctx context.Context, w io.Writer, wallet string, q Query) error {
+	if err := q.Validate(); err != nil {
+		return err
+	}
+	chainID, _ := tokens.ChainID(q.Chain)
+	wallet = strings.ToLower(wallet)
+
+	s.logger.Info("export-transactions",
+		zap.String("wallet", wallet),
+		zap.String("chain", q.Chain),
+		zap.String("format", string(q.Format)),
+	)
+
+	feeCurrency := ""
+	if m, ok := tokens.NativeMetadata(q.Chain); ok {
+		feeCurrency = m.Symbol
+	}
+
+	out := layoutFor(q.Format)
+	cw := csv.NewWriter(w)
+	if err := cw.Write(out.header()); err != nil {
+		return err
+	}
+
+	flush := func() error {
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return err
+		}
+		if f, ok := w.(flusher); ok {
+			f.Flush()
+		}
+		return nil
+	}
+
+	txs := &feed{flush: flush, list: func(page int) ([]transactions.Transaction, error) {
+		return s.transactions.List(ctx, chainID, wallet, page, transactions.HistoryPageSize, transactions.Filters{})
+	}}
+	transfers := &feed{flush: flush, list: func(page int) ([]transactions.Transaction, error) {
+		return s.transfers.TokenTransfers(ctx, chainID, wallet, page, transactions.HistoryPageSize)
+	}}
+	memo := pricing.NewHistoricalMemo(s.prices)
+
+	var written int
+	for {
+		tx, err := next(txs, transfers)
+		if err != nil {
+			return err
+		}
+		// both lists come newest first, so nothing after this is in range
+		if tx == nil || (q.From != nil && tx.Timestamp.Before(*q.From)) {
+			break
+		}
+		if q.To != nil && tx.Timestamp.After(*q.To) {
+			continue
+		}
+		if tx.Spam != nil && tx.Spam.Spam && tx.TokenAddr != "" {
+			continue
+		}
+
+		rec := out.record(s.row(ctx, memo, q.Chain, wallet, feeCurrency, *tx))
+		if rec == nil {
+			continue
+		}
+		if err := cw.Write(rec); err != nil {
+			return err
+		}
+		written++
+	}
+
+	if err := flush(); err != nil {
+		return err
+	}
+	if txs.truncated || transfers.truncated {
+		s.logger.Warn("export-truncated",
+			zap.String("wallet", wallet),
+			zap.Int("rows", written),
+		)
+	}
+	return nil
+}
+
+// feed reads a newest-first list a page at a time
+type feed struct {
+	list      func(page int) ([]transactions.Transaction, error)
+	flush     func() error // called before each page is read
+	page      int
+	buf       []transactions.Transaction
+	done      bool
+	truncated bool // HistoryMaxPages were read and more were left
+}
+
+// peek returns the feed's next transaction, or nil when it is exhausted
+func (f *feed) peek() (*transactions.Transaction, error) {
+	for len(f.buf) == 0 && !f.done {
+		if f.page == transactions.HistoryMaxPages {
+			f.done, f.truncated = true, true
+			break
+		}
+		if err := f.flush(); err != nil {
+			return nil, err
+		}
+		f.page++
+		txs, err := f.list(f.page)
+		if err != nil {
+			return nil, err
+		}
+		f.buf = txs
+		f.done = len(txs) < transactions.HistoryPageSize
+	}
+	if len(f.buf) == 0 {
+		return nil, nil
+	}
+	return &f.buf[0], nil
+}
+
+// next takes the newer of the two feeds' next transactions
+func next(a, b *feed) (*transactions.Transaction, error) {
+	ta, err := a.peek()
+	if err != nil {
+		return nil, err
+	}
+	tb, err := b.peek()
+	if err != nil {
+		return nil, err
+	}
+
+	from := a
+	if ta == nil || (tb != nil && tb.Timestamp.After(ta.Timestamp)) {
+		from = b
+	}
+	if len(from.buf) == 0 {
+		return nil, nil
+	}
+	tx := from.buf[0]
+	from.buf = from.buf[1:]
+	return &tx, nil
+}
+
+// row values a transaction at the historical price on the day it happened
+func (s *Service) row(
+	ctx context.Context,
+	memo *pricing.HistoricalMemo,
+	chain, wallet, feeCurrency string,
+	tx transactions.Transaction,
+) Row {
+	r := Row{Tx: tx, Currency: tx.Token, FeeCurrency: feeCurrency}
+	if r.Currency == "" && tx.TokenAddr == "" {
+		r.Currency = feeCurrency
+	}
+	if strings.EqualFold(tx.From, wallet) {
+		r.Fee = tx.Fee
+	}
+
+	asset := pricing.AssetRef{Chain: chain, ContractAddress: strings.ToLower(tx.TokenAddr)}
+	native := pricing.AssetRef{Chain: chain}
+
+	refs := []pricing.AssetRef{asset}
+	if asset != native && r.Fee > 0 {
+		refs = append(refs, native)
+	}
+
+	prices := memo.Prices(ctx, refs, tx.Timestamp)
+	if hp, ok := prices[asset]; ok {
+		p, v := hp.PriceUSD, hp.PriceUSD*tx.Amount
+		r.PriceUSD, r.ValueUSD = &p, &v
+	}
+	if hp, ok := prices[native]; ok && r.Fee > 0 {
+		fee := hp.PriceUSD * r.Fee
+		r.FeeUSD = &fee
+	}
+	return r
+}
@@ -0,0 +1,223 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/spam"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
+)
+
+const wallet = "0xabc"
+
+// fakeTransactions serves txs, and transfers as token transfers, in pages
+// of the requested size
+type fakeTransactions struct {
+	txs       []transactions.Transaction
+	transfers []transactions.Transaction
+	pages     int
+}
+
+func (f *fakeTransactions) TokenTransfers(
+	ctx context.Context,
+	chain string,
+	wallet string,
+	page int,
+	limit int,
+) ([]transactions.Transaction, error) {
+	start := (page - 1) * limit
+	if start >= len(f.transfers) {
+		return nil, nil
+	}
+	return f.transfers[start:min(start+limit, len(f.transfers))], nil
+}
+
+func (f *fakeTransactions) List(
+	ctx context.Context,
+	chain string,
+	wallet string,
+	page int,
+	limit int,
+	filters transactions.Filters,
+) ([]transactions.Transaction, error) {
+	f.pages++
+	start := (page - 1) * limit
+	if start >= len(f.txs) {
+		return nil, nil
+	}
+	return f.txs[start:min(start+limit, len(f.txs))], nil
+}
+
+type fakePricing struct {
+	pricing.ServiceAPI
+}
+
+func (fakePricing) GetHistoricalPrices(ctx context.Context, assets []pricing.AssetRef, at time.Time) (map[pricing.AssetRef]pricing.HistoricalPrice, error) {
+	out := make(map[pricing.AssetRef]pricing.HistoricalPrice)
+	for _, a := range assets {
+		if a.ContractAddress == "" {
+			out[a] = pricing.HistoricalPrice{PriceUSD: 2000, Time: at}
+		}
+	}
+	return out, nil
+}
+
+func day(d int) time.Time {
+	return time.Date(2025, 1, d, 12, 0, 0, 0, time.UTC)
+}
+
+func testTransactions() []transactions.Transaction {
+	return []transactions.Transaction{
+		{Hash: "0x3", Chain: "1", From: wallet, To: "0xbob", Amount: 0.5, Fee: 0.001, Type: transactions.TypeSend,
+			Status: transactions.StatusSuccess, Direction: transactions.DirectionOut, Timestamp: day(3)},
+		{Hash: "0x2", Chain: "1", From: wallet, To: "0xdex", Amount: 1, Fee: 0.002, Type: transactions.TypeSwap,
+			Status: transactions.StatusFailed, Direction: transactions.DirectionOut, Timestamp: day(2)},
+		{Hash: "0x1", Chain: "1", From: "0xbob", To: wallet, Amount: 2, Fee: 0.003, Token: "ETH", Type: transactions.TypeSend,
+			Status: transactions.StatusSuccess, Direction: transactions.DirectionIn, Timestamp: day(1)},
+	}
+}
+
+func export(t *testing.T, txs []transactions.Transaction, q Query) [][]string {
+	t.Helper()
+	fake := &fakeTransactions{txs: txs}
+	svc := NewService(fake, fake, fakePricing{}, zap.NewNop())
+
+	var buf bytes.Buffer
+	require.NoError(t, svc.Export(context.Background(), &buf, wallet, q))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	return records
+}
+
+func TestExport_Generic(t *testing.T) {
+	records := export(t, testTransactions(), Query{Chain: "ethereum", Format: FormatCSV})
+
+	require.Len(t, records, 4)
+	require.Equal(t, "date", records[0][0])
+	require.Equal(t, []string{
+		"2025-01-03T12:00:00Z", "1", "0x3", "send", "success", "out", wallet, "0xbob",
+		"ETH", "", "0.5", "0.001", "ETH", "2000", "1000.00", "2.00",
+	}, records[1])
+
+	// the sender paid the fee on the incoming transfer
+	require.Equal(t, "0", records[3][11])
+	require.Equal(t, "", records[3][15])
+}
+
+func TestExport_Koinly(t *testing.T) {
+	records := export(t, testTransactions(), Query{Chain: "ethereum", Format: FormatKoinly})
+
+	require.Len(t, records, 4)
+	require.Equal(t, []string{
+		"2025-01-03 12:00:00 UTC", "0.5", "ETH", "", "", "0.001", "ETH", "1000.00", "USD", "", "send", "0x3",
+	}, records[1])
+
+	// the failed swap only cost its fee
+	require.Equal(t, []string{
+		"2025-01-02 12:00:00 UTC", "", "", "", "", "0.002", "ETH", "", "", "", "swap", "0x2",
+	}, records[2])
+
+	require.Equal(t, "2", records[3][3])
+	require.Equal(t, "", records[3][5])
+}
+
+func TestExport_CoinTracker(t *testing.T) {
+	records := export(t, testTransactions(), Query{Chain: "ethereum", Format: FormatCoinTracker})
+
+	require.Len(t, records, 4)
+	require.Equal(t, []string{"Date", "Received Quantity", "Received Currency", "Sent Quantity", "Sent Currency", "Fee Amount", "Fee Currency", "Tag"}, records[0])
+	require.Equal(t, []string{"01/03/2025 12:00:00", "", "", "0.5", "ETH", "0.001", "ETH", ""}, records[1])
+	require.Equal(t, []string{"01/01/2025 12:00:00", "2", "ETH", "", "", "", "", ""}, records[3])
+}
+
+// countingPricing prices every asset at 2 and counts the lookups
+type countingPricing struct {
+	pricing.ServiceAPI
+	lookups int
+}
+
+func (c *countingPricing) GetHistoricalPrices(ctx context.Context, assets []pricing.AssetRef, at time.Time) (map[pricing.AssetRef]pricing.HistoricalPrice, error) {
+	out := make(map[pricing.AssetRef]pricing.HistoricalPrice)
+	for _, a := range assets {
+		c.lookups++
+		out[a] = pricing.HistoricalPrice{PriceUSD: 2, Time: at}
+	}
+	return out, nil
+}
+
+func TestExport_TokenTransfers(t *testing.T) {
+	transfer := func(hash string, at time.Time, token, symbol string, amount float64) transactions.Transaction {
+		return transactions.Transaction{
+			Hash: hash, Chain: "1", From: "0xbob", To: wallet, Token: symbol, TokenAddr: token, Amount: amount,
+			Type: transactions.TypeReceive, Status: transactions.StatusSuccess, Direction: transactions.DirectionIn, Timestamp: at,
+		}
+	}
+	airdrop := transfer("0x9", day(3).Add(time.Hour), "0xscam", "U5DC", 5000)
+	airdrop.Spam = &spam.Verdict{Spam: true}
+
+	fake := &fakeTransactions{
+		txs: testTransactions(),
+		transfers: []transactions.Transaction{
+			airdrop,
+			transfer("0x5", day(2).Add(time.Hour), "0xuni", "UNI", 10),
+			transfer("0x4", day(2).Add(-time.Hour), "0xuni", "UNI", 5),
+		},
+	}
+	prices := &countingPricing{}
+	svc := NewService(fake, fake, prices, zap.NewNop())
+
+	var buf bytes.Buffer
+	require.NoError(t, svc.Export(context.Background(), &buf, wallet, Query{Chain: "ethereum", Format: FormatCSV}))
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+
+	// merged newest first, without the spam airdrop
+	var hashes []string
+	for _, r := range records[1:] {
+		hashes = append(hashes, r[2])
+	}
+	require.Equal(t, []string{"0x3", "0x5", "0x2", "0x4", "0x1"}, hashes)
+	require.Equal(t, []string{"UNI", "0xuni", "10", "0", "ETH", "2", "20.00", ""}, records[2][8:])
+
+	// ETH on days 1 to 3 and UNI on day 2, each looked up once
+	require.Equal(t, 4, prices.lookups)
+}
+
+func TestExport_DateRangeStopsPaging(t *testing.T) {
+	var txs []transactions.Transaction
+	for i := 0; i < 2*transactions.HistoryPageSize; i++ {
+		txs = append(txs, transactions.Transaction{
+			Hash: "0x", Chain: "1", From: "0xbob", To: wallet, Amount: 1,
+			Status: transactions.StatusSuccess, Direction: transactions.DirectionIn,
+			Timestamp: day(20).Add(-time.Duration(i) * time.Hour),
+		})
+	}
+	fake := &fakeTransactions{txs: txs}
+	svc := NewService(fake, fake, fakePricing{}, zap.NewNop())
+
+	from, to := day(18), day(19)
+	var buf bytes.Buffer
+	require.NoError(t, svc.Export(context.Background(), &buf, wallet, Query{Chain: "ethereum", From: &from, To: &to}))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 1+25)
+	require.Equal(t, 1, fake.pages)
+}
+
+func TestExport_Invalid(t *testing.T) {
+	svc := NewService(&fakeTransactions{}, &fakeTransactions{}, fakePricing{}, zap.NewNop())
+
+	var buf bytes.Buffer
+	require.ErrorIs(t, svc.Export(context.Background(), &buf, wallet, Query{Chain: "solana"}), ErrUnsupportedChain)
+	require.ErrorIs(t, svc.Export(context.Background(), &buf, wallet, Query{Chain: "ethereum", Format: "xlsx"}), ErrInvalidFormat)
+	require.Zero(t, buf.Len())
+}
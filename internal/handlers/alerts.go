@@ -0,0 +1,169 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/alerts"
+)
+
+type AlertsHandler struct {
+	service alerts.ServiceAPI
+	logger  *zap.Logger
+}
+
+func NewAlertsHandler(service alerts.ServiceAPI, logger *zap.Logger) *AlertsHandler {
+	return &AlertsHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ListAlertRules godoc
+// @Summary List alert rules
+// @Tags Alerts
+// @Produce json
+// @Success 200 {array} alerts.Rule
+// @Router /alerts/rules [get]
+func (h *AlertsHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.service.ListRules(r.Context())
+	if err != nil {
+		h.logger.Error("list-alert-rules-failed", zap.Error(err))
+		RespondError(w, http.StatusInternalServerError, "ALERTS_FAILED", "failed to list alert rules")
+		return
+	}
+
+	RespondOK(w, http.StatusOK, rules)
+}
+
+// GetAlertRule godoc
+// @Summary Get alert rule
+// @Tags Alerts
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200 {object} alerts.Rule
+// @Failure 404 {object} handlers.ErrorResponse
+// @Router /alerts/rules/{id} [get]
+func (h *AlertsHandler) GetRule(w http.ResponseWriter, r *http.Request) {
+	rule, err := h.service.GetRule(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	RespondOK(w, http.StatusOK, rule)
+}
+
+// CreateAlertRule godoc
+// @Summary Create alert rule
+// @Description Rules fire a signed webhook when price_above/price_below crosses threshold (USD) or pct_change moves by threshold percent within window_minutes (negative for drops). Wallet rules: drawdown fires when the wallet value is threshold percent below its high within window_minutes, concentration when one holding exceeds threshold percent of the wallet. A rule fires once when its condition starts to hold and not again until it clears and cooldown_minutes (default 60, 0 for none) has passed.
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param rule body handlers.AlertRuleRequest true "Rule"
+// @Success 201 {object} alerts.Rule
+// @Failure 400 {object} handlers.ErrorResponse
+// @Router /alerts/rules [post]
+func (h *AlertsHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	var req AlertRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "INVALID_JSON", "invalid request body")
+		return
+	}
+
+	rule, err := h.service.CreateRule(r.Context(), req.ToRule())
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	RespondOK(w, http.StatusCreated, rule)
+}
+
+// UpdateAlertRule godoc
+// @Summary Update alert rule
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param rule body handlers.AlertRuleRequest true "Rule"
+// @Success 200 {object} alerts.Rule
+// @Failure 400 {object} handlers.ErrorResponse
+// @Failure 404 {object} handlers.ErrorResponse
+// @Router /alerts/rules/{id} [put]
+func (h *AlertsHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	var req AlertRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "INVALID_JSON", "invalid request body")
+		return
+	}
+
+	rule, err := h.service.UpdateRule(r.Context(), chi.URLParam(r, "id"), req.ToRule())
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	RespondOK(w, http.StatusOK, rule)
+}
+
+// DeleteAlertRule godoc
+// @Summary Delete alert rule
+// @Tags Alerts
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200
+// @Failure 404 {object} handlers.ErrorResponse
+// @Router /alerts/rules/{id} [delete]
+func (h *AlertsHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.DeleteRule(r.Context(), chi.URLParam(r, "id")); err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	RespondOK(w, http.StatusOK, nil)
+}
+
+// AlertHistory godoc
+// @Summary Wallet alert history
+// @Description Alerts fired by the wallet's drawdown and concentration rules, most recent first
+// @Tags Alerts
+// @Produce json
+// @Param wallet path string true "Wallet address"
+// @Param limit query int false "Max events" default(50)
+// @Success 200 {array} alerts.Event
+// @Router /wallets/{wallet}/alerts [get]
+func (h *AlertsHandler) History(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	events, err := h.service.History(r.Context(), chi.URLParam(r, "wallet"), limit)
+	if err != nil {
+		h.respondError(w, err)
+		return
+	}
+
+	RespondOK(w, http.StatusOK, events)
+}
+
+func (h *AlertsHandler) respondError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, alerts.ErrRuleNotFound):
+		RespondError(w, http.StatusNotFound, "NOT_FOUND", "alert rule not found")
+	case errors.Is(err, alerts.ErrInvalidRule):
+		RespondError(w, http.StatusBadRequest, "INVALID_RULE", "invalid alert rule")
+	default:
+		h.logger.Error("alert-rule-request-failed", zap.Error(err))
+		RespondError(w, http.StatusInternalServerError, "ALERTS_FAILED", "alert rule request failed")
+	}
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/alerts"
+)
+
+type mockAlertService struct {
+	created alerts.Rule
+	err     error
+}
+
+func (m *mockAlertService) ListRules(ctx context.Context) ([]alerts.Rule, error) {
+	return []alerts.Rule{m.created}, m.err
+}
+
+func (m *mockAlertService) GetRule(ctx context.Context, id string) (*alerts.Rule, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &m.created, nil
+}
+
+func (m *mockAlertService) CreateRule(ctx context.Context, r alerts.Rule) (*alerts.Rule, error) {
+	m.created = r
+	return &r, m.err
+}
+
+func (m *mockAlertService) UpdateRule(ctx context.Context, id string, r alerts.Rule) (*alerts.Rule, error) {
+	return &r, m.err
+}
+
+func (m *mockAlertService) DeleteRule(ctx context.Context, id string) error {
+	return m.err
+}
+
+func (m *mockAlertService) History(ctx context.Context, wallet string, limit int) ([]alerts.Event, error) {
+	return []alerts.Event{{Wallet: wallet, Kind: alerts.KindDrawdown}}, m.err
+}
+
+func TestAlertsHandler_CreateRule(t *testing.T) {
+	svc := &mockAlertService{}
+	handler := NewAlertsHandler(svc, zap.NewNop())
+
+	body := `{
+		"kind": "price_above",
+		"chain": "ethereum",
+		"contract_address": "0xa0b8",
+		"threshold": 1.01,
+		"webhook_url": "https://hooks.example.com"
+	}`
+
+	req := httptest.NewRequest(http.MethodPost, "/alerts/rules", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	handler.CreateRule(rec, req)
+
+	require.Equal(t, http.StatusCreated, rec.Code)
+	require.Equal(t, alerts.KindPriceAbove, svc.created.Kind)
+	require.Equal(t, "0xa0b8", svc.created.Asset.ContractAddress)
+	require.True(t, svc.created.Enabled)
+}
+
+func TestAlertsHandler_GetRuleNotFound(t *testing.T) {
+	handler := NewAlertsHandler(&mockAlertService{err: alerts.ErrRuleNotFound}, zap.NewNop())
+	r := chi.NewRouter()
+	r.Get("/alerts/rules/{id}", handler.GetRule)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/alerts/rules/abc", nil))
+
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestAlertsHandler_History(t *testing.T) {
+	handler := NewAlertsHandler(&mockAlertService{}, zap.NewNop())
+	r := chi.NewRouter()
+	r.Get("/wallets/{wallet}/alerts", handler.History)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/0xabc/alerts", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"wallet":"0xabc"`)
+	require.Contains(t, rec.Body.String(), `"kind":"drawdown"`)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/allocation"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+type AllocationHandler struct {
+	service allocation.ServiceAPI
+	logger  *zap.Logger
+}
+
+func NewAllocationHandler(service allocation.ServiceAPI, logger *zap.Logger) *AllocationHandler {
+	return &AllocationHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetAllocation godoc
+// @Summary Portfolio allocation
+// @Description Portfolio value and weight by asset, by chain and by category. Each grouping adds up to total_value_usd.
+// @Tags Portfolio
+// @Produce json
+// @Param wallet path string true "Wallet address"
+// @Success 200 {object} allocation.Allocation
+// @Failure 404 {object} handlers.ErrorResponse
+// @Router /wallets/{wallet}/portfolio/allocation [get]
+func (h *AllocationHandler) Get(w http.ResponseWriter, r *http.Request) {
+	wallet := chi.URLParam(r, "wallet")
+
+	alloc, err := h.service.Allocation(r.Context(), wallet)
+	if err != nil {
+		h.logger.Error("get-allocation-failed", zap.Error(err))
+		RespondError(w, http.StatusNotFound, "PORTFOLIO_NOT_FOUND", "portfolio not found")
+		return
+	}
+
+	RespondOK(w, http.StatusOK, alloc)
+}
+
+// ListCategories godoc
+// @Summary List allocation categories
+// @Description The category taxonomy and every token assigned to a category
+// @Tags Allocation
+// @Produce json
+// @Success 200 {object} allocation.Taxonomy
+// @Router /allocation/categories [get]
+func (h *AllocationHandler) Categories(w http.ResponseWriter, r *http.Request) {
+	taxonomy, err := h.service.Taxonomy(r.Context())
+	if err != nil {
+		h.logger.Error("list-categories-failed", zap.Error(err))
+		RespondError(w, http.StatusInternalServerError, "CATEGORIES_FAILED", "failed to list categories")
+		return
+	}
+
+	RespondOK(w, http.StatusOK, taxonomy)
+}
+
+// AssignCategory godoc
+// @Summary Assign token category
+// @Description Place a token in one of the taxonomy's categories, replacing its current category
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param assignment body handlers.AssignCategoryRequest true "Assignment"
+// @Success 200 {object} allocation.Assignment
+// @Failure 400 {object} handlers.ErrorResponse
+// @Failure 401 {object} handlers.ErrorResponse
+// @Router /admin/allocation/assignments [put]
+func (h *AllocationHandler) Assign(w http.ResponseWriter, r *http.Request) {
+	var req AssignCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "INVALID_JSON", "invalid request body")
+		return
+	}
+
+	assignment, err := h.service.Assign(r.Context(), pricing.AssetRef{
+		Chain:           req.Chain,
+		ContractAddress: req.ContractAddress,
+	}, req.Category)
+	if err != nil {
+		h.logger.Error("assign-category-failed", zap.Error(err))
+		if errors.Is(err, allocation.ErrUnknownCategory) || errors.Is(err, allocation.ErrInvalidAssignment) {
+			RespondError(w, http.StatusBadRequest, "INVALID_ASSIGNMENT", "chain is required and category must be in the taxonomy")
+			return
+		}
+		RespondError(w, http.StatusInternalServerError, "CATEGORIES_FAILED", "failed to assign category")
+		return
+	}
+
+	RespondOK(w, http.StatusOK, assignment)
+}
+
+// UnassignCategory godoc
+// @Summary Remove token category
+// @Description Remove a token's assigned category; it falls back to its taxonomy file default or uncategorized
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param chain query string true "Chain"
+// @Param contract query string false "Contract address"
+// @Success 200
+// @Failure 401 {object} handlers.ErrorResponse
+// @Failure 404 {object} handlers.ErrorResponse
+// @Router /admin/allocation/assignments [delete]
+func (h *AllocationHandler) Unassign(w http.ResponseWriter, r *http.Request) {
+	chain := r.URL.Query().Get("chain")
+	if chain == "" {
+		RespondError(w, http.StatusBadRequest, "MISSING_PARAMS", "chain is required")
+		return
+	}
+
+	err := h.service.Unassign(r.Context(), pricing.AssetRef{
+		Chain:           chain,
+		ContractAddress: r.URL.Query().Get("contract"),
+	})
+	if err != nil {
+		h.logger.Error("unassign-category-failed", zap.Error(err))
+		if errors.Is(err, allocation.ErrAssignmentMissing) {
+			RespondError(w, http.StatusNotFound, "NOT_FOUND", "category assignment not found")
+			return
+		}
+		RespondError(w, http.StatusInternalServerError, "CATEGORIES_FAILED", "failed to remove category")
+		return
+	}
+
+	RespondOK(w, http.StatusOK, nil)
+}
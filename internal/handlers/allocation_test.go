@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/allocation"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+type mockAllocationService struct {
+	asset    pricing.AssetRef
+	category string
+	err      error
+}
+
+func (m *mockAllocationService) Allocation(ctx context.Context, wallet string) (*allocation.Allocation, error) {
+	return &allocation.Allocation{Wallet: wallet}, m.err
+}
+
+func (m *mockAllocationService) Taxonomy(ctx context.Context) (allocation.Taxonomy, error) {
+	return allocation.DefaultTaxonomy(), m.err
+}
+
+func (m *mockAllocationService) Assign(ctx context.Context, asset pricing.AssetRef, category string) (allocation.Assignment, error) {
+	m.asset, m.category = asset, category
+	return allocation.Assignment{Asset: asset, Category: category}, m.err
+}
+
+func (m *mockAllocationService) Unassign(ctx context.Context, asset pricing.AssetRef) error {
+	return m.err
+}
+
+func TestAllocationHandler_Assign(t *testing.T) {
+	svc := &mockAllocationService{}
+	handler := NewAllocationHandler(svc, zap.NewNop())
+
+	body := `{"chain": "ethereum", "contract_address": "0xa0b8", "category": "stablecoins"}`
+	req := httptest.NewRequest(http.MethodPut, "/admin/allocation/assignments", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	handler.Assign(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "0xa0b8", svc.asset.ContractAddress)
+	require.Equal(t, "stablecoins", svc.category)
+}
+
+func TestAllocationHandler_AssignUnknownCategory(t *testing.T) {
+	svc := &mockAllocationService{err: allocation.ErrUnknownCategory}
+	handler := NewAllocationHandler(svc, zap.NewNop())
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/allocation/assignments", bytes.NewBufferString(`{"chain": "ethereum", "category": "memes"}`))
+	rec := httptest.NewRecorder()
+
+	handler.Assign(rec, req)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestAllocationHandler_Categories(t *testing.T) {
+	handler := NewAllocationHandler(&mockAllocationService{}, zap.NewNop())
+
+	rec := httptest.NewRecorder()
+	handler.Categories(rec, httptest.NewRequest(http.MethodGet, "/allocation/categories", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"id":"stablecoins"`)
+}
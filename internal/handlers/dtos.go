@@ -1,14 +1,19 @@
 package handlers
 
 import (
+	"time"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/alerts"
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/reconcile"
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
 )
 
 // price handler dtos
 type PricesRequest struct {
-	Assets []AssetRequest `json:"assets"`
+	Assets            []AssetRequest `json:"assets"`
+	IncludeMarketData bool           `json:"include_market_data"`
 }
 
 type AssetRequest struct {
@@ -24,7 +29,16 @@ func (r AssetRequest) ToAssetRef() pricing.AssetRef {
 }
 
 type PricesResponse struct {
-	Prices map[string]float64 `json:"prices"`
+	Prices     map[string]float64            `json:"prices"`
+	MarketData map[string]pricing.MarketData `json:"market_data,omitempty"`
+}
+
+type OHLCResponse struct {
+	Chain           string           `json:"chain"`
+	ContractAddress string           `json:"contract_address"`
+	Interval        string           `json:"interval"`
+	Range           string           `json:"range"`
+	Candles         []pricing.Candle `json:"candles"`
 }
 
 type PriceAPIResponse struct {
@@ -33,6 +47,95 @@ type PriceAPIResponse struct {
 	Error   *ErrorResponse `json:"error,omitempty"`
 }
 
+// price override dtos
+type SetPriceOverrideRequest struct {
+	Chain           string     `json:"chain"`
+	ContractAddress string     `json:"contract_address"`
+	PriceUSD        float64    `json:"price_usd"`
+	Note            string     `json:"note,omitempty"`
+	ExpiresAt       *time.Time `json:"expires_at,omitempty"`
+}
+
+// allocation dtos
+type AssignCategoryRequest struct {
+	Chain           string `json:"chain"`
+	ContractAddress string `json:"contract_address"`
+	Category        string `json:"category"`
+}
+
+// rebalance dtos
+type SetTargetRequest struct {
+	Weights      []TargetWeightRequest `json:"weights"`
+	TolerancePct float64               `json:"tolerance_pct"`
+	CashAsset    *AssetRequest         `json:"cash_asset,omitempty"`
+}
+
+type TargetWeightRequest struct {
+	Chain           string  `json:"chain"`
+	ContractAddress string  `json:"contract_address"`
+	WeightPct       float64 `json:"weight_pct"`
+}
+
+// reconcile dtos
+type AcceptOnChainRequest struct {
+	Assets       []AssetRequest `json:"assets,omitempty"` // every flagged asset when empty
+	TolerancePct *float64       `json:"tolerance_pct,omitempty"`
+}
+
+type AcceptOnChainResponse struct {
+	Fixed []reconcile.Line `json:"fixed"`
+}
+
+// spam dtos
+type SetSpamOverrideRequest struct {
+	Chain           string `json:"chain"`
+	ContractAddress string `json:"contract_address"`
+	Spam            *bool  `json:"spam"`
+}
+
+// alert handler dtos
+type AlertRuleRequest struct {
+	Kind            alerts.RuleKind `json:"kind"`
+	Chain           string          `json:"chain"`
+	ContractAddress string          `json:"contract_address"`
+	Wallet          string          `json:"wallet,omitempty"` // drawdown and concentration rules
+	Threshold       float64         `json:"threshold"`
+	WindowMinutes   int             `json:"window_minutes,omitempty"`
+	WebhookURL      string          `json:"webhook_url"`
+	CooldownMinutes *int            `json:"cooldown_minutes,omitempty"` // defaults to 60, 0 never waits
+	Enabled         *bool           `json:"enabled,omitempty"`          // defaults to true
+}
+
+func (r AlertRuleRequest) ToRule() alerts.Rule {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+
+	rule := alerts.Rule{
+		Kind:            r.Kind,
+		Wallet:          r.Wallet,
+		Threshold:       r.Threshold,
+		WindowMinutes:   r.WindowMinutes,
+		WebhookURL:      r.WebhookURL,
+		CooldownMinutes: r.CooldownMinutes,
+		Enabled:         enabled,
+	}
+	if r.Chain != "" {
+		rule.Asset = &pricing.AssetRef{
+			Chain:           r.Chain,
+			ContractAddress: r.ContractAddress,
+		}
+	}
+	return rule
+}
+
+// token handler dtos
+type ImportTokenListResponse struct {
+	Name     string `json:"name"`
+	Imported int    `json:"imported"`
+}
+
 // transaction handler dtos
 type TransactionListResponse struct {
 	Success bool `json:"success"`
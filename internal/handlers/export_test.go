@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/export"
+)
+
+type mockExportService struct {
+	query export.Query
+}
+
+func (m *mockExportService) Export(ctx context.Context, w io.Writer, wallet string, q export.Query) error {
+	m.query = q
+	_, err := io.WriteString(w, "date,hash\n")
+	return err
+}
+
+func exportRouter(svc export.ServiceAPI) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/wallets/{wallet}/transactions/export", NewExportHandler(svc, zap.NewNop()).Export)
+	return r
+}
+
+func TestExportHandler_Export(t *testing.T) {
+	svc := &mockExportService{}
+
+	rec := httptest.NewRecorder()
+	exportRouter(svc).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/0xabc/transactions/export?format=koinly&from=2025-01-01T00:00:00Z", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+	require.Contains(t, rec.Header().Get("Content-Disposition"), "transactions-0xabc-koinly.csv")
+	require.Equal(t, "date,hash\n", rec.Body.String())
+
+	require.Equal(t, "ethereum", svc.query.Chain)
+	require.Equal(t, export.FormatKoinly, svc.query.Format)
+	require.NotNil(t, svc.query.From)
+	require.Nil(t, svc.query.To)
+}
+
+func TestExportHandler_ExportInvalid(t *testing.T) {
+	for _, query := range []string{"?format=xlsx", "?chain=solana", "?to=today"} {
+		svc := &mockExportService{}
+
+		rec := httptest.NewRecorder()
+		exportRouter(svc).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/0xabc/transactions/export"+query, nil))
+
+		require.Equal(t, http.StatusBadRequest, rec.Code, query)
+		require.Empty(t, svc.query.Chain, query)
+	}
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/importer"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+)
+
+// maxImportBytes caps the CSV body of a holdings import
+const maxImportBytes = 5 << 20
+
+type ImportHandler struct {
+	service importer.ServiceAPI
+	logger  *zap.Logger
+}
+
+func NewImportHandler(service importer.ServiceAPI, logger *zap.Logger) *ImportHandler {
+	return &ImportHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Import godoc
+// @Summary Import holdings from CSV
+// @Description Reads holdings from a CSV body and applies them in one save. preset picks the column layout (generic, binance, kraken or coinbase) and col_* names override its columns. Rows without a contract address are resolved by symbol on their chain. Every row is validated first; when any row fails, or dry_run is set, nothing is applied and the result lists what would change. mode=add adds amounts to recorded holdings instead of replacing them.
+// @Tags Portfolio
+// @Accept text/csv
+// @Produce json
+// @Param wallet path string true "Wallet address"
+// @Param preset query string false "generic | binance | kraken | coinbase" default(generic)
+// @Param chain query string false "Chain of rows without one" default(ethereum)
+// @Param mode query string false "set | add" default(set)
+// @Param dry_run query bool false "Validate without applying"
+// @Param col_chain query string false "Chain column"
+// @Param col_contract query string false "Contract address column"
+// @Param col_symbol query string false "Symbol column"
+// @Param col_amount query string false "Amount column"
+// @Param col_fee query string false "Fee column, ledger presets only"
+// @Param file body string true "CSV"
+// @Success 200 {object} importer.Result
+// @Failure 400 {object} handlers.ErrorResponse
+// @Failure 503 {object} handlers.ErrorResponse
+// @Router /wallets/{wallet}/portfolio/import [post]
+func (h *ImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	wallet := chi.URLParam(r, "wallet")
+	q := r.URL.Query()
+
+	opts := importer.Options{
+		Preset:       q.Get("preset"),
+		DefaultChain: q.Get("chain"),
+		Mode:         importer.Mode(q.Get("mode")),
+		Mapping: importer.Mapping{
+			Chain:    q.Get("col_chain"),
+			Contract: q.Get("col_contract"),
+			Symbol:   q.Get("col_symbol"),
+			Amount:   q.Get("col_amount"),
+			Fee:      q.Get("col_fee"),
+		},
+	}
+	if v := q.Get("dry_run"); v != "" {
+		dryRun, err := strconv.ParseBool(v)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "INVALID_PARAMS", "dry_run must be true or false")
+			return
+		}
+		opts.DryRun = dryRun
+	}
+
+	body := http.MaxBytesReader(w, r.Body, maxImportBytes)
+	res, err := h.service.Import(r.Context(), wallet, body, opts)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.Is(err, importer.ErrUnknownPreset):
+			RespondError(w, http.StatusBadRequest, "INVALID_PRESET", "preset must be generic, binance, kraken or coinbase")
+		case errors.Is(err, importer.ErrInvalidMode):
+			RespondError(w, http.StatusBadRequest, "INVALID_MODE", "mode must be set or add")
+		case errors.Is(err, importer.ErrUnsupportedChain):
+			RespondError(w, http.StatusBadRequest, "UNSUPPORTED_CHAIN", err.Error())
+		case errors.Is(err, importer.ErrInvalidFile), errors.Is(err, importer.ErrMissingColumn):
+			RespondError(w, http.StatusBadRequest, "INVALID_FILE", err.Error())
+		case errors.As(err, &tooLarge):
+			RespondError(w, http.StatusRequestEntityTooLarge, "FILE_TOO_LARGE", "CSV must be at most 5 MB")
+		case errors.Is(err, tokens.ErrSearchNotReady):
+			RespondError(w, http.StatusServiceUnavailable, "SEARCH_NOT_READY", "token list is still loading, retry shortly")
+		default:
+			h.logger.Error("import-holdings-failed",
+				zap.String("wallet", wallet),
+				zap.Error(err),
+			)
+			RespondError(w, http.StatusInternalServerError, "IMPORT_FAILED", "failed to import holdings")
+		}
+		return
+	}
+
+	RespondOK(w, http.StatusOK, res)
+}
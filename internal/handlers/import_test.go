@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/importer"
+)
+
+type mockImportService struct {
+	body string
+	opts importer.Options
+	err  error
+}
+
+func (m *mockImportService) Import(ctx context.Context, wallet string, r io.Reader, opts importer.Options) (*importer.Result, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	m.body = string(b)
+	m.opts = opts
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &importer.Result{Wallet: wallet, DryRun: opts.DryRun, Holdings: []importer.Line{}, Errors: []importer.RowError{}}, nil
+}
+
+func importRouter(svc importer.ServiceAPI) http.Handler {
+	r := chi.NewRouter()
+	r.Post("/wallets/{wallet}/portfolio/import", NewImportHandler(svc, zap.NewNop()).Import)
+	return r
+}
+
+func TestImportHandler_Import(t *testing.T) {
+	svc := &mockImportService{}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost,
+		"/wallets/0xabc/portfolio/import?preset=kraken&mode=add&dry_run=true&col_amount=qty",
+		strings.NewReader("asset,qty\nXETH,1\n"),
+	)
+	importRouter(svc).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "asset,qty\nXETH,1\n", svc.body)
+	require.Equal(t, "kraken", svc.opts.Preset)
+	require.Equal(t, importer.ModeAdd, svc.opts.Mode)
+	require.True(t, svc.opts.DryRun)
+	require.Equal(t, "qty", svc.opts.Mapping.Amount)
+
+	var resp struct {
+		Data importer.Result `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	require.Equal(t, "0xabc", resp.Data.Wallet)
+	require.True(t, resp.Data.DryRun)
+}
+
+func TestImportHandler_ImportInvalid(t *testing.T) {
+	rec := httptest.NewRecorder()
+	importRouter(&mockImportService{}).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/wallets/0xabc/portfolio/import?dry_run=maybe", strings.NewReader("")))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	for _, err := range []error{importer.ErrUnknownPreset, importer.ErrMissingColumn, importer.ErrInvalidFile} {
+		rec := httptest.NewRecorder()
+		importRouter(&mockImportService{err: err}).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/wallets/0xabc/portfolio/import", strings.NewReader("")))
+		require.Equal(t, http.StatusBadRequest, rec.Code, err.Error())
+	}
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/nft"
+)
+
+type NFTHandler struct {
+	service nft.ServiceAPI
+	logger  *zap.Logger
+}
+
+func NewNFTHandler(service nft.ServiceAPI, logger *zap.Logger) *NFTHandler {
+	return &NFTHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Sync godoc
+// @Summary Sync NFT holdings
+// @Description Nets the wallet's ERC-721 and ERC-1155 transfers on one chain per token and replaces its NFT holdings there with the tokens it still holds. NFTs on other chains and fungible holdings are left alone. The portfolio values NFTs at their collection's floor price.
+// @Tags Portfolio
+// @Produce json
+// @Param wallet path string true "Wallet address"
+// @Param chain query string false "Chain name or ID" default(ethereum)
+// @Success 200 {object} nft.Result
+// @Failure 400 {object} handlers.ErrorResponse
+// @Failure 500 {object} handlers.ErrorResponse
+// @Router /wallets/{wallet}/portfolio/nfts/sync [post]
+func (h *NFTHandler) Sync(w http.ResponseWriter, r *http.Request) {
+	wallet := chi.URLParam(r, "wallet")
+
+	res, err := h.service.Sync(r.Context(), wallet, r.URL.Query().Get("chain"))
+	if err != nil {
+		switch {
+		case errors.Is(err, nft.ErrInvalidAddress):
+			RespondError(w, http.StatusBadRequest, "INVALID_WALLET", "wallet must be a 0x address")
+		case errors.Is(err, nft.ErrUnsupportedChain):
+			RespondError(w, http.StatusBadRequest, "INVALID_PARAMS", err.Error())
+		default:
+			h.logger.Error("sync-nfts-failed",
+				zap.String("wallet", wallet),
+				zap.Error(err),
+			)
+			RespondError(w, http.StatusInternalServerError, "NFT_SYNC_FAILED", "failed to sync NFTs")
+		}
+		return
+	}
+
+	RespondOK(w, http.StatusOK, res)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/nft"
+)
+
+type mockNFTService struct {
+	err   error
+	chain string
+}
+
+func (m *mockNFTService) Sync(ctx context.Context, wallet string, chain string) (*nft.Result, error) {
+	m.chain = chain
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &nft.Result{Wallet: wallet, Chain: chain}, nil
+}
+
+func nftRouter(svc nft.ServiceAPI) http.Handler {
+	r := chi.NewRouter()
+	r.Post("/wallets/{wallet}/portfolio/nfts/sync", NewNFTHandler(svc, zap.NewNop()).Sync)
+	return r
+}
+
+func TestNFTHandler_Sync(t *testing.T) {
+	for _, tc := range []struct {
+		err    error
+		status int
+	}{
+		{nil, http.StatusOK},
+		{nft.ErrInvalidAddress, http.StatusBadRequest},
+		{nft.ErrUnsupportedChain, http.StatusBadRequest},
+	} {
+		svc := &mockNFTService{err: tc.err}
+		rec := httptest.NewRecorder()
+		nftRouter(svc).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/wallets/0xabc/portfolio/nfts/sync?chain=polygon", nil))
+		require.Equal(t, tc.status, rec.Code)
+		require.Equal(t, "polygon", svc.chain)
+	}
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/performance"
+)
+
+type PerformanceHandler struct {
+	service performance.ServiceAPI
+	logger  *zap.Logger
+}
+
+func NewPerformanceHandler(service performance.ServiceAPI, logger *zap.Logger) *PerformanceHandler {
+	return &PerformanceHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetPerformance godoc
+// @Summary Portfolio performance
+// @Description Time-weighted and money-weighted (XIRR) returns, volatility, max drawdown and Sharpe ratio from daily portfolio snapshots, with native transfers in and out of the wallet as cash flows, compared against a benchmark asset
+// @Tags Portfolio
+// @Produce json
+// @Param wallet path string true "Wallet address"
+// @Param period query string false "7d | 30d | 90d | 1y | ytd" default(30d)
+// @Param benchmark query string false "ETH, BTC or chain:contract" default(ETH)
+// @Success 200 {object} performance.Report
+// @Failure 400 {object} handlers.ErrorResponse
+// @Failure 404 {object} handlers.ErrorResponse
+// @Router /wallets/{wallet}/portfolio/performance [get]
+func (h *PerformanceHandler) Get(w http.ResponseWriter, r *http.Request) {
+	wallet := chi.URLParam(r, "wallet")
+
+	period, err := performance.ParsePeriod(r.URL.Query().Get("period"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "INVALID_PERIOD", "period must be 7d, 30d, 90d, 1y or ytd")
+		return
+	}
+
+	benchmark, err := performance.ParseBenchmark(r.URL.Query().Get("benchmark"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "INVALID_BENCHMARK", "benchmark must be ETH, BTC or chain:contract")
+		return
+	}
+
+	report, err := h.service.Report(r.Context(), wallet, period, benchmark)
+	if err != nil {
+		if errors.Is(err, performance.ErrInsufficientHistory) {
+			RespondError(w, http.StatusNotFound, "INSUFFICIENT_HISTORY", "not enough portfolio snapshots in the period")
+			return
+		}
+		h.logger.Error("get-performance-failed", zap.Error(err))
+		RespondError(w, http.StatusInternalServerError, "PERFORMANCE_FAILED", "failed to compute performance")
+		return
+	}
+
+	RespondOK(w, http.StatusOK, report)
+}
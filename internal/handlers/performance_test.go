@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/performance"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+type mockPerformanceService struct {
+	period    performance.Period
+	benchmark pricing.AssetRef
+	err       error
+}
+
+func (m *mockPerformanceService) Report(ctx context.Context, wallet string, period performance.Period, benchmark pricing.AssetRef) (*performance.Report, error) {
+	m.period, m.benchmark = period, benchmark
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &performance.Report{Wallet: wallet, Period: period}, nil
+}
+
+func TestPerformanceHandler_Get(t *testing.T) {
+	svc := &mockPerformanceService{}
+	handler := NewPerformanceHandler(svc, zap.NewNop())
+	r := chi.NewRouter()
+	r.Get("/wallets/{wallet}/portfolio/performance", handler.Get)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/0xabc/portfolio/performance?period=90d&benchmark=btc", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, performance.Period90d, svc.period)
+	require.Equal(t, "0x2260fac5e5542a773aa44fbcfedf7c193bc2c599", svc.benchmark.ContractAddress)
+	require.Contains(t, rec.Body.String(), `"time_weighted_return_pct":0`)
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/0xabc/portfolio/performance?period=2w", nil))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	svc.err = performance.ErrInsufficientHistory
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/0xabc/portfolio/performance", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
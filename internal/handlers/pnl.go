@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pnl"
+)
+
+type PnLHandler struct {
+	service pnl.ServiceAPI
+	logger  *zap.Logger
+}
+
+func NewPnLHandler(service pnl.ServiceAPI, logger *zap.Logger) *PnLHandler {
+	return &PnLHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetRealizedPnL godoc
+// @Summary Realized profit and loss
+// @Description Replays the wallet's transfers on a chain through acquisition lots. Transfers in open lots; transfers out and swaps are disposals matched against them with the chosen method. Every leg is priced at its historical price. Gains are summed per asset and per period for disposals between from and to.
+// @Tags PnL
+// @Produce json
+// @Param wallet path string true "Wallet address"
+// @Param chain query string false "Chain" default(ethereum)
+// @Param method query string false "fifo | lifo | hifo" default(fifo)
+// @Param group_by query string false "month | quarter | year" default(month)
+// @Param from query string false "Disposals from, RFC3339"
+// @Param to query string false "Disposals up to, RFC3339"
+// @Success 200 {object} pnl.Report
+// @Failure 400 {object} handlers.ErrorResponse
+// @Failure 500 {object} handlers.ErrorResponse
+// @Router /wallets/{wallet}/pnl [get]
+func (h *PnLHandler) Get(w http.ResponseWriter, r *http.Request) {
+	wallet := chi.URLParam(r, "wallet")
+
+	q, ok := parsePnLQuery(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.Realized(r.Context(), wallet, q)
+	if err != nil {
+		h.logger.Error("get-realized-pnl-failed", zap.Error(err))
+		if errors.Is(err, pnl.ErrUnsupportedChain) {
+			RespondError(w, http.StatusBadRequest, "UNSUPPORTED_CHAIN", err.Error())
+			return
+		}
+		RespondError(w, http.StatusInternalServerError, "PNL_FAILED", "failed to compute realized pnl")
+		return
+	}
+
+	RespondOK(w, http.StatusOK, report)
+}
+
+// parsePnLQuery reads the chain, method, grouping and window, responding
+// with 400 when one is invalid
+func parsePnLQuery(w http.ResponseWriter, r *http.Request) (pnl.Query, bool) {
+	q := pnl.Query{Chain: r.URL.Query().Get("chain")}
+	if q.Chain == "" {
+		q.Chain = "ethereum"
+	}
+
+	method, err := pnl.ParseMethod(r.URL.Query().Get("method"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "INVALID_METHOD", "method must be fifo, lifo or hifo")
+		return q, false
+	}
+	q.Method = method
+
+	groupBy, err := pnl.ParseGrouping(r.URL.Query().Get("group_by"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "INVALID_GROUPING", "group_by must be month, quarter or year")
+		return q, false
+	}
+	q.GroupBy = groupBy
+
+	for _, p := range []struct {
+		name string
+		dst  **time.Time
+	}{{"from", &q.From}, {"to", &q.To}} {
+		v := r.URL.Query().Get(p.name)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "INVALID_DATE", p.name+" must be RFC3339")
+			return q, false
+		}
+		*p.dst = &t
+	}
+	if q.From != nil && q.To != nil && q.From.After(*q.To) {
+		RespondError(w, http.StatusBadRequest, "INVALID_DATE", "from must be before to")
+		return q, false
+	}
+
+	return q, true
+}
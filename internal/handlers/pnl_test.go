@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pnl"
+)
+
+type mockPnLService struct {
+	query pnl.Query
+	err   error
+}
+
+func (m *mockPnLService) Realized(ctx context.Context, wallet string, q pnl.Query) (*pnl.Report, error) {
+	m.query = q
+	return &pnl.Report{Wallet: wallet, Method: q.Method}, m.err
+}
+
+func TestPnLHandler_Get(t *testing.T) {
+	svc := &mockPnLService{}
+	r := chi.NewRouter()
+	r.Get("/wallets/{wallet}/pnl", NewPnLHandler(svc, zap.NewNop()).Get)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/0xabc/pnl?method=hifo&group_by=year&from=2025-01-01T00:00:00Z", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "ethereum", svc.query.Chain)
+	require.Equal(t, pnl.MethodHIFO, svc.query.Method)
+	require.Equal(t, pnl.GroupYear, svc.query.GroupBy)
+	require.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), *svc.query.From)
+	require.Nil(t, svc.query.To)
+}
+
+func TestPnLHandler_GetInvalid(t *testing.T) {
+	cases := []struct {
+		name  string
+		query string
+		err   error
+	}{
+		{"method", "?method=average", nil},
+		{"grouping", "?group_by=week", nil},
+		{"date", "?to=yesterday", nil},
+		{"window", "?from=2025-02-01T00:00:00Z&to=2025-01-01T00:00:00Z", nil},
+		{"chain", "?chain=solana", pnl.ErrUnsupportedChain},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := chi.NewRouter()
+			r.Get("/wallets/{wallet}/pnl", NewPnLHandler(&mockPnLService{err: tc.err}, zap.NewNop()).Get)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/0xabc/pnl"+tc.query, nil))
+
+			require.Equal(t, http.StatusBadRequest, rec.Code)
+		})
+	}
+}
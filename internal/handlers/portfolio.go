@@ -3,6 +3,8 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
@@ -27,17 +29,47 @@ func NewPortfolioHandler(
 
 // GetPortfolio godoc
 // @Summary Get portfolio
-// @Description Fetch wallet portfolio with live valuation
+// @Description Fetch wallet portfolio with live valuation, or as it was at as_of using rebuilt holdings and historical prices. Each token holding carries its spam verdict; hide_spam leaves spam holdings out of the holdings and totals.
 // @Tags Portfolio
 // @Produce json
 // @Param wallet path string true "Wallet address"
+// @Param as_of query string false "Past time to value the portfolio at (RFC3339)"
+// @Param hide_spam query bool false "Leave holdings judged spam out"
 // @Success 200 {object} handlers.PortfolioResponse
+// @Failure 400 {object} handlers.ErrorResponse
 // @Failure 404 {object} handlers.ErrorResponse
 // @Router /wallets/{wallet}/portfolio [get]
 func (h *PortfolioHandler) Get(w http.ResponseWriter, r *http.Request) {
 	wallet := chi.URLParam(r, "wallet")
 
-	portfolio, err := h.service.Get(r.Context(), wallet)
+	hideSpam := false
+	if v := r.URL.Query().Get("hide_spam"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "INVALID_PARAMS", "hide_spam must be a boolean")
+			return
+		}
+		hideSpam = parsed
+	}
+
+	var (
+		view *portfolio.PortfolioView
+		err  error
+	)
+	if v := r.URL.Query().Get("as_of"); v != "" {
+		asOf, parseErr := time.Parse(time.RFC3339, v)
+		if parseErr != nil {
+			RespondError(w, http.StatusBadRequest, "INVALID_DATE", "as_of must be RFC3339")
+			return
+		}
+		if asOf.After(time.Now()) {
+			RespondError(w, http.StatusBadRequest, "INVALID_DATE", "as_of must not be in the future")
+			return
+		}
+		view, err = h.service.GetAsOf(r.Context(), wallet, asOf.UTC())
+	} else {
+		view, err = h.service.Get(r.Context(), wallet)
+	}
 	if err != nil {
 		h.logger.Error("get-portfolio-failed", zap.Error(err))
 		RespondError(
@@ -48,8 +80,11 @@ func (h *PortfolioHandler) Get(w http.ResponseWriter, r *http.Request) {
 		)
 		return
 	}
+	if hideSpam {
+		view.HideSpam()
+	}
 
-	RespondOK(w, http.StatusOK, portfolio)
+	RespondOK(w, http.StatusOK, view)
 }
 
 // AddHolding godoc
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/snapshots"
+)
+
+// defaultHistoryRange is served when no from date is given
+const defaultHistoryRange = 30 * 24 * time.Hour
+
+type PortfolioHistoryHandler struct {
+	service snapshots.ServiceAPI
+	logger  *zap.Logger
+}
+
+func NewPortfolioHistoryHandler(service snapshots.ServiceAPI, logger *zap.Logger) *PortfolioHistoryHandler {
+	return &PortfolioHistoryHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetPortfolioHistory godoc
+// @Summary Portfolio value history
+// @Description Recorded portfolio snapshots (holdings, prices and total) for value-over-time charts. Downsampled series keep the last snapshot of each hour or day.
+// @Tags Portfolio
+// @Produce json
+// @Param wallet path string true "Wallet address"
+// @Param from query string false "Start date RFC3339 (default 30 days before to)"
+// @Param to query string false "End date RFC3339 (default now)"
+// @Param resolution query string false "raw | hourly | daily" default(raw)
+// @Success 200 {array} snapshots.Snapshot
+// @Failure 400 {object} handlers.ErrorResponse
+// @Router /wallets/{wallet}/portfolio/history [get]
+func (h *PortfolioHistoryHandler) Get(w http.ResponseWriter, r *http.Request) {
+	wallet := chi.URLParam(r, "wallet")
+
+	resolution, err := snapshots.ParseResolution(r.URL.Query().Get("resolution"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "INVALID_RESOLUTION", "resolution must be raw, hourly or daily")
+		return
+	}
+
+	to := time.Now().UTC()
+	if v := r.URL.Query().Get("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "INVALID_DATE", "to must be RFC3339")
+			return
+		}
+		to = t
+	}
+
+	from := to.Add(-defaultHistoryRange)
+	if v := r.URL.Query().Get("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "INVALID_DATE", "from must be RFC3339")
+			return
+		}
+		from = t
+	}
+
+	if from.After(to) {
+		RespondError(w, http.StatusBadRequest, "INVALID_DATE", "from must be before to")
+		return
+	}
+
+	history, err := h.service.History(r.Context(), wallet, from, to, resolution)
+	if err != nil {
+		h.logger.Error("get-portfolio-history-failed", zap.Error(err))
+		RespondError(w, http.StatusInternalServerError, "HISTORY_FAILED", "failed to fetch portfolio history")
+		return
+	}
+
+	RespondOK(w, http.StatusOK, history)
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/snapshots"
+)
+
+type mockSnapshotService struct {
+	from, to   time.Time
+	resolution snapshots.Resolution
+}
+
+func (m *mockSnapshotService) History(ctx context.Context, wallet string, from, to time.Time, resolution snapshots.Resolution) ([]snapshots.Snapshot, error) {
+	m.from, m.to, m.resolution = from, to, resolution
+	return []snapshots.Snapshot{{Wallet: wallet, TotalValueUSD: 100}}, nil
+}
+
+func TestPortfolioHistoryHandler_Get(t *testing.T) {
+	svc := &mockSnapshotService{}
+	handler := NewPortfolioHistoryHandler(svc, zap.NewNop())
+	r := chi.NewRouter()
+	r.Get("/wallets/{wallet}/portfolio/history", handler.Get)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/0xabc/portfolio/history?resolution=daily&to=2025-03-31T00:00:00Z", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, snapshots.ResolutionDaily, svc.resolution)
+	require.Equal(t, 30*24*time.Hour, svc.to.Sub(svc.from))
+	require.Contains(t, rec.Body.String(), `"total_value_usd":100`)
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/0xabc/portfolio/history?resolution=weekly", nil))
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -6,7 +6,9 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/require"
@@ -14,10 +16,12 @@ import (
 
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/handlers"
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/spam"
 )
 
 type mockPortfolioService struct {
 	view *portfolio.PortfolioView
+	asOf *time.Time
 }
 
 type PortfolioResponseTest struct {
@@ -29,6 +33,15 @@ func (m *mockPortfolioService) Get(ctx context.Context, wallet string) (*portfol
 	return m.view, nil
 }
 
+func (m *mockPortfolioService) GetAsOf(ctx context.Context, wallet string, asOf time.Time) (*portfolio.PortfolioView, error) {
+	m.asOf = &asOf
+	return m.view, nil
+}
+
+func (m *mockPortfolioService) Holdings(ctx context.Context, wallet string) ([]portfolio.Holding, error) {
+	return nil, nil
+}
+
 func (m *mockPortfolioService) AddHolding(ctx context.Context, wallet string, h portfolio.Holding) error {
 	return nil
 }
@@ -41,6 +54,22 @@ func (m *mockPortfolioService) RemoveHolding(ctx context.Context, wallet, chain,
 	return nil
 }
 
+func (m *mockPortfolioService) SetHoldings(ctx context.Context, wallet string, hs []portfolio.Holding) error {
+	return nil
+}
+
+func (m *mockPortfolioService) SyncHoldings(ctx context.Context, wallet string, hs []portfolio.Holding, syncedAt time.Time) error {
+	return nil
+}
+
+func (m *mockPortfolioService) SyncNFTs(ctx context.Context, wallet string, chain string, hs []portfolio.NFTHolding) error {
+	return nil
+}
+
+func (m *mockPortfolioService) Wallets(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
 func setupRouter(svc portfolio.Service) http.Handler {
 	r := chi.NewRouter()
 	h := handlers.NewPortfolioHandler(svc, zap.NewNop())
@@ -75,6 +104,53 @@ func TestGetPortfolioHandler(t *testing.T) {
 	require.Equal(t, 1000.0, resp.Data.TotalValueUSD)
 }
 
+func TestGetPortfolioHandler_HideSpam(t *testing.T) {
+	view := &portfolio.PortfolioView{
+		Wallet: "wallet1",
+		Holdings: []portfolio.HoldingView{
+			{Chain: "ethereum", ValueUSD: 1000},
+			{Chain: "ethereum", ContractAddress: "0xscam", ValueUSD: 50, Spam: &spam.Verdict{Spam: true, Reason: spam.ReasonDenyList}},
+		},
+		TotalValueUSD: 1050,
+	}
+	router := setupRouter(&mockPortfolioService{view: view})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/wallet1/portfolio?hide_spam=true", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp PortfolioResponseTest
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	require.Len(t, resp.Data.Holdings, 1)
+	require.Equal(t, 1000.0, resp.Data.TotalValueUSD)
+	require.Equal(t, 1, resp.Data.HiddenSpam)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/wallet1/portfolio?hide_spam=maybe", nil))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestGetPortfolioHandler_AsOf(t *testing.T) {
+	svc := &mockPortfolioService{view: &portfolio.PortfolioView{Wallet: "wallet1"}}
+	router := setupRouter(svc)
+
+	req := httptest.NewRequest(http.MethodGet, "/wallets/wallet1/portfolio?as_of=2024-03-31T23:59:59Z", nil)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, svc.asOf)
+	require.Equal(t, time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC), *svc.asOf)
+
+	for _, asOf := range []string{"yesterday", time.Now().Add(time.Hour).Format(time.RFC3339)} {
+		req := httptest.NewRequest(http.MethodGet, "/wallets/wallet1/portfolio?as_of="+url.QueryEscape(asOf), nil)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusBadRequest, rec.Code, asOf)
+	}
+}
+
 func TestAddHoldingHandler(t *testing.T) {
 	svc := &mockPortfolioService{}
 	router := setupRouter(svc)
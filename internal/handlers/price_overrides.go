@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+type PriceOverridesHandler struct {
+	service pricing.OverrideServiceAPI
+	logger  *zap.Logger
+}
+
+func NewPriceOverridesHandler(
+	service pricing.OverrideServiceAPI,
+	logger *zap.Logger,
+) *PriceOverridesHandler {
+	return &PriceOverridesHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ListOverrides godoc
+// @Summary List price overrides
+// @Description List manual price overrides, including expired ones
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Success 200 {array} pricing.Override
+// @Failure 401 {object} handlers.ErrorResponse
+// @Router /admin/prices/overrides [get]
+func (h *PriceOverridesHandler) List(w http.ResponseWriter, r *http.Request) {
+	overrides, err := h.service.ListOverrides(r.Context())
+	if err != nil {
+		h.logger.Error("list-price-overrides-failed", zap.Error(err))
+		RespondError(w, http.StatusInternalServerError, "OVERRIDES_FAILED", "failed to list price overrides")
+		return
+	}
+
+	RespondOK(w, http.StatusOK, overrides)
+}
+
+// SetOverride godoc
+// @Summary Set price override
+// @Description Set a manual USD price for an asset, with optional expiry and note. It takes precedence over cached and provider prices.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param override body handlers.SetPriceOverrideRequest true "Override"
+// @Success 200 {object} pricing.Override
+// @Failure 400 {object} handlers.ErrorResponse
+// @Failure 401 {object} handlers.ErrorResponse
+// @Router /admin/prices/overrides [put]
+func (h *PriceOverridesHandler) Set(w http.ResponseWriter, r *http.Request) {
+	var req SetPriceOverrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "INVALID_JSON", "invalid request body")
+		return
+	}
+
+	override, err := h.service.SetOverride(r.Context(), pricing.Override{
+		Asset: pricing.AssetRef{
+			Chain:           req.Chain,
+			ContractAddress: req.ContractAddress,
+		},
+		PriceUSD:  req.PriceUSD,
+		Note:      req.Note,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		h.logger.Error("set-price-override-failed", zap.Error(err))
+		if errors.Is(err, pricing.ErrInvalidOverride) {
+			RespondError(w, http.StatusBadRequest, "INVALID_OVERRIDE", "chain is required, price must be >= 0 and expiry in the future")
+			return
+		}
+		RespondError(w, http.StatusInternalServerError, "OVERRIDES_FAILED", "failed to set price override")
+		return
+	}
+
+	RespondOK(w, http.StatusOK, override)
+}
+
+// DeleteOverride godoc
+// @Summary Delete price override
+// @Tags Admin
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param chain query string true "Chain"
+// @Param contract query string false "Contract address"
+// @Success 200
+// @Failure 401 {object} handlers.ErrorResponse
+// @Failure 404 {object} handlers.ErrorResponse
+// @Router /admin/prices/overrides [delete]
+func (h *PriceOverridesHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	chain := r.URL.Query().Get("chain")
+	contract := r.URL.Query().Get("contract")
+
+	if chain == "" {
+		RespondError(w, http.StatusBadRequest, "MISSING_PARAMS", "chain is required")
+		return
+	}
+
+	err := h.service.DeleteOverride(r.Context(), pricing.AssetRef{
+		Chain:           chain,
+		ContractAddress: contract,
+	})
+	if err != nil {
+		h.logger.Error("delete-price-override-failed", zap.Error(err))
+		if errors.Is(err, pricing.ErrOverrideNotFound) {
+			RespondError(w, http.StatusNotFound, "NOT_FOUND", "price override not found")
+			return
+		}
+		RespondError(w, http.StatusInternalServerError, "OVERRIDES_FAILED", "failed to delete price override")
+		return
+	}
+
+	RespondOK(w, http.StatusOK, nil)
+}
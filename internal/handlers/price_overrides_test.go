@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+type mockOverrideService struct {
+	saved pricing.Override
+	err   error
+}
+
+func (m *mockOverrideService) ListOverrides(ctx context.Context) ([]pricing.Override, error) {
+	return []pricing.Override{m.saved}, m.err
+}
+
+func (m *mockOverrideService) SetOverride(ctx context.Context, o pricing.Override) (pricing.Override, error) {
+	m.saved = o
+	return o, m.err
+}
+
+func (m *mockOverrideService) DeleteOverride(ctx context.Context, asset pricing.AssetRef) error {
+	return m.err
+}
+
+func TestPriceOverridesHandler_Set(t *testing.T) {
+	svc := &mockOverrideService{}
+	handler := NewPriceOverridesHandler(svc, zap.NewNop())
+
+	body := `{
+		"chain": "ethereum",
+		"contract_address": "0xvest",
+		"price_usd": 0.25,
+		"note": "seed round",
+		"expires_at": "2030-01-01T00:00:00Z"
+	}`
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/prices/overrides", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	handler.Set(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, 0.25, svc.saved.PriceUSD)
+	require.Equal(t, "0xvest", svc.saved.Asset.ContractAddress)
+	require.NotNil(t, svc.saved.ExpiresAt)
+}
+
+func TestPriceOverridesHandler_SetInvalid(t *testing.T) {
+	svc := &mockOverrideService{err: pricing.ErrInvalidOverride}
+	handler := NewPriceOverridesHandler(svc, zap.NewNop())
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/prices/overrides", bytes.NewBufferString(`{"price_usd": -1}`))
+	rec := httptest.NewRecorder()
+
+	handler.Set(rec, req)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestPriceOverridesHandler_DeleteNotFound(t *testing.T) {
+	svc := &mockOverrideService{err: pricing.ErrOverrideNotFound}
+	handler := NewPriceOverridesHandler(svc, zap.NewNop())
+
+	req := httptest.NewRequest(http.MethodDelete, "/admin/prices/overrides?chain=ethereum&contract=0xvest", nil)
+	rec := httptest.NewRecorder()
+
+	handler.Delete(rec, req)
+
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -2,8 +2,12 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
@@ -26,7 +30,7 @@ func NewPricesHandler(
 
 // GetPrices godoc
 // @Summary Get token prices
-// @Description Fetch USD prices for tokens by chain + contract address
+// @Description Fetch USD prices for tokens by chain + contract address, optionally with 24h change, volume, market cap and last-updated time
 // @Tags Prices
 // @Accept json
 // @Produce json
@@ -72,6 +76,11 @@ func (h *PricesHandler) GetPrices(w http.ResponseWriter, r *http.Request) {
 		assets = append(assets, a.ToAssetRef())
 	}
 
+	if req.IncludeMarketData {
+		h.getMarketData(w, r, assets)
+		return
+	}
+
 	prices, err := h.pricing.GetPrices(r.Context(), assets)
 	if err != nil {
 		h.logger.Error("pricing-failed", zap.Error(err))
@@ -89,9 +98,103 @@ func (h *PricesHandler) GetPrices(w http.ResponseWriter, r *http.Request) {
 	}
 
 	for asset, price := range prices {
-		key := asset.Chain + ":" + asset.ContractAddress
-		resp.Prices[key] = price
+		resp.Prices[assetKey(asset)] = price
+	}
+
+	RespondOK(w, http.StatusOK, resp)
+}
+
+func (h *PricesHandler) getMarketData(w http.ResponseWriter, r *http.Request, assets []pricing.AssetRef) {
+	market, err := h.pricing.GetMarketData(r.Context(), assets)
+	if err != nil {
+		h.logger.Error("market-data-failed", zap.Error(err))
+		RespondError(
+			w,
+			http.StatusInternalServerError,
+			"PRICING_FAILED",
+			"failed to fetch market data",
+		)
+		return
+	}
+
+	resp := PricesResponse{
+		Prices:     make(map[string]float64),
+		MarketData: make(map[string]pricing.MarketData),
+	}
+
+	for asset, md := range market {
+		key := assetKey(asset)
+		resp.Prices[key] = md.PriceUSD
+		resp.MarketData[key] = md
 	}
 
 	RespondOK(w, http.StatusOK, resp)
 }
+
+func assetKey(asset pricing.AssetRef) string {
+	return asset.Chain + ":" + asset.ContractAddress
+}
+
+// GetOHLC godoc
+// @Summary Get OHLC candles
+// @Description Fetch OHLC candles for a token, for charting
+// @Tags Prices
+// @Produce json
+// @Param chain path string true "Chain (ethereum)"
+// @Param contract path string true "Token contract address"
+// @Param interval query string false "Candle interval (1h | 4h | 1d)" default(1d)
+// @Param range query string false "Range in days, e.g. 7d (max 365d, 90d for intraday intervals)" default(30d)
+// @Success 200 {object} handlers.OHLCResponse
+// @Failure 400 {object} handlers.ErrorResponse
+// @Failure 500 {object} handlers.ErrorResponse
+// @Router /prices/{chain}/{contract}/ohlc [get]
+func (h *PricesHandler) GetOHLC(w http.ResponseWriter, r *http.Request) {
+	asset := pricing.AssetRef{
+		Chain:           chi.URLParam(r, "chain"),
+		ContractAddress: strings.ToLower(chi.URLParam(r, "contract")),
+	}
+
+	interval := pricing.Interval(r.URL.Query().Get("interval"))
+	if interval == "" {
+		interval = pricing.Interval1d
+	}
+
+	rangeParam := r.URL.Query().Get("range")
+	if rangeParam == "" {
+		rangeParam = "30d"
+	}
+	days, err := strconv.Atoi(strings.TrimSuffix(rangeParam, "d"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "INVALID_RANGE", "range must look like 7d")
+		return
+	}
+
+	if err := pricing.ValidateCandleRequest(interval, days); err != nil {
+		RespondError(w, http.StatusBadRequest, "INVALID_PARAMS", err.Error())
+		return
+	}
+
+	candles, err := h.pricing.GetCandles(r.Context(), asset, interval, days)
+	if err != nil {
+		h.logger.Error("candles-failed", zap.Error(err))
+		if errors.Is(err, pricing.ErrCandlesNotSupported) {
+			RespondError(w, http.StatusNotImplemented, "CANDLES_NOT_SUPPORTED", "no provider supports candles")
+			return
+		}
+		RespondError(
+			w,
+			http.StatusInternalServerError,
+			"PRICING_FAILED",
+			"failed to fetch candles",
+		)
+		return
+	}
+
+	RespondOK(w, http.StatusOK, OHLCResponse{
+		Chain:           asset.Chain,
+		ContractAddress: asset.ContractAddress,
+		Interval:        string(interval),
+		Range:           rangeParam,
+		Candles:         candles,
+	})
+}
@@ -8,7 +8,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/require"
 	"github.com/test-go/testify/assert"
 	"go.uber.org/zap"
@@ -17,8 +19,10 @@ import (
 )
 
 type mockPricingService struct {
-	result map[pricing.AssetRef]float64
-	err    error
+	result  map[pricing.AssetRef]float64
+	market  map[pricing.AssetRef]pricing.MarketData
+	candles []pricing.Candle
+	err     error
 }
 
 type pricesResponseTest struct {
@@ -33,6 +37,30 @@ func (m *mockPricingService) GetPrices(
 	return m.result, m.err
 }
 
+func (m *mockPricingService) GetMarketData(
+	ctx context.Context,
+	assets []pricing.AssetRef,
+) (map[pricing.AssetRef]pricing.MarketData, error) {
+	return m.market, m.err
+}
+
+func (m *mockPricingService) GetCandles(
+	ctx context.Context,
+	asset pricing.AssetRef,
+	interval pricing.Interval,
+	days int,
+) ([]pricing.Candle, error) {
+	return m.candles, m.err
+}
+
+func (m *mockPricingService) GetHistoricalPrices(
+	ctx context.Context,
+	assets []pricing.AssetRef,
+	at time.Time,
+) (map[pricing.AssetRef]pricing.HistoricalPrice, error) {
+	return nil, m.err
+}
+
 func TestPricesHandler_GetPrices_Success(t *testing.T) {
 	logger := zap.NewNop()
 
@@ -72,6 +100,50 @@ func TestPricesHandler_GetPrices_Success(t *testing.T) {
 	require.Equal(t, 123.45, resp.Data.Prices["ethereum:0xabc"])
 }
 
+func TestPricesHandler_GetPrices_WithMarketData(t *testing.T) {
+	logger := zap.NewNop()
+
+	asset := pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xabc"}
+	mockSvc := &mockPricingService{
+		market: map[pricing.AssetRef]pricing.MarketData{
+			asset: {
+				PriceUSD:     123.45,
+				Change24hPct: -2.5,
+				Volume24hUSD: 1_000_000,
+				MarketCapUSD: 50_000_000,
+			},
+		},
+	}
+
+	handler := NewPricesHandler(mockSvc, logger)
+
+	body := `{
+		"assets": [
+			{
+				"chain": "ethereum",
+				"contract_address": "0xabc"
+			}
+		],
+		"include_market_data": true
+	}`
+
+	req := httptest.NewRequest(http.MethodPost, "/prices", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	handler.GetPrices(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp pricesResponseTest
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	require.NoError(t, err)
+
+	require.Equal(t, 123.45, resp.Data.Prices["ethereum:0xabc"])
+	md := resp.Data.MarketData["ethereum:0xabc"]
+	require.Equal(t, -2.5, md.Change24hPct)
+	require.Equal(t, 50_000_000.0, md.MarketCapUSD)
+}
+
 func TestPricesHandler_GetPrices_InvalidJSON(t *testing.T) {
 	logger := zap.NewNop()
 	handler := NewPricesHandler(&mockPricingService{}, logger)
@@ -123,3 +195,47 @@ func TestPricesHandler_GetPrices_ServiceError(t *testing.T) {
 
 	require.Equal(t, http.StatusInternalServerError, rec.Code)
 }
+
+type ohlcResponseTest struct {
+	Success bool         `json:"success"`
+	Data    OHLCResponse `json:"data"`
+}
+
+func TestPricesHandler_GetOHLC_Success(t *testing.T) {
+	mockSvc := &mockPricingService{
+		candles: []pricing.Candle{
+			{Open: 1, High: 2, Low: 0.5, Close: 1.5},
+		},
+	}
+
+	handler := NewPricesHandler(mockSvc, zap.NewNop())
+	r := chi.NewRouter()
+	r.Get("/prices/{chain}/{contract}/ohlc", handler.GetOHLC)
+
+	req := httptest.NewRequest(http.MethodGet, "/prices/ethereum/0xABC/ohlc?interval=4h&range=7d", nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp ohlcResponseTest
+	err := json.Unmarshal(rec.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	require.Equal(t, "0xabc", resp.Data.ContractAddress)
+	require.Equal(t, "4h", resp.Data.Interval)
+	require.Len(t, resp.Data.Candles, 1)
+}
+
+func TestPricesHandler_GetOHLC_InvalidRange(t *testing.T) {
+	handler := NewPricesHandler(&mockPricingService{}, zap.NewNop())
+	r := chi.NewRouter()
+	r.Get("/prices/{chain}/{contract}/ohlc", handler.GetOHLC)
+
+	req := httptest.NewRequest(http.MethodGet, "/prices/ethereum/0xabc/ohlc?interval=1h&range=365d", nil)
+	rec := httptest.NewRecorder()
+
+	r.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
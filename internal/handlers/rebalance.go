@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/rebalance"
+)
+
+type RebalanceHandler struct {
+	service rebalance.ServiceAPI
+	logger  *zap.Logger
+}
+
+func NewRebalanceHandler(service rebalance.ServiceAPI, logger *zap.Logger) *RebalanceHandler {
+	return &RebalanceHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetTarget godoc
+// @Summary Get target allocation
+// @Description The target weights and tolerance band the wallet is rebalanced against
+// @Tags Rebalance
+// @Produce json
+// @Param wallet path string true "Wallet address"
+// @Success 200 {object} rebalance.Target
+// @Failure 404 {object} handlers.ErrorResponse
+// @Router /wallets/{wallet}/portfolio/targets [get]
+func (h *RebalanceHandler) GetTarget(w http.ResponseWriter, r *http.Request) {
+	wallet := chi.URLParam(r, "wallet")
+
+	target, err := h.service.GetTarget(r.Context(), wallet)
+	if err != nil {
+		h.respondTargetError(w, "get-target-failed", err)
+		return
+	}
+
+	RespondOK(w, http.StatusOK, target)
+}
+
+// SetTarget godoc
+// @Summary Set target allocation
+// @Description Store the wallet's target weights, which must add up to 100, replacing any earlier target. Drift within tolerance_pct percentage points is left alone; cash_asset funds buys in cash-only plans.
+// @Tags Rebalance
+// @Accept json
+// @Produce json
+// @Param wallet path string true "Wallet address"
+// @Param target body handlers.SetTargetRequest true "Target allocation"
+// @Success 200 {object} rebalance.Target
+// @Failure 400 {object} handlers.ErrorResponse
+// @Router /wallets/{wallet}/portfolio/targets [put]
+func (h *RebalanceHandler) SetTarget(w http.ResponseWriter, r *http.Request) {
+	wallet := chi.URLParam(r, "wallet")
+
+	var req SetTargetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "INVALID_JSON", "invalid request body")
+		return
+	}
+
+	target := rebalance.Target{
+		Wallet:       wallet,
+		Weights:      make([]rebalance.TargetWeight, 0, len(req.Weights)),
+		TolerancePct: req.TolerancePct,
+	}
+	for _, tw := range req.Weights {
+		target.Weights = append(target.Weights, rebalance.TargetWeight{
+			Asset:     pricing.AssetRef{Chain: tw.Chain, ContractAddress: tw.ContractAddress},
+			WeightPct: tw.WeightPct,
+		})
+	}
+	if req.CashAsset != nil {
+		cash := req.CashAsset.ToAssetRef()
+		target.CashAsset = &cash
+	}
+
+	saved, err := h.service.SetTarget(r.Context(), target)
+	if err != nil {
+		h.respondTargetError(w, "set-target-failed", err)
+		return
+	}
+
+	RespondOK(w, http.StatusOK, saved)
+}
+
+// DeleteTarget godoc
+// @Summary Delete target allocation
+// @Tags Rebalance
+// @Produce json
+// @Param wallet path string true "Wallet address"
+// @Success 200
+// @Failure 404 {object} handlers.ErrorResponse
+// @Router /wallets/{wallet}/portfolio/targets [delete]
+func (h *RebalanceHandler) DeleteTarget(w http.ResponseWriter, r *http.Request) {
+	wallet := chi.URLParam(r, "wallet")
+
+	if err := h.service.DeleteTarget(r.Context(), wallet); err != nil {
+		h.respondTargetError(w, "delete-target-failed", err)
+		return
+	}
+
+	RespondOK(w, http.StatusOK, nil)
+}
+
+// GetRebalancePlan godoc
+// @Summary Rebalancing plan
+// @Description Buy and sell quantities and USD amounts that bring the live portfolio back to its target allocation. Assets within the tolerance band and trades below min_trade_usd are held. With cash_only, nothing but the target's cash asset is sold and buys are scaled to the cash available.
+// @Tags Rebalance
+// @Produce json
+// @Param wallet path string true "Wallet address"
+// @Param min_trade_usd query number false "Smallest trade to include, in USD"
+// @Param cash_only query bool false "Fund buys from the cash asset only"
+// @Success 200 {object} rebalance.Plan
+// @Failure 400 {object} handlers.ErrorResponse
+// @Failure 404 {object} handlers.ErrorResponse
+// @Router /wallets/{wallet}/portfolio/rebalance [get]
+func (h *RebalanceHandler) Plan(w http.ResponseWriter, r *http.Request) {
+	wallet := chi.URLParam(r, "wallet")
+
+	var opts rebalance.Options
+	if v := r.URL.Query().Get("min_trade_usd"); v != "" {
+		minTrade, err := strconv.ParseFloat(v, 64)
+		if err != nil || minTrade < 0 {
+			RespondError(w, http.StatusBadRequest, "INVALID_PARAMS", "min_trade_usd must be a non-negative number")
+			return
+		}
+		opts.MinTradeUSD = minTrade
+	}
+	if v := r.URL.Query().Get("cash_only"); v != "" {
+		cashOnly, err := strconv.ParseBool(v)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "INVALID_PARAMS", "cash_only must be true or false")
+			return
+		}
+		opts.CashOnly = cashOnly
+	}
+
+	plan, err := h.service.Plan(r.Context(), wallet, opts)
+	if err != nil {
+		h.logger.Error("rebalance-plan-failed", zap.Error(err))
+		switch {
+		case errors.Is(err, rebalance.ErrTargetNotFound):
+			RespondError(w, http.StatusNotFound, "TARGET_NOT_FOUND", "target allocation not found")
+		case errors.Is(err, rebalance.ErrNoCashAsset):
+			RespondError(w, http.StatusBadRequest, "NO_CASH_ASSET", err.Error())
+		default:
+			RespondError(w, http.StatusNotFound, "PORTFOLIO_NOT_FOUND", "portfolio not found")
+		}
+		return
+	}
+
+	RespondOK(w, http.StatusOK, plan)
+}
+
+func (h *RebalanceHandler) respondTargetError(w http.ResponseWriter, msg string, err error) {
+	h.logger.Error(msg, zap.Error(err))
+	switch {
+	case errors.Is(err, rebalance.ErrInvalidTarget):
+		RespondError(w, http.StatusBadRequest, "INVALID_TARGET", err.Error())
+	case errors.Is(err, rebalance.ErrTargetNotFound):
+		RespondError(w, http.StatusNotFound, "TARGET_NOT_FOUND", "target allocation not found")
+	default:
+		RespondError(w, http.StatusInternalServerError, "TARGET_FAILED", "failed to process target allocation")
+	}
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/rebalance"
+)
+
+type mockRebalanceService struct {
+	target rebalance.Target
+	opts   rebalance.Options
+	err    error
+}
+
+func (m *mockRebalanceService) GetTarget(ctx context.Context, wallet string) (*rebalance.Target, error) {
+	return &m.target, m.err
+}
+
+func (m *mockRebalanceService) SetTarget(ctx context.Context, t rebalance.Target) (*rebalance.Target, error) {
+	m.target = t
+	return &t, m.err
+}
+
+func (m *mockRebalanceService) DeleteTarget(ctx context.Context, wallet string) error {
+	return m.err
+}
+
+func (m *mockRebalanceService) Plan(ctx context.Context, wallet string, opts rebalance.Options) (*rebalance.Plan, error) {
+	m.opts = opts
+	return &rebalance.Plan{Wallet: wallet}, m.err
+}
+
+func rebalanceRouter(handler *RebalanceHandler) http.Handler {
+	r := chi.NewRouter()
+	r.Put("/wallets/{wallet}/portfolio/targets", handler.SetTarget)
+	r.Get("/wallets/{wallet}/portfolio/rebalance", handler.Plan)
+	return r
+}
+
+func TestRebalanceHandler_SetTarget(t *testing.T) {
+	svc := &mockRebalanceService{}
+	handler := NewRebalanceHandler(svc, zap.NewNop())
+
+	body := `{
+		"weights": [
+			{"chain": "ethereum", "weight_pct": 60},
+			{"chain": "ethereum", "contract_address": "0xa0b8", "weight_pct": 40}
+		],
+		"tolerance_pct": 5,
+		"cash_asset": {"chain": "ethereum", "contract_address": "0xa0b8"}
+	}`
+	rec := httptest.NewRecorder()
+	rebalanceRouter(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/wallets/0xabc/portfolio/targets", bytes.NewBufferString(body)))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "0xabc", svc.target.Wallet)
+	require.Len(t, svc.target.Weights, 2)
+	require.Equal(t, "0xa0b8", svc.target.Weights[1].Asset.ContractAddress)
+	require.Equal(t, 5.0, svc.target.TolerancePct)
+	require.NotNil(t, svc.target.CashAsset)
+}
+
+func TestRebalanceHandler_SetTargetInvalid(t *testing.T) {
+	handler := NewRebalanceHandler(&mockRebalanceService{err: rebalance.ErrInvalidTarget}, zap.NewNop())
+
+	rec := httptest.NewRecorder()
+	rebalanceRouter(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/wallets/0xabc/portfolio/targets", bytes.NewBufferString(`{"weights": []}`)))
+
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestRebalanceHandler_PlanOptions(t *testing.T) {
+	svc := &mockRebalanceService{}
+	handler := NewRebalanceHandler(svc, zap.NewNop())
+
+	rec := httptest.NewRecorder()
+	rebalanceRouter(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/0xabc/portfolio/rebalance?min_trade_usd=25&cash_only=true", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, rebalance.Options{MinTradeUSD: 25, CashOnly: true}, svc.opts)
+}
+
+func TestRebalanceHandler_PlanErrors(t *testing.T) {
+	cases := []struct {
+		name  string
+		query string
+		err   error
+		code  int
+	}{
+		{"bad min trade", "?min_trade_usd=-1", nil, http.StatusBadRequest},
+		{"bad cash only", "?cash_only=maybe", nil, http.StatusBadRequest},
+		{"no target", "", rebalance.ErrTargetNotFound, http.StatusNotFound},
+		{"no cash asset", "?cash_only=true", rebalance.ErrNoCashAsset, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewRebalanceHandler(&mockRebalanceService{err: tc.err}, zap.NewNop())
+
+			rec := httptest.NewRecorder()
+			rebalanceRouter(handler).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/0xabc/portfolio/rebalance"+tc.query, nil))
+
+			require.Equal(t, tc.code, rec.Code)
+		})
+	}
+}
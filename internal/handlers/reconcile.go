@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/balances"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/reconcile"
+)
+
+type ReconcileHandler struct {
+	service reconcile.ServiceAPI
+	logger  *zap.Logger
+}
+
+func NewReconcileHandler(service reconcile.ServiceAPI, logger *zap.Logger) *ReconcileHandler {
+	return &ReconcileHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Get godoc
+// @Summary Reconcile holdings with the chain
+// @Description Compares each asset's portfolio amount, manual and synced together, with its on-chain balance and classifies it as match, missing (held on-chain only), extra (in the portfolio only), mismatch (different by more than tolerance_pct) or unknown (balance unreadable). Native assets are also compared with the net of the transaction history: transfers in, less transfers out and gas paid.
+// @Tags Portfolio
+// @Produce json
+// @Param wallet path string true "Wallet address"
+// @Param tolerance_pct query number false "Relative difference left unflagged, in percent" default(0.5)
+// @Success 200 {object} reconcile.Report
+// @Failure 400 {object} handlers.ErrorResponse
+// @Failure 404 {object} handlers.ErrorResponse
+// @Failure 503 {object} handlers.ErrorResponse
+// @Router /wallets/{wallet}/portfolio/reconcile [get]
+func (h *ReconcileHandler) Get(w http.ResponseWriter, r *http.Request) {
+	wallet := chi.URLParam(r, "wallet")
+
+	tolerance := reconcile.DefaultTolerancePct
+	if v := r.URL.Query().Get("tolerance_pct"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "INVALID_PARAMS", "tolerance_pct must be a number")
+			return
+		}
+		tolerance = parsed
+	}
+
+	report, err := h.service.Report(r.Context(), wallet, tolerance)
+	if err != nil {
+		h.respondError(w, wallet, "reconcile-holdings-failed", err)
+		return
+	}
+
+	RespondOK(w, http.StatusOK, report)
+}
+
+// Accept godoc
+// @Summary Accept on-chain balances
+// @Description Sets the manual holding of each flagged asset so the portfolio matches its on-chain balance, in one save. Synced amounts are kept and the manual amount takes the difference. Without assets every missing, extra and mismatched asset is fixed.
+// @Tags Portfolio
+// @Accept json
+// @Produce json
+// @Param wallet path string true "Wallet address"
+// @Param request body handlers.AcceptOnChainRequest false "Assets to fix"
+// @Success 200 {object} handlers.AcceptOnChainResponse
+// @Failure 400 {object} handlers.ErrorResponse
+// @Failure 404 {object} handlers.ErrorResponse
+// @Failure 503 {object} handlers.ErrorResponse
+// @Router /wallets/{wallet}/portfolio/reconcile/accept [post]
+func (h *ReconcileHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	wallet := chi.URLParam(r, "wallet")
+
+	var req AcceptOnChainRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		RespondError(w, http.StatusBadRequest, "INVALID_JSON", "invalid request body")
+		return
+	}
+
+	tolerance := reconcile.DefaultTolerancePct
+	if req.TolerancePct != nil {
+		tolerance = *req.TolerancePct
+	}
+	assets := make([]pricing.AssetRef, 0, len(req.Assets))
+	for _, a := range req.Assets {
+		assets = append(assets, a.ToAssetRef())
+	}
+
+	fixed, err := h.service.Accept(r.Context(), wallet, assets, tolerance)
+	if err != nil {
+		h.respondError(w, wallet, "accept-on-chain-failed", err)
+		return
+	}
+
+	RespondOK(w, http.StatusOK, AcceptOnChainResponse{Fixed: fixed})
+}
+
+func (h *ReconcileHandler) respondError(w http.ResponseWriter, wallet, msg string, err error) {
+	switch {
+	case errors.Is(err, reconcile.ErrInvalidTolerance):
+		RespondError(w, http.StatusBadRequest, "INVALID_PARAMS", err.Error())
+	case errors.Is(err, balances.ErrInvalidAddress):
+		RespondError(w, http.StatusBadRequest, "INVALID_WALLET", "wallet must be a 0x address")
+	case errors.Is(err, balances.ErrNoEndpoints):
+		RespondError(w, http.StatusServiceUnavailable, "RECONCILE_UNAVAILABLE", "no RPC endpoints are configured")
+	case errors.Is(err, reconcile.ErrPortfolioNotFound):
+		RespondError(w, http.StatusNotFound, "PORTFOLIO_NOT_FOUND", "portfolio not found")
+	default:
+		h.logger.Error(msg,
+			zap.String("wallet", wallet),
+			zap.Error(err),
+		)
+		RespondError(w, http.StatusInternalServerError, "RECONCILE_FAILED", "failed to reconcile holdings")
+	}
+}
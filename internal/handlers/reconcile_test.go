@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/reconcile"
+)
+
+type mockReconcileService struct {
+	tolerance float64
+	assets    []pricing.AssetRef
+	err       error
+}
+
+func (m *mockReconcileService) Report(ctx context.Context, wallet string, tolerancePct float64) (*reconcile.Report, error) {
+	m.tolerance = tolerancePct
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &reconcile.Report{Wallet: wallet, TolerancePct: tolerancePct}, nil
+}
+
+func (m *mockReconcileService) Accept(ctx context.Context, wallet string, assets []pricing.AssetRef, tolerancePct float64) ([]reconcile.Line, error) {
+	m.tolerance, m.assets = tolerancePct, assets
+	return []reconcile.Line{}, m.err
+}
+
+func reconcileRouter(svc reconcile.ServiceAPI) http.Handler {
+	h := NewReconcileHandler(svc, zap.NewNop())
+	r := chi.NewRouter()
+	r.Get("/wallets/{wallet}/portfolio/reconcile", h.Get)
+	r.Post("/wallets/{wallet}/portfolio/reconcile/accept", h.Accept)
+	return r
+}
+
+func TestReconcileHandler_Get(t *testing.T) {
+	svc := &mockReconcileService{}
+
+	rec := httptest.NewRecorder()
+	reconcileRouter(svc).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/0xabc/portfolio/reconcile", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, reconcile.DefaultTolerancePct, svc.tolerance)
+
+	rec = httptest.NewRecorder()
+	reconcileRouter(svc).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/0xabc/portfolio/reconcile?tolerance_pct=abc", nil))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	reconcileRouter(&mockReconcileService{err: reconcile.ErrPortfolioNotFound}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/0xabc/portfolio/reconcile", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestReconcileHandler_Accept(t *testing.T) {
+	svc := &mockReconcileService{}
+
+	rec := httptest.NewRecorder()
+	body := `{"assets":[{"chain":"ethereum","contract_address":""}],"tolerance_pct":1}`
+	reconcileRouter(svc).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/wallets/0xabc/portfolio/reconcile/accept", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, 1.0, svc.tolerance)
+	require.Equal(t, []pricing.AssetRef{{Chain: "ethereum"}}, svc.assets)
+
+	// an empty body accepts every flagged asset
+	rec = httptest.NewRecorder()
+	reconcileRouter(svc).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/wallets/0xabc/portfolio/reconcile/accept", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, svc.assets)
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/spam"
+)
+
+type SpamHandler struct {
+	service spam.ServiceAPI
+	logger  *zap.Logger
+}
+
+func NewSpamHandler(service spam.ServiceAPI, logger *zap.Logger) *SpamHandler {
+	return &SpamHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ListOverrides godoc
+// @Summary List spam overrides
+// @Description List the tokens the wallet has marked as spam or not spam
+// @Tags Spam
+// @Produce json
+// @Param wallet path string true "Wallet address"
+// @Success 200 {array} spam.Override
+// @Failure 500 {object} handlers.ErrorResponse
+// @Router /wallets/{wallet}/spam/overrides [get]
+func (h *SpamHandler) ListOverrides(w http.ResponseWriter, r *http.Request) {
+	wallet := chi.URLParam(r, "wallet")
+
+	overrides, err := h.service.Overrides(r.Context(), wallet)
+	if err != nil {
+		h.logger.Error("list-spam-overrides-failed", zap.String("wallet", wallet), zap.Error(err))
+		RespondError(w, http.StatusInternalServerError, "SPAM_FAILED", "failed to list spam overrides")
+		return
+	}
+
+	RespondOK(w, http.StatusOK, overrides)
+}
+
+// SetOverride godoc
+// @Summary Mark a token as spam or not
+// @Description Record the wallet's own verdict on a token. It wins over the spam lists and heuristics wherever the wallet's transactions, portfolio and suggestions are judged.
+// @Tags Spam
+// @Accept json
+// @Produce json
+// @Param wallet path string true "Wallet address"
+// @Param override body handlers.SetSpamOverrideRequest true "Override"
+// @Success 200 {object} spam.Override
+// @Failure 400 {object} handlers.ErrorResponse
+// @Router /wallets/{wallet}/spam/overrides [put]
+func (h *SpamHandler) SetOverride(w http.ResponseWriter, r *http.Request) {
+	wallet := chi.URLParam(r, "wallet")
+
+	var req SetSpamOverrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		RespondError(w, http.StatusBadRequest, "INVALID_JSON", "invalid request body")
+		return
+	}
+	if req.Spam == nil {
+		RespondError(w, http.StatusBadRequest, "MISSING_PARAMS", "spam is required")
+		return
+	}
+
+	override, err := h.service.SetOverride(r.Context(), wallet, pricing.AssetRef{
+		Chain:           req.Chain,
+		ContractAddress: req.ContractAddress,
+	}, *req.Spam)
+	if err != nil {
+		if errors.Is(err, spam.ErrInvalidOverride) {
+			RespondError(w, http.StatusBadRequest, "INVALID_OVERRIDE", "chain and contract_address are required")
+			return
+		}
+		h.logger.Error("set-spam-override-failed", zap.String("wallet", wallet), zap.Error(err))
+		RespondError(w, http.StatusInternalServerError, "SPAM_FAILED", "failed to set spam override")
+		return
+	}
+
+	RespondOK(w, http.StatusOK, override)
+}
+
+// DeleteOverride godoc
+// @Summary Remove a spam override
+// @Description Remove the wallet's verdict on a token; it is judged by the lists and heuristics again
+// @Tags Spam
+// @Produce json
+// @Param wallet path string true "Wallet address"
+// @Param chain query string true "Chain"
+// @Param contract query string true "Contract address"
+// @Success 200
+// @Failure 400 {object} handlers.ErrorResponse
+// @Failure 404 {object} handlers.ErrorResponse
+// @Router /wallets/{wallet}/spam/overrides [delete]
+func (h *SpamHandler) DeleteOverride(w http.ResponseWriter, r *http.Request) {
+	wallet := chi.URLParam(r, "wallet")
+	chain := r.URL.Query().Get("chain")
+	contract := r.URL.Query().Get("contract")
+	if chain == "" || contract == "" {
+		RespondError(w, http.StatusBadRequest, "MISSING_PARAMS", "chain and contract are required")
+		return
+	}
+
+	err := h.service.DeleteOverride(r.Context(), wallet, pricing.AssetRef{Chain: chain, ContractAddress: contract})
+	if err != nil {
+		if errors.Is(err, spam.ErrOverrideMissing) {
+			RespondError(w, http.StatusNotFound, "NOT_FOUND", "spam override not found")
+			return
+		}
+		h.logger.Error("delete-spam-override-failed", zap.String("wallet", wallet), zap.Error(err))
+		RespondError(w, http.StatusInternalServerError, "SPAM_FAILED", "failed to delete spam override")
+		return
+	}
+
+	RespondOK(w, http.StatusOK, nil)
+}
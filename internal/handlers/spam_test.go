@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/spam"
+)
+
+type mockSpamService struct {
+	spam.ServiceAPI
+	asset pricing.AssetRef
+	spam  bool
+}
+
+func (m *mockSpamService) Overrides(ctx context.Context, wallet string) ([]spam.Override, error) {
+	return []spam.Override{}, nil
+}
+
+func (m *mockSpamService) SetOverride(ctx context.Context, wallet string, asset pricing.AssetRef, isSpam bool) (*spam.Override, error) {
+	if asset.ContractAddress == "" {
+		return nil, spam.ErrInvalidOverride
+	}
+	m.asset, m.spam = asset, isSpam
+	return &spam.Override{Wallet: wallet, Asset: asset, Spam: isSpam}, nil
+}
+
+func (m *mockSpamService) DeleteOverride(ctx context.Context, wallet string, asset pricing.AssetRef) error {
+	if asset != m.asset {
+		return spam.ErrOverrideMissing
+	}
+	return nil
+}
+
+func spamRouter(svc spam.ServiceAPI) http.Handler {
+	h := NewSpamHandler(svc, zap.NewNop())
+	r := chi.NewRouter()
+	r.Get("/wallets/{wallet}/spam/overrides", h.ListOverrides)
+	r.Put("/wallets/{wallet}/spam/overrides", h.SetOverride)
+	r.Delete("/wallets/{wallet}/spam/overrides", h.DeleteOverride)
+	return r
+}
+
+func TestSpamHandler_Overrides(t *testing.T) {
+	svc := &mockSpamService{}
+	router := spamRouter(svc)
+
+	rec := httptest.NewRecorder()
+	body := `{"chain":"ethereum","contract_address":"0xscam","spam":true}`
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/wallets/0xabc/spam/overrides", strings.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xscam"}, svc.asset)
+	require.True(t, svc.spam)
+
+	for _, body := range []string{`{"chain":"ethereum","contract_address":"0xscam"}`, `{"chain":"ethereum","spam":false}`} {
+		rec = httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/wallets/0xabc/spam/overrides", strings.NewReader(body)))
+		require.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/0xabc/spam/overrides", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/wallets/0xabc/spam/overrides?chain=ethereum&contract=0xother", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/wallets/0xabc/spam/overrides?chain=ethereum&contract=0xscam", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/streaming"
+)
+
+const (
+	wsWriteWait  = 10 * time.Second
+	wsPongWait   = 60 * time.Second
+	wsPingPeriod = wsPongWait * 9 / 10
+)
+
+type StreamHandler struct {
+	hub      *streaming.Hub
+	upgrader websocket.Upgrader
+	logger   *zap.Logger
+}
+
+func NewStreamHandler(hub *streaming.Hub, logger *zap.Logger) *StreamHandler {
+	return &StreamHandler{
+		hub: hub,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			// the API is consumed by dashboards served from other origins
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		logger: logger,
+	}
+}
+
+// Stream godoc
+// @Summary Stream live prices and portfolios
+// @Description Upgrades to a WebSocket. Send {"action":"subscribe","assets":[{"chain":"ethereum","contract_address":"0x.."}]} or {"action":"subscribe","wallet":"0x.."} to receive price and portfolio updates on every refresh.
+// @Tags Streaming
+// @Success 101
+// @Router /ws [get]
+func (h *StreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already written an error response
+		h.logger.Warn("websocket-upgrade-failed", zap.Error(err))
+		return
+	}
+
+	sub := h.hub.Register()
+	go h.readLoop(conn, sub)
+	h.writeLoop(conn, sub)
+}
+
+// readLoop applies subscription messages until the client goes away
+func (h *StreamHandler) readLoop(conn *websocket.Conn, sub *streaming.Subscriber) {
+	defer h.hub.Unregister(sub)
+
+	conn.SetReadLimit(64 * 1024)
+	_ = conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		var msg streaming.ClientMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				h.logger.Warn("websocket-read-failed", zap.Error(err))
+			}
+			return
+		}
+
+		if err := sub.Handle(msg); err != nil {
+			h.logger.Warn("websocket-invalid-message", zap.Error(err))
+			h.hub.Reject(sub, err)
+		}
+	}
+}
+
+// writeLoop is the only writer on conn, as gorilla/websocket requires
+func (h *StreamHandler) writeLoop(conn *websocket.Conn, sub *streaming.Subscriber) {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer func() {
+		ticker.Stop()
+		conn.Close()
+	}()
+
+	for {
+		select {
+		case update, ok := <-sub.Updates():
+			_ = conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if !ok {
+				_ = conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := conn.WriteJSON(update); err != nil {
+				h.logger.Warn("websocket-write-failed", zap.Error(err))
+				return
+			}
+		case <-ticker.C:
+			_ = conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/streaming"
+)
+
+type streamPortfolioService struct {
+	portfolio.Service
+}
+
+func TestStreamHandler_PushesSubscribedPrices(t *testing.T) {
+	asset := pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xabc"}
+	pricingSvc := &mockPricingService{
+		market: map[pricing.AssetRef]pricing.MarketData{
+			asset: {PriceUSD: 123.45},
+		},
+	}
+
+	hub := streaming.NewHub(pricingSvc, &streamPortfolioService{}, time.Hour, zap.NewNop())
+	handler := NewStreamHandler(hub, zap.NewNop())
+
+	ts := httptest.NewServer(http.HandlerFunc(handler.Stream))
+	defer ts.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	err = conn.WriteJSON(streaming.ClientMessage{
+		Action: streaming.ActionSubscribe,
+		Assets: []streaming.AssetMessage{{Chain: "ethereum", ContractAddress: "0xabc"}},
+	})
+	require.NoError(t, err)
+
+	// drive the refresh loop until the subscription has landed and an update arrives
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(10 * time.Millisecond):
+				hub.Refresh(context.Background())
+			}
+		}
+	}()
+
+	var update streaming.Update
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	require.NoError(t, conn.ReadJSON(&update))
+
+	require.Equal(t, streaming.UpdatePrices, update.Type)
+	require.Equal(t, 123.45, update.Prices["ethereum:0xabc"].PriceUSD)
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/discovery"
+)
+
+type SuggestionsHandler struct {
+	service discovery.ServiceAPI
+	logger  *zap.Logger
+}
+
+func NewSuggestionsHandler(service discovery.ServiceAPI, logger *zap.Logger) *SuggestionsHandler {
+	return &SuggestionsHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Get godoc
+// @Summary Suggest untracked tokens
+// @Description Scans the wallet's ERC-20 transfer history on one chain for tokens it still holds but the portfolio does not, reading each balance from the chain or, when that fails, taking the net of the transfers. Tokens judged spam and tokens worth less than min_value_usd are hidden unless asked for; tokens without a price are never counted as dust.
+// @Tags Portfolio
+// @Produce json
+// @Param wallet path string true "Wallet address"
+// @Param chain query string false "Chain name or ID" default(ethereum)
+// @Param include_spam query bool false "Include tokens classified as spam"
+// @Param include_dust query bool false "Include tokens worth less than min_value_usd"
+// @Param min_value_usd query number false "Dust threshold in USD, defaults to DISCOVERY_DUST_USD"
+// @Success 200 {object} discovery.Result
+// @Failure 400 {object} handlers.ErrorResponse
+// @Failure 500 {object} handlers.ErrorResponse
+// @Router /wallets/{wallet}/portfolio/suggestions [get]
+func (h *SuggestionsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	wallet := chi.URLParam(r, "wallet")
+	query := r.URL.Query()
+
+	q := discovery.Query{Chain: query.Get("chain")}
+	for name, dst := range map[string]*bool{
+		"include_spam": &q.IncludeSpam,
+		"include_dust": &q.IncludeDust,
+	} {
+		v := query.Get(name)
+		if v == "" {
+			continue
+		}
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "INVALID_PARAMS", name+" must be a boolean")
+			return
+		}
+		*dst = parsed
+	}
+	if v := query.Get("min_value_usd"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "INVALID_PARAMS", "min_value_usd must be a number")
+			return
+		}
+		q.MinValueUSD = &parsed
+	}
+
+	res, err := h.service.Suggest(r.Context(), wallet, q)
+	if err != nil {
+		switch {
+		case errors.Is(err, discovery.ErrInvalidAddress):
+			RespondError(w, http.StatusBadRequest, "INVALID_WALLET", "wallet must be a 0x address")
+		case errors.Is(err, discovery.ErrUnsupportedChain), errors.Is(err, discovery.ErrInvalidMinValue):
+			RespondError(w, http.StatusBadRequest, "INVALID_PARAMS", err.Error())
+		default:
+			h.logger.Error("discover-tokens-failed",
+				zap.String("wallet", wallet),
+				zap.Error(err),
+			)
+			RespondError(w, http.StatusInternalServerError, "DISCOVERY_FAILED", "failed to scan token transfers")
+		}
+		return
+	}
+
+	RespondOK(w, http.StatusOK, res)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/discovery"
+)
+
+type mockDiscoveryService struct {
+	query discovery.Query
+	err   error
+}
+
+func (m *mockDiscoveryService) Suggest(ctx context.Context, wallet string, q discovery.Query) (*discovery.Result, error) {
+	m.query = q
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &discovery.Result{Wallet: wallet, Chain: "ethereum", Suggestions: []discovery.Suggestion{}}, nil
+}
+
+func suggestionsRouter(svc discovery.ServiceAPI) http.Handler {
+	h := NewSuggestionsHandler(svc, zap.NewNop())
+	r := chi.NewRouter()
+	r.Get("/wallets/{wallet}/portfolio/suggestions", h.Get)
+	return r
+}
+
+func TestSuggestionsHandler_Get(t *testing.T) {
+	svc := &mockDiscoveryService{}
+
+	rec := httptest.NewRecorder()
+	suggestionsRouter(svc).ServeHTTP(rec, httptest.NewRequest(http.MethodGet,
+		"/wallets/0xabc/portfolio/suggestions?chain=polygon-pos&include_spam=true&min_value_usd=5", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "polygon-pos", svc.query.Chain)
+	require.True(t, svc.query.IncludeSpam)
+	require.False(t, svc.query.IncludeDust)
+	require.Equal(t, 5.0, *svc.query.MinValueUSD)
+
+	rec = httptest.NewRecorder()
+	suggestionsRouter(svc).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/0xabc/portfolio/suggestions?include_dust=maybe", nil))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	suggestionsRouter(&mockDiscoveryService{err: discovery.ErrInvalidAddress}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/abc/portfolio/suggestions", nil))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/balances"
+)
+
+type SyncHandler struct {
+	service balances.ServiceAPI
+	logger  *zap.Logger
+}
+
+func NewSyncHandler(service balances.ServiceAPI, logger *zap.Logger) *SyncHandler {
+	return &SyncHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Sync godoc
+// @Summary Sync on-chain balances
+// @Description Reads the wallet's native balance on every chain with an RPC endpoint, and the balance of watched tokens and tokens it already holds, then replaces its synced holdings. Manual holdings are left alone. Balances that cannot be read are listed under errors and keep their previous amount.
+// @Tags Portfolio
+// @Produce json
+// @Param wallet path string true "Wallet address"
+// @Success 200 {object} balances.Result
+// @Failure 400 {object} handlers.ErrorResponse
+// @Failure 503 {object} handlers.ErrorResponse
+// @Router /wallets/{wallet}/portfolio/sync [post]
+func (h *SyncHandler) Sync(w http.ResponseWriter, r *http.Request) {
+	wallet := chi.URLParam(r, "wallet")
+
+	res, err := h.service.Sync(r.Context(), wallet)
+	if err != nil {
+		switch {
+		case errors.Is(err, balances.ErrInvalidAddress):
+			RespondError(w, http.StatusBadRequest, "INVALID_WALLET", "wallet must be a 0x address")
+		case errors.Is(err, balances.ErrNoEndpoints):
+			RespondError(w, http.StatusServiceUnavailable, "SYNC_UNAVAILABLE", "no RPC endpoints are configured")
+		default:
+			h.logger.Error("sync-balances-failed",
+				zap.String("wallet", wallet),
+				zap.Error(err),
+			)
+			RespondError(w, http.StatusInternalServerError, "SYNC_FAILED", "failed to sync balances")
+		}
+		return
+	}
+
+	RespondOK(w, http.StatusOK, res)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/balances"
+)
+
+type mockSyncService struct {
+	balances.ServiceAPI
+	err error
+}
+
+func (m *mockSyncService) Sync(ctx context.Context, wallet string) (*balances.Result, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &balances.Result{Wallet: wallet}, nil
+}
+
+func syncRouter(svc balances.ServiceAPI) http.Handler {
+	r := chi.NewRouter()
+	r.Post("/wallets/{wallet}/portfolio/sync", NewSyncHandler(svc, zap.NewNop()).Sync)
+	return r
+}
+
+func TestSyncHandler_Sync(t *testing.T) {
+	for _, tc := range []struct {
+		err    error
+		status int
+	}{
+		{nil, http.StatusOK},
+		{balances.ErrInvalidAddress, http.StatusBadRequest},
+		{balances.ErrNoEndpoints, http.StatusServiceUnavailable},
+	} {
+		rec := httptest.NewRecorder()
+		syncRouter(&mockSyncService{err: tc.err}).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/wallets/0xabc/portfolio/sync", nil))
+		require.Equal(t, tc.status, rec.Code)
+	}
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pnl"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tax"
+)
+
+type TaxHandler struct {
+	service tax.ServiceAPI
+	logger  *zap.Logger
+}
+
+func NewTaxHandler(service tax.ServiceAPI, logger *zap.Logger) *TaxHandler {
+	return &TaxHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetTaxReport godoc
+// @Summary Tax report
+// @Description Every disposal made in the tax year with its acquired and disposed dates, proceeds, cost basis, gain and short/long-term class under the jurisdiction's holding-period rule. format=json returns the summary; format=csv returns the rows in Form 8949 column order.
+// @Tags Tax
+// @Produce json
+// @Produce text/csv
+// @Param wallet path string true "Wallet address"
+// @Param year query int false "Tax year, defaults to last year"
+// @Param jurisdiction query string false "us | de | au | pt, defaults to TAX_JURISDICTION"
+// @Param chain query string false "Chain" default(ethereum)
+// @Param method query string false "fifo | lifo | hifo" default(fifo)
+// @Param format query string false "json | csv" default(json)
+// @Success 200 {object} tax.Summary
+// @Failure 400 {object} handlers.ErrorResponse
+// @Failure 500 {object} handlers.ErrorResponse
+// @Router /wallets/{wallet}/tax/report [get]
+func (h *TaxHandler) Report(w http.ResponseWriter, r *http.Request) {
+	wallet := chi.URLParam(r, "wallet")
+
+	q := tax.Query{
+		Chain:        r.URL.Query().Get("chain"),
+		Jurisdiction: r.URL.Query().Get("jurisdiction"),
+		Year:         time.Now().UTC().Year() - 1,
+	}
+	if q.Chain == "" {
+		q.Chain = "ethereum"
+	}
+
+	method, err := pnl.ParseMethod(r.URL.Query().Get("method"))
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "INVALID_METHOD", "method must be fifo, lifo or hifo")
+		return
+	}
+	q.Method = method
+
+	if v := r.URL.Query().Get("year"); v != "" {
+		year, err := strconv.Atoi(v)
+		if err != nil {
+			RespondError(w, http.StatusBadRequest, "INVALID_YEAR", "year must be a number")
+			return
+		}
+		q.Year = year
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "csv" {
+		RespondError(w, http.StatusBadRequest, "INVALID_FORMAT", "format must be json or csv")
+		return
+	}
+
+	report, err := h.service.Report(r.Context(), wallet, q)
+	if err != nil {
+		h.logger.Error("tax-report-failed", zap.Error(err))
+		switch {
+		case errors.Is(err, tax.ErrUnknownJurisdiction), errors.Is(err, tax.ErrInvalidYear), errors.Is(err, pnl.ErrUnsupportedChain):
+			RespondError(w, http.StatusBadRequest, "INVALID_PARAMS", err.Error())
+		default:
+			RespondError(w, http.StatusInternalServerError, "TAX_REPORT_FAILED", "failed to build tax report")
+		}
+		return
+	}
+
+	if format != "csv" {
+		RespondOK(w, http.StatusOK, report.Summary)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="tax-%s-%d.csv"`, wallet, q.Year))
+	if err := tax.WriteCSV(w, report.Rows); err != nil {
+		h.logger.Error("write-tax-csv-failed", zap.Error(err))
+	}
+}
+
+// ListJurisdictions godoc
+// @Summary List tax jurisdictions
+// @Description The holding-period rules tax reports can be classified under
+// @Tags Tax
+// @Produce json
+// @Success 200 {array} tax.Jurisdiction
+// @Router /tax/jurisdictions [get]
+func (h *TaxHandler) Jurisdictions(w http.ResponseWriter, r *http.Request) {
+	RespondOK(w, http.StatusOK, tax.Jurisdictions())
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pnl"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tax"
+)
+
+type mockTaxService struct {
+	query tax.Query
+	err   error
+}
+
+func (m *mockTaxService) Report(ctx context.Context, wallet string, q tax.Query) (*tax.Report, error) {
+	m.query = q
+	return &tax.Report{
+		Summary: tax.Summary{Wallet: wallet, Year: q.Year},
+		Rows: []tax.Row{{
+			Description: "1 ETH",
+			Asset:       pricing.AssetRef{Chain: "ethereum"},
+			AcquiredAt:  time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			DisposedAt:  time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC),
+			ProceedsUSD: 3000,
+			Term:        tax.TermLong,
+		}},
+	}, m.err
+}
+
+func taxRouter(svc tax.ServiceAPI) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/wallets/{wallet}/tax/report", NewTaxHandler(svc, zap.NewNop()).Report)
+	return r
+}
+
+func TestTaxHandler_ReportJSON(t *testing.T) {
+	svc := &mockTaxService{}
+
+	rec := httptest.NewRecorder()
+	taxRouter(svc).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/0xabc/tax/report?year=2025&jurisdiction=de&method=lifo", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, tax.Query{Chain: "ethereum", Method: pnl.MethodLIFO, Jurisdiction: "de", Year: 2025}, svc.query)
+	require.Contains(t, rec.Body.String(), `"year":2025`)
+}
+
+func TestTaxHandler_ReportCSV(t *testing.T) {
+	rec := httptest.NewRecorder()
+	taxRouter(&mockTaxService{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/0xabc/tax/report?year=2025&format=csv", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "text/csv", rec.Header().Get("Content-Type"))
+	require.Contains(t, rec.Header().Get("Content-Disposition"), "tax-0xabc-2025.csv")
+
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	require.True(t, strings.HasPrefix(lines[1], "1 ETH,01/02/2024,03/04/2025,3000.00"))
+}
+
+func TestTaxHandler_ReportInvalid(t *testing.T) {
+	cases := map[string]*mockTaxService{
+		"?format=pdf":      {},
+		"?year=last":       {},
+		"?method=average":  {},
+		"?jurisdiction=xx": {err: tax.ErrUnknownJurisdiction},
+		"?year=1999":       {err: tax.ErrInvalidYear},
+	}
+	for query, svc := range cases {
+		rec := httptest.NewRecorder()
+		taxRouter(svc).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/0xabc/tax/report"+query, nil))
+
+		require.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+}
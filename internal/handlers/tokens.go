@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+)
+
+type TokensHandler struct {
+	service tokens.ServiceAPI
+	search  tokens.SearchAPI
+	logger  *zap.Logger
+}
+
+func NewTokensHandler(service tokens.ServiceAPI, search tokens.SearchAPI, logger *zap.Logger) *TokensHandler {
+	return &TokensHandler{
+		service: service,
+		search:  search,
+		logger:  logger,
+	}
+}
+
+// SearchTokens godoc
+// @Summary Search tokens
+// @Description Find tokens by symbol or name, for autocomplete when adding holdings. Each result is one token on one chain; exact matches come first, then by market cap rank.
+// @Tags Tokens
+// @Produce json
+// @Param q query string true "Symbol or name"
+// @Param chain query string false "Restrict results to a chain"
+// @Param limit query int false "Max results" default(20)
+// @Success 200 {array} tokens.SearchResult
+// @Failure 400 {object} handlers.ErrorResponse
+// @Failure 503 {object} handlers.ErrorResponse
+// @Router /tokens/search [get]
+func (h *TokensHandler) Search(w http.ResponseWriter, r *http.Request) {
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	results, err := h.search.Search(r.Context(), r.URL.Query().Get("q"), r.URL.Query().Get("chain"), limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, tokens.ErrInvalidQuery):
+			RespondError(w, http.StatusBadRequest, "MISSING_PARAMS", "q is required")
+		case errors.Is(err, tokens.ErrSearchNotReady):
+			RespondError(w, http.StatusServiceUnavailable, "SEARCH_NOT_READY", "token list is still loading, retry shortly")
+		default:
+			h.logger.Error("search-tokens-failed", zap.Error(err))
+			RespondError(w, http.StatusInternalServerError, "SEARCH_FAILED", "failed to search tokens")
+		}
+		return
+	}
+
+	RespondOK(w, http.StatusOK, results)
+}
+
+// GetToken godoc
+// @Summary Get token metadata
+// @Description Symbol, name, decimals and logo of a token contract
+// @Tags Tokens
+// @Produce json
+// @Param chain path string true "Chain (ethereum, polygon-pos, ...)"
+// @Param contract path string true "Token contract address"
+// @Success 200 {object} tokens.Metadata
+// @Failure 404 {object} handlers.ErrorResponse
+// @Failure 502 {object} handlers.ErrorResponse
+// @Router /tokens/{chain}/{contract} [get]
+func (h *TokensHandler) Get(w http.ResponseWriter, r *http.Request) {
+	asset := pricing.AssetRef{
+		Chain:           chi.URLParam(r, "chain"),
+		ContractAddress: chi.URLParam(r, "contract"),
+	}
+
+	m, err := h.service.Get(r.Context(), asset)
+	if err != nil {
+		if errors.Is(err, tokens.ErrTokenNotFound) {
+			RespondError(w, http.StatusNotFound, "NOT_FOUND", "token not found")
+			return
+		}
+		h.logger.Error("get-token-failed", zap.Error(err))
+		RespondError(w, http.StatusBadGateway, "TOKEN_LOOKUP_FAILED", "failed to resolve token metadata")
+		return
+	}
+
+	RespondOK(w, http.StatusOK, m)
+}
+
+// ImportTokenList godoc
+// @Summary Import token list
+// @Description Import a standard token list (tokenlists.org format). Imported entries take precedence over coingecko metadata.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param X-Admin-Key header string true "Admin API key"
+// @Param list body tokens.TokenList true "Token list"
+// @Success 200 {object} handlers.ImportTokenListResponse
+// @Failure 400 {object} handlers.ErrorResponse
+// @Failure 401 {object} handlers.ErrorResponse
+// @Router /admin/tokens/lists [post]
+func (h *TokensHandler) ImportList(w http.ResponseWriter, r *http.Request) {
+	list, err := tokens.ParseTokenList(r.Body)
+	if err != nil {
+		RespondError(w, http.StatusBadRequest, "INVALID_JSON", "invalid token list")
+		return
+	}
+
+	n, err := h.service.Import(r.Context(), list)
+	if err != nil {
+		h.logger.Error("import-token-list-failed", zap.Error(err))
+		RespondError(w, http.StatusInternalServerError, "IMPORT_FAILED", "failed to import token list")
+		return
+	}
+
+	RespondOK(w, http.StatusOK, ImportTokenListResponse{Name: list.Name, Imported: n})
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+)
+
+type mockTokenService struct {
+	known    map[pricing.AssetRef]tokens.Metadata
+	imported *tokens.TokenList
+}
+
+func (m *mockTokenService) Lookup(ctx context.Context, assets []pricing.AssetRef) map[pricing.AssetRef]tokens.Metadata {
+	return m.known
+}
+
+func (m *mockTokenService) Get(ctx context.Context, asset pricing.AssetRef) (*tokens.Metadata, error) {
+	md, ok := m.known[asset]
+	if !ok {
+		return nil, tokens.ErrTokenNotFound
+	}
+	return &md, nil
+}
+
+func (m *mockTokenService) Import(ctx context.Context, list *tokens.TokenList) (int, error) {
+	m.imported = list
+	return len(list.Tokens), nil
+}
+
+type mockTokenSearch struct {
+	results []tokens.SearchResult
+	err     error
+	limit   int
+}
+
+func (m *mockTokenSearch) Search(ctx context.Context, query string, chain string, limit int) ([]tokens.SearchResult, error) {
+	m.limit = limit
+	return m.results, m.err
+}
+
+func TestTokensHandler_Get(t *testing.T) {
+	svc := &mockTokenService{known: map[pricing.AssetRef]tokens.Metadata{
+		{Chain: "ethereum", ContractAddress: "0xa0b8"}: {Symbol: "USDC", Decimals: 6},
+	}}
+	handler := NewTokensHandler(svc, &mockTokenSearch{}, zap.NewNop())
+	r := chi.NewRouter()
+	r.Get("/tokens/{chain}/{contract}", handler.Get)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tokens/ethereum/0xa0b8", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"symbol":"USDC"`)
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tokens/ethereum/0xdead", nil))
+
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestTokensHandler_ImportList(t *testing.T) {
+	svc := &mockTokenService{}
+	handler := NewTokensHandler(svc, &mockTokenSearch{}, zap.NewNop())
+
+	body := `{"name": "My List", "tokens": [{"chainId": 1, "address": "0xa0b8", "symbol": "USDC", "decimals": 6}]}`
+	req := httptest.NewRequest(http.MethodPost, "/admin/tokens/lists", bytes.NewBufferString(body))
+	rec := httptest.NewRecorder()
+
+	handler.ImportList(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "My List", svc.imported.Name)
+
+	var resp struct {
+		Data ImportTokenListResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(t, 1, resp.Data.Imported)
+}
+
+func TestTokensHandler_Search(t *testing.T) {
+	search := &mockTokenSearch{results: []tokens.SearchResult{
+		{CoinID: "usd-coin", Symbol: "USDC", Chain: "ethereum", ContractAddress: "0xa0b8", MarketCapRank: 7},
+	}}
+	handler := NewTokensHandler(&mockTokenService{}, search, zap.NewNop())
+
+	rec := httptest.NewRecorder()
+	handler.Search(rec, httptest.NewRequest(http.MethodGet, "/tokens/search?q=usdc&limit=500", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `"market_cap_rank":7`)
+	require.Equal(t, 100, search.limit)
+}
+
+func TestTokensHandler_SearchNotReady(t *testing.T) {
+	handler := NewTokensHandler(&mockTokenService{}, &mockTokenSearch{err: tokens.ErrSearchNotReady}, zap.NewNop())
+
+	rec := httptest.NewRecorder()
+	handler.Search(rec, httptest.NewRequest(http.MethodGet, "/tokens/search?q=usdc", nil))
+
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
@@ -29,11 +29,12 @@ func NewTransactionsHandler(s transactions.ServiceAPI, logger *zap.Logger) *Tran
 // @Param chain query string true "Blockchain (ethereum)"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Page size" default(20)
-// @Param type query string false "Transaction type"
+// @Param type query string false "Transaction type: send, receive, swap, stake, or nft to list ERC-721 and ERC-1155 transfers instead"
 // @Param status query string false "Transaction status"
 // @Param token query string false "Token symbol"
 // @Param start_date query string false "Start date RFC3339"
 // @Param end_date query string false "End date RFC3339"
+// @Param hide_spam query bool false "Drop transactions judged spam: spam tokens and zero-value transfers the wallet did not send"
 // @Success 200 {object} handlers.TransactionListResponse
 // @Failure 500 {object} handlers.ErrorResponse
 // @Router /wallets/{wallet}/transactions [get]
@@ -91,6 +92,10 @@ func (h *TransactionsHandler) List(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if v, err := strconv.ParseBool(r.URL.Query().Get("hide_spam")); err == nil {
+		filters.HideSpam = v
+	}
+
 	txs, err := h.service.List(r.Context(), chain, wallet, page, limit, filters)
 	if err != nil {
 		h.logger.Error("get-transactions-failed", zap.Error(err))
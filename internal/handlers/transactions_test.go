@@ -14,7 +14,9 @@ import (
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
 )
 
-type mockTxService struct{}
+type mockTxService struct {
+	filters transactions.Filters
+}
 
 type txListResponse struct {
 	Success bool `json:"success"`
@@ -33,6 +35,7 @@ func (m *mockTxService) List(
 	limit int,
 	filters transactions.Filters,
 ) ([]transactions.Transaction, error) {
+	m.filters = filters
 	return []transactions.Transaction{
 		{
 			Hash:  "tx1",
@@ -66,3 +69,15 @@ func TestTransactionsHandler_List(t *testing.T) {
 	require.Len(t, resp.Data.Items, 1)
 	require.Equal(t, "tx1", resp.Data.Items[0].Hash)
 }
+
+func TestTransactionsHandler_List_HideSpam(t *testing.T) {
+	svc := &mockTxService{}
+	r := chi.NewRouter()
+	r.Get("/wallets/{wallet}/transactions", NewTransactionsHandler(svc, zap.NewNop()).List)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/wallets/0xabc/transactions?chain=1&hide_spam=true", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.True(t, svc.filters.HideSpam)
+}
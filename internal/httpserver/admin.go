@@ -0,0 +1,29 @@
+package httpserver
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/handlers"
+)
+
+// requireAdminKey guards admin routes with a shared key sent in X-Admin-Key.
+// With no key configured the admin API is disabled.
+func requireAdminKey(key string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if key == "" {
+				handlers.RespondError(w, http.StatusForbidden, "ADMIN_DISABLED", "admin API is disabled")
+				return
+			}
+
+			given := r.Header.Get("X-Admin-Key")
+			if subtle.ConstantTimeCompare([]byte(given), []byte(key)) != 1 {
+				handlers.RespondError(w, http.StatusUnauthorized, "UNAUTHORIZED", "invalid admin key")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -9,7 +9,31 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
-func NewRouter(pricesHandler *handlers.PricesHandler, txHandler *handlers.TransactionsHandler, portfolioHander *handlers.PortfolioHandler) http.Handler {
+// Handlers groups every HTTP handler the router mounts
+type Handlers struct {
+	Prices         *handlers.PricesHandler
+	Transactions   *handlers.TransactionsHandler
+	Portfolio      *handlers.PortfolioHandler
+	Stream         *handlers.StreamHandler
+	PriceOverrides *handlers.PriceOverridesHandler
+	Tokens         *handlers.TokensHandler
+	Alerts         *handlers.AlertsHandler
+	History        *handlers.PortfolioHistoryHandler
+	Performance    *handlers.PerformanceHandler
+	Allocation     *handlers.AllocationHandler
+	Rebalance      *handlers.RebalanceHandler
+	PnL            *handlers.PnLHandler
+	Tax            *handlers.TaxHandler
+	Export         *handlers.ExportHandler
+	Import         *handlers.ImportHandler
+	Sync           *handlers.SyncHandler
+	Reconcile      *handlers.ReconcileHandler
+	Suggestions    *handlers.SuggestionsHandler
+	Spam           *handlers.SpamHandler
+	NFT            *handlers.NFTHandler
+}
+
+func NewRouter(h Handlers, adminKey string) http.Handler {
 	r := chi.NewRouter()
 
 	// Middleware
@@ -22,17 +46,78 @@ func NewRouter(pricesHandler *handlers.PricesHandler, txHandler *handlers.Transa
 	handlers.HealthRoute(r)
 
 	// price route
-	r.Post("/prices", pricesHandler.GetPrices)
+	r.Post("/prices", h.Prices.GetPrices)
+	r.Get("/prices/{chain}/{contract}/ohlc", h.Prices.GetOHLC)
+
+	r.Get("/tokens/search", h.Tokens.Search)
+	r.Get("/tokens/{chain}/{contract}", h.Tokens.Get)
 
-	r.Get("/wallets/{wallet}/transactions", txHandler.List)
+	r.Get("/wallets/{wallet}/transactions", h.Transactions.List)
+	r.Get("/wallets/{wallet}/transactions/export", h.Export.Export)
+	r.Get("/wallets/{wallet}/alerts", h.Alerts.History)
+	r.Get("/wallets/{wallet}/pnl", h.PnL.Get)
+	r.Get("/wallets/{wallet}/tax/report", h.Tax.Report)
+	r.Get("/tax/jurisdictions", h.Tax.Jurisdictions)
+
+	r.Route("/wallets/{wallet}/spam/overrides", func(r chi.Router) {
+		r.Get("/", h.Spam.ListOverrides)
+		r.Put("/", h.Spam.SetOverride)
+		r.Delete("/", h.Spam.DeleteOverride)
+	})
 
 	r.Route("/wallets/{wallet}/portfolio", func(r chi.Router) {
-		r.Get("/", portfolioHander.Get)
+		r.Get("/", h.Portfolio.Get)
+		r.Get("/history", h.History.Get)
+		r.Get("/performance", h.Performance.Get)
+		r.Get("/allocation", h.Allocation.Get)
+		r.Get("/rebalance", h.Rebalance.Plan)
+		r.Post("/import", h.Import.Import)
+		r.Post("/sync", h.Sync.Sync)
+		r.Get("/reconcile", h.Reconcile.Get)
+		r.Post("/reconcile/accept", h.Reconcile.Accept)
+		r.Get("/suggestions", h.Suggestions.Get)
+		r.Post("/nfts/sync", h.NFT.Sync)
+
+		r.Route("/targets", func(r chi.Router) {
+			r.Get("/", h.Rebalance.GetTarget)
+			r.Put("/", h.Rebalance.SetTarget)
+			r.Delete("/", h.Rebalance.DeleteTarget)
+		})
 
 		r.Route("/holdings", func(r chi.Router) {
-			r.Post("/", portfolioHander.AddHolding)
-			r.Put("/", portfolioHander.UpdateHolding)
-			r.Delete("/", portfolioHander.RemoveHolding)
+			r.Post("/", h.Portfolio.AddHolding)
+			r.Put("/", h.Portfolio.UpdateHolding)
+			r.Delete("/", h.Portfolio.RemoveHolding)
+		})
+	})
+
+	r.Get("/allocation/categories", h.Allocation.Categories)
+
+	r.Route("/alerts/rules", func(r chi.Router) {
+		r.Get("/", h.Alerts.ListRules)
+		r.Post("/", h.Alerts.CreateRule)
+		r.Get("/{id}", h.Alerts.GetRule)
+		r.Put("/{id}", h.Alerts.UpdateRule)
+		r.Delete("/{id}", h.Alerts.DeleteRule)
+	})
+
+	// live price and portfolio updates
+	r.Get("/ws", h.Stream.Stream)
+
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(requireAdminKey(adminKey))
+
+		r.Route("/prices/overrides", func(r chi.Router) {
+			r.Get("/", h.PriceOverrides.List)
+			r.Put("/", h.PriceOverrides.Set)
+			r.Delete("/", h.PriceOverrides.Delete)
+		})
+
+		r.Post("/tokens/lists", h.Tokens.ImportList)
+
+		r.Route("/allocation/assignments", func(r chi.Router) {
+			r.Put("/", h.Allocation.Assign)
+			r.Delete("/", h.Allocation.Unassign)
 		})
 	})
 
@@ -0,0 +1,159 @@
+package importer
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrUnknownPreset    = errors.New("unknown import preset")
+	ErrInvalidMode      = errors.New("invalid import mode")
+	ErrInvalidFile      = errors.New("file is not valid CSV")
+	ErrMissingColumn    = errors.New("required column missing from the header")
+	ErrUnsupportedChain = errors.New("unsupported chain")
+)
+
+// Kind says how a file's rows turn into holdings
+type Kind string
+
+const (
+	KindBalance Kind = "balance" // one row per asset holding its amount
+	KindLedger  Kind = "ledger"  // signed changes summed per asset
+)
+
+// Mapping names the columns holding each field. Chain and Contract are
+// optional: without a contract the asset is resolved from Symbol on the
+// row's chain, or the import's default chain.
+type Mapping struct {
+	Chain    string `json:"chain,omitempty"`
+	Contract string `json:"contract,omitempty"`
+	Symbol   string `json:"symbol,omitempty"`
+	Amount   string `json:"amount"`
+	Fee      string `json:"fee,omitempty"` // subtracted from the amount in ledgers
+}
+
+// override replaces the columns set in o
+func (m Mapping) override(o Mapping) Mapping {
+	if o.Chain != "" {
+		m.Chain = o.Chain
+	}
+	if o.Contract != "" {
+		m.Contract = o.Contract
+	}
+	if o.Symbol != "" {
+		m.Symbol = o.Symbol
+	}
+	if o.Amount != "" {
+		m.Amount = o.Amount
+	}
+	if o.Fee != "" {
+		m.Fee = o.Fee
+	}
+	return m
+}
+
+// Preset is the layout of a known export
+type Preset struct {
+	Name    string  `json:"name"`
+	Kind    Kind    `json:"kind"`
+	Mapping Mapping `json:"mapping"`
+	// symbol rewrites an exchange's asset code to its common ticker
+	symbol func(string) string
+}
+
+var presets = map[string]Preset{
+	"generic": {
+		Name: "generic",
+		Kind: KindBalance,
+		Mapping: Mapping{
+			Chain:    "chain",
+			Contract: "contract_address",
+			Symbol:   "symbol",
+			Amount:   "amount",
+		},
+	},
+	// Binance "Transaction History" export
+	"binance": {
+		Name:    "binance",
+		Kind:    KindLedger,
+		Mapping: Mapping{Symbol: "Coin", Amount: "Change"},
+	},
+	// Kraken ledger export
+	"kraken": {
+		Name:    "kraken",
+		Kind:    KindLedger,
+		Mapping: Mapping{Symbol: "asset", Amount: "amount", Fee: "fee"},
+		symbol:  krakenSymbol,
+	},
+	// Coinbase "Portfolio" balance export
+	"coinbase": {
+		Name:    "coinbase",
+		Kind:    KindBalance,
+		Mapping: Mapping{Symbol: "Asset", Amount: "Quantity"},
+	},
+}
+
+// DefaultPreset is used when no preset is requested
+const DefaultPreset = "generic"
+
+func LookupPreset(name string) (Preset, error) {
+	if name == "" {
+		name = DefaultPreset
+	}
+	p, ok := presets[strings.ToLower(name)]
+	if !ok {
+		return Preset{}, ErrUnknownPreset
+	}
+	return p, nil
+}
+
+// krakenSymbol strips the X and Z class prefixes of Kraken's legacy
+// four-letter codes and the staking suffixes, and renames XBT and the
+// ETH2 staking balance
+func krakenSymbol(code string) string {
+	code = strings.ToUpper(code)
+	for _, suffix := range []string{".S", ".M", ".F", ".B"} {
+		code = strings.TrimSuffix(code, suffix)
+	}
+	if len(code) == 4 && (code[0] == 'X' || code[0] == 'Z') {
+		code = code[1:]
+	}
+	switch code {
+	case "XBT":
+		code = "BTC"
+	case "ETH2":
+		code = "ETH"
+	}
+	return code
+}
+
+// aliases map tickers with no token of their own on EVM chains to the
+// wrapped token held in their place
+var aliases = map[string]string{
+	"BTC": "WBTC",
+}
+
+// fiat codes appear in exchange exports but are not tracked as holdings
+var fiat = map[string]bool{
+	"USD": true, "EUR": true, "GBP": true, "JPY": true, "CAD": true, "AUD": true,
+	"CHF": true, "KRW": true, "TRY": true, "BRL": true, "NGN": true, "INR": true,
+}
+
+// Mode says how imported amounts combine with holdings already recorded
+type Mode string
+
+const (
+	ModeSet Mode = "set" // imported amounts replace recorded ones
+	ModeAdd Mode = "add" // imported amounts are added to recorded ones
+)
+
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(strings.ToLower(s)); m {
+	case "":
+		return ModeSet, nil
+	case ModeSet, ModeAdd:
+		return m, nil
+	default:
+		return "", ErrInvalidMode
+	}
+}
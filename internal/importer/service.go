@@ -0,0 +1,474 @@
+package importer
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+)
+
+// zero absorbs float rounding when ledger changes net out
+const zero = 1e-12
+
+var contractPattern = regexp.MustCompile(`^0x[0-9a-f]{40}$`)
+
+// Options tune how a file is read and applied
+type Options struct {
+	Preset       string
+	Mapping      Mapping // columns set here replace the preset's
+	DefaultChain string  // chain of rows without one; ethereum when empty
+	Mode         Mode
+	DryRun       bool
+}
+
+// RowError is a problem with one row. Rows are numbered as lines in the
+// file, the header being row 1.
+type RowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// Skip is a row left out on purpose, such as a fiat balance
+type Skip struct {
+	Row    int    `json:"row"`
+	Reason string `json:"reason"`
+}
+
+type Action string
+
+const (
+	ActionAdd       Action = "add"
+	ActionUpdate    Action = "update"
+	ActionRemove    Action = "remove"
+	ActionUnchanged Action = "unchanged"
+)
+
+// Line is the holding an import produces for one asset
+type Line struct {
+	Chain           string  `json:"chain"`
+	ContractAddress string  `json:"contract_address"`
+	Symbol          string  `json:"symbol,omitempty"`
+	Imported        float64 `json:"imported"` // amount read from the file
+	Previous        float64 `json:"previous"` // amount recorded before the import
+	Amount          float64 `json:"amount"`   // amount once applied
+	Action          Action  `json:"action"`
+	Rows            []int   `json:"rows"`
+}
+
+// Result describes what an import did, or would do on a dry run. Nothing
+// is applied when any row has an error.
+type Result struct {
+	Wallet   string     `json:"wallet"`
+	Preset   string     `json:"preset"`
+	Kind     Kind       `json:"kind"`
+	Mode     Mode       `json:"mode"`
+	DryRun   bool       `json:"dry_run"`
+	Applied  bool       `json:"applied"`
+	Rows     int        `json:"rows"`
+	Holdings []Line     `json:"holdings"`
+	Errors   []RowError `json:"errors"`
+	Skipped  []Skip     `json:"skipped,omitempty"`
+}
+
+type ServiceAPI interface {
+	Import(ctx context.Context, wallet string, r io.Reader, opts Options) (*Result, error)
+}
+
+type Service struct {
+	portfolio portfolio.Service
+	search    tokens.SearchAPI
+	logger    *zap.Logger
+}
+
+func NewService(portfolio portfolio.Service, search tokens.SearchAPI, logger *zap.Logger) *Service {
+	return &Service{
+		portfolio: portfolio,
+		search:    search,
+		logger:    logger.With(zap.String("service", "importer")),
+	}
+}
+
+// Import reads holdings from CSV, validates every row and, unless it is a
+// dry run or a row failed, applies them to the wallet in one save
+func (s *Service) Import(ctx context.Context, wallet string, r io.Reader, opts Options) (*Result, error) {
+	preset, err := LookupPreset(opts.Preset)
+	if err != nil {
+		return nil, err
+	}
+	mode, err := ParseMode(string(opts.Mode))
+	if err != nil {
+		return nil, err
+	}
+	defaultChain := tokens.ChainName(opts.DefaultChain)
+	if defaultChain == "" {
+		defaultChain = "ethereum"
+	}
+	if _, ok := tokens.ChainID(defaultChain); !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedChain, defaultChain)
+	}
+
+	s.logger.Info("import-holdings",
+		zap.String("wallet", wallet),
+		zap.String("preset", preset.Name),
+		zap.Bool("dry_run", opts.DryRun),
+	)
+
+	p := &parser{
+		svc:          s,
+		preset:       preset,
+		mapping:      preset.Mapping.override(opts.Mapping),
+		defaultChain: defaultChain,
+		resolved:     make(map[string]resolution),
+	}
+	lines, err := p.parse(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{
+		Wallet:   wallet,
+		Preset:   preset.Name,
+		Kind:     preset.Kind,
+		Mode:     mode,
+		DryRun:   opts.DryRun,
+		Rows:     p.rows,
+		Holdings: []Line{},
+		Errors:   p.errors,
+		Skipped:  p.skipped,
+	}
+	if res.Errors == nil {
+		res.Errors = []RowError{}
+	}
+
+	current, err := s.portfolio.Holdings(ctx, wallet)
+	if err != nil {
+		// a wallet without a portfolio gets one on apply
+		current = nil
+	}
+	previous := make(map[pricing.AssetRef]float64, len(current))
+	for _, h := range current {
+		// synced balances are kept apart and not touched by imports
+		if h.Source == portfolio.HoldingSourceManual {
+			previous[h.AssetRef()] += h.Amount
+		}
+	}
+
+	changed := make([]portfolio.Holding, 0, len(lines))
+	for _, l := range lines {
+		l.Previous = previous[pricing.AssetRef{Chain: l.Chain, ContractAddress: l.ContractAddress}]
+		l.Amount = l.Imported
+		if mode == ModeAdd {
+			l.Amount += l.Previous
+		}
+
+		switch {
+		case l.Amount == l.Previous:
+			l.Action = ActionUnchanged
+		case l.Previous == 0:
+			l.Action = ActionAdd
+		case l.Amount == 0:
+			l.Action = ActionRemove
+		default:
+			l.Action = ActionUpdate
+		}
+		if l.Action == ActionUnchanged && l.Amount == 0 {
+			continue
+		}
+
+		res.Holdings = append(res.Holdings, *l)
+		if l.Action != ActionUnchanged {
+			changed = append(changed, portfolio.Holding{
+				Chain:           l.Chain,
+				ContractAddress: l.ContractAddress,
+				Amount:          l.Amount,
+			})
+		}
+	}
+
+	if opts.DryRun || len(res.Errors) > 0 || len(changed) == 0 {
+		return res, nil
+	}
+
+	if err := s.portfolio.SetHoldings(ctx, wallet, changed); err != nil {
+		return nil, err
+	}
+	res.Applied = true
+
+	s.logger.Info("holdings-imported",
+		zap.String("wallet", wallet),
+		zap.Int("changed", len(changed)),
+	)
+	return res, nil
+}
+
+type resolution struct {
+	asset pricing.AssetRef
+	err   string
+}
+
+// parser turns rows into one line per asset, collecting row errors
+type parser struct {
+	svc          *Service
+	preset       Preset
+	mapping      Mapping
+	defaultChain string
+
+	columns  map[string]int
+	resolved map[string]resolution // chain:symbol -> asset
+
+	rows    int
+	errors  []RowError
+	skipped []Skip
+}
+
+func (p *parser) parse(ctx context.Context, r io.Reader) ([]*Line, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	var perr *csv.ParseError
+	switch {
+	case errors.Is(err, io.EOF):
+		return nil, fmt.Errorf("%w: no header row", ErrInvalidFile)
+	case errors.As(err, &perr):
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, perr)
+	case err != nil:
+		return nil, err
+	}
+	if err := p.index(header); err != nil {
+		return nil, err
+	}
+
+	byAsset := make(map[pricing.AssetRef]*Line)
+	var order []pricing.AssetRef
+
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if errors.As(err, &perr) {
+			p.fail(perr.StartLine, "", perr.Err.Error())
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if blank(record) {
+			continue
+		}
+		row, _ := cr.FieldPos(0)
+		p.rows++
+
+		asset, symbol, ok, err := p.asset(ctx, row, record)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		amount, ok := p.number(row, record, p.mapping.Amount, true)
+		if !ok {
+			continue
+		}
+		if p.preset.Kind == KindBalance && amount < 0 {
+			p.fail(row, p.mapping.Amount, "amount must not be negative")
+			continue
+		}
+		if p.preset.Kind == KindLedger && p.mapping.Fee != "" {
+			fee, ok := p.number(row, record, p.mapping.Fee, false)
+			if !ok {
+				continue
+			}
+			amount -= fee
+		}
+
+		line, seen := byAsset[asset]
+		if seen && p.preset.Kind == KindBalance {
+			p.fail(row, "", fmt.Sprintf("%s is also on row %d", asset, line.Rows[0]))
+			continue
+		}
+		if !seen {
+			line = &Line{Chain: asset.Chain, ContractAddress: asset.ContractAddress, Symbol: symbol}
+			byAsset[asset] = line
+			order = append(order, asset)
+		}
+		line.Imported += amount
+		line.Rows = append(line.Rows, row)
+	}
+
+	lines := make([]*Line, 0, len(order))
+	for _, asset := range order {
+		l := byAsset[asset]
+		if math.Abs(l.Imported) < zero {
+			l.Imported = 0
+		}
+		if l.Imported < 0 {
+			p.fail(l.Rows[len(l.Rows)-1], p.mapping.Amount, fmt.Sprintf("changes to %s add up to %g", asset, l.Imported))
+			continue
+		}
+		lines = append(lines, l)
+	}
+	return lines, nil
+}
+
+// index finds the mapped columns in the header, ignoring case
+func (p *parser) index(header []string) error {
+	p.columns = make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimPrefix(name, "\ufeff")
+		p.columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	required := []string{p.mapping.Amount}
+	if _, ok := p.column(p.mapping.Contract); !ok {
+		required = append(required, p.mapping.Symbol)
+	}
+	if p.mapping.Fee != "" && p.preset.Kind == KindLedger {
+		required = append(required, p.mapping.Fee)
+	}
+	for _, name := range required {
+		if _, ok := p.column(name); !ok {
+			return fmt.Errorf("%w: %q", ErrMissingColumn, name)
+		}
+	}
+	return nil
+}
+
+func (p *parser) column(name string) (int, bool) {
+	if name == "" {
+		return 0, false
+	}
+	i, ok := p.columns[strings.ToLower(name)]
+	return i, ok
+}
+
+func (p *parser) field(record []string, name string) string {
+	i, ok := p.column(name)
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+func (p *parser) number(row int, record []string, column string, required bool) (float64, bool) {
+	v := p.field(record, column)
+	if v == "" {
+		if required {
+			p.fail(row, column, "value is required")
+			return 0, false
+		}
+		return 0, true
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+		p.fail(row, column, fmt.Sprintf("%q is not a number", v))
+		return 0, false
+	}
+	return n, true
+}
+
+// asset resolves a row to a chain and contract. It reports false when the
+// row failed or was skipped; the error is only set when lookups are down.
+func (p *parser) asset(ctx context.Context, row int, record []string) (pricing.AssetRef, string, bool, error) {
+	chain := p.defaultChain
+	if v := p.field(record, p.mapping.Chain); v != "" {
+		chain = tokens.ChainName(strings.ToLower(v))
+	}
+	if _, ok := tokens.ChainID(chain); !ok {
+		p.fail(row, p.mapping.Chain, fmt.Sprintf("unsupported chain %q", chain))
+		return pricing.AssetRef{}, "", false, nil
+	}
+
+	symbol := strings.ToUpper(p.field(record, p.mapping.Symbol))
+	if p.preset.symbol != nil && symbol != "" {
+		symbol = p.preset.symbol(symbol)
+	}
+
+	if contract := strings.ToLower(p.field(record, p.mapping.Contract)); contract != "" {
+		if !contractPattern.MatchString(contract) {
+			p.fail(row, p.mapping.Contract, fmt.Sprintf("%q is not a contract address", contract))
+			return pricing.AssetRef{}, "", false, nil
+		}
+		return pricing.AssetRef{Chain: chain, ContractAddress: contract}, symbol, true, nil
+	}
+
+	if symbol == "" {
+		p.fail(row, p.mapping.Symbol, "a symbol or contract address is required")
+		return pricing.AssetRef{}, "", false, nil
+	}
+	if fiat[symbol] {
+		p.skipped = append(p.skipped, Skip{Row: row, Reason: "fiat currency " + symbol})
+		return pricing.AssetRef{}, "", false, nil
+	}
+
+	res, err := p.resolve(ctx, chain, symbol)
+	if err != nil {
+		return pricing.AssetRef{}, "", false, err
+	}
+	if res.err != "" {
+		p.fail(row, p.mapping.Symbol, res.err)
+		return pricing.AssetRef{}, "", false, nil
+	}
+	return res.asset, symbol, true, nil
+}
+
+// resolve finds the token with exactly this symbol on the chain, preferring
+// the largest by market cap. Results are kept for the rest of the file.
+func (p *parser) resolve(ctx context.Context, chain, symbol string) (resolution, error) {
+	key := chain + ":" + symbol
+	if res, ok := p.resolved[key]; ok {
+		return res, nil
+	}
+
+	lookup := symbol
+	if alias, ok := aliases[symbol]; ok {
+		lookup = alias
+	}
+
+	res := resolution{err: fmt.Sprintf("no token with symbol %s on %s", symbol, chain)}
+	if p.svc.search == nil {
+		res.err = "symbol lookup is not available; give a contract address"
+	} else {
+		matches, err := p.svc.search.Search(ctx, lookup, chain, 20)
+		if err != nil {
+			return resolution{}, err
+		}
+		for _, m := range matches {
+			if strings.EqualFold(m.Symbol, lookup) {
+				res = resolution{asset: pricing.AssetRef{Chain: m.Chain, ContractAddress: m.ContractAddress}}
+				break
+			}
+		}
+	}
+
+	p.resolved[key] = res
+	return res, nil
+}
+
+func (p *parser) fail(row int, column, msg string) {
+	p.errors = append(p.errors, RowError{Row: row, Column: column, Message: msg})
+}
+
+func blank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,183 @@
+package importer_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/importer"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+)
+
+const (
+	wallet = "0xabc"
+	usdc   = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	wbtc   = "0x2260fac5e5542a773aa44fbcf97e8464a3ffe8f4"
+)
+
+type fakeSearch struct {
+	tokens.SearchAPI
+	results []tokens.SearchResult
+}
+
+func (f *fakeSearch) Search(ctx context.Context, query, chain string, limit int) ([]tokens.SearchResult, error) {
+	var out []tokens.SearchResult
+	for _, r := range f.results {
+		if r.Chain == chain && strings.HasPrefix(strings.ToLower(r.Symbol), strings.ToLower(query)) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func newService(t *testing.T, initial ...portfolio.Holding) (*importer.Service, portfolio.Service) {
+	t.Helper()
+
+	var seed []*portfolio.Portfolio
+	if len(initial) > 0 {
+		seed = append(seed, &portfolio.Portfolio{Wallet: wallet, Holdings: initial})
+	}
+	ps := portfolio.NewService(portfolio.NewMemoryRepository(seed), nil, zap.NewNop())
+	search := &fakeSearch{results: []tokens.SearchResult{
+		{Symbol: "eth", Chain: "ethereum"},
+		{Symbol: "usdc", Chain: "ethereum", ContractAddress: usdc},
+		{Symbol: "usdce", Chain: "ethereum", ContractAddress: "0x1111111111111111111111111111111111111111"},
+		{Symbol: "wbtc", Chain: "ethereum", ContractAddress: wbtc},
+	}}
+	return importer.NewService(ps, search, zap.NewNop()), ps
+}
+
+func TestImportGenericApplies(t *testing.T) {
+	svc, ps := newService(t, portfolio.Holding{Chain: "ethereum", ContractAddress: usdc, Amount: 50})
+
+	csv := "Chain,Contract_Address,Symbol,Amount\n" +
+		"ethereum,,ETH,1.5\n" +
+		"1,,USDC,100\n" +
+		"\n" +
+		"ethereum,,EUR,20\n"
+
+	res, err := svc.Import(context.Background(), wallet, strings.NewReader(csv), importer.Options{})
+	require.NoError(t, err)
+	require.Empty(t, res.Errors)
+	require.True(t, res.Applied)
+	require.Equal(t, 3, res.Rows)
+	require.Equal(t, []importer.Skip{{Row: 5, Reason: "fiat currency EUR"}}, res.Skipped)
+	require.Len(t, res.Holdings, 2)
+
+	require.Equal(t, "", res.Holdings[0].ContractAddress)
+	require.Equal(t, importer.ActionAdd, res.Holdings[0].Action)
+	require.Equal(t, usdc, res.Holdings[1].ContractAddress)
+	require.Equal(t, importer.ActionUpdate, res.Holdings[1].Action)
+	require.Equal(t, 50.0, res.Holdings[1].Previous)
+	require.Equal(t, 100.0, res.Holdings[1].Amount)
+
+	hs, err := ps.Holdings(context.Background(), wallet)
+	require.NoError(t, err)
+	require.Len(t, hs, 2)
+}
+
+func TestImportRowErrorsApplyNothing(t *testing.T) {
+	svc, ps := newService(t, portfolio.Holding{Chain: "ethereum", ContractAddress: usdc, Amount: 50})
+
+	csv := "symbol,amount,contract_address,chain\n" +
+		"USDC,75,,\n" +
+		"DOGE,1,,\n" +
+		"ETH,abc,,\n" +
+		"ETH,-1,,\n" +
+		",1,0x123,\n" +
+		"ETH,1,,solana\n" +
+		"USDC,5,,\n"
+
+	res, err := svc.Import(context.Background(), wallet, strings.NewReader(csv), importer.Options{})
+	require.NoError(t, err)
+	require.False(t, res.Applied)
+	require.Len(t, res.Errors, 6)
+
+	rows := make([]int, len(res.Errors))
+	for i, e := range res.Errors {
+		rows[i] = e.Row
+	}
+	require.Equal(t, []int{3, 4, 5, 6, 7, 8}, rows)
+	require.Equal(t, "amount", res.Errors[1].Column)
+	require.Contains(t, res.Errors[5].Message, "also on row 2")
+
+	hs, err := ps.Holdings(context.Background(), wallet)
+	require.NoError(t, err)
+	require.Equal(t, 50.0, hs[0].Amount)
+}
+
+func TestImportDryRun(t *testing.T) {
+	svc, ps := newService(t)
+
+	res, err := svc.Import(context.Background(), wallet,
+		strings.NewReader("symbol,amount\nETH,2\n"),
+		importer.Options{DryRun: true},
+	)
+	require.NoError(t, err)
+	require.False(t, res.Applied)
+	require.Len(t, res.Holdings, 1)
+	require.Equal(t, importer.ActionAdd, res.Holdings[0].Action)
+
+	_, err = ps.Holdings(context.Background(), wallet)
+	require.Error(t, err)
+}
+
+func TestImportKrakenLedger(t *testing.T) {
+	svc, ps := newService(t, portfolio.Holding{Chain: "ethereum", ContractAddress: wbtc, Amount: 1})
+
+	csv := "txid,refid,time,type,subtype,aclass,asset,amount,fee,balance\n" +
+		"a,r1,2024-01-01,deposit,,currency,ZUSD,1000,0,1000\n" +
+		"b,r2,2024-01-02,trade,,currency,XXBT,0.5,0.001,0.499\n" +
+		"c,r3,2024-01-03,trade,,currency,XETH,2,0,2\n" +
+		"d,r4,2024-01-04,withdrawal,,currency,XETH,-0.5,0.005,1.495\n" +
+		"e,r5,2024-01-05,staking,,currency,ETH2.S,0.1,0,0.1\n"
+
+	res, err := svc.Import(context.Background(), wallet, strings.NewReader(csv),
+		importer.Options{Preset: "kraken", Mode: importer.ModeAdd},
+	)
+	require.NoError(t, err)
+	require.Empty(t, res.Errors)
+	require.True(t, res.Applied)
+
+	require.Len(t, res.Holdings, 2)
+	require.Equal(t, wbtc, res.Holdings[0].ContractAddress)
+	require.InDelta(t, 0.499, res.Holdings[0].Imported, 1e-9)
+	require.InDelta(t, 1.499, res.Holdings[0].Amount, 1e-9)
+	require.Equal(t, []int{4, 5, 6}, res.Holdings[1].Rows)
+	require.InDelta(t, 1.595, res.Holdings[1].Amount, 1e-9)
+
+	hs, err := ps.Holdings(context.Background(), wallet)
+	require.NoError(t, err)
+	require.Len(t, hs, 2)
+}
+
+func TestImportMissingColumn(t *testing.T) {
+	svc, _ := newService(t)
+
+	_, err := svc.Import(context.Background(), wallet,
+		strings.NewReader("Coin,Amount\nETH,1\n"),
+		importer.Options{Preset: "binance"},
+	)
+	require.ErrorIs(t, err, importer.ErrMissingColumn)
+
+	res, err := svc.Import(context.Background(), wallet,
+		strings.NewReader("Coin,Amount\nETH,1\n"),
+		importer.Options{Preset: "binance", Mapping: importer.Mapping{Amount: "amount"}, DryRun: true},
+	)
+	require.NoError(t, err)
+	require.Len(t, res.Holdings, 1)
+}
+
+func TestImportInvalidFile(t *testing.T) {
+	svc, _ := newService(t)
+
+	_, err := svc.Import(context.Background(), wallet, strings.NewReader(""), importer.Options{})
+	require.ErrorIs(t, err, importer.ErrInvalidFile)
+
+	_, err = svc.Import(context.Background(), wallet, strings.NewReader("symbol,amount\n"), importer.Options{Preset: "bitstamp"})
+	require.ErrorIs(t, err, importer.ErrUnknownPreset)
+}
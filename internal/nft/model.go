@@ -0,0 +1,36 @@
+package nft
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
+)
+
+var (
+	ErrInvalidAddress   = errors.New("wallet is not an address")
+	ErrUnsupportedChain = errors.New("unsupported chain")
+)
+
+var addressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// Token is an NFT the wallet still holds by its transfer history
+type Token struct {
+	Collection     string                     `json:"collection"`
+	CollectionName string                     `json:"collection_name"`
+	Symbol         string                     `json:"symbol"`
+	TokenID        string                     `json:"token_id"`
+	Standard       transactions.TokenStandard `json:"standard"`
+	Amount         float64                    `json:"amount"`
+	LastReceived   time.Time                  `json:"last_received"`
+}
+
+// Result is the outcome of syncing one wallet's NFTs on one chain
+type Result struct {
+	Wallet    string    `json:"wallet"`
+	Chain     string    `json:"chain"`
+	SyncedAt  time.Time `json:"synced_at"`
+	Tokens    []Token   `json:"tokens"`
+	Truncated bool      `json:"truncated"` // the history is longer than etherscan returns
+}
@@ -0,0 +1,151 @@
+package nft
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
+)
+
+type ServiceAPI interface {
+	Sync(ctx context.Context, wallet string, chain string) (*Result, error)
+}
+
+// Service works out the NFTs a wallet holds from its ERC-721 and ERC-1155
+// transfers and stores them as its NFT holdings
+type Service struct {
+	transfers transactions.NFTTransfersAPI
+	portfolio portfolio.Service
+	logger    *zap.Logger
+}
+
+func NewService(
+	transfers transactions.NFTTransfersAPI,
+	portfolio portfolio.Service,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		transfers: transfers,
+		portfolio: portfolio,
+		logger:    logger.With(zap.String("service", "nft")),
+	}
+}
+
+// tokenKey is one token of a collection
+type tokenKey struct {
+	collection string
+	tokenID    string
+}
+
+// Sync nets the wallet's NFT transfers on one chain per token and replaces
+// its NFT holdings there with the tokens it has a balance of
+func (s *Service) Sync(ctx context.Context, wallet string, chain string) (*Result, error) {
+	if !addressPattern.MatchString(wallet) {
+		return nil, ErrInvalidAddress
+	}
+	if chain == "" {
+		chain = "ethereum"
+	}
+	chain = tokens.ChainName(chain)
+	chainID, ok := tokens.ChainID(chain)
+	if !ok {
+		return nil, ErrUnsupportedChain
+	}
+
+	s.logger.Info("sync-nfts",
+		zap.String("wallet", wallet),
+		zap.String("chain", chain),
+	)
+
+	held, truncated, err := s.scan(ctx, chainID, wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{
+		Wallet:    wallet,
+		Chain:     chain,
+		SyncedAt:  time.Now().UTC().Truncate(time.Second),
+		Tokens:    []Token{},
+		Truncated: truncated,
+	}
+	for _, t := range held {
+		if t.Amount > 0 {
+			res.Tokens = append(res.Tokens, *t)
+		}
+	}
+	sort.Slice(res.Tokens, func(i, j int) bool {
+		a, b := res.Tokens[i], res.Tokens[j]
+		if a.Collection != b.Collection {
+			return a.Collection < b.Collection
+		}
+		return a.TokenID < b.TokenID
+	})
+
+	hs := make([]portfolio.NFTHolding, 0, len(res.Tokens))
+	for _, t := range res.Tokens {
+		hs = append(hs, portfolio.NFTHolding{
+			Chain:          chain,
+			Collection:     t.Collection,
+			CollectionName: t.CollectionName,
+			Symbol:         t.Symbol,
+			TokenID:        t.TokenID,
+			Standard:       t.Standard,
+			Amount:         t.Amount,
+		})
+	}
+	if err := s.portfolio.SyncNFTs(ctx, wallet, chain, hs); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("nfts-synced",
+		zap.String("wallet", wallet),
+		zap.String("chain", chain),
+		zap.Int("tokens", len(res.Tokens)),
+	)
+	return res, nil
+}
+
+// scan pages through the wallet's NFT transfers and nets them per token
+func (s *Service) scan(ctx context.Context, chainID, wallet string) (map[tokenKey]*Token, bool, error) {
+	wallet = strings.ToLower(wallet)
+	out := make(map[tokenKey]*Token)
+
+	txs, truncated, err := transactions.ReadPages(func(page, limit int) ([]transactions.Transaction, error) {
+		return s.transfers.NFTTransfers(ctx, chainID, wallet, page, limit)
+	}, transactions.HistoryPageSize, transactions.HistoryMaxPages)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, tx := range txs {
+		key := tokenKey{collection: strings.ToLower(tx.TokenAddr), tokenID: tx.TokenID}
+		t, ok := out[key]
+		if !ok {
+			t = &Token{Collection: key.collection, TokenID: key.tokenID, Symbol: tx.Token, Standard: tx.Standard}
+			if tx.TokenMeta != nil {
+				t.CollectionName = tx.TokenMeta.Name
+			}
+			out[key] = t
+		}
+
+		from := strings.ToLower(tx.From) == wallet
+		to := strings.ToLower(tx.To) == wallet
+		switch {
+		case from && !to:
+			t.Amount -= tx.Amount
+		case to && !from:
+			t.Amount += tx.Amount
+			if tx.Timestamp.After(t.LastReceived) {
+				t.LastReceived = tx.Timestamp
+			}
+		}
+	}
+	return out, truncated, nil
+}
@@ -0,0 +1,98 @@
+package nft_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/nft"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
+)
+
+const wallet = "0x00000000000000000000000000000000000000aa"
+
+type fakeTransfers struct {
+	txs   []transactions.Transaction
+	chain string
+}
+
+func (f *fakeTransfers) NFTTransfers(
+	ctx context.Context,
+	chain string,
+	wallet string,
+	page int,
+	limit int,
+) ([]transactions.Transaction, error) {
+	f.chain = chain
+	start := (page - 1) * limit
+	if start >= len(f.txs) {
+		return nil, nil
+	}
+	return f.txs[start:min(start+limit, len(f.txs))], nil
+}
+
+func transfer(from, to, collection, tokenID string, amount float64, standard transactions.TokenStandard, at int64) transactions.Transaction {
+	return transactions.Transaction{
+		From:      from,
+		To:        to,
+		Token:     "SYM",
+		TokenAddr: collection,
+		TokenID:   tokenID,
+		Standard:  standard,
+		Amount:    amount,
+		Type:      transactions.TypeNFT,
+		Timestamp: time.Unix(at, 0),
+	}
+}
+
+func TestSync(t *testing.T) {
+	transfers := &fakeTransfers{txs: []transactions.Transaction{
+		// sold punk 1, still holds punk 2
+		transfer(wallet, "0xbuyer", "0xPunks", "1", 1, transactions.StandardERC721, 300),
+		transfer("0xmint", wallet, "0xpunks", "2", 1, transactions.StandardERC721, 200),
+		transfer("0xmint", wallet, "0xpunks", "1", 1, transactions.StandardERC721, 100),
+		// gave one of three items away
+		transfer(wallet, "0xfriend", "0xitems", "7", 1, transactions.StandardERC1155, 250),
+		transfer("0xshop", wallet, "0xitems", "7", 3, transactions.StandardERC1155, 150),
+		// moved to itself
+		transfer(wallet, wallet, "0xitems", "8", 1, transactions.StandardERC1155, 50),
+	}}
+	repo := portfolio.NewMemoryRepository(nil)
+	svc := nft.NewService(transfers, portfolio.NewService(repo, nil, zap.NewNop()), zap.NewNop())
+
+	res, err := svc.Sync(context.Background(), wallet, "")
+	require.NoError(t, err)
+	require.Equal(t, "1", transfers.chain)
+	require.Equal(t, "ethereum", res.Chain)
+	require.False(t, res.Truncated)
+	require.Len(t, res.Tokens, 2)
+
+	require.Equal(t, "0xitems", res.Tokens[0].Collection)
+	require.Equal(t, 2.0, res.Tokens[0].Amount)
+	require.Equal(t, transactions.StandardERC1155, res.Tokens[0].Standard)
+
+	require.Equal(t, "0xpunks", res.Tokens[1].Collection)
+	require.Equal(t, "2", res.Tokens[1].TokenID)
+	require.Equal(t, "SYM", res.Tokens[1].Symbol)
+	require.Equal(t, time.Unix(200, 0), res.Tokens[1].LastReceived)
+
+	p, err := repo.Get(context.Background(), wallet)
+	require.NoError(t, err)
+	require.Len(t, p.NFTs, 2)
+	require.Equal(t, "ethereum", p.NFTs[1].Chain)
+	require.Equal(t, "2", p.NFTs[1].TokenID)
+}
+
+func TestSync_Errors(t *testing.T) {
+	svc := nft.NewService(&fakeTransfers{}, nil, zap.NewNop())
+
+	_, err := svc.Sync(context.Background(), "wallet1", "ethereum")
+	require.ErrorIs(t, err, nft.ErrInvalidAddress)
+
+	_, err = svc.Sync(context.Background(), wallet, "dogechain")
+	require.ErrorIs(t, err, nft.ErrUnsupportedChain)
+}
@@ -0,0 +1,187 @@
+package performance
+
+import (
+	"math"
+	"time"
+)
+
+const year = 365 * 24 * time.Hour
+
+// ValuePoint is a portfolio value at a point in time
+type ValuePoint struct {
+	Time     time.Time
+	ValueUSD float64
+}
+
+// CashFlow is money moved into (positive) or out of (negative) the portfolio
+// from outside it
+type CashFlow struct {
+	Time      time.Time `json:"time"`
+	AmountUSD float64   `json:"amount_usd"`
+	Chain     string    `json:"chain"`
+	Hash      string    `json:"hash"`
+}
+
+// PeriodReturns splits the series into sub-periods between consecutive points
+// and returns each one's Modified Dietz return, which weights a cash flow by
+// the share of the sub-period it was invested for. Flows outside the series
+// are ignored.
+func PeriodReturns(points []ValuePoint, flows []CashFlow) []float64 {
+	if len(points) < 2 {
+		return nil
+	}
+
+	returns := make([]float64, 0, len(points)-1)
+	for i := 1; i < len(points); i++ {
+		start, end := points[i-1], points[i]
+		length := end.Time.Sub(start.Time).Seconds()
+
+		var net, weighted float64
+		for _, f := range flows {
+			if !f.Time.After(start.Time) || f.Time.After(end.Time) {
+				continue
+			}
+			net += f.AmountUSD
+			weighted += f.AmountUSD * end.Time.Sub(f.Time).Seconds() / length
+		}
+
+		var r float64
+		if base := start.ValueUSD + weighted; base > 0 {
+			r = (end.ValueUSD - start.ValueUSD - net) / base
+		}
+		returns = append(returns, r)
+	}
+	return returns
+}
+
+// Compound chains period returns into a cumulative return
+func Compound(returns []float64) float64 {
+	growth := 1.0
+	for _, r := range returns {
+		growth *= 1 + r
+	}
+	return growth - 1
+}
+
+// Annualize scales a cumulative return earned over span to a yearly rate
+func Annualize(total float64, span time.Duration) float64 {
+	if span <= 0 || total <= -1 {
+		return 0
+	}
+	return math.Pow(1+total, float64(year)/float64(span)) - 1
+}
+
+// PeriodsPerYear is how many sub-periods of the series' average length fit
+// in a year
+func PeriodsPerYear(points []ValuePoint) float64 {
+	if len(points) < 2 {
+		return 0
+	}
+	avg := points[len(points)-1].Time.Sub(points[0].Time) / time.Duration(len(points)-1)
+	if avg <= 0 {
+		return 0
+	}
+	return float64(year) / float64(avg)
+}
+
+// Volatility is the annualized sample standard deviation of period returns
+func Volatility(returns []float64, periodsPerYear float64) float64 {
+	return stddev(returns) * math.Sqrt(periodsPerYear)
+}
+
+// Sharpe is the annualized excess return over riskFree per unit of
+// volatility. It reports false when volatility is zero.
+func Sharpe(returns []float64, periodsPerYear, riskFree float64) (float64, bool) {
+	vol := Volatility(returns, periodsPerYear)
+	if vol == 0 {
+		return 0, false
+	}
+	return (mean(returns)*periodsPerYear - riskFree) / vol, true
+}
+
+// MaxDrawdown is the largest fall from a peak of the growth index built from
+// period returns, as a positive fraction. Using returns rather than values
+// keeps deposits and withdrawals from showing up as gains or losses.
+func MaxDrawdown(returns []float64) float64 {
+	index, peak, worst := 1.0, 1.0, 0.0
+	for _, r := range returns {
+		index *= 1 + r
+		peak = math.Max(peak, index)
+		if peak > 0 {
+			worst = math.Max(worst, (peak-index)/peak)
+		}
+	}
+	return worst
+}
+
+// XIRR solves for the annual rate at which the dated amounts have a net
+// present value of zero, measuring time from the first amount. It reports
+// false when the amounts do not change sign or no rate is found.
+func XIRR(flows []CashFlow) (float64, bool) {
+	if len(flows) < 2 {
+		return 0, false
+	}
+
+	var pos, neg bool
+	for _, f := range flows {
+		pos = pos || f.AmountUSD > 0
+		neg = neg || f.AmountUSD < 0
+	}
+	if !pos || !neg {
+		return 0, false
+	}
+
+	t0 := flows[0].Time
+	npv := func(rate float64) float64 {
+		var sum float64
+		for _, f := range flows {
+			sum += f.AmountUSD / math.Pow(1+rate, float64(f.Time.Sub(t0))/float64(year))
+		}
+		return sum
+	}
+
+	// bisection on a bracket that is widened until the sign changes; NPV is
+	// monotonic in the rate for a conventional investment
+	lo, hi := -0.9999, 1.0
+	for npv(lo)*npv(hi) > 0 {
+		hi *= 2
+		if hi > 1e9 {
+			return 0, false
+		}
+	}
+	for i := 0; i < 200; i++ {
+		mid := (lo + hi) / 2
+		if npv(lo)*npv(mid) <= 0 {
+			hi = mid
+		} else {
+			lo = mid
+		}
+		if hi-lo < 1e-10 {
+			break
+		}
+	}
+	return (lo + hi) / 2, true
+}
+
+func mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+func stddev(xs []float64) float64 {
+	if len(xs) < 2 {
+		return 0
+	}
+	m := mean(xs)
+	var sq float64
+	for _, x := range xs {
+		sq += (x - m) * (x - m)
+	}
+	return math.Sqrt(sq / float64(len(xs)-1))
+}
@@ -0,0 +1,85 @@
+package performance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var day0 = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestPeriodReturns_ExcludesCashFlows(t *testing.T) {
+	points := []ValuePoint{
+		{Time: day0, ValueUSD: 1000},
+		{Time: day0.Add(24 * time.Hour), ValueUSD: 1100},
+		{Time: day0.Add(48 * time.Hour), ValueUSD: 2200},
+	}
+	// 1000 deposited at the very start of the second day, so the whole day
+	// counts it as invested
+	flows := []CashFlow{{Time: day0.Add(24*time.Hour + time.Nanosecond), AmountUSD: 1000}}
+
+	returns := PeriodReturns(points, flows)
+
+	require.Len(t, returns, 2)
+	require.InDelta(t, 0.10, returns[0], 1e-9)
+	require.InDelta(t, 100.0/2100, returns[1], 1e-6)
+	require.InDelta(t, 1.1*(1+100.0/2100)-1, Compound(returns), 1e-6)
+}
+
+func TestMaxDrawdown(t *testing.T) {
+	// 1 -> 1.2 -> 0.9 -> 1.08: worst fall is 25% from the 1.2 peak
+	require.InDelta(t, 0.25, MaxDrawdown([]float64{0.2, -0.25, 0.2}), 1e-9)
+	require.Zero(t, MaxDrawdown([]float64{0.1, 0.1}))
+}
+
+func TestXIRR(t *testing.T) {
+	flows := []CashFlow{
+		{Time: day0, AmountUSD: -1000},
+		{Time: day0.Add(year), AmountUSD: 1100},
+	}
+	irr, ok := XIRR(flows)
+	require.True(t, ok)
+	require.InDelta(t, 0.10, irr, 1e-6)
+
+	// a deposit half way needs a higher rate on the combined money
+	flows = []CashFlow{
+		{Time: day0, AmountUSD: -1000},
+		{Time: day0.Add(year / 2), AmountUSD: -1000},
+		{Time: day0.Add(year), AmountUSD: 2200},
+	}
+	irr, ok = XIRR(flows)
+	require.True(t, ok)
+	require.Greater(t, irr, 0.10)
+
+	_, ok = XIRR([]CashFlow{{Time: day0, AmountUSD: 100}, {Time: day0.Add(year), AmountUSD: 100}})
+	require.False(t, ok)
+}
+
+func TestSharpeAndVolatility(t *testing.T) {
+	returns := []float64{0.01, -0.01, 0.01, -0.01}
+
+	vol := Volatility(returns, 365)
+	require.Greater(t, vol, 0.0)
+
+	sharpe, ok := Sharpe(returns, 365, 0)
+	require.True(t, ok)
+	require.InDelta(t, 0, sharpe, 1e-9)
+
+	_, ok = Sharpe([]float64{0.01, 0.01}, 365, 0)
+	require.False(t, ok)
+}
+
+func TestParseBenchmark(t *testing.T) {
+	ref, err := ParseBenchmark("")
+	require.NoError(t, err)
+	require.Equal(t, "ethereum", ref.Chain)
+	require.Empty(t, ref.ContractAddress)
+
+	ref, err = ParseBenchmark("polygon-pos:0xABC")
+	require.NoError(t, err)
+	require.Equal(t, "0xabc", ref.ContractAddress)
+
+	_, err = ParseBenchmark("DOGE")
+	require.ErrorIs(t, err, ErrInvalidBenchmark)
+}
@@ -0,0 +1,114 @@
+package performance
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+var (
+	ErrInvalidPeriod       = errors.New("invalid period")
+	ErrInvalidBenchmark    = errors.New("invalid benchmark")
+	ErrInsufficientHistory = errors.New("not enough portfolio history for the period")
+)
+
+// Period is a lookback window ending now
+type Period string
+
+const (
+	Period7d  Period = "7d"
+	Period30d Period = "30d"
+	Period90d Period = "90d"
+	Period1y  Period = "1y"
+	PeriodYTD Period = "ytd"
+)
+
+func ParsePeriod(s string) (Period, error) {
+	switch p := Period(strings.ToLower(s)); p {
+	case "":
+		return Period30d, nil
+	case Period7d, Period30d, Period90d, Period1y, PeriodYTD:
+		return p, nil
+	default:
+		return "", ErrInvalidPeriod
+	}
+}
+
+// Start returns when the period ending at now begins
+func (p Period) Start(now time.Time) time.Time {
+	switch p {
+	case Period7d:
+		return now.AddDate(0, 0, -7)
+	case Period90d:
+		return now.AddDate(0, 0, -90)
+	case Period1y:
+		return now.AddDate(-1, 0, 0)
+	case PeriodYTD:
+		return time.Date(now.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return now.AddDate(0, 0, -30)
+	}
+}
+
+// benchmarks are the shorthand names accepted for a benchmark asset
+var benchmarks = map[string]pricing.AssetRef{
+	"ETH": {Chain: "ethereum"},
+	"BTC": {Chain: "ethereum", ContractAddress: "0x2260fac5e5542a773aa44fbcfedf7c193bc2c599"}, // WBTC
+}
+
+// DefaultBenchmark is used when no benchmark is requested
+const DefaultBenchmark = "ETH"
+
+// ParseBenchmark accepts a shorthand name (ETH, BTC) or chain:contract
+func ParseBenchmark(s string) (pricing.AssetRef, error) {
+	if s == "" {
+		s = DefaultBenchmark
+	}
+	if ref, ok := benchmarks[strings.ToUpper(s)]; ok {
+		return ref, nil
+	}
+
+	chain, contract, ok := strings.Cut(s, ":")
+	if !ok || chain == "" {
+		return pricing.AssetRef{}, ErrInvalidBenchmark
+	}
+	return pricing.AssetRef{Chain: chain, ContractAddress: strings.ToLower(contract)}, nil
+}
+
+// Metrics are the risk and return figures of one series. Percentages are in
+// percent; MoneyWeightedReturnPct and SharpeRatio are nil when they cannot
+// be computed.
+type Metrics struct {
+	TimeWeightedReturnPct  float64  `json:"time_weighted_return_pct"`
+	AnnualizedReturnPct    float64  `json:"annualized_return_pct"`
+	MoneyWeightedReturnPct *float64 `json:"money_weighted_return_pct,omitempty"` // annualized XIRR
+	VolatilityPct          float64  `json:"volatility_pct"`                      // annualized
+	MaxDrawdownPct         float64  `json:"max_drawdown_pct"`
+	SharpeRatio            *float64 `json:"sharpe_ratio,omitempty"`
+}
+
+// Benchmark is the same metrics for holding a single asset over the period
+type Benchmark struct {
+	Asset pricing.AssetRef `json:"asset"`
+	Metrics
+	ExcessReturnPct float64 `json:"excess_return_pct"` // portfolio TWR minus benchmark return
+}
+
+// Report is the performance of a wallet over a period
+type Report struct {
+	Wallet        string    `json:"wallet"`
+	Period        Period    `json:"period"`
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	StartValueUSD float64   `json:"start_value_usd"`
+	EndValueUSD   float64   `json:"end_value_usd"`
+	NetFlowsUSD   float64   `json:"net_flows_usd"` // deposits minus withdrawals
+	DataPoints    int       `json:"data_points"`
+	RiskFreePct   float64   `json:"risk_free_pct"`
+	Metrics
+	CashFlows []CashFlow `json:"cash_flows"`
+	Truncated bool       `json:"truncated,omitempty"` // the period's history was longer than could be read
+	Benchmark *Benchmark `json:"benchmark,omitempty"` // nil when the benchmark could not be priced
+}
@@ -0,0 +1,366 @@
+package performance
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/snapshots"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
+)
+
+type ServiceAPI interface {
+	Report(
+		ctx context.Context,
+		wallet string,
+		period Period,
+		benchmark pricing.AssetRef,
+	) (*Report, error)
+}
+
+// Service computes return and risk metrics from recorded portfolio
+// snapshots and the wallet's transfers in and out
+type Service struct {
+	history      snapshots.ServiceAPI
+	transactions transactions.ServiceAPI
+	transfers    transactions.TokenTransfersAPI
+	prices       pricing.ServiceAPI
+	riskFree     float64 // annual rate as a fraction
+	logger       *zap.Logger
+}
+
+func NewService(
+	history snapshots.ServiceAPI,
+	txs transactions.ServiceAPI,
+	transfers transactions.TokenTransfersAPI,
+	prices pricing.ServiceAPI,
+	riskFreePct float64,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		history:      history,
+		transactions: txs,
+		transfers:    transfers,
+		prices:       prices,
+		riskFree:     riskFreePct / 100,
+		logger:       logger.With(zap.String("service", "performance")),
+	}
+}
+
+// Report measures the wallet over the period using daily snapshots. Cash
+// flows are native and ERC-20 transfers in and out of the wallet; swaps and
+// staking calls move value within the portfolio and are not counted.
+func (s *Service) Report(
+	ctx context.Context,
+	wallet string,
+	period Period,
+	benchmark pricing.AssetRef,
+) (*Report, error) {
+	now := time.Now().UTC()
+	from := period.Start(now)
+
+	s.logger.Info("performance-report",
+		zap.String("wallet", wallet),
+		zap.String("period", string(period)),
+	)
+
+	snaps, err := s.history.History(ctx, wallet, from, now, snapshots.ResolutionDaily)
+	if err != nil {
+		return nil, err
+	}
+	if len(snaps) < 2 {
+		return nil, ErrInsufficientHistory
+	}
+
+	points := make([]ValuePoint, 0, len(snaps))
+	for _, snap := range snaps {
+		points = append(points, ValuePoint{Time: snap.TakenAt, ValueUSD: snap.TotalValueUSD})
+	}
+	start, end := points[0], points[len(points)-1]
+
+	flows, truncated := s.cashFlows(ctx, wallet, snaps)
+	returns := PeriodReturns(points, flows)
+
+	report := &Report{
+		Wallet:        wallet,
+		Period:        period,
+		From:          start.Time,
+		To:            end.Time,
+		StartValueUSD: start.ValueUSD,
+		EndValueUSD:   end.ValueUSD,
+		DataPoints:    len(points),
+		RiskFreePct:   s.riskFree * 100,
+		Metrics:       s.metrics(points, returns),
+		CashFlows:     flows,
+		Truncated:     truncated,
+	}
+
+	// the investor's view: the starting value and deposits are paid in,
+	// withdrawals and the ending value are paid out
+	irrFlows := []CashFlow{{Time: start.Time, AmountUSD: -start.ValueUSD}}
+	for _, f := range flows {
+		report.NetFlowsUSD += f.AmountUSD
+		irrFlows = append(irrFlows, CashFlow{Time: f.Time, AmountUSD: -f.AmountUSD})
+	}
+	irrFlows = append(irrFlows, CashFlow{Time: end.Time, AmountUSD: end.ValueUSD})
+	if irr, ok := XIRR(irrFlows); ok {
+		pct := irr * 100
+		report.MoneyWeightedReturnPct = &pct
+	}
+
+	if b, err := s.benchmark(ctx, benchmark, points); err != nil {
+		s.logger.Warn("benchmark-unavailable",
+			zap.String("asset", benchmark.String()),
+			zap.Error(err),
+		)
+	} else {
+		b.ExcessReturnPct = report.TimeWeightedReturnPct - b.TimeWeightedReturnPct
+		report.Benchmark = b
+	}
+
+	return report, nil
+}
+
+func (s *Service) metrics(points []ValuePoint, returns []float64) Metrics {
+	total := Compound(returns)
+	perYear := PeriodsPerYear(points)
+
+	m := Metrics{
+		TimeWeightedReturnPct: total * 100,
+		AnnualizedReturnPct:   Annualize(total, points[len(points)-1].Time.Sub(points[0].Time)) * 100,
+		VolatilityPct:         Volatility(returns, perYear) * 100,
+		MaxDrawdownPct:        MaxDrawdown(returns) * 100,
+	}
+	if sharpe, ok := Sharpe(returns, perYear, s.riskFree); ok {
+		m.SharpeRatio = &sharpe
+	}
+	return m
+}
+
+// benchmark prices the asset at each snapshot time from daily candles and
+// measures holding it over the same series
+func (s *Service) benchmark(ctx context.Context, asset pricing.AssetRef, points []ValuePoint) (*Benchmark, error) {
+	days := int(math.Ceil(time.Since(points[0].Time).Hours()/24)) + 1
+	days = min(days, pricing.MaxCandleDays)
+
+	candles, err := s.prices.GetCandles(ctx, asset, pricing.Interval1d, days)
+	if err != nil {
+		return nil, err
+	}
+
+	series := make([]ValuePoint, 0, len(points))
+	for _, p := range points {
+		price, ok := closeAt(candles, p.Time)
+		if !ok {
+			continue
+		}
+		series = append(series, ValuePoint{Time: p.Time, ValueUSD: price})
+	}
+	if len(series) < 2 {
+		return nil, ErrInsufficientHistory
+	}
+
+	return &Benchmark{
+		Asset:   asset,
+		Metrics: s.metrics(series, PeriodReturns(series, nil)),
+	}, nil
+}
+
+// closeAt returns the close of the latest candle opened at or before t
+func closeAt(candles []pricing.Candle, t time.Time) (float64, bool) {
+	var (
+		found  bool
+		latest pricing.Candle
+	)
+	for _, c := range candles {
+		if c.Time.After(t) {
+			continue
+		}
+		if !found || c.Time.After(latest.Time) {
+			latest = c
+			found = true
+		}
+	}
+	return latest.Close, found
+}
+
+// cashFlows finds transfers in and out of the wallet within the snapshot
+// range of the assets the snapshots held, and values them in USD. It
+// reports true when a chain's history was longer than could be read.
+func (s *Service) cashFlows(ctx context.Context, wallet string, snaps []snapshots.Snapshot) ([]CashFlow, bool) {
+	if s.transactions == nil {
+		return nil, false
+	}
+
+	from, to := snaps[0].TakenAt, snaps[len(snaps)-1].TakenAt
+
+	held := make(map[pricing.AssetRef]bool)
+	chains := make(map[string]bool) // whether tokens were held on the chain
+	for _, snap := range snaps {
+		for _, h := range snap.Holdings {
+			held[pricing.AssetRef{Chain: h.Chain, ContractAddress: strings.ToLower(h.ContractAddress)}] = true
+			chains[h.Chain] = chains[h.Chain] || h.ContractAddress != ""
+		}
+	}
+
+	var flows []CashFlow
+	var truncated bool
+	memo := pricing.NewHistoricalMemo(s.prices)
+	for chain, withTokens := range chains {
+		chainID, ok := tokens.ChainID(chain)
+		if !ok {
+			continue
+		}
+
+		txs, more, err := s.transfersBetween(ctx, chainID, wallet, from, to, withTokens)
+		if err != nil {
+			s.logger.Warn("list-cash-flows-failed",
+				zap.String("wallet", wallet),
+				zap.String("chain", chain),
+				zap.Error(err),
+			)
+			continue
+		}
+		truncated = truncated || more
+
+		for _, tx := range txs {
+			asset := pricing.AssetRef{Chain: chain, ContractAddress: strings.ToLower(tx.TokenAddr)}
+			if !held[asset] {
+				continue
+			}
+			price := priceAt(ctx, memo, asset, tx.Timestamp, snaps)
+			amount := tx.Amount * price
+			if tx.Direction == transactions.DirectionOut {
+				amount = -amount
+			}
+			flows = append(flows, CashFlow{
+				Time:      tx.Timestamp,
+				AmountUSD: amount,
+				Chain:     chain,
+				Hash:      tx.Hash,
+			})
+		}
+	}
+
+	sort.Slice(flows, func(i, j int) bool { return flows[i].Time.Before(flows[j].Time) })
+	return flows, truncated
+}
+
+// transfersBetween reads a wallet's transactions and, with tokens, its
+// ERC-20 transfers made after from, and keeps the successful ones up to to
+// that move value in or out of the wallet. Value a contract pays back
+// during the wallet's own call (a DEX payout, a refund, a withdrawal) is
+// the result of that call rather than a deposit, and tokens moved by its
+// own calls to other contracts (swaps, protocol deposits) stay in the
+// portfolio; only a call to the token itself transfers it out. It reports
+// true when either history was longer than could be read.
+func (s *Service) transfersBetween(
+	ctx context.Context,
+	chainID string,
+	wallet string,
+	from time.Time,
+	to time.Time,
+	withTokens bool,
+) ([]transactions.Transaction, bool, error) {
+	wallet = strings.ToLower(wallet)
+
+	txs, truncated, err := transactions.ReadPages(func(page, limit int) ([]transactions.Transaction, error) {
+		txs, err := s.transactions.List(ctx, chainID, wallet, page, limit, transactions.Filters{})
+		return transactions.NewerThan(txs, from), err
+	}, transactions.HistoryPageSize, transactions.HistoryMaxPages)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// the contract each of the wallet's own calls went to, by hash
+	ownCalls := make(map[string]string)
+	for _, tx := range txs {
+		if tx.ParentHash == "" && strings.EqualFold(tx.From, wallet) {
+			ownCalls[tx.Hash] = strings.ToLower(tx.To)
+		}
+	}
+
+	var out []transactions.Transaction
+	for _, tx := range txs {
+		if tx.TokenAddr != "" || tx.Timestamp.After(to) || !isCashFlow(tx) {
+			continue
+		}
+		if _, own := ownCalls[tx.ParentHash]; tx.ParentHash != "" && own {
+			continue
+		}
+		out = append(out, tx)
+	}
+
+	if !withTokens || s.transfers == nil {
+		return out, truncated, nil
+	}
+
+	transfers, more, err := transactions.ReadPages(func(page, limit int) ([]transactions.Transaction, error) {
+		txs, err := s.transfers.TokenTransfers(ctx, chainID, wallet, page, limit)
+		return transactions.NewerThan(txs, from), err
+	}, transactions.HistoryPageSize, transactions.HistoryMaxPages)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, tx := range transfers {
+		if tx.TokenAddr == "" || tx.Timestamp.After(to) || !isCashFlow(tx) {
+			continue
+		}
+		if tx.Spam != nil && tx.Spam.Spam {
+			continue
+		}
+		if called, own := ownCalls[tx.Hash]; own && called != strings.ToLower(tx.TokenAddr) {
+			continue
+		}
+		out = append(out, tx)
+	}
+	return out, truncated || more, nil
+}
+
+func isCashFlow(tx transactions.Transaction) bool {
+	if tx.Status != transactions.StatusSuccess || tx.Amount <= 0 {
+		return false
+	}
+	if tx.Type == transactions.TypeSwap || tx.Type == transactions.TypeStake {
+		return false
+	}
+	return tx.Direction == transactions.DirectionIn || tx.Direction == transactions.DirectionOut
+}
+
+// priceAt values an asset at t with the price recorded in the last
+// snapshot taken at or before t, which is at most a day old. Only when no
+// snapshot holds the asset yet is the historical price looked up.
+func priceAt(
+	ctx context.Context,
+	memo *pricing.HistoricalMemo,
+	asset pricing.AssetRef,
+	t time.Time,
+	snaps []snapshots.Snapshot,
+) float64 {
+	var price float64
+	for _, snap := range snaps {
+		if snap.TakenAt.After(t) {
+			break
+		}
+		for _, h := range snap.Holdings {
+			if h.Chain == asset.Chain && strings.EqualFold(h.ContractAddress, asset.ContractAddress) {
+				price = h.PriceUSD
+			}
+		}
+	}
+	if price > 0 {
+		return price
+	}
+
+	if hp, ok := memo.Price(ctx, asset, t); ok {
+		return hp.PriceUSD
+	}
+	return 0
+}
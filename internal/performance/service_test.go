@@ -0,0 +1,243 @@
+package performance
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/snapshots"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
+)
+
+type fakeHistory struct {
+	snaps []snapshots.Snapshot
+}
+
+func (f *fakeHistory) History(ctx context.Context, wallet string, from, to time.Time, resolution snapshots.Resolution) ([]snapshots.Snapshot, error) {
+	return f.snaps, nil
+}
+
+type fakeTransactions struct {
+	txs       []transactions.Transaction
+	transfers []transactions.Transaction
+	pages     int // List pages read
+}
+
+func (f *fakeTransactions) List(ctx context.Context, chain, wallet string, page, limit int, filters transactions.Filters) ([]transactions.Transaction, error) {
+	f.pages++
+	start := (page - 1) * limit
+	if start >= len(f.txs) {
+		return nil, nil
+	}
+	return f.txs[start:min(start+limit, len(f.txs))], nil
+}
+
+func (f *fakeTransactions) TokenTransfers(ctx context.Context, chain, wallet string, page, limit int) ([]transactions.Transaction, error) {
+	if page > 1 {
+		return nil, nil
+	}
+	return f.transfers, nil
+}
+
+type fakePricing struct {
+	pricing.ServiceAPI
+	candles    []pricing.Candle
+	historical float64
+}
+
+func (f *fakePricing) GetCandles(ctx context.Context, asset pricing.AssetRef, interval pricing.Interval, days int) ([]pricing.Candle, error) {
+	return f.candles, nil
+}
+
+func (f *fakePricing) GetHistoricalPrices(ctx context.Context, assets []pricing.AssetRef, at time.Time) (map[pricing.AssetRef]pricing.HistoricalPrice, error) {
+	out := make(map[pricing.AssetRef]pricing.HistoricalPrice)
+	for _, a := range assets {
+		out[a] = pricing.HistoricalPrice{PriceUSD: f.historical, Time: at}
+	}
+	return out, nil
+}
+
+func TestService_Report(t *testing.T) {
+	start := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -3)
+
+	snap := func(d int, value float64) snapshots.Snapshot {
+		return snapshots.Snapshot{
+			Wallet:        "0xabc",
+			TakenAt:       start.AddDate(0, 0, d),
+			TotalValueUSD: value,
+			Holdings: []snapshots.SnapshotHolding{
+				{Chain: "ethereum", Amount: value / 2000, PriceUSD: 2000, ValueUSD: value},
+			},
+		}
+	}
+
+	history := &fakeHistory{snaps: []snapshots.Snapshot{
+		snap(0, 2000),
+		snap(1, 2200),
+		snap(2, 4200), // 1 ETH deposited, no gain
+		snap(3, 3850),
+	}}
+
+	txs := &fakeTransactions{txs: []transactions.Transaction{
+		// after the last snapshot and not counted
+		{Hash: "0x4", Amount: 5, Type: transactions.TypeSend, Status: transactions.StatusSuccess, Direction: transactions.DirectionIn, Timestamp: start.AddDate(0, 0, 4)},
+		{Hash: "0x3", Amount: 1, Type: transactions.TypeSwap, Status: transactions.StatusSuccess, Direction: transactions.DirectionOut, Timestamp: start.AddDate(0, 0, 2).Add(time.Hour)},
+		{Hash: "0x2", Amount: 1, Type: transactions.TypeSend, Status: transactions.StatusSuccess, Direction: transactions.DirectionIn, Timestamp: start.AddDate(0, 0, 2)},
+		{Hash: "0x1", Amount: 1, Type: transactions.TypeSend, Status: transactions.StatusSuccess, Direction: transactions.DirectionIn, Timestamp: start},
+	}}
+
+	prices := &fakePricing{
+		// the snapshot's 2000 is used before a historical lookup
+		historical: 2500,
+		candles: []pricing.Candle{
+			{Time: start, Close: 100},
+			{Time: start.AddDate(0, 0, 1), Close: 110},
+			{Time: start.AddDate(0, 0, 2), Close: 121},
+			{Time: start.AddDate(0, 0, 3), Close: 110},
+		},
+	}
+
+	svc := NewService(history, txs, txs, prices, 0, zap.NewNop())
+
+	report, err := svc.Report(context.Background(), "0xabc", Period7d, pricing.AssetRef{Chain: "ethereum"})
+	require.NoError(t, err)
+
+	require.Len(t, report.CashFlows, 1)
+	require.Equal(t, "0x2", report.CashFlows[0].Hash)
+	require.Equal(t, 2000.0, report.NetFlowsUSD)
+
+	// +10%, 0% on the deposit day, then -8.33%
+	require.InDelta(t, (1.1*(3850.0/4200)-1)*100, report.TimeWeightedReturnPct, 1e-6)
+	require.InDelta(t, (1-3850.0/4200)*100, report.MaxDrawdownPct, 1e-6)
+	require.NotNil(t, report.MoneyWeightedReturnPct)
+	require.NotNil(t, report.SharpeRatio)
+
+	require.NotNil(t, report.Benchmark)
+	require.InDelta(t, 10.0, report.Benchmark.TimeWeightedReturnPct, 1e-6)
+	require.InDelta(t, report.TimeWeightedReturnPct-10, report.Benchmark.ExcessReturnPct, 1e-6)
+}
+
+func TestService_ReportNeedsTwoSnapshots(t *testing.T) {
+	svc := NewService(&fakeHistory{snaps: []snapshots.Snapshot{{TotalValueUSD: 1}}}, nil, nil, &fakePricing{}, 0, zap.NewNop())
+
+	_, err := svc.Report(context.Background(), "0xabc", Period30d, pricing.AssetRef{Chain: "ethereum"})
+	require.ErrorIs(t, err, ErrInsufficientHistory)
+}
+
+func TestService_ReportSkipsPayoutsOfOwnCalls(t *testing.T) {
+	start := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -2)
+
+	snap := func(d int, value float64) snapshots.Snapshot {
+		return snapshots.Snapshot{
+			Wallet:        "0xabc",
+			TakenAt:       start.AddDate(0, 0, d),
+			TotalValueUSD: value,
+			Holdings: []snapshots.SnapshotHolding{
+				{Chain: "ethereum", Amount: value / 2000, PriceUSD: 2000, ValueUSD: value},
+			},
+		}
+	}
+	history := &fakeHistory{snaps: []snapshots.Snapshot{snap(0, 2000), snap(1, 4000), snap(2, 6000)}}
+
+	withdraw := start.Add(6 * time.Hour)
+	payment := start.AddDate(0, 0, 1).Add(6 * time.Hour)
+	txs := &fakeTransactions{txs: []transactions.Transaction{
+		// a contract the wallet did not call pays it: an external deposit
+		{Hash: "0xother", ParentHash: "0xother", From: "0xsplitter", To: "0xabc", Amount: 1, Type: transactions.TypeReceive, Status: transactions.StatusSuccess, Direction: transactions.DirectionIn, Timestamp: payment},
+		// the wallet withdraws from a vault and is paid back by the vault
+		{Hash: "0xcall", From: "0xABC", To: "0xvault", Amount: 0, Type: transactions.TypeSend, Status: transactions.StatusSuccess, Direction: transactions.DirectionOut, Timestamp: withdraw},
+		{Hash: "0xcall", ParentHash: "0xcall", From: "0xvault", To: "0xabc", Amount: 1, Type: transactions.TypeReceive, Status: transactions.StatusSuccess, Direction: transactions.DirectionIn, Timestamp: withdraw},
+	}}
+
+	svc := NewService(history, txs, txs, &fakePricing{}, 0, zap.NewNop())
+
+	report, err := svc.Report(context.Background(), "0xabc", Period7d, pricing.AssetRef{Chain: "ethereum"})
+	require.NoError(t, err)
+
+	require.Len(t, report.CashFlows, 1)
+	require.Equal(t, "0xother", report.CashFlows[0].Hash)
+	require.Equal(t, 2000.0, report.NetFlowsUSD)
+}
+
+func TestService_ReportTokenFlows(t *testing.T) {
+	start := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -2)
+
+	snap := func(d int, usdc float64) snapshots.Snapshot {
+		return snapshots.Snapshot{
+			Wallet:        "0xabc",
+			TakenAt:       start.AddDate(0, 0, d),
+			TotalValueUSD: 2000 + usdc,
+			Holdings: []snapshots.SnapshotHolding{
+				{Chain: "ethereum", Amount: 1, PriceUSD: 2000, ValueUSD: 2000},
+				{Chain: "ethereum", ContractAddress: "0xusdc", Amount: usdc, PriceUSD: 1, ValueUSD: usdc},
+			},
+		}
+	}
+	history := &fakeHistory{snaps: []snapshots.Snapshot{snap(0, 1000), snap(1, 1500), snap(2, 1300)}}
+
+	at := func(d int) time.Time { return start.AddDate(0, 0, d).Add(6 * time.Hour) }
+	transfer := func(hash string, d int, token, from, to string, amount float64, dir transactions.Direction) transactions.Transaction {
+		return transactions.Transaction{Hash: hash, TokenAddr: token, From: from, To: to, Amount: amount, Type: transactions.TypeSend, Status: transactions.StatusSuccess, Direction: dir, Timestamp: at(d)}
+	}
+	txs := &fakeTransactions{
+		txs: []transactions.Transaction{
+			// the wallet sends USDC itself, then swaps some through a router
+			{Hash: "0xsend", From: "0xabc", To: "0xusdc", Type: transactions.TypeSend, Status: transactions.StatusSuccess, Direction: transactions.DirectionOut, Timestamp: at(1)},
+			{Hash: "0xswap", From: "0xabc", To: "0xrouter", Type: transactions.TypeSwap, Status: transactions.StatusSuccess, Direction: transactions.DirectionOut, Timestamp: at(1)},
+		},
+		transfers: []transactions.Transaction{
+			transfer("0xsend", 1, "0xUSDC", "0xabc", "0xbob", 200, transactions.DirectionOut),
+			transfer("0xswap", 1, "0xusdc", "0xabc", "0xpool", 300, transactions.DirectionOut),
+			// a deposit from an exchange, and a token the wallet never held
+			transfer("0xcex", 0, "0xusdc", "0xcex", "0xabc", 500, transactions.DirectionIn),
+			transfer("0xdrop", 0, "0xscam", "0xscammer", "0xabc", 1e6, transactions.DirectionIn),
+		},
+	}
+
+	svc := NewService(history, txs, txs, &fakePricing{}, 0, zap.NewNop())
+
+	report, err := svc.Report(context.Background(), "0xabc", Period7d, pricing.AssetRef{Chain: "ethereum"})
+	require.NoError(t, err)
+
+	require.Len(t, report.CashFlows, 2)
+	require.Equal(t, "0xcex", report.CashFlows[0].Hash)
+	require.Equal(t, 500.0, report.CashFlows[0].AmountUSD)
+	require.Equal(t, "0xsend", report.CashFlows[1].Hash)
+	require.Equal(t, -200.0, report.CashFlows[1].AmountUSD)
+	require.False(t, report.Truncated)
+
+	// the USDC moves were flows, so the value never changed through returns
+	require.InDelta(t, 0, report.TimeWeightedReturnPct, 1e-6)
+}
+
+func TestService_ReportTruncated(t *testing.T) {
+	start := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -2)
+
+	snap := func(d int) snapshots.Snapshot {
+		return snapshots.Snapshot{
+			Wallet:        "0xabc",
+			TakenAt:       start.AddDate(0, 0, d),
+			TotalValueUSD: 2000,
+			Holdings:      []snapshots.SnapshotHolding{{Chain: "ethereum", Amount: 1, PriceUSD: 2000, ValueUSD: 2000}},
+		}
+	}
+	history := &fakeHistory{snaps: []snapshots.Snapshot{snap(0), snap(1), snap(2)}}
+
+	// more failed calls within the period than etherscan returns
+	failed := make([]transactions.Transaction, transactions.MaxResults+1)
+	for i := range failed {
+		failed[i] = transactions.Transaction{Status: transactions.StatusFailed, Timestamp: start.AddDate(0, 0, 1)}
+	}
+	txs := &fakeTransactions{txs: failed}
+
+	svc := NewService(history, txs, txs, &fakePricing{}, 0, zap.NewNop())
+
+	report, err := svc.Report(context.Background(), "0xabc", Period7d, pricing.AssetRef{Chain: "ethereum"})
+	require.NoError(t, err)
+	require.True(t, report.Truncated)
+	require.Equal(t, transactions.HistoryMaxPages, txs.pages)
+}
@@ -0,0 +1,191 @@
+package pnl
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+// dust is the quantity below which a lot counts as used up; it absorbs
+// float rounding left over from partial matches
+const dust = 1e-12
+
+// Method picks which open lots a disposal is matched against
+type Method string
+
+const (
+	MethodFIFO Method = "fifo" // oldest lots first
+	MethodLIFO Method = "lifo" // newest lots first
+	MethodHIFO Method = "hifo" // most expensive lots first
+)
+
+func ParseMethod(s string) (Method, error) {
+	switch m := Method(strings.ToLower(s)); m {
+	case "":
+		return MethodFIFO, nil
+	case MethodFIFO, MethodLIFO, MethodHIFO:
+		return m, nil
+	default:
+		return "", ErrInvalidMethod
+	}
+}
+
+type EventKind string
+
+const (
+	EventAcquire EventKind = "acquire"
+	EventDispose EventKind = "dispose"
+)
+
+// Event is a quantity of an asset entering or leaving the wallet, priced at
+// the time it happened
+type Event struct {
+	Kind     EventKind
+	Asset    pricing.AssetRef
+	Quantity float64
+	PriceUSD float64
+	Time     time.Time
+	Hash     string
+}
+
+// Lot is what is left of one acquisition
+type Lot struct {
+	Asset       pricing.AssetRef `json:"asset"`
+	Quantity    float64          `json:"quantity"`
+	CostUSD     float64          `json:"cost_usd"` // per unit
+	AcquiredAt  time.Time        `json:"acquired_at"`
+	AcquireHash string           `json:"acquire_hash"`
+}
+
+// Realization is the part of a disposal matched against one lot. Quantity
+// disposed of without an open lot to match is Unmatched, with a zero cost
+// basis and the disposal time as its acquisition time.
+type Realization struct {
+	Asset        pricing.AssetRef `json:"asset"`
+	Quantity     float64          `json:"quantity"`
+	AcquiredAt   time.Time        `json:"acquired_at"`
+	DisposedAt   time.Time        `json:"disposed_at"`
+	ProceedsUSD  float64          `json:"proceeds_usd"`
+	CostBasisUSD float64          `json:"cost_basis_usd"`
+	GainUSD      float64          `json:"gain_usd"`
+	AcquireHash  string           `json:"acquire_hash,omitempty"`
+	DisposeHash  string           `json:"dispose_hash"`
+	Unmatched    bool             `json:"unmatched,omitempty"`
+}
+
+// Book keeps the open lots of every asset and matches disposals against them
+type Book struct {
+	method Method
+	lots   map[pricing.AssetRef][]*Lot
+}
+
+func NewBook(method Method) *Book {
+	return &Book{method: method, lots: make(map[pricing.AssetRef][]*Lot)}
+}
+
+// Apply records an event; disposals return what they realized
+func (b *Book) Apply(e Event) []Realization {
+	if e.Quantity <= 0 {
+		return nil
+	}
+	if e.Kind == EventAcquire {
+		b.lots[e.Asset] = append(b.lots[e.Asset], &Lot{
+			Asset:       e.Asset,
+			Quantity:    e.Quantity,
+			CostUSD:     e.PriceUSD,
+			AcquiredAt:  e.Time,
+			AcquireHash: e.Hash,
+		})
+		return nil
+	}
+	return b.dispose(e)
+}
+
+func (b *Book) dispose(e Event) []Realization {
+	lots := b.lots[e.Asset]
+	b.order(lots)
+
+	var out []Realization
+	remaining := e.Quantity
+	for _, lot := range lots {
+		if remaining <= dust {
+			break
+		}
+		if lot.Quantity <= dust {
+			continue
+		}
+
+		qty := min(remaining, lot.Quantity)
+		lot.Quantity -= qty
+		remaining -= qty
+
+		out = append(out, realize(e, qty, lot.CostUSD*qty, lot.AcquiredAt, lot.AcquireHash))
+	}
+
+	if remaining > dust {
+		r := realize(e, remaining, 0, e.Time, "")
+		r.Unmatched = true
+		out = append(out, r)
+	}
+
+	kept := lots[:0]
+	for _, lot := range lots {
+		if lot.Quantity > dust {
+			kept = append(kept, lot)
+		}
+	}
+	b.lots[e.Asset] = kept
+
+	return out
+}
+
+func realize(e Event, qty, cost float64, acquiredAt time.Time, acquireHash string) Realization {
+	proceeds := e.PriceUSD * qty
+	return Realization{
+		Asset:        e.Asset,
+		Quantity:     qty,
+		AcquiredAt:   acquiredAt,
+		DisposedAt:   e.Time,
+		ProceedsUSD:  proceeds,
+		CostBasisUSD: cost,
+		GainUSD:      proceeds - cost,
+		AcquireHash:  acquireHash,
+		DisposeHash:  e.Hash,
+	}
+}
+
+// order sorts lots in the sequence the method consumes them
+func (b *Book) order(lots []*Lot) {
+	sort.SliceStable(lots, func(i, j int) bool {
+		switch b.method {
+		case MethodLIFO:
+			return lots[i].AcquiredAt.After(lots[j].AcquiredAt)
+		case MethodHIFO:
+			if lots[i].CostUSD != lots[j].CostUSD {
+				return lots[i].CostUSD > lots[j].CostUSD
+			}
+			return lots[i].AcquiredAt.Before(lots[j].AcquiredAt)
+		default:
+			return lots[i].AcquiredAt.Before(lots[j].AcquiredAt)
+		}
+	})
+}
+
+// Open returns the lots still held, oldest first
+func (b *Book) Open() []Lot {
+	var out []Lot
+	for _, lots := range b.lots {
+		for _, lot := range lots {
+			out = append(out, *lot)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].AcquiredAt.Equal(out[j].AcquiredAt) {
+			return out[i].AcquiredAt.Before(out[j].AcquiredAt)
+		}
+		return out[i].Asset.String() < out[j].Asset.String()
+	})
+	return out
+}
@@ -0,0 +1,97 @@
+package pnl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+var eth = pricing.AssetRef{Chain: "ethereum"}
+
+func day(d int) time.Time {
+	return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC)
+}
+
+// three lots of 1 ETH bought at 1000, 3000 and 2000, then 1.5 ETH sold at 2500
+func lotEvents() []Event {
+	return []Event{
+		{Kind: EventAcquire, Asset: eth, Quantity: 1, PriceUSD: 1000, Time: day(1), Hash: "0xa"},
+		{Kind: EventAcquire, Asset: eth, Quantity: 1, PriceUSD: 3000, Time: day(2), Hash: "0xb"},
+		{Kind: EventAcquire, Asset: eth, Quantity: 1, PriceUSD: 2000, Time: day(3), Hash: "0xc"},
+		{Kind: EventDispose, Asset: eth, Quantity: 1.5, PriceUSD: 2500, Time: day(4), Hash: "0xd"},
+	}
+}
+
+func TestBook_Methods(t *testing.T) {
+	cases := []struct {
+		method Method
+		lots   []string
+		cost   float64
+	}{
+		{MethodFIFO, []string{"0xa", "0xb"}, 1000 + 1500},
+		{MethodLIFO, []string{"0xc", "0xb"}, 2000 + 1500},
+		{MethodHIFO, []string{"0xb", "0xc"}, 3000 + 1000},
+	}
+
+	for _, tc := range cases {
+		t.Run(string(tc.method), func(t *testing.T) {
+			book := NewBook(tc.method)
+
+			var realized []Realization
+			for _, e := range lotEvents() {
+				realized = append(realized, book.Apply(e)...)
+			}
+
+			require.Len(t, realized, 2)
+			var cost, proceeds float64
+			for i, r := range realized {
+				require.Equal(t, tc.lots[i], r.AcquireHash)
+				require.Equal(t, "0xd", r.DisposeHash)
+				cost += r.CostBasisUSD
+				proceeds += r.ProceedsUSD
+			}
+			require.InDelta(t, tc.cost, cost, 1e-9)
+			require.InDelta(t, 3750, proceeds, 1e-9)
+
+			var open float64
+			for _, lot := range book.Open() {
+				open += lot.Quantity
+			}
+			require.InDelta(t, 1.5, open, 1e-9)
+		})
+	}
+}
+
+func TestBook_UnmatchedDisposal(t *testing.T) {
+	book := NewBook(MethodFIFO)
+
+	book.Apply(Event{Kind: EventAcquire, Asset: eth, Quantity: 1, PriceUSD: 1000, Time: day(1), Hash: "0xa"})
+	realized := book.Apply(Event{Kind: EventDispose, Asset: eth, Quantity: 3, PriceUSD: 2000, Time: day(2), Hash: "0xb"})
+
+	require.Len(t, realized, 2)
+	require.False(t, realized[0].Unmatched)
+	require.InDelta(t, 1000, realized[0].GainUSD, 1e-9)
+
+	require.True(t, realized[1].Unmatched)
+	require.InDelta(t, 2, realized[1].Quantity, 1e-9)
+	require.Zero(t, realized[1].CostBasisUSD)
+	require.Equal(t, day(2), realized[1].AcquiredAt)
+
+	require.Empty(t, book.Open())
+}
+
+func TestParseMethod(t *testing.T) {
+	m, err := ParseMethod("")
+	require.NoError(t, err)
+	require.Equal(t, MethodFIFO, m)
+
+	m, err = ParseMethod("HIFO")
+	require.NoError(t, err)
+	require.Equal(t, MethodHIFO, m)
+
+	_, err = ParseMethod("average")
+	require.ErrorIs(t, err, ErrInvalidMethod)
+}
@@ -0,0 +1,102 @@
+package pnl
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+var (
+	ErrInvalidMethod    = errors.New("invalid lot matching method")
+	ErrInvalidGrouping  = errors.New("invalid period grouping")
+	ErrUnsupportedChain = errors.New("chain has no transaction history")
+)
+
+// Grouping is the length of the periods realized gains are summed over
+type Grouping string
+
+const (
+	GroupMonth   Grouping = "month"
+	GroupQuarter Grouping = "quarter"
+	GroupYear    Grouping = "year"
+)
+
+func ParseGrouping(s string) (Grouping, error) {
+	switch g := Grouping(strings.ToLower(s)); g {
+	case "":
+		return GroupMonth, nil
+	case GroupMonth, GroupQuarter, GroupYear:
+		return g, nil
+	default:
+		return "", ErrInvalidGrouping
+	}
+}
+
+// Key names the period t falls in: 2026-03, 2026-Q1 or 2026
+func (g Grouping) Key(t time.Time) string {
+	t = t.UTC()
+	switch g {
+	case GroupYear:
+		return fmt.Sprintf("%d", t.Year())
+	case GroupQuarter:
+		return fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())-1)/3+1)
+	default:
+		return t.Format("2006-01")
+	}
+}
+
+// Query selects the chain, matching method and disposal window of a report.
+// Lots are built from the whole history whatever the window.
+type Query struct {
+	Chain   string
+	Method  Method
+	GroupBy Grouping
+	From    *time.Time
+	To      *time.Time
+}
+
+// Totals add up a set of realizations
+type Totals struct {
+	ProceedsUSD  float64 `json:"proceeds_usd"`
+	CostBasisUSD float64 `json:"cost_basis_usd"`
+	GainUSD      float64 `json:"gain_usd"`
+	Disposals    int     `json:"disposals"`
+}
+
+func (t *Totals) add(r Realization) {
+	t.ProceedsUSD += r.ProceedsUSD
+	t.CostBasisUSD += r.CostBasisUSD
+	t.GainUSD += r.GainUSD
+	t.Disposals++
+}
+
+type AssetTotals struct {
+	Asset    pricing.AssetRef `json:"asset"`
+	Quantity float64          `json:"quantity"`
+	Totals
+}
+
+type PeriodTotals struct {
+	Period string `json:"period"`
+	Totals
+}
+
+// Report is the realized profit and loss of a wallet on one chain
+type Report struct {
+	Wallet       string         `json:"wallet"`
+	Chain        string         `json:"chain"`
+	Method       Method         `json:"method"`
+	GroupBy      Grouping       `json:"group_by"`
+	From         *time.Time     `json:"from,omitempty"`
+	To           *time.Time     `json:"to,omitempty"`
+	Totals       Totals         `json:"totals"`
+	ByAsset      []AssetTotals  `json:"by_asset"`
+	ByPeriod     []PeriodTotals `json:"by_period"`
+	Realizations []Realization  `json:"realizations"`
+	OpenLots     []Lot          `json:"open_lots"`
+	Unpriced     []string       `json:"unpriced,omitempty"`  // hashes of transfers with no historical price, valued at zero
+	Truncated    bool           `json:"truncated,omitempty"` // the history was longer than could be read
+}
@@ -0,0 +1,298 @@
+package pnl
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
+)
+
+type ServiceAPI interface {
+	Realized(ctx context.Context, wallet string, q Query) (*Report, error)
+}
+
+// Service replays a wallet's transfers through a lot book to find its
+// realized gains
+type Service struct {
+	transactions transactions.ServiceAPI
+	transfers    transactions.TokenTransfersAPI
+	prices       pricing.ServiceAPI
+	logger       *zap.Logger
+}
+
+func NewService(
+	txs transactions.ServiceAPI,
+	transfers transactions.TokenTransfersAPI,
+	prices pricing.ServiceAPI,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		transactions: txs,
+		transfers:    transfers,
+		prices:       prices,
+		logger:       logger.With(zap.String("service", "pnl")),
+	}
+}
+
+// Realized reads the wallet's whole history on the chain, native and
+// ERC-20, oldest first. Transfers in open lots; transfers out and swaps
+// dispose of them. Every
+// leg is valued at the historical price on the day it happened.
+func (s *Service) Realized(ctx context.Context, wallet string, q Query) (*Report, error) {
+	chainID, ok := tokens.ChainID(q.Chain)
+	if !ok {
+		return nil, ErrUnsupportedChain
+	}
+
+	s.logger.Info("realized-pnl",
+		zap.String("wallet", wallet),
+		zap.String("chain", q.Chain),
+		zap.String("method", string(q.Method)),
+	)
+
+	txs, truncated, err := s.history(ctx, chainID, wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	events, unpriced := s.events(ctx, q.Chain, wallet, txs)
+
+	report := Realize(wallet, events, q)
+	report.Unpriced = unpriced
+	report.Truncated = truncated
+
+	return report, nil
+}
+
+// history reads the wallet's transactions and ERC-20 transfers and returns
+// them oldest first. Token transfers of a decoded swap are left out, as its
+// legs already hold them; spam tokens are never acquired or disposed of.
+// It reports true when more pages were left unread.
+func (s *Service) history(ctx context.Context, chainID, wallet string) ([]transactions.Transaction, bool, error) {
+	txs, truncated, err := transactions.ReadPages(func(page, limit int) ([]transactions.Transaction, error) {
+		return s.transactions.List(ctx, chainID, wallet, page, limit, transactions.Filters{})
+	}, transactions.HistoryPageSize, transactions.HistoryMaxPages)
+	if err != nil {
+		return nil, false, err
+	}
+	transfers, transfersTruncated, err := transactions.ReadPages(func(page, limit int) ([]transactions.Transaction, error) {
+		return s.transfers.TokenTransfers(ctx, chainID, wallet, page, limit)
+	}, transactions.HistoryPageSize, transactions.HistoryMaxPages)
+	if err != nil {
+		return nil, false, err
+	}
+
+	swaps := make(map[string]bool)
+	for _, tx := range txs {
+		if len(tx.Legs) > 0 {
+			swaps[tx.Hash] = true
+		}
+	}
+
+	out := txs
+	for _, tx := range transfers {
+		if swaps[tx.Hash] || (tx.Spam != nil && tx.Spam.Spam) {
+			continue
+		}
+		out = append(out, tx)
+	}
+
+	truncated = truncated || transfersTruncated
+	if truncated {
+		s.logger.Warn("pnl-history-truncated",
+			zap.String("wallet", wallet),
+			zap.Int("transactions", len(out)),
+		)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Timestamp.Before(out[j].Timestamp) })
+	return out, truncated, nil
+}
+
+// events turns transfers into priced lot events, returning the hashes of
+// transfers that could not be priced
+func (s *Service) events(
+	ctx context.Context,
+	chain string,
+	wallet string,
+	txs []transactions.Transaction,
+) ([]Event, []string) {
+
+	wallet = strings.ToLower(wallet)
+
+	// a decoded swap is replayed leg by leg, and its legs already hold the
+	// native value paid back to the wallet by internal transactions
+	swaps := make(map[string]bool)
+	for _, tx := range txs {
+		if len(tx.Legs) > 0 {
+			swaps[tx.Hash] = true
+		}
+	}
+
+	var (
+		events   []Event
+		unpriced []string
+	)
+	memo := pricing.NewHistoricalMemo(s.prices)
+	for _, tx := range txs {
+		if tx.ParentHash != "" && swaps[tx.ParentHash] {
+			continue
+		}
+		if len(tx.Legs) > 0 {
+			for _, leg := range tx.Legs {
+				legEvents, ok := swapEvents(ctx, memo, chain, tx, leg)
+				if !ok {
+					unpriced = append(unpriced, tx.Hash)
+				}
+				events = append(events, legEvents...)
+			}
+			continue
+		}
+
+		kind, ok := eventKind(tx, wallet)
+		if !ok {
+			continue
+		}
+
+		asset := pricing.AssetRef{Chain: chain, ContractAddress: strings.ToLower(tx.TokenAddr)}
+		e := Event{Kind: kind, Asset: asset, Quantity: tx.Amount, Time: tx.Timestamp, Hash: tx.Hash}
+
+		if hp, found := memo.Price(ctx, asset, tx.Timestamp); found {
+			e.PriceUSD = hp.PriceUSD
+		} else {
+			unpriced = append(unpriced, tx.Hash)
+		}
+
+		events = append(events, e)
+	}
+	return events, unpriced
+}
+
+// swapEvents disposes of what a swap leg gave and acquires what it got,
+// both at the value of the side with a historical price, so the cost basis
+// of the token received is what was given for it. It reports false when
+// neither side could be priced.
+func swapEvents(
+	ctx context.Context,
+	memo *pricing.HistoricalMemo,
+	chain string,
+	tx transactions.Transaction,
+	leg transactions.SwapLeg,
+) ([]Event, bool) {
+	in := pricing.AssetRef{Chain: chain, ContractAddress: strings.ToLower(leg.TokenIn)}
+	out := pricing.AssetRef{Chain: chain, ContractAddress: strings.ToLower(leg.TokenOut)}
+
+	var value float64
+	priced := false
+	prices := memo.Prices(ctx, []pricing.AssetRef{in, out}, tx.Timestamp)
+	if hp, found := prices[in]; found {
+		value, priced = hp.PriceUSD*leg.AmountIn, true
+	} else if hp, found := prices[out]; found {
+		value, priced = hp.PriceUSD*leg.AmountOut, true
+	}
+
+	events := make([]Event, 0, 2)
+	if leg.AmountIn > 0 {
+		events = append(events, Event{Kind: EventDispose, Asset: in, Quantity: leg.AmountIn, PriceUSD: value / leg.AmountIn, Time: tx.Timestamp, Hash: tx.Hash})
+	}
+	if leg.AmountOut > 0 {
+		events = append(events, Event{Kind: EventAcquire, Asset: out, Quantity: leg.AmountOut, PriceUSD: value / leg.AmountOut, Time: tx.Timestamp, Hash: tx.Hash})
+	}
+	return events, priced
+}
+
+// eventKind reports whether a transaction moves value in or out of the
+// wallet. Staking calls keep the value in the wallet's control and
+// self-transfers do not move it at all.
+func eventKind(tx transactions.Transaction, wallet string) (EventKind, bool) {
+	if tx.Status != transactions.StatusSuccess || tx.Amount <= 0 || tx.Type == transactions.TypeStake {
+		return "", false
+	}
+	if strings.EqualFold(tx.From, tx.To) {
+		return "", false
+	}
+	switch tx.Direction {
+	case transactions.DirectionIn:
+		return EventAcquire, true
+	case transactions.DirectionOut:
+		return EventDispose, true
+	}
+	return "", false
+}
+
+// Realize runs events, oldest first, through a lot book and sums the
+// disposals that fall within the query's window
+func Realize(wallet string, events []Event, q Query) *Report {
+	book := NewBook(q.Method)
+
+	report := &Report{
+		Wallet:       wallet,
+		Chain:        q.Chain,
+		Method:       q.Method,
+		GroupBy:      q.GroupBy,
+		From:         q.From,
+		To:           q.To,
+		Realizations: []Realization{},
+	}
+
+	byAsset := make(map[pricing.AssetRef]*AssetTotals)
+	byPeriod := make(map[string]*PeriodTotals)
+
+	for _, e := range events {
+		for _, r := range book.Apply(e) {
+			if q.From != nil && r.DisposedAt.Before(*q.From) {
+				continue
+			}
+			if q.To != nil && r.DisposedAt.After(*q.To) {
+				continue
+			}
+
+			report.Realizations = append(report.Realizations, r)
+			report.Totals.add(r)
+
+			a, ok := byAsset[r.Asset]
+			if !ok {
+				a = &AssetTotals{Asset: r.Asset}
+				byAsset[r.Asset] = a
+			}
+			a.Quantity += r.Quantity
+			a.add(r)
+
+			key := q.GroupBy.Key(r.DisposedAt)
+			p, ok := byPeriod[key]
+			if !ok {
+				p = &PeriodTotals{Period: key}
+				byPeriod[key] = p
+			}
+			p.add(r)
+		}
+	}
+
+	report.ByAsset = make([]AssetTotals, 0, len(byAsset))
+	for _, a := range byAsset {
+		report.ByAsset = append(report.ByAsset, *a)
+	}
+	sort.Slice(report.ByAsset, func(i, j int) bool {
+		return report.ByAsset[i].Asset.String() < report.ByAsset[j].Asset.String()
+	})
+
+	report.ByPeriod = make([]PeriodTotals, 0, len(byPeriod))
+	for _, p := range byPeriod {
+		report.ByPeriod = append(report.ByPeriod, *p)
+	}
+	sort.Slice(report.ByPeriod, func(i, j int) bool {
+		return report.ByPeriod[i].Period < report.ByPeriod[j].Period
+	})
+
+	report.OpenLots = book.Open()
+	if report.OpenLots == nil {
+		report.OpenLots = []Lot{}
+	}
+
+	return report
+}
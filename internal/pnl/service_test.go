@@ -0,0 +1,240 @@
+package pnl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/spam"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
+)
+
+type fakeTransactions struct {
+	txs []transactions.Transaction
+}
+
+func (f *fakeTransactions) List(
+	ctx context.Context,
+	chain string,
+	wallet string,
+	page int,
+	limit int,
+	filters transactions.Filters,
+) ([]transactions.Transaction, error) {
+	if page > 1 {
+		return nil, nil
+	}
+	return f.txs, nil
+}
+
+func (f *fakeTransactions) TokenTransfers(
+	ctx context.Context,
+	chain string,
+	wallet string,
+	page int,
+	limit int,
+) ([]transactions.Transaction, error) {
+	return f.List(ctx, chain, wallet, page, limit, transactions.Filters{})
+}
+
+// fakePricing prices ETH at 1000 times the day of the month and UNI at 10
+// times
+type fakePricing struct {
+	pricing.ServiceAPI
+}
+
+func (fakePricing) GetHistoricalPrices(ctx context.Context, assets []pricing.AssetRef, at time.Time) (map[pricing.AssetRef]pricing.HistoricalPrice, error) {
+	out := make(map[pricing.AssetRef]pricing.HistoricalPrice)
+	for _, a := range assets {
+		switch a {
+		case eth:
+			out[a] = pricing.HistoricalPrice{PriceUSD: float64(at.Day()) * 1000, Time: at}
+		case uni:
+			out[a] = pricing.HistoricalPrice{PriceUSD: float64(at.Day()) * 10, Time: at}
+		}
+	}
+	return out, nil
+}
+
+const wallet = "0xabc"
+
+var uni = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0x1f98"}
+
+func tx(hash string, d int, from, to string, amount float64, typ transactions.TransactionType, dir transactions.Direction) transactions.Transaction {
+	return transactions.Transaction{
+		Hash:      hash,
+		Chain:     "1",
+		From:      from,
+		To:        to,
+		Amount:    amount,
+		Type:      typ,
+		Status:    transactions.StatusSuccess,
+		Direction: dir,
+		Timestamp: day(d),
+	}
+}
+
+func TestService_Realized(t *testing.T) {
+	// newest first, as the transactions service returns them
+	txs := []transactions.Transaction{
+		tx("0x5", 5, wallet, "0xdex", 1, transactions.TypeSwap, transactions.DirectionOut),
+		tx("0x4", 4, wallet, "0xstake", 1, transactions.TypeStake, transactions.DirectionOut),
+		tx("0x3", 3, wallet, "0xbob", 1, transactions.TypeSend, transactions.DirectionOut),
+		tx("0x2", 2, "0xbob", wallet, 2, transactions.TypeSend, transactions.DirectionIn),
+		tx("0x1", 1, "0xbob", wallet, 1, transactions.TypeSend, transactions.DirectionIn),
+	}
+
+	svc := NewService(&fakeTransactions{txs: txs}, &fakeTransactions{}, fakePricing{}, zap.NewNop())
+
+	report, err := svc.Realized(context.Background(), wallet, Query{
+		Chain:   "ethereum",
+		Method:  MethodFIFO,
+		GroupBy: GroupMonth,
+	})
+	require.NoError(t, err)
+
+	// day 3 sells the day 1 lot (1000 -> 3000) and day 5 one unit of the
+	// day 2 lot (2000 -> 5000); staking is not a disposal
+	require.Len(t, report.Realizations, 2)
+	require.Equal(t, "0x1", report.Realizations[0].AcquireHash)
+	require.Equal(t, "0x2", report.Realizations[1].AcquireHash)
+	require.InDelta(t, 8000, report.Totals.ProceedsUSD, 1e-9)
+	require.InDelta(t, 3000, report.Totals.CostBasisUSD, 1e-9)
+	require.InDelta(t, 5000, report.Totals.GainUSD, 1e-9)
+
+	require.Len(t, report.ByAsset, 1)
+	require.InDelta(t, 2, report.ByAsset[0].Quantity, 1e-9)
+	require.Equal(t, []PeriodTotals{{Period: "2025-01", Totals: report.Totals}}, report.ByPeriod)
+
+	require.Len(t, report.OpenLots, 1)
+	require.InDelta(t, 1, report.OpenLots[0].Quantity, 1e-9)
+	require.Empty(t, report.Unpriced)
+	require.False(t, report.Truncated)
+}
+
+func TestService_RealizedSwapLegs(t *testing.T) {
+	usdc := pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xusdc"}
+
+	buy := tx("0x2", 3, wallet, "0xrouter", 1, transactions.TypeSwap, transactions.DirectionOut)
+	buy.Legs = []transactions.SwapLeg{{AmountIn: 1, TokenOut: "0xUSDC", AmountOut: 3000}}
+	sell := tx("0x3", 4, wallet, "0xrouter", 0, transactions.TypeSwap, transactions.DirectionOut)
+	sell.Legs = []transactions.SwapLeg{{TokenIn: "0xusdc", AmountIn: 1500, AmountOut: 0.5}}
+	payout := tx("0x3", 4, "0xrouter", wallet, 0.5, transactions.TypeReceive, transactions.DirectionIn)
+	payout.ParentHash = "0x3"
+
+	txs := []transactions.Transaction{
+		payout,
+		sell,
+		buy,
+		tx("0x1", 1, "0xbob", wallet, 2, transactions.TypeSend, transactions.DirectionIn),
+	}
+	svc := NewService(&fakeTransactions{txs: txs}, &fakeTransactions{}, fakePricing{}, zap.NewNop())
+
+	report, err := svc.Realized(context.Background(), wallet, Query{Chain: "ethereum", Method: MethodFIFO, GroupBy: GroupMonth})
+	require.NoError(t, err)
+	require.Empty(t, report.Unpriced)
+
+	// day 3 sells 1 ETH bought at 1000 for USDC worth 3000; day 4 sells half
+	// that USDC, which cost 1500, for ETH worth 2000, valued at the ETH side
+	// as USDC has no price
+	require.Len(t, report.Realizations, 2)
+	require.Equal(t, eth, report.Realizations[0].Asset)
+	require.InDelta(t, 2000, report.Realizations[0].GainUSD, 1e-9)
+	require.Equal(t, usdc, report.Realizations[1].Asset)
+	require.InDelta(t, 1500, report.Realizations[1].CostBasisUSD, 1e-9)
+	require.InDelta(t, 2000, report.Realizations[1].ProceedsUSD, 1e-9)
+
+	// the payout is part of the day 4 leg, not a second acquisition
+	open := make(map[pricing.AssetRef]float64)
+	for _, l := range report.OpenLots {
+		open[l.Asset] += l.Quantity
+	}
+	require.InDelta(t, 1.5, open[eth], 1e-9)
+	require.InDelta(t, 1500, open[usdc], 1e-9)
+}
+
+func TestService_RealizedTokenTransfers(t *testing.T) {
+	transfer := func(hash string, d int, token, from, to string, amount float64, dir transactions.Direction) transactions.Transaction {
+		out := tx(hash, d, from, to, amount, transactions.TypeSend, dir)
+		out.TokenAddr = token
+		return out
+	}
+
+	// the day 4 swap is decoded, so its token transfer is already a leg
+	swap := tx("0x4", 4, wallet, "0xrouter", 0, transactions.TypeSwap, transactions.DirectionOut)
+	swap.Legs = []transactions.SwapLeg{{TokenIn: "0x1f98", AmountIn: 2, AmountOut: 0.01}}
+	airdrop := transfer("0x6", 6, "0xscam", "0xscammer", wallet, 1000, transactions.DirectionIn)
+	airdrop.Spam = &spam.Verdict{Spam: true}
+
+	txs := &fakeTransactions{txs: []transactions.Transaction{swap}}
+	transfers := &fakeTransactions{txs: []transactions.Transaction{
+		airdrop,
+		transfer("0x5", 5, "0x1f98", wallet, "0xbob", 4, transactions.DirectionOut),
+		transfer("0x4", 4, "0x1f98", wallet, "0xpool", 2, transactions.DirectionOut),
+		transfer("0x2", 2, "0x1f98", "0xbob", wallet, 10, transactions.DirectionIn),
+	}}
+	svc := NewService(txs, transfers, fakePricing{}, zap.NewNop())
+
+	report, err := svc.Realized(context.Background(), wallet, Query{Chain: "ethereum", Method: MethodFIFO, GroupBy: GroupMonth})
+	require.NoError(t, err)
+	require.Empty(t, report.Unpriced)
+
+	// 10 UNI bought at 20; 2 swapped on day 4 at 40, then 4 sent on day 5 at 50
+	require.Len(t, report.Realizations, 2)
+	require.Equal(t, uni, report.Realizations[0].Asset)
+	require.InDelta(t, 2, report.Realizations[0].Quantity, 1e-9)
+	require.InDelta(t, 40, report.Realizations[0].GainUSD, 1e-9)
+	require.Equal(t, "0x5", report.Realizations[1].DisposeHash)
+	require.InDelta(t, 4, report.Realizations[1].Quantity, 1e-9)
+	require.InDelta(t, 120, report.Realizations[1].GainUSD, 1e-9)
+
+	open := make(map[pricing.AssetRef]float64)
+	for _, l := range report.OpenLots {
+		open[l.Asset] += l.Quantity
+	}
+	require.InDelta(t, 4, open[uni], 1e-9)
+	require.InDelta(t, 0.01, open[eth], 1e-9)
+	require.NotContains(t, open, pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xscam"})
+}
+
+func TestService_RealizedWindow(t *testing.T) {
+	txs := []transactions.Transaction{
+		tx("0x3", 3, wallet, "0xbob", 1, transactions.TypeSend, transactions.DirectionOut),
+		tx("0x2", 2, wallet, "0xbob", 1, transactions.TypeSend, transactions.DirectionOut),
+		tx("0x1", 1, "0xbob", wallet, 2, transactions.TypeSend, transactions.DirectionIn),
+	}
+	svc := NewService(&fakeTransactions{txs: txs}, &fakeTransactions{}, fakePricing{}, zap.NewNop())
+
+	from := day(3)
+	report, err := svc.Realized(context.Background(), wallet, Query{
+		Chain:   "ethereum",
+		Method:  MethodFIFO,
+		GroupBy: GroupYear,
+		From:    &from,
+	})
+	require.NoError(t, err)
+
+	// the day 2 disposal still uses up its share of the lot
+	require.Len(t, report.Realizations, 1)
+	require.Equal(t, "0x3", report.Realizations[0].DisposeHash)
+	require.Equal(t, "2025", report.ByPeriod[0].Period)
+	require.Empty(t, report.OpenLots)
+}
+
+func TestService_RealizedUnsupportedChain(t *testing.T) {
+	svc := NewService(&fakeTransactions{}, &fakeTransactions{}, fakePricing{}, zap.NewNop())
+
+	_, err := svc.Realized(context.Background(), wallet, Query{Chain: "solana"})
+	require.ErrorIs(t, err, ErrUnsupportedChain)
+}
+
+func TestGrouping_Key(t *testing.T) {
+	at := time.Date(2026, 8, 14, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, "2026-08", GroupMonth.Key(at))
+	require.Equal(t, "2026-Q3", GroupQuarter.Key(at))
+	require.Equal(t, "2026", GroupYear.Key(at))
+}
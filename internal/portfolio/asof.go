@@ -0,0 +1,252 @@
+package portfolio
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
+)
+
+const (
+	// replayPageSize and replayMaxPages bound how many transactions are read
+	// to rebuild one native holding
+	replayPageSize = 100
+	replayMaxPages = 10
+)
+
+// GetAsOf values the wallet as it was at asOf. Each holding's amount is
+// rebuilt from recorded holding changes when there are any, otherwise by
+// replaying transactions for native assets, otherwise the current amount is
+// used; every line is priced with the historical price at asOf.
+func (s *service) GetAsOf(ctx context.Context, wallet string, asOf time.Time) (*PortfolioView, error) {
+	s.logger.Info("get-portfolio-as-of",
+		zap.String("wallet", wallet),
+		zap.Time("as_of", asOf),
+	)
+
+	p, err := s.repo.Get(ctx, wallet)
+	if err != nil {
+		s.logger.Error("portfolio-not-found",
+			zap.String("wallet", wallet),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	holdings, sources, err := s.holdingsAt(ctx, p, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]pricing.AssetRef, 0, len(holdings))
+	for _, h := range holdings {
+		refs = append(refs, h.AssetRef())
+	}
+
+	prices, err := s.pricing.GetHistoricalPrices(ctx, refs, asOf)
+	if err != nil {
+		s.logger.Error("historical-pricing-failed",
+			zap.String("wallet", wallet),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	view := newHistoricalView(wallet, holdings, sources, prices)
+	view.AsOf = &asOf
+	if s.tokens != nil {
+		view.AttachTokens(s.tokens.Lookup(ctx, refs))
+	}
+	s.attachSpam(ctx, view)
+
+	s.logger.Info("portfolio-valued-as-of",
+		zap.String("wallet", wallet),
+		zap.Time("as_of", asOf),
+		zap.Int("holdings", len(view.Holdings)),
+		zap.Float64("total_usd", view.TotalValueUSD),
+	)
+
+	return view, nil
+}
+
+// holdingsAt rebuilds the holdings of p at asOf, leaving out assets that were
+// not held then
+func (s *service) holdingsAt(
+	ctx context.Context,
+	p *Portfolio,
+	asOf time.Time,
+) ([]Holding, map[pricing.AssetRef]QuantitySource, error) {
+
+	var changes []HoldingChange
+	if s.history != nil {
+		c, err := s.history.List(ctx, p.Wallet)
+		if err != nil {
+			return nil, nil, err
+		}
+		changes = c
+	}
+
+	// every asset held now or at some point in the recorded history
+	held := p.Combined()
+	order := make([]pricing.AssetRef, 0, len(held))
+	current := make(map[pricing.AssetRef]float64, len(held))
+	for _, h := range held {
+		ref := h.AssetRef()
+		if _, ok := current[ref]; !ok {
+			order = append(order, ref)
+		}
+		current[ref] = h.Amount
+	}
+
+	byAsset := make(map[pricing.AssetRef][]HoldingChange)
+	for _, c := range changes {
+		ref := pricing.AssetRef{Chain: c.Chain, ContractAddress: c.ContractAddress}
+		if _, ok := current[ref]; !ok && len(byAsset[ref]) == 0 {
+			order = append(order, ref)
+		}
+		byAsset[ref] = append(byAsset[ref], c)
+	}
+
+	holdings := make([]Holding, 0, len(order))
+	sources := make(map[pricing.AssetRef]QuantitySource, len(order))
+
+	for _, ref := range order {
+		amount, source := current[ref], QuantitySourceCurrent
+
+		if history := byAsset[ref]; len(history) > 0 {
+			amount, source = amountFromHistory(history, asOf), QuantitySourceHoldingHistory
+		} else if replayed, ok := s.replayNative(ctx, p.Wallet, ref, amount, asOf); ok {
+			amount, source = replayed, QuantitySourceTransactions
+		}
+
+		if amount <= 0 {
+			continue
+		}
+
+		holdings = append(holdings, Holding{
+			Chain:           ref.Chain,
+			ContractAddress: ref.ContractAddress,
+			Amount:          amount,
+		})
+		sources[ref] = source
+	}
+
+	return holdings, sources, nil
+}
+
+// amountFromHistory returns the amount after the last change at or before
+// asOf, or the amount before the first change when all of them are later
+func amountFromHistory(history []HoldingChange, asOf time.Time) float64 {
+	amount := history[0].PreviousAmount
+	for _, c := range history {
+		if c.Time.After(asOf) {
+			break
+		}
+		amount = c.Amount
+	}
+	return amount
+}
+
+// replayNative walks a native holding back to asOf by undoing every
+// successful transfer made after it. Gas fees are not part of the
+// transaction history and are not undone. It reports false when the
+// replay is not possible or would need more than replayMaxPages pages.
+func (s *service) replayNative(
+	ctx context.Context,
+	wallet string,
+	ref pricing.AssetRef,
+	amount float64,
+	asOf time.Time,
+) (float64, bool) {
+
+	if s.transactions == nil || ref.ContractAddress != "" {
+		return 0, false
+	}
+	chainID, ok := tokens.ChainID(ref.Chain)
+	if !ok {
+		return 0, false
+	}
+
+	wallet = strings.ToLower(wallet)
+
+	// transactions come newest first
+	txs, truncated, err := transactions.ReadPages(func(page, limit int) ([]transactions.Transaction, error) {
+		txs, err := s.transactions.List(ctx, chainID, wallet, page, limit, transactions.Filters{})
+		return transactions.NewerThan(txs, asOf), err
+	}, replayPageSize, replayMaxPages)
+	if err != nil {
+		s.logger.Warn("replay-transactions-failed",
+			zap.String("wallet", wallet),
+			zap.String("chain", ref.Chain),
+			zap.Error(err),
+		)
+		return 0, false
+	}
+	if truncated {
+		s.logger.Warn("replay-transactions-truncated",
+			zap.String("wallet", wallet),
+			zap.String("chain", ref.Chain),
+			zap.Int("max_pages", replayMaxPages),
+		)
+		return 0, false
+	}
+
+	for _, tx := range txs {
+		if tx.Status != transactions.StatusSuccess || tx.TokenAddr != "" {
+			continue
+		}
+		switch tx.Direction {
+		case transactions.DirectionIn:
+			amount -= tx.Amount
+		case transactions.DirectionOut:
+			amount += tx.Amount
+		}
+	}
+	return amount, true
+}
+
+// newHistoricalView values rebuilt holdings against historical prices.
+// Holdings without a price are kept with a zero value and a note.
+func newHistoricalView(
+	wallet string,
+	holdings []Holding,
+	sources map[pricing.AssetRef]QuantitySource,
+	prices map[pricing.AssetRef]pricing.HistoricalPrice,
+) *PortfolioView {
+
+	var total float64
+	views := make([]HoldingView, 0, len(holdings))
+
+	for _, h := range holdings {
+		view := HoldingView{
+			Chain:           h.Chain,
+			ContractAddress: h.ContractAddress,
+			Amount:          h.Amount,
+			QuantitySource:  sources[h.AssetRef()],
+		}
+
+		if hp, ok := prices[h.AssetRef()]; ok {
+			priceTime := hp.Time
+			view.PriceUSD = hp.PriceUSD
+			view.ValueUSD = hp.PriceUSD * h.Amount
+			view.PriceSource = hp.Source
+			view.PriceTime = &priceTime
+		} else {
+			view.PriceNote = "no historical price available"
+		}
+
+		total += view.ValueUSD
+		views = append(views, view)
+	}
+
+	return &PortfolioView{
+		Wallet:        wallet,
+		Holdings:      views,
+		TotalValueUSD: total,
+	}
+}
@@ -0,0 +1,24 @@
+package portfolio
+
+import (
+	"context"
+	"time"
+)
+
+// HoldingChange records a holding's amount before and after an add, update
+// or remove, so past holdings can be rebuilt
+type HoldingChange struct {
+	Wallet          string
+	Chain           string
+	ContractAddress string
+	PreviousAmount  float64 // 0 when the holding was added
+	Amount          float64 // 0 when the holding was removed
+	Time            time.Time
+}
+
+// HoldingHistoryRepository stores holding changes per wallet
+type HoldingHistoryRepository interface {
+	Append(ctx context.Context, c HoldingChange) error
+	// List returns a wallet's changes oldest first
+	List(ctx context.Context, wallet string) ([]HoldingChange, error)
+}
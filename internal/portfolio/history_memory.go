@@ -0,0 +1,34 @@
+package portfolio
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+type memoryHistoryRepository struct {
+	mu   sync.RWMutex
+	data map[string][]HoldingChange
+}
+
+func NewMemoryHistoryRepository() HoldingHistoryRepository {
+	return &memoryHistoryRepository{data: make(map[string][]HoldingChange)}
+}
+
+func (r *memoryHistoryRepository) Append(ctx context.Context, c HoldingChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.data[c.Wallet] = append(r.data[c.Wallet], c)
+	return nil
+}
+
+func (r *memoryHistoryRepository) List(ctx context.Context, wallet string) ([]HoldingChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]HoldingChange, len(r.data[wallet]))
+	copy(out, r.data[wallet])
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Time.Before(out[j].Time) })
+	return out, nil
+}
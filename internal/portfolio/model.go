@@ -1,25 +1,103 @@
 package portfolio
 
+import (
+	"time"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/spam"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
+)
+
+// HoldingSource says whether a holding was entered by hand or read from
+// the chain
+type HoldingSource string
+
+const (
+	HoldingSourceManual HoldingSource = "manual"
+	HoldingSourceSync   HoldingSource = "sync"
+)
+
 // Holding represents an owned asset in a portfolio
 type Holding struct {
-	Chain           string  // ethereum, polygon, etc
-	ContractAddress string  // empty for native asset
-	Amount          float64 // the amount owned
+	Chain           string        // ethereum, polygon, etc
+	ContractAddress string        // empty for native asset
+	Amount          float64       // the amount owned
+	Source          HoldingSource // set on holdings returned by Service.Holdings
+}
+
+func (h Holding) AssetRef() pricing.AssetRef {
+	return pricing.AssetRef{
+		Chain:           h.Chain,
+		ContractAddress: h.ContractAddress,
+	}
+}
+
+// NFTHolding is a token of an NFT collection the wallet owns. Amount is
+// always 1 for ERC-721 tokens; an ERC-1155 token can be held several times.
+type NFTHolding struct {
+	Chain          string // ethereum, polygon, etc
+	Collection     string // the collection's contract address
+	CollectionName string
+	Symbol         string
+	TokenID        string
+	Standard       transactions.TokenStandard
+	Amount         float64
+}
+
+func (h NFTHolding) CollectionRef() pricing.AssetRef {
+	return pricing.AssetRef{
+		Chain:           h.Chain,
+		ContractAddress: h.Collection,
+	}
 }
 
-// Portfolio represents a wallet portfolio snapshot
+// QuantitySource says where a valued holding's amount came from
+type QuantitySource string
+
+const (
+	QuantitySourceCurrent        QuantitySource = "current"         // the holding as it is now
+	QuantitySourceHoldingHistory QuantitySource = "holding_history" // rebuilt from recorded holding changes
+	QuantitySourceTransactions   QuantitySource = "transactions"    // rebuilt by replaying transactions
+)
+
+// Portfolio represents a wallet portfolio snapshot. Holdings entered by
+// hand and those read from on-chain balances are kept apart, so neither
+// overwrites the other; valuations add them up per asset.
 type Portfolio struct {
 	Wallet   string
-	Holdings []Holding
+	Holdings []Holding // entered by hand or imported
+	Synced   []Holding // on-chain balances, replaced by every sync
+	SyncedAt *time.Time
+	NFTs     []NFTHolding // read from NFT transfers, replaced per chain by every NFT sync
 }
 
 //
 type HoldingView struct {
-	Chain           string
-	ContractAddress string
-	Amount          float64
-	PriceUSD        float64
-	ValueUSD        float64
+	Chain             string
+	ContractAddress   string
+	Amount            float64
+	SyncedAmount      float64 // part of Amount read from on-chain balances
+	QuantitySource    QuantitySource
+	PriceUSD          float64
+	ValueUSD          float64
+	Change24hPct      float64 // price change over the last 24h
+	ValueChange24hUSD float64 // ValueUSD minus the same amount valued 24h ago
+	PriceSource       pricing.PriceSource
+	PriceOverridden   bool   // true when PriceUSD is a manual override, not a market price
+	PriceNote         string // note attached to an override
+	PriceDerivation   *pricing.Derivation
+	PriceTime         *time.Time       // when a historical price was observed
+	Token             *tokens.Metadata // nil until the token has been resolved
+	Spam              *spam.Verdict    // nil when no spam classifier is set
+}
+
+// NFTHoldingView is an NFT valued at its collection's floor price; both
+// are zero when the collection has no floor
+type NFTHoldingView struct {
+	NFTHolding
+	FloorPriceUSD float64
+	ValueUSD      float64
 }
 
 // portfolio to be returned with computed field TotalValueUSD
@@ -27,4 +105,11 @@ type PortfolioView struct {
 	Wallet        string
 	Holdings      []HoldingView
 	TotalValueUSD float64
+	PnL24hUSD     float64    // sum of the holdings' ValueChange24hUSD
+	PnL24hPct     float64    // PnL24hUSD relative to the portfolio value 24h ago
+	AsOf          *time.Time // set when the portfolio was valued at a past time
+	SyncedAt      *time.Time // last on-chain balance sync, nil when never synced
+	HiddenSpam    int        // holdings left out as spam, when asked to
+	NFTs          []NFTHoldingView
+	NFTValueUSD   float64 // floor value of the NFTs, not part of TotalValueUSD
 }
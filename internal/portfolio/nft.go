@@ -0,0 +1,85 @@
+package portfolio
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+// SyncNFTs replaces the wallet's NFT holdings on one chain, leaving those
+// on other chains and every fungible holding alone. The portfolio is
+// created when the wallet has none.
+func (s *service) SyncNFTs(ctx context.Context, wallet string, chain string, hs []NFTHolding) error {
+	s.logger.Info("sync-nfts",
+		zap.String("wallet", wallet),
+		zap.String("chain", chain),
+		zap.Int("nfts", len(hs)),
+	)
+
+	current := &Portfolio{Wallet: wallet}
+	if p, err := s.repo.Get(ctx, wallet); err == nil {
+		current = p
+	}
+
+	nfts := make([]NFTHolding, 0, len(current.NFTs)+len(hs))
+	for _, h := range current.NFTs {
+		if h.Chain != chain {
+			nfts = append(nfts, h)
+		}
+	}
+	for _, h := range hs {
+		if h.Amount > 0 {
+			h.Chain = chain
+			nfts = append(nfts, h)
+		}
+	}
+
+	return s.repo.Save(ctx, &Portfolio{
+		Wallet:   wallet,
+		Holdings: current.Holdings,
+		Synced:   current.Synced,
+		SyncedAt: current.SyncedAt,
+		NFTs:     nfts,
+	})
+}
+
+// attachNFTs values the NFT holdings at their collection's floor price. A
+// floor price failure leaves them unvalued rather than failing the view.
+func (s *service) attachNFTs(ctx context.Context, view *PortfolioView, nfts []NFTHolding) {
+	if len(nfts) == 0 {
+		return
+	}
+
+	var floors map[pricing.AssetRef]float64
+	if s.floors != nil {
+		seen := make(map[pricing.AssetRef]bool, len(nfts))
+		collections := make([]pricing.AssetRef, 0, len(nfts))
+		for _, h := range nfts {
+			if ref := h.CollectionRef(); !seen[ref] {
+				seen[ref] = true
+				collections = append(collections, ref)
+			}
+		}
+
+		var err error
+		floors, err = s.floors.GetFloorPrices(ctx, collections)
+		if err != nil {
+			s.logger.Warn("floor-prices-failed",
+				zap.String("wallet", view.Wallet),
+				zap.String("provider", s.floors.Name()),
+				zap.Error(err),
+			)
+		}
+	}
+
+	view.NFTs = make([]NFTHoldingView, 0, len(nfts))
+	view.NFTValueUSD = 0
+	for _, h := range nfts {
+		floor := floors[h.CollectionRef()]
+		value := floor * h.Amount
+		view.NFTValueUSD += value
+		view.NFTs = append(view.NFTs, NFTHoldingView{NFTHolding: h, FloorPriceUSD: floor, ValueUSD: value})
+	}
+}
@@ -5,4 +5,5 @@ import "context"
 type Repository interface {
 	Get(ctx context.Context, wallet string) (*Portfolio, error)
 	Save(ctx context.Context, p *Portfolio) error
+	Wallets(ctx context.Context) ([]string, error)
 }
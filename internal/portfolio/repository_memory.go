@@ -3,6 +3,7 @@ package portfolio
 import (
 	"context"
 	"errors"
+	"sort"
 	"sync"
 )
 
@@ -37,3 +38,15 @@ func (r *memoryRepository) Save(ctx context.Context, p *Portfolio) error {
 	r.data[p.Wallet] = p
 	return nil
 }
+
+func (r *memoryRepository) Wallets(ctx context.Context) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]string, 0, len(r.data))
+	for wallet := range r.data {
+		out = append(out, wallet)
+	}
+	sort.Strings(out)
+	return out, nil
+}
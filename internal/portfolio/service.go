@@ -3,30 +3,90 @@ package portfolio
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/spam"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
 	"go.uber.org/zap"
 )
 
 type Service interface {
 	Get(ctx context.Context, wallet string) (*PortfolioView, error)
+	GetAsOf(ctx context.Context, wallet string, asOf time.Time) (*PortfolioView, error)
+	Holdings(ctx context.Context, wallet string) ([]Holding, error)
 	AddHolding(ctx context.Context, wallet string, h Holding) error
 	UpdateHolding(ctx context.Context, wallet string, h Holding) error
 	RemoveHolding(ctx context.Context, wallet string, chain string, contract string) error
+	SetHoldings(ctx context.Context, wallet string, hs []Holding) error
+	SyncHoldings(ctx context.Context, wallet string, hs []Holding, syncedAt time.Time) error
+	SyncNFTs(ctx context.Context, wallet string, chain string, hs []NFTHolding) error
+	Wallets(ctx context.Context) ([]string, error)
 }
 
 type service struct {
-	repo    Repository
-	pricing pricing.ServiceAPI
-	logger  *zap.Logger
+	repo         Repository
+	pricing      pricing.ServiceAPI
+	tokens       tokens.ServiceAPI
+	history      HoldingHistoryRepository
+	transactions transactions.ServiceAPI
+	spam         spam.ServiceAPI
+	floors       pricing.FloorPriceProvider
+	logger       *zap.Logger
 }
 
-func NewService(repo Repository, pricing pricing.ServiceAPI, logger *zap.Logger) Service {
-	return &service{
+// Option configures optional collaborators on the portfolio service
+type Option func(*service)
+
+// WithTokens attaches token metadata to each holding in portfolio views
+func WithTokens(t tokens.ServiceAPI) Option {
+	return func(s *service) {
+		s.tokens = t
+	}
+}
+
+// WithHoldingHistory records every holding change so GetAsOf can rebuild
+// past holdings
+func WithHoldingHistory(h HoldingHistoryRepository) Option {
+	return func(s *service) {
+		s.history = h
+	}
+}
+
+// WithTransactions lets GetAsOf rebuild native holdings that have no
+// recorded history by replaying the wallet's transactions
+func WithTransactions(t transactions.ServiceAPI) Option {
+	return func(s *service) {
+		s.transactions = t
+	}
+}
+
+// WithSpam judges every token holding in portfolio views so spam can be
+// flagged and hidden
+func WithSpam(c spam.ServiceAPI) Option {
+	return func(s *service) {
+		s.spam = c
+	}
+}
+
+// WithFloorPrices values NFT holdings at their collection's floor price
+func WithFloorPrices(f pricing.FloorPriceProvider) Option {
+	return func(s *service) {
+		s.floors = f
+	}
+}
+
+func NewService(repo Repository, pricing pricing.ServiceAPI, logger *zap.Logger, opts ...Option) Service {
+	s := &service{
 		repo:    repo,
 		pricing: pricing,
 		logger:  logger,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *service) AddHolding(ctx context.Context, wallet string, h Holding) error {
@@ -57,7 +117,12 @@ func (s *service) AddHolding(ctx context.Context, wallet string, h Holding) erro
 	}
 
 	p.Holdings = append(p.Holdings, h)
-	return s.repo.Save(ctx, p)
+	if err := s.repo.Save(ctx, p); err != nil {
+		return err
+	}
+	base := p.syncedAmount(h.Chain, h.ContractAddress)
+	s.recordChange(ctx, wallet, h.Chain, h.ContractAddress, base, base+h.Amount)
+	return nil
 }
 
 func (s *service) UpdateHolding(ctx context.Context, wallet string, h Holding) error {
@@ -78,8 +143,10 @@ func (s *service) UpdateHolding(ctx context.Context, wallet string, h Holding) e
 	}
 
 	found := false
+	var previous float64
 	for i, existing := range p.Holdings {
 		if existing.Chain == h.Chain && existing.ContractAddress == h.ContractAddress {
+			previous = existing.Amount
 			p.Holdings[i].Amount = h.Amount
 			found = true
 			break
@@ -95,7 +162,12 @@ func (s *service) UpdateHolding(ctx context.Context, wallet string, h Holding) e
 		return fmt.Errorf("holding not found")
 	}
 
-	return s.repo.Save(ctx, p)
+	if err := s.repo.Save(ctx, p); err != nil {
+		return err
+	}
+	base := p.syncedAmount(h.Chain, h.ContractAddress)
+	s.recordChange(ctx, wallet, h.Chain, h.ContractAddress, base+previous, base+h.Amount)
+	return nil
 }
 
 func (s *service) RemoveHolding(ctx context.Context, wallet, chain, contract string) error {
@@ -115,15 +187,114 @@ func (s *service) RemoveHolding(ctx context.Context, wallet, chain, contract str
 	}
 
 	out := make([]Holding, 0, len(p.Holdings))
+	var removed []Holding
 	for _, h := range p.Holdings {
 		if h.Chain == chain && h.ContractAddress == contract {
+			removed = append(removed, h)
 			continue
 		}
 		out = append(out, h)
 	}
 
 	p.Holdings = out
-	return s.repo.Save(ctx, p)
+	if err := s.repo.Save(ctx, p); err != nil {
+		return err
+	}
+	for _, h := range removed {
+		base := p.syncedAmount(h.Chain, h.ContractAddress)
+		s.recordChange(ctx, wallet, h.Chain, h.ContractAddress, base+h.Amount, base)
+	}
+	return nil
+}
+
+// SetHoldings sets the amount of every given manual holding in a single
+// save, adding those not held yet and removing those set to zero. The
+// portfolio is created when the wallet has none. Nothing is saved if the
+// save fails.
+func (s *service) SetHoldings(ctx context.Context, wallet string, hs []Holding) error {
+	s.logger.Info("set-holdings",
+		zap.String("wallet", wallet),
+		zap.Int("holdings", len(hs)),
+	)
+
+	current := &Portfolio{Wallet: wallet}
+	if p, err := s.repo.Get(ctx, wallet); err == nil {
+		current = p
+	}
+
+	// work on a copy so a failed save leaves the stored portfolio untouched
+	next := &Portfolio{
+		Wallet:   wallet,
+		Holdings: make([]Holding, 0, len(current.Holdings)+len(hs)),
+		Synced:   current.Synced,
+		SyncedAt: current.SyncedAt,
+		NFTs:     current.NFTs,
+	}
+	next.Holdings = append(next.Holdings, current.Holdings...)
+
+	type change struct {
+		h        Holding
+		previous float64
+	}
+	changes := make([]change, 0, len(hs))
+
+	for _, h := range hs {
+		found := false
+		for i, existing := range next.Holdings {
+			if existing.Chain == h.Chain && existing.ContractAddress == h.ContractAddress {
+				changes = append(changes, change{h: h, previous: existing.Amount})
+				next.Holdings[i].Amount = h.Amount
+				found = true
+				break
+			}
+		}
+		if !found {
+			changes = append(changes, change{h: h})
+			next.Holdings = append(next.Holdings, h)
+		}
+	}
+
+	kept := next.Holdings[:0]
+	for _, h := range next.Holdings {
+		if h.Amount > 0 {
+			kept = append(kept, h)
+		}
+	}
+	next.Holdings = kept
+
+	if err := s.repo.Save(ctx, next); err != nil {
+		return err
+	}
+	for _, c := range changes {
+		if c.previous != c.h.Amount {
+			base := next.syncedAmount(c.h.Chain, c.h.ContractAddress)
+			s.recordChange(ctx, wallet, c.h.Chain, c.h.ContractAddress, base+c.previous, base+c.h.Amount)
+		}
+	}
+	return nil
+}
+
+// recordChange appends to the holding history when one is configured. A
+// failure is logged rather than failing a change that is already saved.
+func (s *service) recordChange(ctx context.Context, wallet, chain, contract string, previous, amount float64) {
+	if s.history == nil {
+		return
+	}
+
+	err := s.history.Append(ctx, HoldingChange{
+		Wallet:          wallet,
+		Chain:           chain,
+		ContractAddress: contract,
+		PreviousAmount:  previous,
+		Amount:          amount,
+		Time:            time.Now().UTC(),
+	})
+	if err != nil {
+		s.logger.Warn("record-holding-change-failed",
+			zap.String("wallet", wallet),
+			zap.Error(err),
+		)
+	}
 }
 
 func (s *service) Get(ctx context.Context, wallet string) (*PortfolioView, error) {
@@ -140,16 +311,13 @@ func (s *service) Get(ctx context.Context, wallet string) (*PortfolioView, error
 		return nil, err
 	}
 
-	refs := make([]pricing.AssetRef, 0, len(p.Holdings))
-	for _, h := range p.Holdings {
-		refs = append(refs, pricing.AssetRef{
-			Chain:           h.Chain,
-			ContractAddress: h.ContractAddress,
-		})
+	holdings := p.Combined()
+	refs := make([]pricing.AssetRef, 0, len(holdings))
+	for _, h := range holdings {
+		refs = append(refs, h.AssetRef())
 	}
 
-	prices, err := s.pricing.GetPrices(ctx, refs)
-	fmt.Println(prices)
+	market, err := s.pricing.GetMarketData(ctx, refs)
 	if err != nil {
 		s.logger.Error("pricing-failed",
 			zap.String("wallet", wallet),
@@ -158,37 +326,163 @@ func (s *service) Get(ctx context.Context, wallet string) (*PortfolioView, error
 		return nil, err
 	}
 
-	var total float64
-	views := make([]HoldingView, 0, len(p.Holdings))
+	view := NewView(wallet, holdings, market)
+	for i, h := range view.Holdings {
+		view.Holdings[i].SyncedAmount = p.syncedAmount(h.Chain, h.ContractAddress)
+	}
+	view.SyncedAt = p.SyncedAt
+	if s.tokens != nil {
+		view.AttachTokens(s.tokens.Lookup(ctx, refs))
+	}
+	s.attachSpam(ctx, view)
+	s.attachNFTs(ctx, view, p.NFTs)
+
+	s.logger.Info("portfolio-valued",
+		zap.String("wallet", wallet),
+		zap.Int("holdings", len(view.Holdings)),
+		zap.Float64("total_usd", view.TotalValueUSD),
+		zap.Float64("pnl_24h_usd", view.PnL24hUSD),
+	)
+
+	return view, nil
+}
 
+// Holdings returns the raw holdings of a wallet without valuing them:
+// the manual ones, then the synced ones, each with its Source set
+func (s *service) Holdings(ctx context.Context, wallet string) ([]Holding, error) {
+	p, err := s.repo.Get(ctx, wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Holding, 0, len(p.Holdings)+len(p.Synced))
 	for _, h := range p.Holdings {
-		ref := pricing.AssetRef{
-			Chain:           h.Chain,
-			ContractAddress: h.ContractAddress,
-		}
+		h.Source = HoldingSourceManual
+		out = append(out, h)
+	}
+	for _, h := range p.Synced {
+		h.Source = HoldingSourceSync
+		out = append(out, h)
+	}
+	return out, nil
+}
 
-		price := prices[ref]
-		value := price * h.Amount
+// Wallets lists every wallet that has a portfolio
+func (s *service) Wallets(ctx context.Context) ([]string, error) {
+	return s.repo.Wallets(ctx)
+}
+
+// NewView values holdings against already fetched market data
+func NewView(wallet string, holdings []Holding, market map[pricing.AssetRef]pricing.MarketData) *PortfolioView {
+	var total, pnl24h float64
+	views := make([]HoldingView, 0, len(holdings))
+
+	for _, h := range holdings {
+		md := market[h.AssetRef()]
+		value := md.PriceUSD * h.Amount
+		change := valueChange24h(value, md.Change24hPct)
 		total += value
+		pnl24h += change
 
 		views = append(views, HoldingView{
-			Chain:           h.Chain,
-			ContractAddress: h.ContractAddress,
-			Amount:          h.Amount,
-			PriceUSD:        price,
-			ValueUSD:        value,
+			Chain:             h.Chain,
+			ContractAddress:   h.ContractAddress,
+			Amount:            h.Amount,
+			QuantitySource:    QuantitySourceCurrent,
+			PriceUSD:          md.PriceUSD,
+			ValueUSD:          value,
+			Change24hPct:      md.Change24hPct,
+			ValueChange24hUSD: change,
+			PriceSource:       md.Source,
+			PriceOverridden:   md.Source == pricing.PriceSourceOverride,
+			PriceNote:         md.Note,
+			PriceDerivation:   md.Derivation,
 		})
 	}
 
-	s.logger.Info("portfolio-valued",
-		zap.String("wallet", wallet),
-		zap.Int("holdings", len(views)),
-		zap.Float64("total_usd", total),
-	)
+	var pnl24hPct float64
+	if prev := total - pnl24h; prev > 0 {
+		pnl24hPct = pnl24h / prev * 100
+	}
 
 	return &PortfolioView{
 		Wallet:        wallet,
 		Holdings:      views,
 		TotalValueUSD: total,
-	}, nil
+		PnL24hUSD:     pnl24h,
+		PnL24hPct:     pnl24hPct,
+	}
+}
+
+// AttachTokens sets the metadata of every holding it has an entry for
+func (v *PortfolioView) AttachTokens(meta map[pricing.AssetRef]tokens.Metadata) {
+	for i, h := range v.Holdings {
+		m, ok := meta[pricing.AssetRef{Chain: h.Chain, ContractAddress: h.ContractAddress}]
+		if !ok {
+			continue
+		}
+		v.Holdings[i].Token = &m
+	}
+}
+
+// AttachSpam sets the verdict of every holding it has an entry for
+func (v *PortfolioView) AttachSpam(verdicts map[pricing.AssetRef]spam.Verdict) {
+	for i, h := range v.Holdings {
+		verdict, ok := verdicts[pricing.AssetRef{Chain: h.Chain, ContractAddress: h.ContractAddress}]
+		if !ok {
+			continue
+		}
+		v.Holdings[i].Spam = &verdict
+	}
+}
+
+// HideSpam drops the holdings judged spam and totals the portfolio
+// without them
+func (v *PortfolioView) HideSpam() {
+	kept := make([]HoldingView, 0, len(v.Holdings))
+	var total, pnl24h float64
+	for _, h := range v.Holdings {
+		if h.Spam != nil && h.Spam.Spam {
+			v.HiddenSpam++
+			continue
+		}
+		kept = append(kept, h)
+		total += h.ValueUSD
+		pnl24h += h.ValueChange24hUSD
+	}
+
+	v.Holdings = kept
+	v.TotalValueUSD = total
+	v.PnL24hUSD = pnl24h
+	v.PnL24hPct = 0
+	if prev := total - pnl24h; prev > 0 {
+		v.PnL24hPct = pnl24h / prev * 100
+	}
+}
+
+// attachSpam classifies the view's holdings when a classifier is set
+func (s *service) attachSpam(ctx context.Context, view *PortfolioView) {
+	if s.spam == nil || len(view.Holdings) == 0 {
+		return
+	}
+	judged := make([]spam.Token, 0, len(view.Holdings))
+	for _, h := range view.Holdings {
+		t := spam.Token{Asset: pricing.AssetRef{Chain: h.Chain, ContractAddress: h.ContractAddress}}
+		if h.Token != nil {
+			t.Symbol = h.Token.Symbol
+			t.Name = h.Token.Name
+		}
+		judged = append(judged, t)
+	}
+	view.AttachSpam(s.spam.Classify(ctx, view.Wallet, judged))
+}
+
+// valueChange24h derives how much a position's value moved given its current
+// value and the price change percentage over the same window
+func valueChange24h(value, changePct float64) float64 {
+	if changePct <= -100 {
+		return 0
+	}
+	previous := value / (1 + changePct/100)
+	return value - previous
 }
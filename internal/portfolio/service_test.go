@@ -3,16 +3,22 @@ package portfolio_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/spam"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
 )
 
 type mockPricingService struct {
-	prices map[pricing.AssetRef]float64
+	prices     map[pricing.AssetRef]float64
+	changes    map[pricing.AssetRef]float64
+	sources    map[pricing.AssetRef]pricing.PriceSource
+	historical map[pricing.AssetRef]float64
 }
 
 func (m *mockPricingService) GetPrices(
@@ -22,6 +28,40 @@ func (m *mockPricingService) GetPrices(
 	return m.prices, nil
 }
 
+func (m *mockPricingService) GetMarketData(
+	ctx context.Context,
+	assets []pricing.AssetRef,
+) (map[pricing.AssetRef]pricing.MarketData, error) {
+	out := make(map[pricing.AssetRef]pricing.MarketData)
+	for a, p := range m.prices {
+		out[a] = pricing.MarketData{PriceUSD: p, Change24hPct: m.changes[a], Source: m.sources[a]}
+	}
+	return out, nil
+}
+
+func (m *mockPricingService) GetCandles(
+	ctx context.Context,
+	asset pricing.AssetRef,
+	interval pricing.Interval,
+	days int,
+) ([]pricing.Candle, error) {
+	return nil, nil
+}
+
+func (m *mockPricingService) GetHistoricalPrices(
+	ctx context.Context,
+	assets []pricing.AssetRef,
+	at time.Time,
+) (map[pricing.AssetRef]pricing.HistoricalPrice, error) {
+	out := make(map[pricing.AssetRef]pricing.HistoricalPrice)
+	for _, a := range assets {
+		if p, ok := m.historical[a]; ok {
+			out[a] = pricing.HistoricalPrice{PriceUSD: p, Time: at.Truncate(time.Hour), Source: pricing.PriceSourceHistorical}
+		}
+	}
+	return out, nil
+}
+
 func setupService() portfolio.Service {
 	logger := zap.NewNop()
 
@@ -64,6 +104,155 @@ func TestGetPortfolio(t *testing.T) {
 	require.Equal(t, 4000.0, view.TotalValueUSD)
 }
 
+func TestGetPortfolio_PnL24h(t *testing.T) {
+	repo := portfolio.NewMemoryRepository([]*portfolio.Portfolio{
+		{
+			Wallet: "wallet1",
+			Holdings: []portfolio.Holding{
+				{Chain: "ethereum", ContractAddress: "", Amount: 2},
+				{Chain: "ethereum", ContractAddress: "0xusdc", Amount: 1000},
+			},
+		},
+	})
+
+	eth := pricing.AssetRef{Chain: "ethereum", ContractAddress: ""}
+	usdc := pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xusdc"}
+
+	pricingSvc := &mockPricingService{
+		prices:  map[pricing.AssetRef]float64{eth: 2200, usdc: 1},
+		changes: map[pricing.AssetRef]float64{eth: 10},
+	}
+
+	svc := portfolio.NewService(repo, pricingSvc, zap.NewNop())
+
+	view, err := svc.Get(context.Background(), "wallet1")
+	require.NoError(t, err)
+
+	// ETH was 2000 a day ago: 2 * (2200 - 2000)
+	require.InDelta(t, 400.0, view.Holdings[0].ValueChange24hUSD, 1e-9)
+	require.Equal(t, 10.0, view.Holdings[0].Change24hPct)
+	require.Equal(t, 0.0, view.Holdings[1].ValueChange24hUSD)
+
+	require.Equal(t, 5400.0, view.TotalValueUSD)
+	require.InDelta(t, 400.0, view.PnL24hUSD, 1e-9)
+	require.InDelta(t, 8.0, view.PnL24hPct, 1e-9) // 400 / 5000
+}
+
+func TestGetPortfolio_HidesSpam(t *testing.T) {
+	repo := portfolio.NewMemoryRepository([]*portfolio.Portfolio{
+		{
+			Wallet: "wallet1",
+			Holdings: []portfolio.Holding{
+				{Chain: "ethereum", ContractAddress: "", Amount: 2},
+				{Chain: "ethereum", ContractAddress: "0xscam", Amount: 5000},
+			},
+		},
+	})
+
+	eth := pricing.AssetRef{Chain: "ethereum", ContractAddress: ""}
+	scam := pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xscam"}
+
+	pricingSvc := &mockPricingService{
+		prices:  map[pricing.AssetRef]float64{eth: 2200, scam: 1},
+		changes: map[pricing.AssetRef]float64{eth: 10},
+	}
+	classifier := spam.NewService(spam.List{Deny: []pricing.AssetRef{scam}}, spam.NewMemoryOverrideRepository(), nil, nil, zap.NewNop())
+
+	svc := portfolio.NewService(repo, pricingSvc, zap.NewNop(), portfolio.WithSpam(classifier))
+
+	view, err := svc.Get(context.Background(), "wallet1")
+	require.NoError(t, err)
+	require.Len(t, view.Holdings, 2)
+	require.False(t, view.Holdings[0].Spam.Spam)
+	require.Equal(t, spam.ReasonDenyList, view.Holdings[1].Spam.Reason)
+	require.Equal(t, 9400.0, view.TotalValueUSD)
+
+	view.HideSpam()
+	require.Len(t, view.Holdings, 1)
+	require.Equal(t, 1, view.HiddenSpam)
+	require.Equal(t, 4400.0, view.TotalValueUSD)
+	require.InDelta(t, 10.0, view.PnL24hPct, 1e-9) // 400 / 4000
+}
+
+type mockFloorPrices map[pricing.AssetRef]float64
+
+func (m mockFloorPrices) Name() string { return "mock" }
+
+func (m mockFloorPrices) GetFloorPrices(ctx context.Context, collections []pricing.AssetRef) (map[pricing.AssetRef]float64, error) {
+	return m, nil
+}
+
+func TestGetPortfolio_ValuesNFTs(t *testing.T) {
+	punks := pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xpunks"}
+	svc := portfolio.NewService(
+		portfolio.NewMemoryRepository(nil),
+		&mockPricingService{prices: map[pricing.AssetRef]float64{{Chain: "ethereum"}: 2000}},
+		zap.NewNop(),
+		portfolio.WithFloorPrices(mockFloorPrices{punks: 50_000}),
+	)
+	ctx := context.Background()
+
+	require.NoError(t, svc.AddHolding(ctx, "wallet1", portfolio.Holding{Chain: "ethereum", Amount: 1}))
+	require.NoError(t, svc.SyncNFTs(ctx, "wallet1", "ethereum", []portfolio.NFTHolding{
+		{Collection: "0xpunks", TokenID: "42", Standard: transactions.StandardERC721, Amount: 1},
+		{Collection: "0xitems", TokenID: "7", Standard: transactions.StandardERC1155, Amount: 3},
+		{Collection: "0xpunks", TokenID: "43", Amount: 0},
+	}))
+	require.NoError(t, svc.SyncNFTs(ctx, "wallet1", "polygon", []portfolio.NFTHolding{
+		{Collection: "0xland", TokenID: "1", Amount: 1},
+	}))
+
+	// a later sync of a chain only replaces that chain's NFTs, and a
+	// holdings sync leaves them all alone
+	require.NoError(t, svc.SyncNFTs(ctx, "wallet1", "ethereum", []portfolio.NFTHolding{
+		{Collection: "0xpunks", TokenID: "42", Standard: transactions.StandardERC721, Amount: 1},
+		{Collection: "0xitems", TokenID: "7", Standard: transactions.StandardERC1155, Amount: 2},
+	}))
+	require.NoError(t, svc.SyncHoldings(ctx, "wallet1", nil, time.Now()))
+
+	view, err := svc.Get(ctx, "wallet1")
+	require.NoError(t, err)
+	require.Equal(t, 2000.0, view.TotalValueUSD)
+	require.Len(t, view.NFTs, 3)
+
+	byToken := make(map[string]portfolio.NFTHoldingView)
+	for _, n := range view.NFTs {
+		byToken[n.Collection+"/"+n.TokenID] = n
+	}
+	require.Equal(t, 50_000.0, byToken["0xpunks/42"].ValueUSD)
+	require.Equal(t, "ethereum", byToken["0xpunks/42"].Chain)
+	require.Equal(t, 2.0, byToken["0xitems/7"].Amount)
+	// collections without a floor are unvalued
+	require.Zero(t, byToken["0xitems/7"].FloorPriceUSD)
+	require.Equal(t, "polygon", byToken["0xland/1"].Chain)
+	require.Equal(t, 50_000.0, view.NFTValueUSD)
+}
+
+func TestGetPortfolio_FlagsOverriddenPrices(t *testing.T) {
+	repo := portfolio.NewMemoryRepository([]*portfolio.Portfolio{
+		{
+			Wallet: "wallet1",
+			Holdings: []portfolio.Holding{
+				{Chain: "ethereum", ContractAddress: "0xvest", Amount: 1000},
+			},
+		},
+	})
+
+	vest := pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xvest"}
+	pricingSvc := &mockPricingService{
+		prices:  map[pricing.AssetRef]float64{vest: 0.25},
+		sources: map[pricing.AssetRef]pricing.PriceSource{vest: pricing.PriceSourceOverride},
+	}
+
+	svc := portfolio.NewService(repo, pricingSvc, zap.NewNop())
+
+	view, err := svc.Get(context.Background(), "wallet1")
+	require.NoError(t, err)
+	require.True(t, view.Holdings[0].PriceOverridden)
+	require.Equal(t, pricing.PriceSourceOverride, view.Holdings[0].PriceSource)
+	require.Equal(t, 250.0, view.TotalValueUSD)
+}
+
 func TestAddHolding(t *testing.T) {
 	svc := setupService()
 
@@ -103,3 +292,223 @@ func TestRemoveHolding(t *testing.T) {
 	view, _ := svc.Get(context.Background(), "wallet1")
 	require.Len(t, view.Holdings, 0)
 }
+
+func TestSetHoldings(t *testing.T) {
+	history := portfolio.NewMemoryHistoryRepository()
+	repo := portfolio.NewMemoryRepository(nil)
+	svc := portfolio.NewService(repo, &mockPricingService{}, zap.NewNop(), portfolio.WithHoldingHistory(history))
+
+	ctx := context.Background()
+	require.NoError(t, svc.AddHolding(ctx, "wallet1", portfolio.Holding{Chain: "ethereum", Amount: 1}))
+	require.NoError(t, svc.AddHolding(ctx, "wallet1", portfolio.Holding{Chain: "ethereum", ContractAddress: "0xdai", Amount: 50}))
+
+	err := svc.SetHoldings(ctx, "wallet1", []portfolio.Holding{
+		{Chain: "ethereum", Amount: 2},
+		{Chain: "ethereum", ContractAddress: "0xusdc", Amount: 100},
+		{Chain: "ethereum", ContractAddress: "0xdai", Amount: 0},
+	})
+	require.NoError(t, err)
+
+	holdings, err := svc.Holdings(ctx, "wallet1")
+	require.NoError(t, err)
+	require.Equal(t, []portfolio.Holding{
+		{Chain: "ethereum", Amount: 2, Source: portfolio.HoldingSourceManual},
+		{Chain: "ethereum", ContractAddress: "0xusdc", Amount: 100, Source: portfolio.HoldingSourceManual},
+	}, holdings)
+
+	changes, err := history.List(ctx, "wallet1")
+	require.NoError(t, err)
+	require.Len(t, changes, 2+3)
+}
+
+func TestSyncHoldingsKeptApart(t *testing.T) {
+	history := portfolio.NewMemoryHistoryRepository()
+	repo := portfolio.NewMemoryRepository(nil)
+	pricingSvc := &mockPricingService{
+		prices: map[pricing.AssetRef]float64{
+			{Chain: "ethereum"}:                            2000,
+			{Chain: "ethereum", ContractAddress: "0xusdc"}: 1,
+		},
+	}
+	svc := portfolio.NewService(repo, pricingSvc, zap.NewNop(), portfolio.WithHoldingHistory(history))
+
+	ctx := context.Background()
+	require.NoError(t, svc.AddHolding(ctx, "wallet1", portfolio.Holding{Chain: "ethereum", Amount: 1}))
+
+	syncedAt := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, svc.SyncHoldings(ctx, "wallet1", []portfolio.Holding{
+		{Chain: "ethereum", Amount: 0.5},
+		{Chain: "ethereum", ContractAddress: "0xusdc", Amount: 100},
+	}, syncedAt))
+
+	// a manual update leaves the synced amount alone
+	require.NoError(t, svc.UpdateHolding(ctx, "wallet1", portfolio.Holding{Chain: "ethereum", Amount: 2}))
+
+	holdings, err := svc.Holdings(ctx, "wallet1")
+	require.NoError(t, err)
+	require.Equal(t, []portfolio.Holding{
+		{Chain: "ethereum", Amount: 2, Source: portfolio.HoldingSourceManual},
+		{Chain: "ethereum", Amount: 0.5, Source: portfolio.HoldingSourceSync},
+		{Chain: "ethereum", ContractAddress: "0xusdc", Amount: 100, Source: portfolio.HoldingSourceSync},
+	}, holdings)
+
+	view, err := svc.Get(ctx, "wallet1")
+	require.NoError(t, err)
+	require.Len(t, view.Holdings, 2)
+	require.Equal(t, 2.5, view.Holdings[0].Amount)
+	require.Equal(t, 0.5, view.Holdings[0].SyncedAmount)
+	require.Equal(t, 5100.0, view.TotalValueUSD)
+	require.Equal(t, syncedAt, *view.SyncedAt)
+
+	// history records the combined amount of each asset
+	changes, err := history.List(ctx, "wallet1")
+	require.NoError(t, err)
+	require.Equal(t, 2.5, changes[len(changes)-1].Amount)
+
+	// a later sync without USDC drops it
+	require.NoError(t, svc.SyncHoldings(ctx, "wallet1", []portfolio.Holding{{Chain: "ethereum", Amount: 0.5}}, syncedAt))
+	view, err = svc.Get(ctx, "wallet1")
+	require.NoError(t, err)
+	require.Len(t, view.Holdings, 1)
+}
+
+type fakeTransactions struct {
+	txs []transactions.Transaction
+}
+
+func (f *fakeTransactions) List(
+	ctx context.Context,
+	chain string,
+	wallet string,
+	page int,
+	limit int,
+	filters transactions.Filters,
+) ([]transactions.Transaction, error) {
+	start := (page - 1) * limit
+	if start >= len(f.txs) {
+		return nil, nil
+	}
+	return f.txs[start:min(start+limit, len(f.txs))], nil
+}
+
+func TestGetAsOf_RebuildsFromHoldingHistory(t *testing.T) {
+	asOf := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+	usdc := pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xusdc"}
+	wbtc := pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xwbtc"}
+	link := pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xlink"}
+
+	repo := portfolio.NewMemoryRepository([]*portfolio.Portfolio{
+		{
+			Wallet: "wallet1",
+			Holdings: []portfolio.Holding{
+				{Chain: "ethereum", ContractAddress: "0xusdc", Amount: 2500},
+				{Chain: "ethereum", ContractAddress: "0xlink", Amount: 10},
+			},
+		},
+	})
+
+	history := portfolio.NewMemoryHistoryRepository()
+	for _, c := range []portfolio.HoldingChange{
+		{ContractAddress: "0xusdc", Amount: 1000, Time: asOf.AddDate(0, -1, 0)},
+		{ContractAddress: "0xwbtc", Amount: 0.5, Time: asOf.AddDate(0, -1, 0)},
+		{ContractAddress: "0xusdc", PreviousAmount: 1000, Amount: 2500, Time: asOf.AddDate(0, 0, 5)},
+		{ContractAddress: "0xwbtc", PreviousAmount: 0.5, Amount: 0, Time: asOf.AddDate(0, 0, 6)},
+		{ContractAddress: "0xlink", Amount: 10, Time: asOf.AddDate(0, 0, 7)},
+	} {
+		c.Wallet, c.Chain = "wallet1", "ethereum"
+		require.NoError(t, history.Append(context.Background(), c))
+	}
+
+	pricingSvc := &mockPricingService{
+		historical: map[pricing.AssetRef]float64{usdc: 1, wbtc: 70000, link: 18},
+	}
+
+	svc := portfolio.NewService(repo, pricingSvc, zap.NewNop(), portfolio.WithHoldingHistory(history))
+
+	view, err := svc.GetAsOf(context.Background(), "wallet1", asOf)
+	require.NoError(t, err)
+	require.Equal(t, asOf, *view.AsOf)
+
+	// LINK was only added after asOf; WBTC was held then and sold since
+	require.Len(t, view.Holdings, 2)
+	require.Equal(t, "0xusdc", view.Holdings[0].ContractAddress)
+	require.Equal(t, 1000.0, view.Holdings[0].Amount)
+	require.Equal(t, "0xwbtc", view.Holdings[1].ContractAddress)
+	require.Equal(t, 0.5, view.Holdings[1].Amount)
+
+	for _, h := range view.Holdings {
+		require.Equal(t, portfolio.QuantitySourceHoldingHistory, h.QuantitySource)
+		require.Equal(t, pricing.PriceSourceHistorical, h.PriceSource)
+		require.NotNil(t, h.PriceTime)
+	}
+	require.Equal(t, 36000.0, view.TotalValueUSD)
+}
+
+func TestGetAsOf_ReplaysNativeTransactions(t *testing.T) {
+	asOf := time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)
+	eth := pricing.AssetRef{Chain: "ethereum", ContractAddress: ""}
+
+	repo := portfolio.NewMemoryRepository([]*portfolio.Portfolio{
+		{
+			Wallet: "wallet1",
+			Holdings: []portfolio.Holding{
+				{Chain: "ethereum", ContractAddress: "", Amount: 5},
+				{Chain: "ethereum", ContractAddress: "0xusdc", Amount: 100},
+			},
+		},
+	})
+
+	txs := &fakeTransactions{txs: []transactions.Transaction{
+		{Amount: 3, Direction: transactions.DirectionIn, Status: transactions.StatusSuccess, Timestamp: asOf.Add(48 * time.Hour)},
+		{Amount: 9, Direction: transactions.DirectionIn, Status: transactions.StatusFailed, Timestamp: asOf.Add(36 * time.Hour)},
+		{Amount: 1, Direction: transactions.DirectionOut, Status: transactions.StatusSuccess, Timestamp: asOf.Add(24 * time.Hour)},
+		{Amount: 4, Direction: transactions.DirectionIn, Status: transactions.StatusSuccess, Timestamp: asOf.Add(-time.Hour)},
+	}}
+
+	pricingSvc := &mockPricingService{
+		historical: map[pricing.AssetRef]float64{eth: 3000},
+	}
+
+	svc := portfolio.NewService(repo, pricingSvc, zap.NewNop(), portfolio.WithTransactions(txs))
+
+	view, err := svc.GetAsOf(context.Background(), "wallet1", asOf)
+	require.NoError(t, err)
+	require.Len(t, view.Holdings, 2)
+
+	// 5 now, minus 3 received and plus 1 sent after asOf
+	native := view.Holdings[0]
+	require.Equal(t, 3.0, native.Amount)
+	require.Equal(t, portfolio.QuantitySourceTransactions, native.QuantitySource)
+	require.Equal(t, 9000.0, native.ValueUSD)
+
+	// tokens have no history to rebuild from and keep the current amount,
+	// and are left unvalued without a historical price
+	token := view.Holdings[1]
+	require.Equal(t, 100.0, token.Amount)
+	require.Equal(t, portfolio.QuantitySourceCurrent, token.QuantitySource)
+	require.Zero(t, token.ValueUSD)
+	require.Nil(t, token.PriceTime)
+	require.NotEmpty(t, token.PriceNote)
+
+	require.Equal(t, 9000.0, view.TotalValueUSD)
+}
+
+func TestHoldingChangesAreRecorded(t *testing.T) {
+	history := portfolio.NewMemoryHistoryRepository()
+	repo := portfolio.NewMemoryRepository(nil)
+	svc := portfolio.NewService(repo, &mockPricingService{}, zap.NewNop(), portfolio.WithHoldingHistory(history))
+
+	ctx := context.Background()
+	h := portfolio.Holding{Chain: "ethereum", ContractAddress: "0xusdc", Amount: 10}
+	require.NoError(t, svc.AddHolding(ctx, "wallet1", h))
+	h.Amount = 25
+	require.NoError(t, svc.UpdateHolding(ctx, "wallet1", h))
+	require.NoError(t, svc.RemoveHolding(ctx, "wallet1", "ethereum", "0xusdc"))
+
+	changes, err := history.List(ctx, "wallet1")
+	require.NoError(t, err)
+	require.Len(t, changes, 3)
+	require.Equal(t, [2]float64{0, 10}, [2]float64{changes[0].PreviousAmount, changes[0].Amount})
+	require.Equal(t, [2]float64{10, 25}, [2]float64{changes[1].PreviousAmount, changes[1].Amount})
+	require.Equal(t, [2]float64{25, 0}, [2]float64{changes[2].PreviousAmount, changes[2].Amount})
+}
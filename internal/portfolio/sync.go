@@ -0,0 +1,98 @@
+package portfolio
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+// Combined lists every asset once with its manual and synced amounts added
+// up, manual holdings first
+func (p *Portfolio) Combined() []Holding {
+	out := make([]Holding, 0, len(p.Holdings)+len(p.Synced))
+	index := make(map[pricing.AssetRef]int, len(p.Holdings)+len(p.Synced))
+
+	for _, list := range [][]Holding{p.Holdings, p.Synced} {
+		for _, h := range list {
+			ref := h.AssetRef()
+			if i, ok := index[ref]; ok {
+				out[i].Amount += h.Amount
+				continue
+			}
+			index[ref] = len(out)
+			out = append(out, Holding{Chain: h.Chain, ContractAddress: h.ContractAddress, Amount: h.Amount})
+		}
+	}
+	return out
+}
+
+// syncedAmount is the amount of an asset last read from the chain
+func (p *Portfolio) syncedAmount(chain, contract string) float64 {
+	var total float64
+	for _, h := range p.Synced {
+		if h.Chain == chain && h.ContractAddress == contract {
+			total += h.Amount
+		}
+	}
+	return total
+}
+
+// SyncHoldings replaces the wallet's synced holdings with on-chain balances
+// read at syncedAt, leaving manual holdings alone. The portfolio is created
+// when the wallet has none.
+func (s *service) SyncHoldings(ctx context.Context, wallet string, hs []Holding, syncedAt time.Time) error {
+	s.logger.Info("sync-holdings",
+		zap.String("wallet", wallet),
+		zap.Int("holdings", len(hs)),
+	)
+
+	current := &Portfolio{Wallet: wallet}
+	if p, err := s.repo.Get(ctx, wallet); err == nil {
+		current = p
+	}
+
+	synced := make([]Holding, 0, len(hs))
+	for _, h := range hs {
+		if h.Amount > 0 {
+			h.Source = HoldingSourceSync
+			synced = append(synced, h)
+		}
+	}
+
+	next := &Portfolio{
+		Wallet:   wallet,
+		Holdings: current.Holdings,
+		Synced:   synced,
+		SyncedAt: &syncedAt,
+		NFTs:     current.NFTs,
+	}
+	if err := s.repo.Save(ctx, next); err != nil {
+		return err
+	}
+
+	// history holds the combined amount of each asset
+	before := amounts(current.Combined())
+	after := amounts(next.Combined())
+	for ref, amount := range after {
+		if before[ref] != amount {
+			s.recordChange(ctx, wallet, ref.Chain, ref.ContractAddress, before[ref], amount)
+		}
+	}
+	for ref, amount := range before {
+		if _, ok := after[ref]; !ok {
+			s.recordChange(ctx, wallet, ref.Chain, ref.ContractAddress, amount, 0)
+		}
+	}
+	return nil
+}
+
+func amounts(hs []Holding) map[pricing.AssetRef]float64 {
+	out := make(map[pricing.AssetRef]float64, len(hs))
+	for _, h := range hs {
+		out[h.AssetRef()] = h.Amount
+	}
+	return out
+}
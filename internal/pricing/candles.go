@@ -0,0 +1,160 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidInterval     = errors.New("invalid candle interval")
+	ErrInvalidRange        = errors.New("invalid candle range")
+	ErrCandlesNotSupported = errors.New("no provider supports candles")
+)
+
+// MaxCandleDays is the longest range that can be requested
+const MaxCandleDays = 365
+
+// maxIntradayDays is the longest range for which intraday (hourly) source
+// data is available, so sub-daily candles are limited to it
+const maxIntradayDays = 90
+
+// ValidateCandleRequest checks that interval and days form a range the providers can serve
+func ValidateCandleRequest(interval Interval, days int) error {
+	if interval.Duration() == 0 {
+		return ErrInvalidInterval
+	}
+	if days <= 0 || days > MaxCandleDays {
+		return ErrInvalidRange
+	}
+	if interval != Interval1d && days > maxIntradayDays {
+		return fmt.Errorf("%w: %s candles are limited to %d days", ErrInvalidRange, interval, maxIntradayDays)
+	}
+	return nil
+}
+
+// GetCandles returns OHLC candles for an asset. Candles are cached per
+// granularity, coarser candles being kept longer.
+func (s *Service) GetCandles(
+	ctx context.Context,
+	asset AssetRef,
+	interval Interval,
+	days int,
+) ([]Candle, error) {
+	s.logger.Info("get-candles",
+		zap.String("chain", asset.Chain),
+		zap.String("contract", asset.ContractAddress),
+		zap.String("interval", string(interval)),
+		zap.Int("days", days),
+	)
+
+	if err := ValidateCandleRequest(interval, days); err != nil {
+		return nil, err
+	}
+
+	key := candleCacheKey(asset, interval, days)
+	if cachedStr, err := s.cache.Get(ctx, key); err == nil {
+		var candles []Candle
+		if err := json.Unmarshal([]byte(cachedStr), &candles); err == nil {
+			return candles, nil
+		}
+	}
+
+	candles, err := fetchCandles(ctx, s.primary, asset, interval, days)
+	if err != nil {
+		s.logger.Warn("primary-candles-failed",
+			zap.String("provider", s.primary.Name()),
+			zap.Error(err),
+		)
+
+		candles, err = fetchCandles(ctx, s.fallback, asset, interval, days)
+		if err != nil {
+			return nil, fmt.Errorf("candles failed: %w", err)
+		}
+	}
+
+	if encoded, err := json.Marshal(candles); err == nil {
+		_ = s.cache.Set(ctx, key, string(encoded), candleCacheTTL(interval))
+	}
+
+	return candles, nil
+}
+
+func fetchCandles(
+	ctx context.Context,
+	provider PriceProvider,
+	asset AssetRef,
+	interval Interval,
+	days int,
+) ([]Candle, error) {
+	cp, ok := provider.(CandleProvider)
+	if !ok {
+		return nil, ErrCandlesNotSupported
+	}
+	return cp.GetCandles(ctx, asset, interval, days)
+}
+
+// BuildCandles buckets [timestamp, price] points into candles of the given
+// interval. Points need not be sorted.
+func BuildCandles(points []PricePoint, interval Interval) []Candle {
+	width := interval.Duration()
+	if width == 0 || len(points) == 0 {
+		return nil
+	}
+
+	sorted := make([]PricePoint, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Time.Before(sorted[j].Time)
+	})
+
+	candles := make([]Candle, 0)
+	for _, p := range sorted {
+		open := p.Time.UTC().Truncate(width)
+
+		n := len(candles)
+		if n > 0 && candles[n-1].Time.Equal(open) {
+			c := &candles[n-1]
+			c.High = max(c.High, p.PriceUSD)
+			c.Low = min(c.Low, p.PriceUSD)
+			c.Close = p.PriceUSD
+			continue
+		}
+
+		candles = append(candles, Candle{
+			Time:  open,
+			Open:  p.PriceUSD,
+			High:  p.PriceUSD,
+			Low:   p.PriceUSD,
+			Close: p.PriceUSD,
+		})
+	}
+
+	return candles
+}
+
+// PricePoint is a single observed price
+type PricePoint struct {
+	Time     time.Time
+	PriceUSD float64
+}
+
+func candleCacheTTL(interval Interval) time.Duration {
+	switch interval {
+	case Interval1h:
+		return 5 * time.Minute
+	case Interval4h:
+		return 15 * time.Minute
+	default:
+		return time.Hour
+	}
+}
+
+func candleCacheKey(a AssetRef, interval Interval, days int) string {
+	return fmt.Sprintf("ohlc:%s:%d:%s:%s", interval, days, a.Chain, a.ContractAddress)
+}
@@ -0,0 +1,97 @@
+package pricing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeCandleProvider struct {
+	fakeProvider
+	candles []Candle
+}
+
+func (f *fakeCandleProvider) GetCandles(
+	ctx context.Context,
+	asset AssetRef,
+	interval Interval,
+	days int,
+) ([]Candle, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.candles, nil
+}
+
+func TestBuildCandles(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	points := []PricePoint{
+		{Time: base.Add(90 * time.Minute), PriceUSD: 12},
+		{Time: base, PriceUSD: 10},
+		{Time: base.Add(30 * time.Minute), PriceUSD: 14},
+		{Time: base.Add(45 * time.Minute), PriceUSD: 9},
+		{Time: base.Add(60 * time.Minute), PriceUSD: 11},
+	}
+
+	candles := BuildCandles(points, Interval1h)
+
+	require.Len(t, candles, 2)
+	require.Equal(t, Candle{Time: base, Open: 10, High: 14, Low: 9, Close: 9}, candles[0])
+	require.Equal(t, Candle{Time: base.Add(time.Hour), Open: 11, High: 12, Low: 11, Close: 12}, candles[1])
+}
+
+func TestValidateCandleRequest(t *testing.T) {
+	require.NoError(t, ValidateCandleRequest(Interval1d, 365))
+	require.NoError(t, ValidateCandleRequest(Interval1h, 90))
+	require.ErrorIs(t, ValidateCandleRequest(Interval1h, 91), ErrInvalidRange)
+	require.ErrorIs(t, ValidateCandleRequest("5m", 1), ErrInvalidInterval)
+	require.ErrorIs(t, ValidateCandleRequest(Interval1d, 0), ErrInvalidRange)
+}
+
+func TestPricingService_GetCandles_Cached(t *testing.T) {
+	cache := newFakeCache()
+	asset := AssetRef{Chain: "ethereum", ContractAddress: "0xabc"}
+
+	primary := &fakeCandleProvider{
+		fakeProvider: fakeProvider{name: "primary"},
+		candles:      []Candle{{Open: 1, High: 2, Low: 1, Close: 2}},
+	}
+
+	svc := NewService(cache, primary, nil, time.Minute, zap.NewNop())
+
+	_, err := svc.GetCandles(context.Background(), asset, Interval1d, 7)
+	require.NoError(t, err)
+
+	candles, err := svc.GetCandles(context.Background(), asset, Interval1d, 7)
+	require.NoError(t, err)
+	require.Len(t, candles, 1)
+	require.Equal(t, 1, primary.calls)
+
+	// a different granularity is a separate cache entry
+	_, err = svc.GetCandles(context.Background(), asset, Interval4h, 7)
+	require.NoError(t, err)
+	require.Equal(t, 2, primary.calls)
+}
+
+func TestPricingService_GetCandles_FallbackWhenUnsupported(t *testing.T) {
+	cache := newFakeCache()
+	asset := AssetRef{Chain: "ethereum", ContractAddress: "0xabc"}
+
+	primary := &fakeProvider{name: "primary"}
+	fallback := &fakeCandleProvider{
+		fakeProvider: fakeProvider{name: "fallback"},
+		candles:      []Candle{{Open: 3, High: 3, Low: 3, Close: 3}},
+	}
+
+	svc := NewService(cache, primary, fallback, time.Minute, zap.NewNop())
+
+	candles, err := svc.GetCandles(context.Background(), asset, Interval1d, 7)
+
+	require.NoError(t, err)
+	require.Equal(t, 3.0, candles[0].Close)
+}
@@ -3,6 +3,7 @@ package coingecko
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,6 +13,9 @@ import (
 	"golang.org/x/time/rate"
 )
 
+// ErrNotFound is returned when coingecko does not know the requested coin or contract
+var ErrNotFound = errors.New("coingecko: not found")
+
 type Client struct {
 	httpClient *http.Client
 	limiter    *rate.Limiter
@@ -34,6 +38,25 @@ func (c *Client) FetchTokenPrices(
 	chain string,
 	contracts []string,
 ) (TokenPriceResponse, error) {
+	return c.fetchTokenPrice(ctx, chain, contracts, false)
+}
+
+// FetchTokenMarketData is FetchTokenPrices with the market cap, 24h volume,
+// 24h change and last-updated flags switched on
+func (c *Client) FetchTokenMarketData(
+	ctx context.Context,
+	chain string,
+	contracts []string,
+) (TokenPriceResponse, error) {
+	return c.fetchTokenPrice(ctx, chain, contracts, true)
+}
+
+func (c *Client) fetchTokenPrice(
+	ctx context.Context,
+	chain string,
+	contracts []string,
+	withMarketData bool,
+) (TokenPriceResponse, error) {
 
 	if c.limiter != nil {
 		if err := c.limiter.Wait(ctx); err != nil {
@@ -47,6 +70,9 @@ func (c *Client) FetchTokenPrices(
 		chain,
 		strings.Join(contracts, ","),
 	)
+	if withMarketData {
+		url += "&include_market_cap=true&include_24hr_vol=true&include_24hr_change=true&include_last_updated_at=true"
+	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -77,3 +103,223 @@ func (c *Client) FetchTokenPrices(
 
 	return decoded, nil
 }
+
+// FetchContractMarketChart returns the price history of a token contract over
+// the last `days` days. Coingecko picks the granularity: 5 minutely for 1 day,
+// hourly up to 90 days and daily beyond.
+func (c *Client) FetchContractMarketChart(
+	ctx context.Context,
+	chain string,
+	contract string,
+	days int,
+) (*MarketChartResponse, error) {
+
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	url := fmt.Sprintf(
+		"%s/coins/%s/contract/%s/market_chart?vs_currency=usd&days=%d",
+		c.baseURL,
+		chain,
+		strings.ToLower(contract),
+		days,
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-cg-demo-api-key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("coingecko error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var decoded MarketChartResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	return &decoded, nil
+}
+
+// FetchCoinMarketChart is FetchContractMarketChart for a coin ID, used for
+// native assets that have no contract
+func (c *Client) FetchCoinMarketChart(
+	ctx context.Context,
+	coinID string,
+	days int,
+) (*MarketChartResponse, error) {
+	url := fmt.Sprintf(
+		"%s/coins/%s/market_chart?vs_currency=usd&days=%d",
+		c.baseURL,
+		coinID,
+		days,
+	)
+
+	var decoded MarketChartResponse
+	if err := c.getJSON(ctx, url, &decoded); err != nil {
+		return nil, err
+	}
+	return &decoded, nil
+}
+
+// FetchContractMarketChartRange returns the price history of a token contract
+// between two times. Ranges under 90 days come back hourly.
+func (c *Client) FetchContractMarketChartRange(
+	ctx context.Context,
+	chain string,
+	contract string,
+	from time.Time,
+	to time.Time,
+) (*MarketChartResponse, error) {
+	url := fmt.Sprintf(
+		"%s/coins/%s/contract/%s/market_chart/range?vs_currency=usd&from=%d&to=%d",
+		c.baseURL,
+		chain,
+		strings.ToLower(contract),
+		from.Unix(),
+		to.Unix(),
+	)
+
+	var decoded MarketChartResponse
+	if err := c.getJSON(ctx, url, &decoded); err != nil {
+		return nil, err
+	}
+	return &decoded, nil
+}
+
+// FetchCoinMarketChartRange is FetchContractMarketChartRange for a coin ID,
+// used for native assets that have no contract
+func (c *Client) FetchCoinMarketChartRange(
+	ctx context.Context,
+	coinID string,
+	from time.Time,
+	to time.Time,
+) (*MarketChartResponse, error) {
+	url := fmt.Sprintf(
+		"%s/coins/%s/market_chart/range?vs_currency=usd&from=%d&to=%d",
+		c.baseURL,
+		coinID,
+		from.Unix(),
+		to.Unix(),
+	)
+
+	var decoded MarketChartResponse
+	if err := c.getJSON(ctx, url, &decoded); err != nil {
+		return nil, err
+	}
+	return &decoded, nil
+}
+
+// FetchContractInfo returns the coin coingecko tracks for a token contract,
+// including its symbol, name, logo and decimals per platform
+func (c *Client) FetchContractInfo(
+	ctx context.Context,
+	chain string,
+	contract string,
+) (*ContractInfoResponse, error) {
+
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	url := fmt.Sprintf(
+		"%s/coins/%s/contract/%s?localization=false&tickers=false&market_data=false&community_data=false&developer_data=false",
+		c.baseURL,
+		chain,
+		strings.ToLower(contract),
+	)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-cg-demo-api-key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("coingecko error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var decoded ContractInfoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, err
+	}
+
+	return &decoded, nil
+}
+
+// FetchCoinsList returns every coin coingecko tracks with its contract
+// address per platform. The list is large and should be cached by the caller.
+func (c *Client) FetchCoinsList(ctx context.Context) ([]CoinListItem, error) {
+	var decoded []CoinListItem
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/coins/list?include_platform=true", c.baseURL), &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+// FetchCoinsMarkets returns one page of coins ordered by market cap
+func (c *Client) FetchCoinsMarkets(ctx context.Context, page, perPage int) ([]CoinMarketItem, error) {
+	url := fmt.Sprintf(
+		"%s/coins/markets?vs_currency=usd&order=market_cap_desc&per_page=%d&page=%d",
+		c.baseURL,
+		perPage,
+		page,
+	)
+
+	var decoded []CoinMarketItem
+	if err := c.getJSON(ctx, url, &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+func (c *Client) getJSON(ctx context.Context, url string, out any) error {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("x-cg-demo-api-key", c.apiKey)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("coingecko error %d: %s", resp.StatusCode, string(body))
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,91 @@
+package coingecko
+
+import (
+	"context"
+	"time"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/utils"
+)
+
+const (
+	// market cap ranks are fetched for the top rankedPages*marketsPerPage coins
+	rankedPages    = 4
+	marketsPerPage = 250
+)
+
+// CoinListProvider builds the token search list from coingecko's coins list
+// and market cap ranking
+type CoinListProvider struct {
+	client *Client
+}
+
+func NewCoinListProvider(client *Client) *CoinListProvider {
+	return &CoinListProvider{client: client}
+}
+
+var _ tokens.CoinListProvider = (*CoinListProvider)(nil)
+
+func (p *CoinListProvider) Name() string {
+	return "coingecko"
+}
+
+func (p *CoinListProvider) GetCoins(ctx context.Context) ([]tokens.Coin, error) {
+	retry := utils.RetryConfig{
+		MaxRetries: 3,
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   4 * time.Second,
+	}
+
+	var list []CoinListItem
+	err := utils.Retry(ctx, retry, func() error {
+		l, err := p.client.FetchCoinsList(ctx)
+		if err != nil {
+			return err
+		}
+		list = l
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ranks := make(map[string]int)
+	for page := 1; page <= rankedPages; page++ {
+		var markets []CoinMarketItem
+		err := utils.Retry(ctx, retry, func() error {
+			m, err := p.client.FetchCoinsMarkets(ctx, page, marketsPerPage)
+			if err != nil {
+				return err
+			}
+			markets = m
+			return nil
+		})
+		if err != nil {
+			// ranks only order results, the list is still usable without them
+			break
+		}
+
+		for _, m := range markets {
+			if m.MarketCapRank != nil {
+				ranks[m.ID] = *m.MarketCapRank
+			}
+		}
+		if len(markets) < marketsPerPage {
+			break
+		}
+	}
+
+	coins := make([]tokens.Coin, 0, len(list))
+	for _, item := range list {
+		coins = append(coins, tokens.Coin{
+			ID:            item.ID,
+			Symbol:        item.Symbol,
+			Name:          item.Name,
+			MarketCapRank: ranks[item.ID],
+			Platforms:     item.Platforms,
+		})
+	}
+
+	return coins, nil
+}
@@ -0,0 +1,50 @@
+package coingecko
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+)
+
+// MetadataProvider resolves token symbol, name, decimals and logo from coingecko
+type MetadataProvider struct {
+	client *Client
+}
+
+func NewMetadataProvider(client *Client) *MetadataProvider {
+	return &MetadataProvider{client: client}
+}
+
+var _ tokens.MetadataProvider = (*MetadataProvider)(nil)
+
+func (p *MetadataProvider) Name() string {
+	return "coingecko"
+}
+
+// GetMetadata is not retried: misses are resolved by the background
+// resolver and come back around on the next lookup
+func (p *MetadataProvider) GetMetadata(ctx context.Context, asset pricing.AssetRef) (*tokens.Metadata, error) {
+	info, err := p.client.FetchContractInfo(ctx, asset.Chain, asset.ContractAddress)
+	if errors.Is(err, ErrNotFound) {
+		return nil, tokens.ErrTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m := &tokens.Metadata{
+		Chain:           asset.Chain,
+		ContractAddress: strings.ToLower(asset.ContractAddress),
+		Symbol:          strings.ToUpper(info.Symbol),
+		Name:            info.Name,
+		LogoURL:         info.Image.Small,
+	}
+	if platform, ok := info.DetailPlatforms[asset.Chain]; ok && platform.DecimalPlace != nil {
+		m.Decimals = *platform.DecimalPlace
+	}
+
+	return m, nil
+}
@@ -2,11 +2,13 @@ package coingecko
 
 import (
 	"context"
+	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/utils"
 )
 
@@ -18,6 +20,8 @@ func NewProvider(client *Client) *Provider {
 	return &Provider{client: client}
 }
 
+var _ pricing.MarketDataProvider = (*Provider)(nil)
+
 func (p *Provider) Name() string {
 	return "coingecko"
 }
@@ -26,7 +30,56 @@ func (p *Provider) GetPrices(ctx context.Context, assets []pricing.AssetRef) (ma
 
 	result := make(map[pricing.AssetRef]float64)
 
-	// group by chain as required by coingecko
+	err := p.fetchGrouped(ctx, assets, p.client.FetchTokenPrices, func(a pricing.AssetRef, obj map[string]any) {
+		price, ok := toFloat(obj["usd"])
+		if !ok {
+			return
+		}
+		result[a] = price
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (p *Provider) GetMarketData(ctx context.Context, assets []pricing.AssetRef) (map[pricing.AssetRef]pricing.MarketData, error) {
+
+	result := make(map[pricing.AssetRef]pricing.MarketData)
+
+	err := p.fetchGrouped(ctx, assets, p.client.FetchTokenMarketData, func(a pricing.AssetRef, obj map[string]any) {
+		price, ok := toFloat(obj["usd"])
+		if !ok {
+			return
+		}
+
+		md := pricing.MarketData{PriceUSD: price}
+		md.Change24hPct, _ = toFloat(obj["usd_24h_change"])
+		md.Volume24hUSD, _ = toFloat(obj["usd_24h_vol"])
+		md.MarketCapUSD, _ = toFloat(obj["usd_market_cap"])
+		if ts, ok := toFloat(obj["last_updated_at"]); ok && ts > 0 {
+			md.LastUpdated = time.Unix(int64(ts), 0)
+		}
+
+		result[a] = md
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// fetchGrouped batches assets per chain, as required by coingecko, and hands
+// each asset's raw response object to collect
+func (p *Provider) fetchGrouped(
+	ctx context.Context,
+	assets []pricing.AssetRef,
+	fetch func(ctx context.Context, chain string, contracts []string) (TokenPriceResponse, error),
+	collect func(a pricing.AssetRef, obj map[string]any),
+) error {
+
 	grouped := make(map[string][]pricing.AssetRef)
 	for _, a := range assets {
 		grouped[a.Chain] = append(grouped[a.Chain], a)
@@ -45,7 +98,7 @@ func (p *Provider) GetPrices(ctx context.Context, assets []pricing.AssetRef) (ma
 				contracts = append(contracts, a.ContractAddress)
 			}
 
-			raw, err := p.client.FetchTokenPrices(ctx, chain, contracts)
+			raw, err := fetch(ctx, chain, contracts)
 			if err != nil {
 				return err
 			}
@@ -60,36 +113,152 @@ func (p *Provider) GetPrices(ctx context.Context, assets []pricing.AssetRef) (ma
 				if !ok {
 					continue
 				}
-
-				usdVal, ok := obj["usd"]
-				if !ok {
-					continue
-				}
-				var price float64
-
-				// resolving coingecko return type inconsistency
-				switch val := usdVal.(type) {
-				case float64:
-					price = val
-				case string:
-					price, err = strconv.ParseFloat(val, 64)
-					if err != nil {
-						continue
-					}
-				default:
-					continue
-				}
-				result[a] = price
-
+				collect(a, obj)
 			}
 
 			return nil
 		})
 
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
 
-	return result, nil
+	return nil
+}
+
+// toFloat resolves coingecko return type inconsistency (numbers vs strings)
+func toFloat(v any) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, true
+	case string:
+		f, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return 0, false
+		}
+		return f, true
+	default:
+		return 0, false
+	}
+}
+
+var _ pricing.CandleProvider = (*Provider)(nil)
+
+// GetCandles builds OHLC candles from the contract's market chart, or the
+// coin's for a native asset
+func (p *Provider) GetCandles(
+	ctx context.Context,
+	asset pricing.AssetRef,
+	interval pricing.Interval,
+	days int,
+) ([]pricing.Candle, error) {
+
+	fetch := func() (*MarketChartResponse, error) {
+		return p.client.FetchContractMarketChart(ctx, asset.Chain, asset.ContractAddress, days)
+	}
+	if asset.ContractAddress == "" {
+		coinID, ok := tokens.NativeCoinID(asset.Chain)
+		if !ok {
+			return nil, fmt.Errorf("no native coin for chain %s", asset.Chain)
+		}
+		fetch = func() (*MarketChartResponse, error) {
+			return p.client.FetchCoinMarketChart(ctx, coinID, days)
+		}
+	}
+
+	var chart *MarketChartResponse
+
+	err := utils.Retry(ctx, utils.RetryConfig{
+		MaxRetries: 3,
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   4 * time.Second,
+	}, func() error {
+		c, err := fetch()
+		if err != nil {
+			return err
+		}
+		chart = c
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pricing.BuildCandles(toPricePoints(chart.Prices), interval), nil
+}
+
+func toPricePoints(raw [][2]float64) []pricing.PricePoint {
+	points := make([]pricing.PricePoint, 0, len(raw))
+	for _, r := range raw {
+		points = append(points, pricing.PricePoint{
+			Time:     time.UnixMilli(int64(r[0])),
+			PriceUSD: r[1],
+		})
+	}
+	return points
+}
+
+var _ pricing.HistoricalPriceProvider = (*Provider)(nil)
+
+// historicalWindow is how far before the requested time a price point is
+// still accepted
+const historicalWindow = 48 * time.Hour
+
+// GetHistoricalPrice returns the last price coingecko recorded at or before at
+func (p *Provider) GetHistoricalPrice(
+	ctx context.Context,
+	asset pricing.AssetRef,
+	at time.Time,
+) (pricing.HistoricalPrice, error) {
+
+	fetch := func() (*MarketChartResponse, error) {
+		return p.client.FetchContractMarketChartRange(ctx, asset.Chain, asset.ContractAddress, at.Add(-historicalWindow), at)
+	}
+	if asset.ContractAddress == "" {
+		coinID, ok := tokens.NativeCoinID(asset.Chain)
+		if !ok {
+			return pricing.HistoricalPrice{}, fmt.Errorf("no native coin for chain %s", asset.Chain)
+		}
+		fetch = func() (*MarketChartResponse, error) {
+			return p.client.FetchCoinMarketChartRange(ctx, coinID, at.Add(-historicalWindow), at)
+		}
+	}
+
+	var chart *MarketChartResponse
+
+	err := utils.Retry(ctx, utils.RetryConfig{
+		MaxRetries: 3,
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   4 * time.Second,
+	}, func() error {
+		c, err := fetch()
+		if err != nil {
+			return err
+		}
+		chart = c
+		return nil
+	})
+	if err != nil {
+		return pricing.HistoricalPrice{}, err
+	}
+
+	var (
+		found  bool
+		latest pricing.PricePoint
+	)
+	for _, pt := range toPricePoints(chart.Prices) {
+		if pt.Time.After(at) {
+			continue
+		}
+		if !found || pt.Time.After(latest.Time) {
+			latest = pt
+			found = true
+		}
+	}
+	if !found {
+		return pricing.HistoricalPrice{}, fmt.Errorf("no price for %s within %s before %s", asset, historicalWindow, at.Format(time.RFC3339))
+	}
+
+	return pricing.HistoricalPrice{PriceUSD: latest.PriceUSD, Time: latest.Time}, nil
 }
@@ -2,11 +2,15 @@ package coingecko
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
 	"github.com/test-go/testify/require"
 )
 
@@ -32,3 +36,158 @@ func TestCoinGeckoProvider_ParsesResponse(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, 123.45, prices[asset])
 }
+
+func TestCoinGeckoProvider_ParsesMarketData(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "true", r.URL.Query().Get("include_24hr_change"))
+		w.Write([]byte(`{
+			"0xabc": {
+				"usd": 123.45,
+				"usd_market_cap": 1000000,
+				"usd_24h_vol": "2500.5",
+				"usd_24h_change": -3.2,
+				"last_updated_at": 1700000000
+			}
+		}`))
+	}))
+	defer ts.Close()
+
+	provider := NewProvider(
+		NewClient("test", ts.URL),
+	)
+
+	asset := pricing.AssetRef{
+		Chain:           "ethereum",
+		ContractAddress: "0xabc",
+	}
+
+	data, err := provider.GetMarketData(context.Background(), []pricing.AssetRef{asset})
+
+	require.NoError(t, err)
+	md := data[asset]
+	require.Equal(t, 123.45, md.PriceUSD)
+	require.Equal(t, 1000000.0, md.MarketCapUSD)
+	require.Equal(t, 2500.5, md.Volume24hUSD)
+	require.Equal(t, -3.2, md.Change24hPct)
+	require.Equal(t, time.Unix(1700000000, 0), md.LastUpdated)
+}
+
+func TestCoinGeckoProvider_GetCandles(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/coins/ethereum/contract/0xabc/market_chart", r.URL.Path)
+		require.Equal(t, "2", r.URL.Query().Get("days"))
+		w.Write([]byte(fmt.Sprintf(`{"prices": [[%d, 10], [%d, 12], [%d, 8]]}`,
+			base.UnixMilli(),
+			base.Add(30*time.Minute).UnixMilli(),
+			base.Add(26*time.Hour).UnixMilli(),
+		)))
+	}))
+	defer ts.Close()
+
+	provider := NewProvider(
+		NewClient("test", ts.URL),
+	)
+
+	asset := pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xabc"}
+
+	candles, err := provider.GetCandles(context.Background(), asset, pricing.Interval1d, 2)
+
+	require.NoError(t, err)
+	require.Len(t, candles, 2)
+	require.Equal(t, 12.0, candles[0].High)
+	require.Equal(t, 8.0, candles[1].Close)
+}
+
+func TestMetadataProvider_GetMetadata(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/coins/ethereum/contract/0xa0b8" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{
+			"id": "usd-coin",
+			"symbol": "usdc",
+			"name": "USDC",
+			"image": { "small": "https://example.com/usdc.png" },
+			"detail_platforms": {
+				"ethereum": { "decimal_place": 6, "contract_address": "0xa0b8" }
+			}
+		}`))
+	}))
+	defer ts.Close()
+
+	provider := NewMetadataProvider(NewClient("test", ts.URL))
+
+	m, err := provider.GetMetadata(context.Background(), pricing.AssetRef{
+		Chain:           "ethereum",
+		ContractAddress: "0xA0B8",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "USDC", m.Symbol)
+	require.Equal(t, "USDC", m.Name)
+	require.Equal(t, 6, m.Decimals)
+	require.Equal(t, "0xa0b8", m.ContractAddress)
+	require.Equal(t, "https://example.com/usdc.png", m.LogoURL)
+
+	_, err = provider.GetMetadata(context.Background(), pricing.AssetRef{
+		Chain:           "ethereum",
+		ContractAddress: "0xdead",
+	})
+	require.True(t, errors.Is(err, tokens.ErrTokenNotFound))
+}
+
+func TestCoinListProvider_MergesMarketCapRanks(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/coins/list":
+			require.Equal(t, "true", r.URL.Query().Get("include_platform"))
+			w.Write([]byte(`[
+				{"id": "usd-coin", "symbol": "usdc", "name": "USDC", "platforms": {"ethereum": "0xa0b8"}},
+				{"id": "obscure", "symbol": "obs", "name": "Obscure", "platforms": {}}
+			]`))
+		case "/coins/markets":
+			w.Write([]byte(`[{"id": "usd-coin", "market_cap_rank": 7}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	client := NewClient("test", ts.URL)
+	client.limiter = nil
+
+	coins, err := NewCoinListProvider(client).GetCoins(context.Background())
+	require.NoError(t, err)
+	require.Len(t, coins, 2)
+	require.Equal(t, 7, coins[0].MarketCapRank)
+	require.Equal(t, "0xa0b8", coins[0].Platforms["ethereum"])
+	require.Equal(t, 0, coins[1].MarketCapRank)
+}
+
+func TestCoinGeckoProvider_GetHistoricalPrice(t *testing.T) {
+	at := time.Date(2024, 3, 31, 23, 59, 0, 0, time.UTC)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/coins/ethereum/market_chart/range", r.URL.Path)
+		require.Equal(t, fmt.Sprint(at.Unix()), r.URL.Query().Get("to"))
+		w.Write([]byte(fmt.Sprintf(`{"prices": [[%d, 3400], [%d, 3500], [%d, 3600]]}`,
+			at.Add(-2*time.Hour).UnixMilli(),
+			at.Add(-time.Hour).UnixMilli(),
+			at.Add(time.Minute).UnixMilli(),
+		)))
+	}))
+	defer ts.Close()
+
+	provider := NewProvider(
+		NewClient("test", ts.URL),
+	)
+
+	// native ETH has no contract and is fetched by its coin ID
+	hp, err := provider.GetHistoricalPrice(context.Background(), pricing.AssetRef{Chain: "ethereum"}, at)
+
+	require.NoError(t, err)
+	require.Equal(t, 3500.0, hp.PriceUSD)
+	require.True(t, hp.Time.Equal(at.Add(-time.Hour)))
+}
@@ -1,3 +1,37 @@
 package coingecko
 
 type TokenPriceResponse map[string]any
+
+// MarketChartResponse holds [unix millis, value] pairs
+type MarketChartResponse struct {
+	Prices       [][2]float64 `json:"prices"`
+	MarketCaps   [][2]float64 `json:"market_caps"`
+	TotalVolumes [][2]float64 `json:"total_volumes"`
+}
+
+type ContractInfoResponse struct {
+	ID     string `json:"id"`
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+	Image  struct {
+		Thumb string `json:"thumb"`
+		Small string `json:"small"`
+		Large string `json:"large"`
+	} `json:"image"`
+	DetailPlatforms map[string]struct {
+		DecimalPlace    *int   `json:"decimal_place"`
+		ContractAddress string `json:"contract_address"`
+	} `json:"detail_platforms"`
+}
+
+type CoinListItem struct {
+	ID        string            `json:"id"`
+	Symbol    string            `json:"symbol"`
+	Name      string            `json:"name"`
+	Platforms map[string]string `json:"platforms"` // platform -> contract address
+}
+
+type CoinMarketItem struct {
+	ID            string `json:"id"`
+	MarketCapRank *int   `json:"market_cap_rank"`
+}
@@ -0,0 +1,103 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// derive prices an asset from its rule, pricing the underlying first
+func (s *Service) derive(
+	ctx context.Context,
+	rule Rule,
+	deriving map[AssetRef]bool,
+) (MarketData, error) {
+	if rule.Kind == RulePeg {
+		return MarketData{
+			PriceUSD:    rule.PriceUSD,
+			LastUpdated: time.Now().UTC(),
+			Source:      PriceSourceDerived,
+			Derivation:  &Derivation{Kind: RulePeg},
+		}, nil
+	}
+
+	// rule sets are checked for cycles when built; this second check keeps
+	// evaluation from recursing forever if one slips through
+	if deriving[rule.Asset] {
+		return MarketData{}, fmt.Errorf("%w through %s", ErrRuleCycle, rule.Asset)
+	}
+	next := make(map[AssetRef]bool, len(deriving)+1)
+	for a := range deriving {
+		next[a] = true
+	}
+	next[rule.Asset] = true
+
+	underlying := *rule.Underlying
+	data, err := s.marketData(ctx, []AssetRef{underlying}, next)
+	if err != nil {
+		return MarketData{}, err
+	}
+	base, ok := data[underlying]
+	if !ok {
+		return MarketData{}, fmt.Errorf("underlying %s has no price", underlying)
+	}
+
+	factor := rule.Factor
+	if rule.Kind == RuleExchangeRate {
+		factor, err = s.exchangeRate(ctx, rule)
+		if err != nil {
+			return MarketData{}, err
+		}
+	}
+
+	return MarketData{
+		PriceUSD: base.PriceUSD * factor,
+		// a wrapper moves with its underlying, the rate drifts too slowly to matter over 24h
+		Change24hPct: base.Change24hPct,
+		LastUpdated:  base.LastUpdated,
+		Source:       PriceSourceDerived,
+		Derivation: &Derivation{
+			Kind:               rule.Kind,
+			Underlying:         &underlying,
+			Factor:             factor,
+			UnderlyingPriceUSD: base.PriceUSD,
+			UnderlyingSource:   base.Source,
+		},
+	}, nil
+}
+
+// exchangeRate reads the wrapper rate on-chain, caching it like a price
+func (s *Service) exchangeRate(ctx context.Context, rule Rule) (float64, error) {
+	if s.rates == nil {
+		return 0, fmt.Errorf("no rate reader configured for %s", rule.Asset)
+	}
+
+	contract := rule.RateContract
+	if contract == "" {
+		contract = rule.Asset.ContractAddress
+	}
+
+	key := fmt.Sprintf("rate:%s:%s:%s", rule.Asset.Chain, contract, rule.RateMethod)
+	if cachedStr, err := s.cache.Get(ctx, key); err == nil {
+		if rate, err := strconv.ParseFloat(cachedStr, 64); err == nil {
+			return rate, nil
+		}
+	}
+
+	decimals := rule.RateDecimals
+	if decimals == 0 {
+		decimals = 18
+	}
+
+	rate, err := s.rates.ExchangeRate(ctx, rule.Asset.Chain, contract, rule.RateMethod, decimals)
+	if err != nil {
+		return 0, err
+	}
+	if rate <= 0 {
+		return 0, fmt.Errorf("non-positive exchange rate for %s", rule.Asset)
+	}
+
+	_ = s.cache.Set(ctx, key, strconv.FormatFloat(rate, 'f', -1, 64), s.cacheTTL)
+	return rate, nil
+}
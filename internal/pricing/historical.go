@@ -0,0 +1,160 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// PriceSourceHistorical marks a past price taken from a provider's price history
+const PriceSourceHistorical PriceSource = "historical"
+
+var ErrHistoricalNotSupported = errors.New("provider does not support historical prices")
+
+// historicalCacheTTL is long because past prices do not change
+const historicalCacheTTL = 7 * 24 * time.Hour
+
+// HistoricalPriceProvider is implemented by providers that can price an
+// asset at a past time. Providers that only make prices up must not
+// implement it: a past price is recorded as cost basis and cached for days.
+type HistoricalPriceProvider interface {
+	// GetHistoricalPrice returns the last known price at or before at
+	GetHistoricalPrice(ctx context.Context, asset AssetRef, at time.Time) (HistoricalPrice, error)
+}
+
+// HistoricalPrice is a past USD price and the time it was observed
+type HistoricalPrice struct {
+	PriceUSD float64     `json:"price_usd"`
+	Time     time.Time   `json:"time"`
+	Source   PriceSource `json:"source"`
+}
+
+// GetHistoricalPrices prices assets at a past time. Assets no provider can
+// price are left out of the result rather than failing the whole request.
+func (s *Service) GetHistoricalPrices(
+	ctx context.Context,
+	assets []AssetRef,
+	at time.Time,
+) (map[AssetRef]HistoricalPrice, error) {
+	s.logger.Info("get-historical-prices")
+
+	results := make(map[AssetRef]HistoricalPrice, len(assets))
+	for _, a := range assets {
+		key := historicalCacheKey(a, at)
+
+		if cached, err := s.cache.Get(ctx, key); err == nil {
+			var hp HistoricalPrice
+			if err := json.Unmarshal([]byte(cached), &hp); err == nil {
+				results[a] = hp
+				continue
+			}
+		}
+
+		hp, err := s.fetchHistorical(ctx, a, at)
+		if err != nil {
+			s.logger.Warn("historical-price-unavailable",
+				zap.String("asset", a.String()),
+				zap.Time("at", at),
+				zap.Error(err),
+			)
+			continue
+		}
+
+		hp.Source = PriceSourceHistorical
+		if encoded, err := json.Marshal(hp); err == nil {
+			_ = s.cache.Set(ctx, key, string(encoded), historicalCacheTTL)
+		}
+		results[a] = hp
+	}
+
+	return results, nil
+}
+
+func (s *Service) fetchHistorical(ctx context.Context, asset AssetRef, at time.Time) (HistoricalPrice, error) {
+	var errs []error
+	for _, provider := range []PriceProvider{s.primary, s.fallback} {
+		if provider == nil {
+			continue
+		}
+		hp, ok := provider.(HistoricalPriceProvider)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), ErrHistoricalNotSupported))
+			continue
+		}
+
+		price, err := hp.GetHistoricalPrice(ctx, asset, at)
+		if err == nil {
+			return price, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+	}
+	return HistoricalPrice{}, errors.Join(errs...)
+}
+
+// historicalCacheKey buckets by minute so repeated as-of queries share entries
+func historicalCacheKey(a AssetRef, at time.Time) string {
+	return fmt.Sprintf("hist:%s:%s:%d", a.Chain, a.ContractAddress, at.Truncate(time.Minute).Unix())
+}
+
+// HistoricalMemo prices assets at past times for the length of one request,
+// asking the service at most once per asset and UTC day. Every time is
+// priced at the start of its day, so a day's transfers share one lookup and
+// none is valued with a price from after it happened. It is not safe for
+// concurrent use.
+type HistoricalMemo struct {
+	prices ServiceAPI
+	days   map[memoKey]memoEntry
+}
+
+type memoKey struct {
+	asset AssetRef
+	day   int64
+}
+
+type memoEntry struct {
+	price HistoricalPrice
+	found bool
+}
+
+func NewHistoricalMemo(prices ServiceAPI) *HistoricalMemo {
+	return &HistoricalMemo{prices: prices, days: make(map[memoKey]memoEntry)}
+}
+
+// Prices returns the assets that have a price on at's day. A failed lookup
+// is remembered as unpriced rather than retried.
+func (m *HistoricalMemo) Prices(ctx context.Context, assets []AssetRef, at time.Time) map[AssetRef]HistoricalPrice {
+	day := at.UTC().Truncate(24 * time.Hour)
+
+	var missing []AssetRef
+	for _, a := range assets {
+		if _, ok := m.days[memoKey{a, day.Unix()}]; !ok {
+			missing = append(missing, a)
+		}
+	}
+
+	if len(missing) > 0 {
+		prices, err := m.prices.GetHistoricalPrices(ctx, missing, day)
+		for _, a := range missing {
+			hp, found := prices[a]
+			m.days[memoKey{a, day.Unix()}] = memoEntry{price: hp, found: found && err == nil}
+		}
+	}
+
+	out := make(map[AssetRef]HistoricalPrice, len(assets))
+	for _, a := range assets {
+		if e := m.days[memoKey{a, day.Unix()}]; e.found {
+			out[a] = e.price
+		}
+	}
+	return out
+}
+
+// Price returns the asset's price on at's day
+func (m *HistoricalMemo) Price(ctx context.Context, asset AssetRef, at time.Time) (HistoricalPrice, bool) {
+	hp, ok := m.Prices(ctx, []AssetRef{asset}, at)[asset]
+	return hp, ok
+}
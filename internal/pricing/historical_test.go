@@ -0,0 +1,120 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/test-go/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeHistoricalProvider struct {
+	fakeProvider
+	history map[AssetRef]HistoricalPrice
+}
+
+func (f *fakeHistoricalProvider) GetHistoricalPrice(
+	ctx context.Context,
+	asset AssetRef,
+	at time.Time,
+) (HistoricalPrice, error) {
+	f.calls++
+	if f.err != nil {
+		return HistoricalPrice{}, f.err
+	}
+	hp, ok := f.history[asset]
+	if !ok {
+		return HistoricalPrice{}, errors.New("no history")
+	}
+	return hp, nil
+}
+
+func TestPricingService_HistoricalPrices_FallbackAndCache(t *testing.T) {
+	cache := newFakeCache()
+	at := time.Date(2024, 3, 31, 23, 59, 0, 0, time.UTC)
+	eth := AssetRef{Chain: "ethereum", ContractAddress: ""}
+	unknown := AssetRef{Chain: "ethereum", ContractAddress: "0xdead"}
+
+	// the primary cannot price history at all, the fallback can
+	primary := &fakeProvider{name: "primary"}
+	fallback := &fakeHistoricalProvider{
+		fakeProvider: fakeProvider{name: "fallback"},
+		history: map[AssetRef]HistoricalPrice{
+			eth: {PriceUSD: 3500, Time: at.Truncate(time.Hour)},
+		},
+	}
+
+	svc := NewService(cache, primary, fallback, time.Minute, zap.NewNop())
+
+	prices, err := svc.GetHistoricalPrices(context.Background(), []AssetRef{eth, unknown}, at)
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	require.Equal(t, HistoricalPrice{PriceUSD: 3500, Time: at.Truncate(time.Hour), Source: PriceSourceHistorical}, prices[eth])
+	require.Equal(t, 2, fallback.calls)
+
+	// the priced asset is served from cache, the unknown one is retried
+	prices, err = svc.GetHistoricalPrices(context.Background(), []AssetRef{eth, unknown}, at)
+	require.NoError(t, err)
+	require.Equal(t, 3500.0, prices[eth].PriceUSD)
+	require.Equal(t, 3, fallback.calls)
+	require.Zero(t, primary.calls)
+}
+
+func TestPricingService_HistoricalPrices_UnpricedWithoutHistory(t *testing.T) {
+	cache := newFakeCache()
+	at := time.Date(2024, 3, 31, 23, 59, 0, 0, time.UTC)
+	eth := AssetRef{Chain: "ethereum", ContractAddress: ""}
+
+	// neither provider has price history, so nothing is made up or cached
+	svc := NewService(cache, &fakeProvider{name: "primary"}, &fakeProvider{name: "fallback"}, time.Minute, zap.NewNop())
+
+	prices, err := svc.GetHistoricalPrices(context.Background(), []AssetRef{eth}, at)
+	require.NoError(t, err)
+	require.Empty(t, prices)
+	require.Empty(t, cache.(*fakeCache).data)
+}
+
+type countingHistorical struct {
+	ServiceAPI
+	asked []time.Time
+}
+
+func (c *countingHistorical) GetHistoricalPrices(ctx context.Context, assets []AssetRef, at time.Time) (map[AssetRef]HistoricalPrice, error) {
+	c.asked = append(c.asked, at)
+	out := make(map[AssetRef]HistoricalPrice)
+	for _, a := range assets {
+		if a.ContractAddress == "" {
+			out[a] = HistoricalPrice{PriceUSD: float64(at.Day()), Time: at}
+		}
+	}
+	return out, nil
+}
+
+func TestHistoricalMemo_OneLookupPerAssetAndDay(t *testing.T) {
+	prices := &countingHistorical{}
+	memo := NewHistoricalMemo(prices)
+	ctx := context.Background()
+	eth := AssetRef{Chain: "ethereum"}
+	unknown := AssetRef{Chain: "ethereum", ContractAddress: "0xdead"}
+
+	morning := time.Date(2024, 3, 5, 9, 30, 0, 0, time.UTC)
+	hp, ok := memo.Price(ctx, eth, morning)
+	require.True(t, ok)
+	require.Equal(t, 5.0, hp.PriceUSD)
+
+	// later the same day, and an unpriced asset asked twice
+	_, ok = memo.Price(ctx, eth, morning.Add(12*time.Hour))
+	require.True(t, ok)
+	_, ok = memo.Price(ctx, unknown, morning)
+	require.False(t, ok)
+	_, ok = memo.Price(ctx, unknown, morning.Add(time.Hour))
+	require.False(t, ok)
+
+	_, ok = memo.Price(ctx, eth, morning.Add(24*time.Hour))
+	require.True(t, ok)
+
+	day := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, []time.Time{day, day, day.Add(24 * time.Hour)}, prices.asked)
+}
@@ -2,7 +2,9 @@ package mock
 
 import (
 	"context"
+	"fmt"
 	"hash/fnv"
+	"time"
 
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
 )
@@ -18,6 +20,7 @@ func (p *Provider) Name() string {
 }
 
 var _ pricing.PriceProvider = (*Provider)(nil)
+var _ pricing.MarketDataProvider = (*Provider)(nil)
 
 func (p *Provider) GetPrices(
 	ctx context.Context,
@@ -31,8 +34,78 @@ func (p *Provider) GetPrices(
 	return result, nil
 }
 
+func (p *Provider) GetMarketData(
+	ctx context.Context,
+	assets []pricing.AssetRef,
+) (map[pricing.AssetRef]pricing.MarketData, error) {
+
+	now := time.Now()
+	result := make(map[pricing.AssetRef]pricing.MarketData)
+	for _, a := range assets {
+		price := deterministicPrice(a.ContractAddress)
+		result[a] = pricing.MarketData{
+			PriceUSD:     price,
+			Change24hPct: float64(hash(a.ContractAddress)%2_000)/100 - 10, // -10% .. +10%
+			Volume24hUSD: price * 10_000,
+			MarketCapUSD: price * 1_000_000,
+			LastUpdated:  now,
+		}
+	}
+	return result, nil
+}
+
 func deterministicPrice(input string) float64 {
+	return float64(hash(input)%50_000) / 100
+}
+
+func hash(input string) uint32 {
 	h := fnv.New32a()
 	h.Write([]byte(input))
-	return float64(h.Sum32()%50_000) / 100
+	return h.Sum32()
+}
+
+var _ pricing.CandleProvider = (*Provider)(nil)
+
+// GetCandles returns a deterministic series that oscillates around the mock price
+func (p *Provider) GetCandles(
+	ctx context.Context,
+	asset pricing.AssetRef,
+	interval pricing.Interval,
+	days int,
+) ([]pricing.Candle, error) {
+
+	width := interval.Duration()
+	base := deterministicPrice(asset.ContractAddress)
+	end := time.Now().UTC().Truncate(width)
+	count := int(time.Duration(days) * 24 * time.Hour / width)
+
+	candles := make([]pricing.Candle, 0, count)
+	for i := count - 1; i >= 0; i-- {
+		open := base * (1 + float64(hash(fmt.Sprint(asset.ContractAddress, i))%200-100)/10_000)
+		closePrice := base * (1 + float64(hash(fmt.Sprint(asset.ContractAddress, i-1))%200-100)/10_000)
+		candles = append(candles, pricing.Candle{
+			Time:  end.Add(-time.Duration(i) * width),
+			Open:  open,
+			High:  max(open, closePrice) * 1.005,
+			Low:   min(open, closePrice) * 0.995,
+			Close: closePrice,
+		})
+	}
+	return candles, nil
+}
+
+var _ pricing.FloorPriceProvider = (*Provider)(nil)
+
+// GetFloorPrices returns a deterministic floor of up to 10,000 USD for
+// every collection
+func (p *Provider) GetFloorPrices(
+	ctx context.Context,
+	collections []pricing.AssetRef,
+) (map[pricing.AssetRef]float64, error) {
+
+	result := make(map[pricing.AssetRef]float64)
+	for _, c := range collections {
+		result[c] = float64(hash("floor:"+c.ContractAddress)%1_000_000) / 100
+	}
+	return result, nil
 }
@@ -0,0 +1,37 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrOverrideNotFound = errors.New("price override not found")
+
+// Override is a manually set price for an asset no provider can price,
+// such as vesting, private-sale or delisted tokens
+type Override struct {
+	Asset     AssetRef   `json:"asset"`
+	PriceUSD  float64    `json:"price_usd"`
+	Note      string     `json:"note,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // nil never expires
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func (o Override) Active(now time.Time) bool {
+	return o.ExpiresAt == nil || now.Before(*o.ExpiresAt)
+}
+
+type OverrideRepository interface {
+	List(ctx context.Context) ([]Override, error)
+	// Find returns the overrides, expired or not, set on any of assets
+	Find(ctx context.Context, assets []AssetRef) ([]Override, error)
+	Save(ctx context.Context, o Override) error
+	Delete(ctx context.Context, asset AssetRef) error
+}
+
+type OverrideServiceAPI interface {
+	ListOverrides(ctx context.Context) ([]Override, error)
+	SetOverride(ctx context.Context, o Override) (Override, error)
+	DeleteOverride(ctx context.Context, asset AssetRef) error
+}
@@ -0,0 +1,58 @@
+package pricing
+
+import (
+	"context"
+	"sync"
+)
+
+type memoryOverrideRepository struct {
+	mu   sync.RWMutex
+	data map[AssetRef]Override
+}
+
+func NewMemoryOverrideRepository() OverrideRepository {
+	return &memoryOverrideRepository{data: make(map[AssetRef]Override)}
+}
+
+func (r *memoryOverrideRepository) List(ctx context.Context) ([]Override, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]Override, 0, len(r.data))
+	for _, o := range r.data {
+		out = append(out, o)
+	}
+	return out, nil
+}
+
+func (r *memoryOverrideRepository) Find(ctx context.Context, assets []AssetRef) ([]Override, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]Override, 0)
+	for _, a := range assets {
+		if o, ok := r.data[a]; ok {
+			out = append(out, o)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryOverrideRepository) Save(ctx context.Context, o Override) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.data[o.Asset] = o
+	return nil
+}
+
+func (r *memoryOverrideRepository) Delete(ctx context.Context, asset AssetRef) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.data[asset]; !ok {
+		return ErrOverrideNotFound
+	}
+	delete(r.data, asset)
+	return nil
+}
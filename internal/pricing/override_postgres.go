@@ -0,0 +1,108 @@
+package pricing
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresOverrideRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresOverrideRepository(pool *pgxpool.Pool) OverrideRepository {
+	return &postgresOverrideRepository{pool: pool}
+}
+
+func (r *postgresOverrideRepository) List(ctx context.Context) ([]Override, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT chain, contract_address, price_usd, note, expires_at, updated_at
+		FROM price_overrides
+		ORDER BY chain, contract_address`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanOverrides(rows)
+}
+
+// Find looks the assets up by primary key, so it stays cheap on the pricing
+// path however many overrides are stored
+func (r *postgresOverrideRepository) Find(ctx context.Context, assets []AssetRef) ([]Override, error) {
+	chains := make([]string, len(assets))
+	contracts := make([]string, len(assets))
+	for i, a := range assets {
+		chains[i] = a.Chain
+		contracts[i] = a.ContractAddress
+	}
+
+	rows, err := r.pool.Query(ctx, `
+		SELECT o.chain, o.contract_address, o.price_usd, o.note, o.expires_at, o.updated_at
+		FROM price_overrides o
+		JOIN unnest($1::text[], $2::text[]) AS w(chain, contract_address)
+			ON o.chain = w.chain AND o.contract_address = w.contract_address`,
+		chains,
+		contracts,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanOverrides(rows)
+}
+
+func scanOverrides(rows pgx.Rows) ([]Override, error) {
+	out := make([]Override, 0)
+	for rows.Next() {
+		var o Override
+		if err := rows.Scan(
+			&o.Asset.Chain,
+			&o.Asset.ContractAddress,
+			&o.PriceUSD,
+			&o.Note,
+			&o.ExpiresAt,
+			&o.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, o)
+	}
+	return out, rows.Err()
+}
+
+func (r *postgresOverrideRepository) Save(ctx context.Context, o Override) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO price_overrides (chain, contract_address, price_usd, note, expires_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (chain, contract_address) DO UPDATE SET
+			price_usd  = EXCLUDED.price_usd,
+			note       = EXCLUDED.note,
+			expires_at = EXCLUDED.expires_at,
+			updated_at = EXCLUDED.updated_at`,
+		o.Asset.Chain,
+		o.Asset.ContractAddress,
+		o.PriceUSD,
+		o.Note,
+		o.ExpiresAt,
+		o.UpdatedAt,
+	)
+	return err
+}
+
+func (r *postgresOverrideRepository) Delete(ctx context.Context, asset AssetRef) error {
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM price_overrides WHERE chain = $1 AND contract_address = $2`,
+		asset.Chain,
+		asset.ContractAddress,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrOverrideNotFound
+	}
+	return nil
+}
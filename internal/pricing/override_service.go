@@ -0,0 +1,65 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrOverridesDisabled = errors.New("price overrides are not configured")
+	ErrInvalidOverride   = errors.New("invalid price override")
+)
+
+func (s *Service) ListOverrides(ctx context.Context) ([]Override, error) {
+	if s.overrides == nil {
+		return nil, ErrOverridesDisabled
+	}
+	return s.overrides.List(ctx)
+}
+
+// SetOverride creates or replaces the override for o.Asset
+func (s *Service) SetOverride(ctx context.Context, o Override) (Override, error) {
+	if s.overrides == nil {
+		return Override{}, ErrOverridesDisabled
+	}
+
+	o.Asset.ContractAddress = strings.ToLower(o.Asset.ContractAddress)
+	o.UpdatedAt = time.Now().UTC()
+
+	if o.Asset.Chain == "" || o.PriceUSD < 0 {
+		return Override{}, ErrInvalidOverride
+	}
+	if o.ExpiresAt != nil && !o.ExpiresAt.After(o.UpdatedAt) {
+		return Override{}, ErrInvalidOverride
+	}
+
+	s.logger.Info("set-price-override",
+		zap.String("chain", o.Asset.Chain),
+		zap.String("contract", o.Asset.ContractAddress),
+		zap.Float64("price_usd", o.PriceUSD),
+	)
+
+	if err := s.overrides.Save(ctx, o); err != nil {
+		return Override{}, err
+	}
+	return o, nil
+}
+
+func (s *Service) DeleteOverride(ctx context.Context, asset AssetRef) error {
+	if s.overrides == nil {
+		return ErrOverridesDisabled
+	}
+
+	asset.ContractAddress = strings.ToLower(asset.ContractAddress)
+
+	s.logger.Info("delete-price-override",
+		zap.String("chain", asset.Chain),
+		zap.String("contract", asset.ContractAddress),
+	)
+
+	return s.overrides.Delete(ctx, asset)
+}
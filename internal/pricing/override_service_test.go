@@ -0,0 +1,108 @@
+package pricing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPricingService_OverrideWinsOverCacheAndProviders(t *testing.T) {
+	cache := newFakeCache()
+	asset := AssetRef{Chain: "ethereum", ContractAddress: "0xvest"}
+	cache.Set(context.Background(), cacheKey(asset), "1.0", time.Minute)
+
+	primary := &fakeProvider{name: "primary", prices: map[AssetRef]float64{}}
+	overrides := NewMemoryOverrideRepository()
+
+	svc := NewService(cache, primary, nil, time.Minute, zap.NewNop(), WithOverrides(overrides))
+
+	_, err := svc.SetOverride(context.Background(), Override{
+		Asset:    AssetRef{Chain: "ethereum", ContractAddress: "0xVEST"},
+		PriceUSD: 0.25,
+		Note:     "seed round price",
+	})
+	require.NoError(t, err)
+
+	prices, err := svc.GetPrices(context.Background(), []AssetRef{asset})
+	require.NoError(t, err)
+	require.Equal(t, 0.25, prices[asset])
+
+	market, err := svc.GetMarketData(context.Background(), []AssetRef{asset})
+	require.NoError(t, err)
+	require.Equal(t, 0.25, market[asset].PriceUSD)
+	require.Equal(t, PriceSourceOverride, market[asset].Source)
+	require.Equal(t, "seed round price", market[asset].Note)
+
+	require.Equal(t, 0, primary.calls)
+}
+
+// lookupOnlyOverrides fails the test if pricing loads the whole override table
+type lookupOnlyOverrides struct {
+	OverrideRepository
+	t *testing.T
+}
+
+func (r lookupOnlyOverrides) List(ctx context.Context) ([]Override, error) {
+	r.t.Fatal("pricing listed every override")
+	return nil, nil
+}
+
+func TestPricingService_OverridesLookedUpByAsset(t *testing.T) {
+	asset := AssetRef{Chain: "ethereum", ContractAddress: "0xvest"}
+	other := AssetRef{Chain: "ethereum", ContractAddress: "0xother"}
+
+	overrides := NewMemoryOverrideRepository()
+	require.NoError(t, overrides.Save(context.Background(), Override{Asset: asset, PriceUSD: 0.25}))
+	require.NoError(t, overrides.Save(context.Background(), Override{Asset: other, PriceUSD: 9}))
+
+	primary := &fakeProvider{name: "primary", prices: map[AssetRef]float64{}}
+	svc := NewService(newFakeCache(), primary, nil, time.Minute, zap.NewNop(),
+		WithOverrides(lookupOnlyOverrides{OverrideRepository: overrides, t: t}))
+
+	prices, err := svc.GetPrices(context.Background(), []AssetRef{asset})
+	require.NoError(t, err)
+	require.Equal(t, map[AssetRef]float64{asset: 0.25}, prices)
+}
+
+func TestPricingService_ExpiredOverrideIgnored(t *testing.T) {
+	cache := newFakeCache()
+	asset := AssetRef{Chain: "ethereum", ContractAddress: "0xvest"}
+
+	primary := &fakeProvider{name: "primary", prices: map[AssetRef]float64{asset: 3.0}}
+	overrides := NewMemoryOverrideRepository()
+	expired := time.Now().Add(-time.Hour)
+	require.NoError(t, overrides.Save(context.Background(), Override{
+		Asset:     asset,
+		PriceUSD:  0.25,
+		ExpiresAt: &expired,
+	}))
+
+	svc := NewService(cache, primary, nil, time.Minute, zap.NewNop(), WithOverrides(overrides))
+
+	market, err := svc.GetMarketData(context.Background(), []AssetRef{asset})
+	require.NoError(t, err)
+	require.Equal(t, 3.0, market[asset].PriceUSD)
+	require.Equal(t, PriceSourceMarket, market[asset].Source)
+}
+
+func TestPricingService_SetOverrideValidation(t *testing.T) {
+	svc := NewService(newFakeCache(), nil, nil, time.Minute, zap.NewNop(), WithOverrides(NewMemoryOverrideRepository()))
+
+	past := time.Now().Add(-time.Minute)
+
+	_, err := svc.SetOverride(context.Background(), Override{PriceUSD: 1})
+	require.ErrorIs(t, err, ErrInvalidOverride)
+
+	_, err = svc.SetOverride(context.Background(), Override{
+		Asset:     AssetRef{Chain: "ethereum", ContractAddress: "0xabc"},
+		PriceUSD:  1,
+		ExpiresAt: &past,
+	})
+	require.ErrorIs(t, err, ErrInvalidOverride)
+
+	err = svc.DeleteOverride(context.Background(), AssetRef{Chain: "ethereum", ContractAddress: "0xabc"})
+	require.ErrorIs(t, err, ErrOverrideNotFound)
+}
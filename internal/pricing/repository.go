@@ -1,13 +1,87 @@
 package pricing
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type AssetRef struct {
-	Chain           string // e.g. "ethereum", "polygon"
-	ContractAddress string // lowercase hex
+	Chain           string `json:"chain"`            // e.g. "ethereum", "polygon"
+	ContractAddress string `json:"contract_address"` // lowercase hex
 }
 
 type PriceProvider interface {
 	GetPrices(ctx context.Context, assets []AssetRef) (map[AssetRef]float64, error)
 	Name() string
 }
+
+// PriceSource tells where a price came from
+type PriceSource string
+
+const (
+	PriceSourceMarket   PriceSource = "market"   // a price provider, possibly via cache
+	PriceSourceOverride PriceSource = "override" // a manual admin override
+)
+
+// MarketData is a USD price enriched with 24h market context
+type MarketData struct {
+	PriceUSD     float64     `json:"price_usd"`
+	Change24hPct float64     `json:"change_24h_pct"`
+	Volume24hUSD float64     `json:"volume_24h_usd"`
+	MarketCapUSD float64     `json:"market_cap_usd"`
+	LastUpdated  time.Time   `json:"last_updated"`
+	Source       PriceSource `json:"source,omitempty"`
+	Note         string      `json:"note,omitempty"`
+	Derivation   *Derivation `json:"derivation,omitempty"` // set when Source is derived
+}
+
+// MarketDataProvider is implemented by providers that can return market
+// context alongside the price. Providers without it only contribute PriceUSD.
+type MarketDataProvider interface {
+	GetMarketData(ctx context.Context, assets []AssetRef) (map[AssetRef]MarketData, error)
+}
+
+// Candle is a single OHLC bar opening at Time
+type Candle struct {
+	Time  time.Time `json:"time"`
+	Open  float64   `json:"open"`
+	High  float64   `json:"high"`
+	Low   float64   `json:"low"`
+	Close float64   `json:"close"`
+}
+
+// Interval is the width of a candle
+type Interval string
+
+const (
+	Interval1h Interval = "1h"
+	Interval4h Interval = "4h"
+	Interval1d Interval = "1d"
+)
+
+func (i Interval) Duration() time.Duration {
+	switch i {
+	case Interval1h:
+		return time.Hour
+	case Interval4h:
+		return 4 * time.Hour
+	case Interval1d:
+		return 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// CandleProvider is implemented by providers that can supply OHLC candles
+// covering the last `days` days
+type CandleProvider interface {
+	GetCandles(ctx context.Context, asset AssetRef, interval Interval, days int) ([]Candle, error)
+}
+
+// FloorPriceProvider values NFT collections at their floor price, the
+// lowest price any of their tokens is listed at. Collections without a
+// floor are left out of the result.
+type FloorPriceProvider interface {
+	GetFloorPrices(ctx context.Context, collections []AssetRef) (map[AssetRef]float64, error)
+	Name() string
+}
@@ -0,0 +1,160 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+var (
+	ErrInvalidRule = errors.New("invalid price rule")
+	ErrRuleCycle   = errors.New("price rule cycle")
+)
+
+const PriceSourceDerived PriceSource = "derived"
+
+type RuleKind string
+
+const (
+	RulePeg          RuleKind = "peg"           // fixed USD price, e.g. bridged USDC
+	RuleMultiple     RuleKind = "multiple"      // Factor x underlying, e.g. WBTC on another chain
+	RuleExchangeRate RuleKind = "exchange_rate" // on-chain rate x underlying, e.g. wstETH, rETH
+)
+
+// Rule derives an asset's price from a fixed peg or from another asset
+type Rule struct {
+	Asset      AssetRef  `json:"asset"`
+	Kind       RuleKind  `json:"kind"`
+	PriceUSD   float64   `json:"price_usd,omitempty"`  // peg
+	Underlying *AssetRef `json:"underlying,omitempty"` // multiple, exchange_rate
+	Factor     float64   `json:"factor,omitempty"`     // multiple
+
+	// exchange_rate: a no-argument view method returning a uint256 rate,
+	// read from RateContract (defaults to the asset's own contract)
+	RateMethod   string `json:"rate_method,omitempty"` // 4-byte selector, e.g. 0x035faf82 for stEthPerToken()
+	RateDecimals int    `json:"rate_decimals,omitempty"`
+	RateContract string `json:"rate_contract,omitempty"`
+}
+
+// Derivation is the provenance attached to a derived price
+type Derivation struct {
+	Kind               RuleKind    `json:"kind"`
+	Underlying         *AssetRef   `json:"underlying,omitempty"`
+	Factor             float64     `json:"factor,omitempty"` // multiplier or exchange rate applied
+	UnderlyingPriceUSD float64     `json:"underlying_price_usd,omitempty"`
+	UnderlyingSource   PriceSource `json:"underlying_source,omitempty"`
+}
+
+// RateReader reads exchange rates from wrapper contracts
+type RateReader interface {
+	ExchangeRate(ctx context.Context, chain, contract, selector string, decimals int) (float64, error)
+}
+
+// RuleSet is a validated, cycle-free set of rules keyed by asset
+type RuleSet struct {
+	rules map[AssetRef]Rule
+}
+
+func NewRuleSet(rules []Rule) (*RuleSet, error) {
+	set := &RuleSet{rules: make(map[AssetRef]Rule, len(rules))}
+
+	for _, r := range rules {
+		r.Asset.ContractAddress = strings.ToLower(r.Asset.ContractAddress)
+		if r.Underlying != nil {
+			u := *r.Underlying
+			u.ContractAddress = strings.ToLower(u.ContractAddress)
+			r.Underlying = &u
+		}
+		r.RateContract = strings.ToLower(r.RateContract)
+
+		if err := validateRule(r); err != nil {
+			return nil, err
+		}
+		if _, dup := set.rules[r.Asset]; dup {
+			return nil, fmt.Errorf("%w: duplicate rule for %s", ErrInvalidRule, r.Asset)
+		}
+		set.rules[r.Asset] = r
+	}
+
+	for asset := range set.rules {
+		if err := set.checkCycle(asset, map[AssetRef]bool{}); err != nil {
+			return nil, err
+		}
+	}
+
+	return set, nil
+}
+
+// LoadRules reads a JSON array of rules from path
+func LoadRules(path string) ([]Rule, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []Rule
+	if err := json.Unmarshal(raw, &rules); err != nil {
+		return nil, fmt.Errorf("parse price rules: %w", err)
+	}
+	return rules, nil
+}
+
+func (s *RuleSet) Get(asset AssetRef) (Rule, bool) {
+	if s == nil {
+		return Rule{}, false
+	}
+	r, ok := s.rules[asset]
+	return r, ok
+}
+
+func (s *RuleSet) checkCycle(asset AssetRef, visiting map[AssetRef]bool) error {
+	if visiting[asset] {
+		return fmt.Errorf("%w through %s", ErrRuleCycle, asset)
+	}
+
+	r, ok := s.rules[asset]
+	if !ok || r.Underlying == nil {
+		return nil
+	}
+
+	visiting[asset] = true
+	defer delete(visiting, asset)
+
+	return s.checkCycle(*r.Underlying, visiting)
+}
+
+func validateRule(r Rule) error {
+	if r.Asset.Chain == "" {
+		return fmt.Errorf("%w: asset chain is required", ErrInvalidRule)
+	}
+
+	switch r.Kind {
+	case RulePeg:
+		if r.PriceUSD <= 0 {
+			return fmt.Errorf("%w: peg for %s needs price_usd", ErrInvalidRule, r.Asset)
+		}
+	case RuleMultiple:
+		if r.Underlying == nil || r.Factor <= 0 {
+			return fmt.Errorf("%w: multiple for %s needs underlying and factor", ErrInvalidRule, r.Asset)
+		}
+	case RuleExchangeRate:
+		if r.Underlying == nil || r.RateMethod == "" {
+			return fmt.Errorf("%w: exchange_rate for %s needs underlying and rate_method", ErrInvalidRule, r.Asset)
+		}
+	default:
+		return fmt.Errorf("%w: unknown kind %q", ErrInvalidRule, r.Kind)
+	}
+
+	if r.Underlying != nil && *r.Underlying == r.Asset {
+		return fmt.Errorf("%w: %s derives from itself", ErrRuleCycle, r.Asset)
+	}
+
+	return nil
+}
+
+func (a AssetRef) String() string {
+	return a.Chain + ":" + a.ContractAddress
+}
@@ -0,0 +1,113 @@
+package pricing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeRateReader struct {
+	rate  float64
+	calls int
+}
+
+func (f *fakeRateReader) ExchangeRate(ctx context.Context, chain, contract, selector string, decimals int) (float64, error) {
+	f.calls++
+	return f.rate, nil
+}
+
+var (
+	btc     = AssetRef{Chain: "ethereum", ContractAddress: "0xwbtc"}
+	arbBTC  = AssetRef{Chain: "arbitrum-one", ContractAddress: "0xwbtc"}
+	steth   = AssetRef{Chain: "ethereum", ContractAddress: "0xsteth"}
+	wsteth  = AssetRef{Chain: "ethereum", ContractAddress: "0xwsteth"}
+	polyUSD = AssetRef{Chain: "polygon-pos", ContractAddress: "0xusdc"}
+)
+
+func TestNewRuleSet_DetectsCycles(t *testing.T) {
+	_, err := NewRuleSet([]Rule{
+		{Asset: arbBTC, Kind: RuleMultiple, Underlying: &btc, Factor: 1},
+		{Asset: btc, Kind: RuleMultiple, Underlying: &arbBTC, Factor: 1},
+	})
+	require.ErrorIs(t, err, ErrRuleCycle)
+
+	_, err = NewRuleSet([]Rule{
+		{Asset: btc, Kind: RuleMultiple, Underlying: &btc, Factor: 1},
+	})
+	require.ErrorIs(t, err, ErrRuleCycle)
+}
+
+func TestNewRuleSet_Validates(t *testing.T) {
+	_, err := NewRuleSet([]Rule{{Asset: polyUSD, Kind: RulePeg}})
+	require.ErrorIs(t, err, ErrInvalidRule)
+
+	_, err = NewRuleSet([]Rule{{Asset: arbBTC, Kind: RuleMultiple, Factor: 1}})
+	require.ErrorIs(t, err, ErrInvalidRule)
+
+	_, err = NewRuleSet([]Rule{{Asset: wsteth, Kind: RuleExchangeRate, Underlying: &steth}})
+	require.ErrorIs(t, err, ErrInvalidRule)
+
+	_, err = NewRuleSet([]Rule{{Asset: polyUSD, Kind: "oracle"}})
+	require.ErrorIs(t, err, ErrInvalidRule)
+}
+
+func TestPricingService_DerivedPrices(t *testing.T) {
+	rules, err := NewRuleSet([]Rule{
+		{Asset: polyUSD, Kind: RulePeg, PriceUSD: 1},
+		{Asset: arbBTC, Kind: RuleMultiple, Underlying: &btc, Factor: 1},
+		{Asset: wsteth, Kind: RuleExchangeRate, Underlying: &steth, RateMethod: "0x035faf82"},
+	})
+	require.NoError(t, err)
+
+	primary := &fakeMarketProvider{
+		fakeProvider: fakeProvider{name: "primary"},
+		market: map[AssetRef]MarketData{
+			btc:   {PriceUSD: 60_000, Change24hPct: 2},
+			steth: {PriceUSD: 3_000},
+		},
+	}
+	rates := &fakeRateReader{rate: 1.2}
+
+	svc := NewService(newFakeCache(), primary, nil, time.Minute, zap.NewNop(), WithRules(rules, rates))
+
+	data, err := svc.GetMarketData(context.Background(), []AssetRef{polyUSD, arbBTC, wsteth})
+	require.NoError(t, err)
+
+	require.Equal(t, 1.0, data[polyUSD].PriceUSD)
+	require.Equal(t, RulePeg, data[polyUSD].Derivation.Kind)
+
+	require.Equal(t, 60_000.0, data[arbBTC].PriceUSD)
+	require.Equal(t, 2.0, data[arbBTC].Change24hPct)
+	require.Equal(t, PriceSourceDerived, data[arbBTC].Source)
+	require.Equal(t, btc, *data[arbBTC].Derivation.Underlying)
+	require.Equal(t, PriceSourceMarket, data[arbBTC].Derivation.UnderlyingSource)
+
+	require.InDelta(t, 3_600.0, data[wsteth].PriceUSD, 1e-9)
+	require.Equal(t, 1.2, data[wsteth].Derivation.Factor)
+
+	// the exchange rate is cached like a price
+	prices, err := svc.GetPrices(context.Background(), []AssetRef{wsteth})
+	require.NoError(t, err)
+	require.InDelta(t, 3_600.0, prices[wsteth], 1e-9)
+	require.Equal(t, 1, rates.calls)
+}
+
+func TestPricingService_OverrideBeatsRule(t *testing.T) {
+	rules, err := NewRuleSet([]Rule{{Asset: polyUSD, Kind: RulePeg, PriceUSD: 1}})
+	require.NoError(t, err)
+
+	overrides := NewMemoryOverrideRepository()
+	require.NoError(t, overrides.Save(context.Background(), Override{Asset: polyUSD, PriceUSD: 0.97}))
+
+	svc := NewService(newFakeCache(), &fakeProvider{name: "primary"}, nil, time.Minute, zap.NewNop(),
+		WithOverrides(overrides),
+		WithRules(rules, nil),
+	)
+
+	prices, err := svc.GetPrices(context.Background(), []AssetRef{polyUSD})
+	require.NoError(t, err)
+	require.Equal(t, 0.97, prices[polyUSD])
+}
@@ -2,6 +2,7 @@ package pricing
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"time"
@@ -16,14 +17,54 @@ type ServiceAPI interface {
 		ctx context.Context,
 		assets []AssetRef,
 	) (map[AssetRef]float64, error)
+
+	GetMarketData(
+		ctx context.Context,
+		assets []AssetRef,
+	) (map[AssetRef]MarketData, error)
+
+	GetCandles(
+		ctx context.Context,
+		asset AssetRef,
+		interval Interval,
+		days int,
+	) ([]Candle, error)
+
+	GetHistoricalPrices(
+		ctx context.Context,
+		assets []AssetRef,
+		at time.Time,
+	) (map[AssetRef]HistoricalPrice, error)
 }
 
 type Service struct {
-	cache    cache.CacheManager
-	primary  PriceProvider
-	fallback PriceProvider
-	cacheTTL time.Duration
-	logger   *zap.Logger
+	cache     cache.CacheManager
+	primary   PriceProvider
+	fallback  PriceProvider
+	cacheTTL  time.Duration
+	logger    *zap.Logger
+	overrides OverrideRepository
+	rules     *RuleSet
+	rates     RateReader
+}
+
+// Option configures optional pricing sources on the Service
+type Option func(*Service)
+
+// WithOverrides makes the service consult manual price overrides before the cache and providers
+func WithOverrides(repo OverrideRepository) Option {
+	return func(s *Service) {
+		s.overrides = repo
+	}
+}
+
+// WithRules derives prices of wrapped and pegged assets from their
+// underlying. rates is only needed by exchange_rate rules and may be nil.
+func WithRules(rules *RuleSet, rates RateReader) Option {
+	return func(s *Service) {
+		s.rules = rules
+		s.rates = rates
+	}
 }
 
 func NewService(
@@ -32,14 +73,19 @@ func NewService(
 	fallback PriceProvider,
 	cacheTTL time.Duration,
 	logger *zap.Logger,
+	opts ...Option,
 ) *Service {
-	return &Service{
+	s := &Service{
 		cache:    cache,
 		primary:  primary,
 		fallback: fallback,
 		cacheTTL: cacheTTL,
 		logger:   logger,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *Service) GetPrices(
@@ -51,8 +97,17 @@ func (s *Service) GetPrices(
 	results := make(map[AssetRef]float64)
 	missing := make([]AssetRef, 0)
 
-	// Cache lookup first
+	// Manual overrides and derivation rules win over everything else
+	manual := s.resolveManual(ctx, assets, nil)
+	for a, md := range manual {
+		results[a] = md.PriceUSD
+	}
+
+	// Cache lookup next
 	for _, a := range assets {
+		if _, ok := manual[a]; ok {
+			continue
+		}
 		key := cacheKey(a)
 
 		cachedStr, err := s.cache.Get(ctx, key)
@@ -97,6 +152,165 @@ func (s *Service) GetPrices(
 	return results, nil
 }
 
+// GetMarketData returns prices together with 24h change, volume, market cap
+// and last-updated time. It follows the same cache-aside and fallback flow as GetPrices.
+func (s *Service) GetMarketData(
+	ctx context.Context,
+	assets []AssetRef,
+) (map[AssetRef]MarketData, error) {
+	s.logger.Info("get-market-data")
+
+	return s.marketData(ctx, assets, nil)
+}
+
+// marketData is GetMarketData with the chain of assets currently being
+// derived, so rules that price an underlying can detect cycles
+func (s *Service) marketData(
+	ctx context.Context,
+	assets []AssetRef,
+	deriving map[AssetRef]bool,
+) (map[AssetRef]MarketData, error) {
+	results := s.resolveManual(ctx, assets, deriving)
+	missing := make([]AssetRef, 0)
+
+	for _, a := range assets {
+		if _, ok := results[a]; ok {
+			continue
+		}
+		cachedStr, err := s.cache.Get(ctx, marketCacheKey(a))
+		if err == nil {
+			var md MarketData
+			if err := json.Unmarshal([]byte(cachedStr), &md); err == nil {
+				results[a] = md
+				continue
+			}
+		}
+		missing = append(missing, a)
+	}
+
+	if len(missing) == 0 {
+		return results, nil
+	}
+
+	data, err := fetchMarketData(ctx, s.primary, missing)
+	if err != nil {
+		s.logger.Warn("primary-market-data-failed",
+			zap.String("provider", s.primary.Name()),
+			zap.Error(err),
+		)
+
+		data, err = fetchMarketData(ctx, s.fallback, missing)
+		if err != nil {
+			return nil, fmt.Errorf("market data failed: %w", err)
+		}
+	}
+
+	for asset, md := range data {
+		md.Source = PriceSourceMarket
+		if encoded, err := json.Marshal(md); err == nil {
+			_ = s.cache.Set(ctx, marketCacheKey(asset), string(encoded), s.cacheTTL)
+		}
+		priceStr := strconv.FormatFloat(md.PriceUSD, 'f', -1, 64)
+		_ = s.cache.Set(ctx, cacheKey(asset), priceStr, s.cacheTTL)
+		results[asset] = md
+	}
+
+	return results, nil
+}
+
+// resolveManual prices the assets that have an active override or a
+// derivation rule. Assets it cannot resolve are left for the cache and providers.
+func (s *Service) resolveManual(
+	ctx context.Context,
+	assets []AssetRef,
+	deriving map[AssetRef]bool,
+) map[AssetRef]MarketData {
+	out := make(map[AssetRef]MarketData)
+
+	overrides := s.activeOverrides(ctx, assets)
+	for a, o := range overrides {
+		out[a] = MarketData{
+			PriceUSD:    o.PriceUSD,
+			LastUpdated: o.UpdatedAt,
+			Source:      PriceSourceOverride,
+			Note:        o.Note,
+		}
+	}
+
+	for _, a := range assets {
+		if _, ok := out[a]; ok {
+			continue
+		}
+		rule, ok := s.rules.Get(a)
+		if !ok {
+			continue
+		}
+
+		md, err := s.derive(ctx, rule, deriving)
+		if err != nil {
+			s.logger.Warn("price-derivation-failed",
+				zap.String("asset", a.String()),
+				zap.String("rule", string(rule.Kind)),
+				zap.Error(err),
+			)
+			continue
+		}
+		out[a] = md
+	}
+
+	return out
+}
+
+// activeOverrides returns the unexpired overrides for the requested assets.
+// A failing override store is logged and skipped so pricing stays available.
+func (s *Service) activeOverrides(ctx context.Context, assets []AssetRef) map[AssetRef]Override {
+	out := make(map[AssetRef]Override)
+	if s.overrides == nil || len(assets) == 0 {
+		return out
+	}
+
+	found, err := s.overrides.Find(ctx, assets)
+	if err != nil {
+		s.logger.Warn("price-overrides-unavailable", zap.Error(err))
+		return out
+	}
+
+	now := time.Now()
+	for _, o := range found {
+		if o.Active(now) {
+			out[o.Asset] = o
+		}
+	}
+	return out
+}
+
+// fetchMarketData uses the provider's market data support when it has one,
+// and degrades to a bare price otherwise
+func fetchMarketData(
+	ctx context.Context,
+	provider PriceProvider,
+	assets []AssetRef,
+) (map[AssetRef]MarketData, error) {
+	if mp, ok := provider.(MarketDataProvider); ok {
+		return mp.GetMarketData(ctx, assets)
+	}
+
+	prices, err := provider.GetPrices(ctx, assets)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[AssetRef]MarketData, len(prices))
+	for asset, price := range prices {
+		out[asset] = MarketData{PriceUSD: price}
+	}
+	return out, nil
+}
+
 func cacheKey(a AssetRef) string {
 	return fmt.Sprintf("price:%s:%s", a.Chain, a.ContractAddress)
 }
+
+func marketCacheKey(a AssetRef) string {
+	return fmt.Sprintf("market:%s:%s", a.Chain, a.ContractAddress)
+}
@@ -158,3 +158,66 @@ func TestPricingService_AllProvidersFail(t *testing.T) {
 
 	require.Error(t, err)
 }
+
+type fakeMarketProvider struct {
+	fakeProvider
+	market map[AssetRef]MarketData
+}
+
+func (f *fakeMarketProvider) GetMarketData(
+	ctx context.Context,
+	assets []AssetRef,
+) (map[AssetRef]MarketData, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.market, nil
+}
+
+func TestPricingService_MarketData_PrimarySuccess(t *testing.T) {
+	cache := newFakeCache()
+	asset := AssetRef{Chain: "ethereum", ContractAddress: "0xabc"}
+
+	primary := &fakeMarketProvider{
+		fakeProvider: fakeProvider{name: "primary"},
+		market: map[AssetRef]MarketData{
+			asset: {PriceUSD: 42.0, Change24hPct: 5, MarketCapUSD: 1000},
+		},
+	}
+
+	svc := NewService(cache, primary, nil, time.Minute, zap.NewNop())
+
+	data, err := svc.GetMarketData(context.Background(), []AssetRef{asset})
+	require.NoError(t, err)
+	require.Equal(t, 5.0, data[asset].Change24hPct)
+
+	// second call is served from cache, and the plain price is cached too
+	data, err = svc.GetMarketData(context.Background(), []AssetRef{asset})
+	require.NoError(t, err)
+	require.Equal(t, 1000.0, data[asset].MarketCapUSD)
+	require.Equal(t, 1, primary.calls)
+
+	prices, err := svc.GetPrices(context.Background(), []AssetRef{asset})
+	require.NoError(t, err)
+	require.Equal(t, 42.0, prices[asset])
+	require.Equal(t, 1, primary.calls)
+}
+
+func TestPricingService_MarketData_PriceOnlyFallback(t *testing.T) {
+	cache := newFakeCache()
+	asset := AssetRef{Chain: "ethereum", ContractAddress: "0xabc"}
+
+	primary := &fakeProvider{name: "primary", err: errors.New("down")}
+	fallback := &fakeProvider{
+		name:   "fallback",
+		prices: map[AssetRef]float64{asset: 99.0},
+	}
+
+	svc := NewService(cache, primary, fallback, time.Minute, zap.NewNop())
+
+	data, err := svc.GetMarketData(context.Background(), []AssetRef{asset})
+
+	require.NoError(t, err)
+	require.Equal(t, MarketData{PriceUSD: 99.0, Source: PriceSourceMarket}, data[asset])
+}
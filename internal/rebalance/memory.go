@@ -0,0 +1,45 @@
+package rebalance
+
+import (
+	"context"
+	"sync"
+)
+
+type memoryTargetRepository struct {
+	mu   sync.RWMutex
+	data map[string]Target
+}
+
+func NewMemoryTargetRepository() TargetRepository {
+	return &memoryTargetRepository{data: make(map[string]Target)}
+}
+
+func (r *memoryTargetRepository) Get(ctx context.Context, wallet string) (*Target, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.data[wallet]
+	if !ok {
+		return nil, ErrTargetNotFound
+	}
+	return &t, nil
+}
+
+func (r *memoryTargetRepository) Save(ctx context.Context, t Target) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.data[t.Wallet] = t
+	return nil
+}
+
+func (r *memoryTargetRepository) Delete(ctx context.Context, wallet string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.data[wallet]; !ok {
+		return ErrTargetNotFound
+	}
+	delete(r.data, wallet)
+	return nil
+}
@@ -0,0 +1,121 @@
+package rebalance
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+var (
+	ErrInvalidTarget  = errors.New("invalid target allocation")
+	ErrTargetNotFound = errors.New("target allocation not found")
+	ErrNoCashAsset    = errors.New("cash-only plans need a cash asset on the target")
+)
+
+// weightSumTolerance absorbs rounding in weights that should add up to 100
+const weightSumTolerance = 0.01
+
+// TargetWeight is the share of the portfolio value an asset should have
+type TargetWeight struct {
+	Asset     pricing.AssetRef `json:"asset"`
+	WeightPct float64          `json:"weight_pct"`
+}
+
+// Target is the allocation a wallet is managed against
+type Target struct {
+	Wallet       string            `json:"wallet"`
+	Weights      []TargetWeight    `json:"weights"`
+	TolerancePct float64           `json:"tolerance_pct"`        // drift in percentage points left alone
+	CashAsset    *pricing.AssetRef `json:"cash_asset,omitempty"` // funds buys in cash-only plans
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+func (t *Target) normalize() error {
+	if t.TolerancePct < 0 || t.TolerancePct >= 100 {
+		return fmt.Errorf("%w: tolerance must be between 0 and 100", ErrInvalidTarget)
+	}
+	if len(t.Weights) == 0 {
+		return fmt.Errorf("%w: at least one weight is required", ErrInvalidTarget)
+	}
+
+	seen := make(map[pricing.AssetRef]bool, len(t.Weights))
+	var sum float64
+	for i, w := range t.Weights {
+		if w.Asset.Chain == "" {
+			return fmt.Errorf("%w: weight %d has no chain", ErrInvalidTarget, i)
+		}
+		if w.WeightPct < 0 {
+			return fmt.Errorf("%w: weight %d is negative", ErrInvalidTarget, i)
+		}
+		w.Asset.ContractAddress = strings.ToLower(w.Asset.ContractAddress)
+		if seen[w.Asset] {
+			return fmt.Errorf("%w: %s is listed twice", ErrInvalidTarget, w.Asset)
+		}
+		seen[w.Asset] = true
+		t.Weights[i] = w
+		sum += w.WeightPct
+	}
+	if math.Abs(sum-100) > weightSumTolerance {
+		return fmt.Errorf("%w: weights add up to %.4g, not 100", ErrInvalidTarget, sum)
+	}
+
+	if t.CashAsset != nil {
+		if t.CashAsset.Chain == "" {
+			return fmt.Errorf("%w: cash asset has no chain", ErrInvalidTarget)
+		}
+		t.CashAsset.ContractAddress = strings.ToLower(t.CashAsset.ContractAddress)
+	}
+	return nil
+}
+
+// Options tune how a plan is built
+type Options struct {
+	MinTradeUSD float64 // smaller trades are left out
+	CashOnly    bool    // only buy, funded by the cash asset; nothing else is sold
+}
+
+type Action string
+
+const (
+	ActionBuy  Action = "buy"
+	ActionSell Action = "sell"
+	ActionHold Action = "hold"
+)
+
+// Line is what the plan does with one asset
+type Line struct {
+	Asset            pricing.AssetRef `json:"asset"`
+	Action           Action           `json:"action"`
+	Quantity         float64          `json:"quantity"`   // units to buy or sell, 0 for hold
+	AmountUSD        float64          `json:"amount_usd"` // value to buy or sell, 0 for hold
+	PriceUSD         float64          `json:"price_usd"`
+	CurrentValueUSD  float64          `json:"current_value_usd"`
+	CurrentWeightPct float64          `json:"current_weight_pct"`
+	TargetWeightPct  float64          `json:"target_weight_pct"`
+	PostWeightPct    float64          `json:"post_weight_pct"`  // weight once the plan is executed
+	Reason           string           `json:"reason,omitempty"` // why a drifted asset is held
+}
+
+// Plan is the set of trades that brings a wallet back to its target
+type Plan struct {
+	Wallet        string             `json:"wallet"`
+	TotalValueUSD float64            `json:"total_value_usd"`
+	TolerancePct  float64            `json:"tolerance_pct"`
+	MinTradeUSD   float64            `json:"min_trade_usd"`
+	CashOnly      bool               `json:"cash_only"`
+	Lines         []Line             `json:"lines"`
+	TotalBuyUSD   float64            `json:"total_buy_usd"`
+	TotalSellUSD  float64            `json:"total_sell_usd"`
+	Unpriced      []pricing.AssetRef `json:"unpriced,omitempty"` // targets that could not be priced and are left out
+}
+
+const (
+	ReasonWithinTolerance = "within_tolerance"
+	ReasonBelowMinTrade   = "below_min_trade"
+	ReasonCashOnly        = "cash_only"
+	ReasonNoCash          = "no_cash"
+)
@@ -0,0 +1,201 @@
+package rebalance
+
+import (
+	"math"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+// position is an asset's current state in the portfolio
+type position struct {
+	asset     pricing.AssetRef
+	value     float64
+	price     float64
+	targetPct float64
+}
+
+// BuildPlan compares a valued portfolio with its target. Assets drifting
+// from their target weight by more than the tolerance are traded back to it;
+// assets held but missing from the target have a target of zero. prices
+// covers targeted assets the wallet does not hold yet. Trades are sized at
+// current prices and assume the portfolio value does not change.
+func BuildPlan(view *portfolio.PortfolioView, target Target, prices map[pricing.AssetRef]float64, opts Options) (*Plan, error) {
+	if opts.CashOnly && target.CashAsset == nil {
+		return nil, ErrNoCashAsset
+	}
+
+	total := view.TotalValueUSD
+	plan := &Plan{
+		Wallet:        view.Wallet,
+		TotalValueUSD: total,
+		TolerancePct:  target.TolerancePct,
+		MinTradeUSD:   opts.MinTradeUSD,
+		CashOnly:      opts.CashOnly,
+	}
+
+	positions := positionsOf(view, target, prices)
+
+	var lines []Line
+	for _, p := range positions {
+		if p.price <= 0 {
+			if p.targetPct > 0 {
+				plan.Unpriced = append(plan.Unpriced, p.asset)
+			}
+			continue
+		}
+		lines = append(lines, Line{
+			Asset:            p.asset,
+			Action:           ActionHold,
+			PriceUSD:         p.price,
+			CurrentValueUSD:  p.value,
+			CurrentWeightPct: weightOf(p.value, total),
+			TargetWeightPct:  p.targetPct,
+		})
+	}
+
+	if opts.CashOnly {
+		planCashOnly(lines, *target.CashAsset, total, target.TolerancePct, opts.MinTradeUSD)
+	} else {
+		planFull(lines, total, target.TolerancePct, opts.MinTradeUSD)
+	}
+
+	for i, l := range lines {
+		post := l.CurrentValueUSD
+		switch l.Action {
+		case ActionBuy:
+			post += l.AmountUSD
+			plan.TotalBuyUSD += l.AmountUSD
+		case ActionSell:
+			post -= l.AmountUSD
+			plan.TotalSellUSD += l.AmountUSD
+		}
+		lines[i].PostWeightPct = weightOf(post, total)
+	}
+
+	plan.Lines = lines
+	return plan, nil
+}
+
+// planFull trades every asset outside the band straight back to its target
+func planFull(lines []Line, total, tolerance, minTrade float64) {
+	for i, l := range lines {
+		if math.Abs(l.CurrentWeightPct-l.TargetWeightPct) <= tolerance {
+			lines[i].Reason = ReasonWithinTolerance
+			continue
+		}
+
+		delta := total*l.TargetWeightPct/100 - l.CurrentValueUSD
+		if math.Abs(delta) < minTrade {
+			lines[i].Reason = ReasonBelowMinTrade
+			continue
+		}
+		setTrade(&lines[i], delta)
+	}
+}
+
+// planCashOnly buys underweight assets with the cash asset's value above its
+// own target, sharing it out in proportion to each shortfall when it does
+// not cover them all. Nothing but the cash asset is sold.
+func planCashOnly(lines []Line, cash pricing.AssetRef, total, tolerance, minTrade float64) {
+	cashIdx := -1
+	for i, l := range lines {
+		if l.Asset == cash {
+			cashIdx = i
+		}
+	}
+
+	var available float64
+	if cashIdx >= 0 {
+		c := lines[cashIdx]
+		available = math.Max(0, c.CurrentValueUSD-total*c.TargetWeightPct/100)
+	}
+
+	var shortfall float64
+	for i, l := range lines {
+		if i == cashIdx {
+			continue
+		}
+		drift := l.CurrentWeightPct - l.TargetWeightPct
+		switch {
+		case math.Abs(drift) <= tolerance:
+			lines[i].Reason = ReasonWithinTolerance
+		case drift > 0:
+			lines[i].Reason = ReasonCashOnly
+		default:
+			shortfall += total*l.TargetWeightPct/100 - l.CurrentValueUSD
+		}
+	}
+
+	scale := 1.0
+	if shortfall > available {
+		scale = available / shortfall
+	}
+
+	var spent float64
+	for i, l := range lines {
+		if i == cashIdx || l.Reason != "" {
+			continue
+		}
+		amount := (total*l.TargetWeightPct/100 - l.CurrentValueUSD) * scale
+		switch {
+		case available == 0:
+			lines[i].Reason = ReasonNoCash
+		case amount < minTrade:
+			lines[i].Reason = ReasonBelowMinTrade
+		default:
+			setTrade(&lines[i], amount)
+			spent += amount
+		}
+	}
+
+	if cashIdx >= 0 && spent > 0 {
+		setTrade(&lines[cashIdx], -spent)
+	}
+}
+
+func setTrade(l *Line, deltaUSD float64) {
+	l.Action = ActionBuy
+	if deltaUSD < 0 {
+		l.Action = ActionSell
+	}
+	l.AmountUSD = math.Abs(deltaUSD)
+	l.Quantity = l.AmountUSD / l.PriceUSD
+	l.Reason = ""
+}
+
+// positionsOf lists targeted assets in target order, then held assets
+// without a target
+func positionsOf(view *portfolio.PortfolioView, target Target, prices map[pricing.AssetRef]float64) []position {
+	held := make(map[pricing.AssetRef]portfolio.HoldingView, len(view.Holdings))
+	for _, h := range view.Holdings {
+		held[pricing.AssetRef{Chain: h.Chain, ContractAddress: h.ContractAddress}] = h
+	}
+
+	out := make([]position, 0, len(target.Weights)+len(view.Holdings))
+	targeted := make(map[pricing.AssetRef]bool, len(target.Weights))
+	for _, w := range target.Weights {
+		targeted[w.Asset] = true
+		p := position{asset: w.Asset, targetPct: w.WeightPct, price: prices[w.Asset]}
+		if h, ok := held[w.Asset]; ok {
+			p.value, p.price = h.ValueUSD, h.PriceUSD
+		}
+		out = append(out, p)
+	}
+
+	for _, h := range view.Holdings {
+		ref := pricing.AssetRef{Chain: h.Chain, ContractAddress: h.ContractAddress}
+		if targeted[ref] {
+			continue
+		}
+		out = append(out, position{asset: ref, value: h.ValueUSD, price: h.PriceUSD})
+	}
+	return out
+}
+
+func weightOf(value, total float64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return value / total * 100
+}
@@ -0,0 +1,89 @@
+package rebalance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresTargetRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresTargetRepository(pool *pgxpool.Pool) TargetRepository {
+	return &postgresTargetRepository{pool: pool}
+}
+
+func (r *postgresTargetRepository) Get(ctx context.Context, wallet string) (*Target, error) {
+	var (
+		t       Target
+		weights []byte
+		cash    []byte
+	)
+	err := r.pool.QueryRow(ctx, `
+		SELECT wallet, weights, tolerance_pct, cash_asset, updated_at
+		FROM portfolio_targets
+		WHERE wallet = $1`,
+		wallet,
+	).Scan(&t.Wallet, &weights, &t.TolerancePct, &cash, &t.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrTargetNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(weights, &t.Weights); err != nil {
+		return nil, err
+	}
+	if cash != nil {
+		if err := json.Unmarshal(cash, &t.CashAsset); err != nil {
+			return nil, err
+		}
+	}
+	return &t, nil
+}
+
+func (r *postgresTargetRepository) Save(ctx context.Context, t Target) error {
+	weights, err := json.Marshal(t.Weights)
+	if err != nil {
+		return err
+	}
+
+	var cash []byte
+	if t.CashAsset != nil {
+		if cash, err = json.Marshal(t.CashAsset); err != nil {
+			return err
+		}
+	}
+
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO portfolio_targets (wallet, weights, tolerance_pct, cash_asset, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (wallet) DO UPDATE SET
+			weights       = EXCLUDED.weights,
+			tolerance_pct = EXCLUDED.tolerance_pct,
+			cash_asset    = EXCLUDED.cash_asset,
+			updated_at    = EXCLUDED.updated_at`,
+		t.Wallet,
+		weights,
+		t.TolerancePct,
+		cash,
+		t.UpdatedAt,
+	)
+	return err
+}
+
+func (r *postgresTargetRepository) Delete(ctx context.Context, wallet string) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM portfolio_targets WHERE wallet = $1`, wallet)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrTargetNotFound
+	}
+	return nil
+}
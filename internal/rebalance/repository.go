@@ -0,0 +1,10 @@
+package rebalance
+
+import "context"
+
+// TargetRepository stores one target allocation per wallet
+type TargetRepository interface {
+	Get(ctx context.Context, wallet string) (*Target, error)
+	Save(ctx context.Context, t Target) error
+	Delete(ctx context.Context, wallet string) error
+}
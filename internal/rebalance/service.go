@@ -0,0 +1,114 @@
+package rebalance
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+type ServiceAPI interface {
+	GetTarget(ctx context.Context, wallet string) (*Target, error)
+	SetTarget(ctx context.Context, t Target) (*Target, error)
+	DeleteTarget(ctx context.Context, wallet string) error
+
+	// Plan builds a rebalancing plan from the wallet's live valuation
+	Plan(ctx context.Context, wallet string, opts Options) (*Plan, error)
+}
+
+type Service struct {
+	targets   TargetRepository
+	portfolio portfolio.Service
+	pricing   pricing.ServiceAPI
+	logger    *zap.Logger
+}
+
+func NewService(
+	targets TargetRepository,
+	portfolio portfolio.Service,
+	pricing pricing.ServiceAPI,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		targets:   targets,
+		portfolio: portfolio,
+		pricing:   pricing,
+		logger:    logger,
+	}
+}
+
+func (s *Service) GetTarget(ctx context.Context, wallet string) (*Target, error) {
+	return s.targets.Get(ctx, wallet)
+}
+
+// SetTarget validates and stores the wallet's target, replacing any earlier one
+func (s *Service) SetTarget(ctx context.Context, t Target) (*Target, error) {
+	if err := t.normalize(); err != nil {
+		return nil, err
+	}
+	t.UpdatedAt = time.Now().UTC()
+
+	s.logger.Info("set-target-allocation",
+		zap.String("wallet", t.Wallet),
+		zap.Int("weights", len(t.Weights)),
+		zap.Float64("tolerance_pct", t.TolerancePct),
+	)
+
+	if err := s.targets.Save(ctx, t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (s *Service) DeleteTarget(ctx context.Context, wallet string) error {
+	return s.targets.Delete(ctx, wallet)
+}
+
+func (s *Service) Plan(ctx context.Context, wallet string, opts Options) (*Plan, error) {
+	target, err := s.targets.Get(ctx, wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	view, err := s.portfolio.Get(ctx, wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	// targeted assets the wallet does not hold yet still need a price
+	held := make(map[pricing.AssetRef]bool, len(view.Holdings))
+	for _, h := range view.Holdings {
+		held[pricing.AssetRef{Chain: h.Chain, ContractAddress: h.ContractAddress}] = true
+	}
+	var missing []pricing.AssetRef
+	for _, w := range target.Weights {
+		if !held[w.Asset] {
+			missing = append(missing, w.Asset)
+		}
+	}
+
+	prices := map[pricing.AssetRef]float64{}
+	if len(missing) > 0 {
+		prices, err = s.pricing.GetPrices(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	plan, err := BuildPlan(view, *target, prices, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("rebalance-plan",
+		zap.String("wallet", wallet),
+		zap.Bool("cash_only", opts.CashOnly),
+		zap.Float64("buy_usd", plan.TotalBuyUSD),
+		zap.Float64("sell_usd", plan.TotalSellUSD),
+	)
+
+	return plan, nil
+}
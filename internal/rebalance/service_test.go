@@ -0,0 +1,222 @@
+package rebalance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+type fakePortfolio struct {
+	portfolio.Service
+	view *portfolio.PortfolioView
+}
+
+func (f *fakePortfolio) Get(ctx context.Context, wallet string) (*portfolio.PortfolioView, error) {
+	return f.view, nil
+}
+
+type fakePricing struct {
+	pricing.ServiceAPI
+	prices    map[pricing.AssetRef]float64
+	requested []pricing.AssetRef
+}
+
+func (f *fakePricing) GetPrices(ctx context.Context, assets []pricing.AssetRef) (map[pricing.AssetRef]float64, error) {
+	f.requested = assets
+	out := make(map[pricing.AssetRef]float64)
+	for _, a := range assets {
+		if p, ok := f.prices[a]; ok {
+			out[a] = p
+		}
+	}
+	return out, nil
+}
+
+var (
+	eth  = pricing.AssetRef{Chain: "ethereum"}
+	usdc = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"}
+	wbtc = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0x2260fac5e5542a773aa44fbcfedf7c193bc2c599"}
+	arb  = pricing.AssetRef{Chain: "arbitrum-one", ContractAddress: "0x912ce59144191c1204e64559fe8253a0e49e6548"}
+)
+
+// 6000 ETH, 3000 USDC, 1000 ARB
+func testView() *portfolio.PortfolioView {
+	return &portfolio.PortfolioView{
+		Wallet:        "0xabc",
+		TotalValueUSD: 10_000,
+		Holdings: []portfolio.HoldingView{
+			{Chain: eth.Chain, Amount: 2, PriceUSD: 3000, ValueUSD: 6000},
+			{Chain: usdc.Chain, ContractAddress: usdc.ContractAddress, Amount: 3000, PriceUSD: 1, ValueUSD: 3000},
+			{Chain: arb.Chain, ContractAddress: arb.ContractAddress, Amount: 2000, PriceUSD: 0.5, ValueUSD: 1000},
+		},
+	}
+}
+
+func lineFor(t *testing.T, plan *Plan, asset pricing.AssetRef) Line {
+	t.Helper()
+	for _, l := range plan.Lines {
+		if l.Asset == asset {
+			return l
+		}
+	}
+	t.Fatalf("no line for %s", asset)
+	return Line{}
+}
+
+func TestBuildPlan_TradesBackToTarget(t *testing.T) {
+	target := Target{
+		Weights: []TargetWeight{
+			{Asset: eth, WeightPct: 50},
+			{Asset: usdc, WeightPct: 30},
+			{Asset: wbtc, WeightPct: 20},
+		},
+		TolerancePct: 2,
+	}
+	prices := map[pricing.AssetRef]float64{wbtc: 50_000}
+
+	plan, err := BuildPlan(testView(), target, prices, Options{})
+	require.NoError(t, err)
+
+	ethLine := lineFor(t, plan, eth)
+	require.Equal(t, ActionSell, ethLine.Action)
+	require.InDelta(t, 1000, ethLine.AmountUSD, 1e-9)
+	require.InDelta(t, 1.0/3, ethLine.Quantity, 1e-9)
+	require.InDelta(t, 50, ethLine.PostWeightPct, 1e-9)
+
+	usdcLine := lineFor(t, plan, usdc)
+	require.Equal(t, ActionHold, usdcLine.Action)
+	require.Equal(t, ReasonWithinTolerance, usdcLine.Reason)
+
+	wbtcLine := lineFor(t, plan, wbtc)
+	require.Equal(t, ActionBuy, wbtcLine.Action)
+	require.InDelta(t, 2000, wbtcLine.AmountUSD, 1e-9)
+	require.InDelta(t, 0.04, wbtcLine.Quantity, 1e-9)
+
+	// ARB is held but not targeted, so it is sold off
+	arbLine := lineFor(t, plan, arb)
+	require.Equal(t, ActionSell, arbLine.Action)
+	require.InDelta(t, 2000, arbLine.Quantity, 1e-9)
+	require.InDelta(t, 0, arbLine.PostWeightPct, 1e-9)
+
+	require.InDelta(t, plan.TotalBuyUSD, plan.TotalSellUSD, 1e-9)
+}
+
+func TestBuildPlan_MinTradeAndUnpriced(t *testing.T) {
+	target := Target{
+		Weights: []TargetWeight{
+			{Asset: eth, WeightPct: 55},
+			{Asset: usdc, WeightPct: 35},
+			{Asset: arb, WeightPct: 5},
+			{Asset: wbtc, WeightPct: 5},
+		},
+		TolerancePct: 1,
+	}
+
+	plan, err := BuildPlan(testView(), target, nil, Options{MinTradeUSD: 600})
+	require.NoError(t, err)
+
+	require.Equal(t, []pricing.AssetRef{wbtc}, plan.Unpriced)
+	require.Len(t, plan.Lines, 3)
+
+	require.Equal(t, ReasonBelowMinTrade, lineFor(t, plan, eth).Reason)
+	require.Equal(t, ReasonBelowMinTrade, lineFor(t, plan, usdc).Reason)
+	require.Equal(t, ActionHold, lineFor(t, plan, usdc).Action)
+	require.Equal(t, ActionHold, lineFor(t, plan, arb).Action)
+}
+
+func TestBuildPlan_CashOnly(t *testing.T) {
+	target := Target{
+		Weights: []TargetWeight{
+			{Asset: eth, WeightPct: 40},
+			{Asset: usdc, WeightPct: 10},
+			{Asset: arb, WeightPct: 20},
+			{Asset: wbtc, WeightPct: 30},
+		},
+		CashAsset: &usdc,
+	}
+	prices := map[pricing.AssetRef]float64{wbtc: 50_000}
+
+	plan, err := BuildPlan(testView(), target, prices, Options{CashOnly: true})
+	require.NoError(t, err)
+
+	// ETH is overweight but only cash may be sold
+	require.Equal(t, ActionHold, lineFor(t, plan, eth).Action)
+	require.Equal(t, ReasonCashOnly, lineFor(t, plan, eth).Reason)
+
+	// 2000 of spare cash against shortfalls of 1000 ARB and 3000 WBTC
+	require.InDelta(t, 500, lineFor(t, plan, arb).AmountUSD, 1e-9)
+	require.InDelta(t, 1500, lineFor(t, plan, wbtc).AmountUSD, 1e-9)
+
+	cash := lineFor(t, plan, usdc)
+	require.Equal(t, ActionSell, cash.Action)
+	require.InDelta(t, 2000, cash.AmountUSD, 1e-9)
+	require.InDelta(t, 10, cash.PostWeightPct, 1e-9)
+
+	require.InDelta(t, 2000, plan.TotalBuyUSD, 1e-9)
+	require.InDelta(t, 2000, plan.TotalSellUSD, 1e-9)
+}
+
+func TestBuildPlan_CashOnlyNeedsCashAsset(t *testing.T) {
+	target := Target{Weights: []TargetWeight{{Asset: eth, WeightPct: 100}}}
+
+	_, err := BuildPlan(testView(), target, nil, Options{CashOnly: true})
+	require.ErrorIs(t, err, ErrNoCashAsset)
+}
+
+func TestService_SetTargetValidates(t *testing.T) {
+	svc := NewService(NewMemoryTargetRepository(), &fakePortfolio{}, &fakePricing{}, zap.NewNop())
+	ctx := context.Background()
+
+	_, err := svc.SetTarget(ctx, Target{
+		Wallet:  "0xabc",
+		Weights: []TargetWeight{{Asset: eth, WeightPct: 60}, {Asset: usdc, WeightPct: 30}},
+	})
+	require.ErrorIs(t, err, ErrInvalidTarget)
+
+	_, err = svc.SetTarget(ctx, Target{
+		Wallet:  "0xabc",
+		Weights: []TargetWeight{{Asset: eth, WeightPct: 50}, {Asset: eth, WeightPct: 50}},
+	})
+	require.ErrorIs(t, err, ErrInvalidTarget)
+
+	saved, err := svc.SetTarget(ctx, Target{
+		Wallet: "0xabc",
+		Weights: []TargetWeight{
+			{Asset: eth, WeightPct: 70},
+			{Asset: pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xA0B86991C6218B36C1D19D4A2E9EB0CE3606EB48"}, WeightPct: 30},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, usdc, saved.Weights[1].Asset)
+	require.False(t, saved.UpdatedAt.IsZero())
+
+	got, err := svc.GetTarget(ctx, "0xabc")
+	require.NoError(t, err)
+	require.Equal(t, saved.Weights, got.Weights)
+}
+
+func TestService_PlanPricesUnheldTargets(t *testing.T) {
+	prices := &fakePricing{prices: map[pricing.AssetRef]float64{wbtc: 50_000}}
+	svc := NewService(NewMemoryTargetRepository(), &fakePortfolio{view: testView()}, prices, zap.NewNop())
+	ctx := context.Background()
+
+	_, err := svc.Plan(ctx, "0xabc", Options{})
+	require.ErrorIs(t, err, ErrTargetNotFound)
+
+	_, err = svc.SetTarget(ctx, Target{
+		Wallet:  "0xabc",
+		Weights: []TargetWeight{{Asset: eth, WeightPct: 80}, {Asset: wbtc, WeightPct: 20}},
+	})
+	require.NoError(t, err)
+
+	plan, err := svc.Plan(ctx, "0xabc", Options{})
+	require.NoError(t, err)
+	require.Equal(t, []pricing.AssetRef{wbtc}, prices.requested)
+	require.Equal(t, ActionBuy, lineFor(t, plan, wbtc).Action)
+	require.InDelta(t, 2000, lineFor(t, plan, wbtc).AmountUSD, 1e-9)
+}
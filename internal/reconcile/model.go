@@ -0,0 +1,103 @@
+package reconcile
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+var (
+	ErrInvalidTolerance  = errors.New("tolerance must be between 0 and 100")
+	ErrPortfolioNotFound = errors.New("portfolio not found")
+)
+
+// DefaultTolerancePct is the relative difference left unflagged when no
+// tolerance is given
+const DefaultTolerancePct = 0.5
+
+// dust is the absolute difference always treated as equal
+const dust = 1e-9
+
+// Status classifies one asset
+type Status string
+
+const (
+	StatusMatch    Status = "match"    // the amounts agree within the tolerance
+	StatusMissing  Status = "missing"  // held on-chain, not in the portfolio
+	StatusExtra    Status = "extra"    // in the portfolio, not held on-chain
+	StatusMismatch Status = "mismatch" // both hold it in different amounts
+	StatusUnknown  Status = "unknown"  // the on-chain balance could not be read
+)
+
+// Line compares one asset's portfolio amount with the chain and with the
+// net of the transaction history
+type Line struct {
+	Chain           string  `json:"chain"`
+	ContractAddress string  `json:"contract_address"`
+	Holding         float64 `json:"holding"` // manual and synced amounts together
+	Manual          float64 `json:"manual"`
+	OnChain         float64 `json:"on_chain"`
+	Diff            float64 `json:"diff"` // on-chain minus holding
+	Status          Status  `json:"status"`
+	Error           string  `json:"error,omitempty"`
+
+	// Transactions is the sum of transfers in less transfers out, and for
+	// native assets gas paid; nil when the history cannot be read
+	Transactions       *float64 `json:"transactions,omitempty"`
+	TransactionsStatus Status   `json:"transactions_status,omitempty"`
+}
+
+// Summary counts lines by status
+type Summary struct {
+	Match    int `json:"match"`
+	Missing  int `json:"missing"`
+	Extra    int `json:"extra"`
+	Mismatch int `json:"mismatch"`
+	Unknown  int `json:"unknown"`
+}
+
+func (s *Summary) add(status Status) {
+	switch status {
+	case StatusMatch:
+		s.Match++
+	case StatusMissing:
+		s.Missing++
+	case StatusExtra:
+		s.Extra++
+	case StatusMismatch:
+		s.Mismatch++
+	case StatusUnknown:
+		s.Unknown++
+	}
+}
+
+type Report struct {
+	Wallet       string    `json:"wallet"`
+	CheckedAt    time.Time `json:"checked_at"`
+	TolerancePct float64   `json:"tolerance_pct"`
+	Summary      Summary   `json:"summary"`
+	Lines        []Line    `json:"lines"`
+	// Truncated is set when a chain's history is longer than the 10,000
+	// records etherscan returns, so the transaction sums are partial
+	Truncated bool `json:"truncated"`
+}
+
+// classify compares an expected amount with an actual one, allowing
+// tolerancePct of the larger as difference
+func classify(expected, actual, tolerancePct float64) Status {
+	held, real := expected > dust, actual > dust
+	switch {
+	case !held && !real:
+		return StatusMatch
+	case !held:
+		return StatusMissing
+	case !real:
+		return StatusExtra
+	}
+
+	allowed := math.Max(dust, math.Max(expected, actual)*tolerancePct/100)
+	if math.Abs(actual-expected) <= allowed {
+		return StatusMatch
+	}
+	return StatusMismatch
+}
@@ -0,0 +1,292 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/balances"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
+)
+
+type ServiceAPI interface {
+	Report(ctx context.Context, wallet string, tolerancePct float64) (*Report, error)
+	Accept(ctx context.Context, wallet string, assets []pricing.AssetRef, tolerancePct float64) ([]Line, error)
+}
+
+// Service compares a wallet's portfolio with its on-chain balances and
+// transaction history
+type Service struct {
+	portfolio    portfolio.Service
+	balances     balances.ServiceAPI
+	transactions transactions.ServiceAPI
+	transfers    transactions.TokenTransfersAPI
+	logger       *zap.Logger
+}
+
+func NewService(
+	portfolio portfolio.Service,
+	balances balances.ServiceAPI,
+	transactions transactions.ServiceAPI,
+	transfers transactions.TokenTransfersAPI,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		portfolio:    portfolio,
+		balances:     balances,
+		transactions: transactions,
+		transfers:    transfers,
+		logger:       logger.With(zap.String("service", "reconcile")),
+	}
+}
+
+// tokenNets is the net of a chain's token transfers per contract
+type tokenNets struct {
+	net       map[string]float64
+	truncated bool
+	ok        bool
+}
+
+// Report reads the on-chain balance of every held, watched and native asset
+// and classifies it against the portfolio
+func (s *Service) Report(ctx context.Context, wallet string, tolerancePct float64) (*Report, error) {
+	if tolerancePct < 0 || tolerancePct > 100 {
+		return nil, ErrInvalidTolerance
+	}
+
+	s.logger.Info("reconcile-holdings",
+		zap.String("wallet", wallet),
+		zap.Float64("tolerance_pct", tolerancePct),
+	)
+
+	held, err := s.portfolio.Holdings(ctx, wallet)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrPortfolioNotFound, err)
+	}
+
+	readings, err := s.balances.Read(ctx, wallet, held)
+	if err != nil {
+		return nil, err
+	}
+
+	holding := make(map[pricing.AssetRef]float64)
+	manual := make(map[pricing.AssetRef]float64)
+	for _, h := range held {
+		holding[h.AssetRef()] += h.Amount
+		if h.Source == portfolio.HoldingSourceManual {
+			manual[h.AssetRef()] += h.Amount
+		}
+	}
+
+	report := &Report{
+		Wallet:       wallet,
+		CheckedAt:    time.Now().UTC().Truncate(time.Second),
+		TolerancePct: tolerancePct,
+		Lines:        []Line{},
+	}
+
+	// token transfers are read once per chain
+	tokenHistory := make(map[string]tokenNets)
+
+	for _, r := range readings {
+		l := Line{
+			Chain:           r.Asset.Chain,
+			ContractAddress: r.Asset.ContractAddress,
+			Holding:         holding[r.Asset],
+			Manual:          manual[r.Asset],
+			OnChain:         r.Amount,
+			Diff:            r.Amount - holding[r.Asset],
+		}
+		if r.Err != nil {
+			l.Status, l.Error = StatusUnknown, r.Err.Error()
+		} else {
+			l.Status = classify(l.Holding, l.OnChain, tolerancePct)
+		}
+
+		// nothing to say about assets neither side holds
+		if l.Status == StatusMatch && l.Holding == 0 && l.OnChain == 0 {
+			continue
+		}
+
+		if r.Asset.ContractAddress == "" {
+			net, truncated, ok := s.netTransfers(ctx, wallet, r.Asset.Chain)
+			if ok {
+				l.Transactions = &net
+				l.TransactionsStatus = classify(l.Holding, net, tolerancePct)
+				report.Truncated = report.Truncated || truncated
+			}
+		} else {
+			h, seen := tokenHistory[r.Asset.Chain]
+			if !seen {
+				h.net, h.truncated, h.ok = s.netTokenTransfers(ctx, wallet, r.Asset.Chain)
+				tokenHistory[r.Asset.Chain] = h
+			}
+			if h.ok {
+				net := h.net[strings.ToLower(r.Asset.ContractAddress)]
+				l.Transactions = &net
+				l.TransactionsStatus = classify(l.Holding, net, tolerancePct)
+				report.Truncated = report.Truncated || h.truncated
+			}
+		}
+
+		report.Summary.add(l.Status)
+		report.Lines = append(report.Lines, l)
+	}
+
+	s.logger.Info("holdings-reconciled",
+		zap.String("wallet", wallet),
+		zap.Int("mismatch", report.Summary.Mismatch),
+		zap.Int("missing", report.Summary.Missing),
+		zap.Int("extra", report.Summary.Extra),
+	)
+	return report, nil
+}
+
+// Accept sets the manual holding of each flagged asset so the portfolio
+// matches the chain, in one save. With no assets given every missing,
+// extra and mismatched asset is fixed. It returns the lines it fixed.
+func (s *Service) Accept(ctx context.Context, wallet string, assets []pricing.AssetRef, tolerancePct float64) ([]Line, error) {
+	report, err := s.Report(ctx, wallet, tolerancePct)
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := make(map[pricing.AssetRef]bool, len(assets))
+	for _, a := range assets {
+		a.ContractAddress = strings.ToLower(a.ContractAddress)
+		wanted[a] = true
+	}
+
+	fixed := []Line{}
+	var set []portfolio.Holding
+	for _, l := range report.Lines {
+		switch l.Status {
+		case StatusMissing, StatusExtra, StatusMismatch:
+		default:
+			continue
+		}
+		ref := pricing.AssetRef{Chain: l.Chain, ContractAddress: l.ContractAddress}
+		if len(wanted) > 0 && !wanted[ref] {
+			continue
+		}
+
+		// synced amounts stay as they are, so the manual part takes the
+		// difference
+		amount := l.OnChain - (l.Holding - l.Manual)
+		if amount < 0 {
+			amount = 0
+		}
+		set = append(set, portfolio.Holding{Chain: l.Chain, ContractAddress: l.ContractAddress, Amount: amount})
+		fixed = append(fixed, l)
+	}
+
+	if len(set) == 0 {
+		return fixed, nil
+	}
+	if err := s.portfolio.SetHoldings(ctx, wallet, set); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("on-chain-balances-accepted",
+		zap.String("wallet", wallet),
+		zap.Int("holdings", len(set)),
+	)
+	return fixed, nil
+}
+
+// netTransfers sums the native transfers in and out of the wallet on a
+// chain, less the gas it paid. It reports false when the history cannot be
+// read.
+func (s *Service) netTransfers(ctx context.Context, wallet, chain string) (float64, bool, bool) {
+	if s.transactions == nil {
+		return 0, false, false
+	}
+	chainID, ok := tokens.ChainID(chain)
+	if !ok {
+		return 0, false, false
+	}
+
+	wallet = strings.ToLower(wallet)
+
+	txs, truncated, err := transactions.ReadPages(func(page, limit int) ([]transactions.Transaction, error) {
+		return s.transactions.List(ctx, chainID, wallet, page, limit, transactions.Filters{})
+	}, transactions.HistoryPageSize, transactions.HistoryMaxPages)
+	if err != nil {
+		s.logger.Warn("reconcile-transactions-failed",
+			zap.String("wallet", wallet),
+			zap.String("chain", chain),
+			zap.Error(err),
+		)
+		return 0, false, false
+	}
+
+	var net float64
+	for _, tx := range txs {
+		if tx.TokenAddr != "" {
+			continue
+		}
+		from := strings.ToLower(tx.From) == wallet
+		to := strings.ToLower(tx.To) == wallet
+		if from {
+			net -= tx.Fee
+		}
+		if tx.Status != transactions.StatusSuccess || from == to {
+			continue
+		}
+		if from {
+			net -= tx.Amount
+		} else {
+			net += tx.Amount
+		}
+	}
+	return net, truncated, true
+}
+
+// netTokenTransfers sums the ERC-20 transfers in and out of the wallet on a
+// chain per contract. It reports false when the history cannot be read.
+func (s *Service) netTokenTransfers(ctx context.Context, wallet, chain string) (map[string]float64, bool, bool) {
+	if s.transfers == nil {
+		return nil, false, false
+	}
+	chainID, ok := tokens.ChainID(chain)
+	if !ok {
+		return nil, false, false
+	}
+
+	wallet = strings.ToLower(wallet)
+
+	txs, truncated, err := transactions.ReadPages(func(page, limit int) ([]transactions.Transaction, error) {
+		return s.transfers.TokenTransfers(ctx, chainID, wallet, page, limit)
+	}, transactions.HistoryPageSize, transactions.HistoryMaxPages)
+	if err != nil {
+		s.logger.Warn("reconcile-token-transfers-failed",
+			zap.String("wallet", wallet),
+			zap.String("chain", chain),
+			zap.Error(err),
+		)
+		return nil, false, false
+	}
+
+	net := make(map[string]float64)
+	for _, tx := range txs {
+		if tx.TokenAddr == "" {
+			continue
+		}
+		contract := strings.ToLower(tx.TokenAddr)
+		from := strings.ToLower(tx.From) == wallet
+		to := strings.ToLower(tx.To) == wallet
+		switch {
+		case from && !to:
+			net[contract] -= tx.Amount
+		case to && !from:
+			net[contract] += tx.Amount
+		}
+	}
+	return net, truncated, true
+}
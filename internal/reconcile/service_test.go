@@ -0,0 +1,182 @@
+package reconcile_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/balances"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/reconcile"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
+)
+
+const wallet = "0x00000000000000000000000000000000000000aa"
+
+var (
+	eth  = pricing.AssetRef{Chain: "ethereum"}
+	usdc = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xusdc"}
+	dai  = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xdai"}
+	link = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xlink"}
+	pepe = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xpepe"}
+)
+
+type fakeBalances struct {
+	balances.ServiceAPI
+	readings []balances.Reading
+}
+
+func (f *fakeBalances) Read(ctx context.Context, wallet string, held []portfolio.Holding) ([]balances.Reading, error) {
+	return f.readings, nil
+}
+
+type fakeTransactions struct {
+	txs       []transactions.Transaction
+	transfers []transactions.Transaction
+}
+
+func (f *fakeTransactions) List(
+	ctx context.Context,
+	chain string,
+	wallet string,
+	page int,
+	limit int,
+	filters transactions.Filters,
+) ([]transactions.Transaction, error) {
+	start := (page - 1) * limit
+	if start >= len(f.txs) {
+		return nil, nil
+	}
+	return f.txs[start:min(start+limit, len(f.txs))], nil
+}
+
+func (f *fakeTransactions) TokenTransfers(
+	ctx context.Context,
+	chain string,
+	wallet string,
+	page int,
+	limit int,
+) ([]transactions.Transaction, error) {
+	if page > 1 {
+		return nil, nil
+	}
+	return f.transfers, nil
+}
+
+func setup() (*reconcile.Service, portfolio.Service) {
+	ps := portfolio.NewService(portfolio.NewMemoryRepository([]*portfolio.Portfolio{{
+		Wallet: wallet,
+		Holdings: []portfolio.Holding{
+			{Chain: "ethereum", Amount: 1.5},
+			{Chain: "ethereum", ContractAddress: "0xusdc", Amount: 100},
+			{Chain: "ethereum", ContractAddress: "0xdai", Amount: 40},
+			{Chain: "ethereum", ContractAddress: "0xpepe", Amount: 5},
+		},
+		Synced: []portfolio.Holding{{Chain: "ethereum", ContractAddress: "0xdai", Amount: 10}},
+	}}), nil, zap.NewNop())
+
+	bs := &fakeBalances{readings: []balances.Reading{
+		{Asset: eth, Amount: 1.2},
+		{Asset: dai, Amount: 50.1},
+		{Asset: link, Amount: 7},
+		{Asset: pepe, Err: errors.New("execution reverted")},
+		{Asset: usdc, Amount: 0},
+	}}
+
+	txs := &fakeTransactions{txs: []transactions.Transaction{
+		{From: "0xother", To: wallet, Amount: 2, Fee: 0.01, Status: transactions.StatusSuccess},
+		{From: wallet, To: "0xother", Amount: 0.75, Fee: 0.02, Status: transactions.StatusSuccess},
+		{From: wallet, To: "0xother", Amount: 5, Fee: 0.03, Status: transactions.StatusFailed},
+		{From: wallet, To: "0xtoken", TokenAddr: "0xusdc", Amount: 100, Status: transactions.StatusSuccess},
+		// a withdrawal whose payout arrives as an internal transaction
+		{Hash: "0xexit", From: wallet, To: "0xvault", Fee: 0.01, Status: transactions.StatusSuccess},
+		{Hash: "0xexit", ParentHash: "0xexit", From: "0xvault", To: wallet, Amount: 0.01, Status: transactions.StatusSuccess},
+	}, transfers: []transactions.Transaction{
+		{From: "0xbob", To: wallet, TokenAddr: "0xDAI", Amount: 60},
+		{From: wallet, To: "0xbob", TokenAddr: "0xdai", Amount: 10},
+		{From: wallet, To: wallet, TokenAddr: "0xdai", Amount: 99},
+		{From: "0xbob", To: wallet, TokenAddr: "0xlink", Amount: 7},
+	}}
+
+	return reconcile.NewService(ps, bs, txs, txs, zap.NewNop()), ps
+}
+
+func TestReport(t *testing.T) {
+	svc, _ := setup()
+
+	report, err := svc.Report(context.Background(), wallet, 0.5)
+	require.NoError(t, err)
+
+	byAsset := make(map[pricing.AssetRef]reconcile.Line)
+	for _, l := range report.Lines {
+		byAsset[pricing.AssetRef{Chain: l.Chain, ContractAddress: l.ContractAddress}] = l
+	}
+	require.Len(t, byAsset, 5)
+
+	require.Equal(t, reconcile.StatusMismatch, byAsset[eth].Status)
+	require.InDelta(t, -0.3, byAsset[eth].Diff, 1e-9)
+	require.NotNil(t, byAsset[eth].Transactions)
+	require.InDelta(t, 1.2, *byAsset[eth].Transactions, 1e-9)
+	require.Equal(t, reconcile.StatusMismatch, byAsset[eth].TransactionsStatus)
+
+	// 50 held across manual and synced, 50.1 is within 0.5%
+	require.Equal(t, reconcile.StatusMatch, byAsset[dai].Status)
+	require.Equal(t, 50.0, byAsset[dai].Holding)
+	require.NotNil(t, byAsset[dai].Transactions)
+	require.InDelta(t, 50, *byAsset[dai].Transactions, 1e-9)
+	require.Equal(t, reconcile.StatusMatch, byAsset[dai].TransactionsStatus)
+
+	// tokens never transferred net to zero
+	require.NotNil(t, byAsset[usdc].Transactions)
+	require.Zero(t, *byAsset[usdc].Transactions)
+	require.Equal(t, reconcile.StatusExtra, byAsset[usdc].TransactionsStatus)
+
+	require.Equal(t, reconcile.StatusMissing, byAsset[link].Status)
+	require.Equal(t, reconcile.StatusExtra, byAsset[usdc].Status)
+	require.Equal(t, reconcile.StatusUnknown, byAsset[pepe].Status)
+	require.Equal(t, "execution reverted", byAsset[pepe].Error)
+
+	require.Equal(t, reconcile.Summary{Match: 1, Missing: 1, Extra: 1, Mismatch: 1, Unknown: 1}, report.Summary)
+
+	_, err = svc.Report(context.Background(), wallet, -1)
+	require.ErrorIs(t, err, reconcile.ErrInvalidTolerance)
+}
+
+func TestAccept(t *testing.T) {
+	svc, ps := setup()
+
+	fixed, err := svc.Accept(context.Background(), wallet, []pricing.AssetRef{eth, {Chain: "ethereum", ContractAddress: "0xLINK"}}, 0.5)
+	require.NoError(t, err)
+	require.Len(t, fixed, 2)
+
+	holdings, err := ps.Holdings(context.Background(), wallet)
+	require.NoError(t, err)
+	amounts := make(map[pricing.AssetRef]float64)
+	for _, h := range holdings {
+		if h.Source == portfolio.HoldingSourceManual {
+			amounts[h.AssetRef()] = h.Amount
+		}
+	}
+	require.Equal(t, 1.2, amounts[eth])
+	require.Equal(t, 7.0, amounts[link])
+	require.Equal(t, 100.0, amounts[usdc])
+
+	// everything flagged when no asset is named; the unreadable one is left
+	fixed, err = svc.Accept(context.Background(), wallet, nil, 0.5)
+	require.NoError(t, err)
+	require.Len(t, fixed, 1)
+	require.Equal(t, "0xusdc", fixed[0].ContractAddress)
+
+	holdings, err = ps.Holdings(context.Background(), wallet)
+	require.NoError(t, err)
+	for _, h := range holdings {
+		require.NotEqual(t, usdc, h.AssetRef())
+		if h.AssetRef() == pepe {
+			require.Equal(t, 5.0, h.Amount)
+		}
+	}
+}
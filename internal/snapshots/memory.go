@@ -0,0 +1,78 @@
+package snapshots
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+type memoryRepository struct {
+	mu   sync.RWMutex
+	data map[string][]Snapshot // wallet -> snapshots, oldest first
+}
+
+func NewMemoryRepository() Repository {
+	return &memoryRepository{data: make(map[string][]Snapshot)}
+}
+
+func (r *memoryRepository) Save(ctx context.Context, s Snapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	snaps := append(r.data[s.Wallet], s)
+	sort.SliceStable(snaps, func(i, j int) bool {
+		return snaps[i].TakenAt.Before(snaps[j].TakenAt)
+	})
+	r.data[s.Wallet] = snaps
+	return nil
+}
+
+func (r *memoryRepository) List(ctx context.Context, wallet string, from, to time.Time) ([]Snapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]Snapshot, 0)
+	for _, s := range r.data[wallet] {
+		if s.TakenAt.Before(from) || s.TakenAt.After(to) {
+			continue
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func (r *memoryRepository) Delete(ctx context.Context, wallet string, takenAt []time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	drop := make(map[int64]struct{}, len(takenAt))
+	for _, t := range takenAt {
+		drop[t.UnixNano()] = struct{}{}
+	}
+
+	kept := make([]Snapshot, 0, len(r.data[wallet]))
+	for _, s := range r.data[wallet] {
+		if _, ok := drop[s.TakenAt.UnixNano()]; !ok {
+			kept = append(kept, s)
+		}
+	}
+	r.data[wallet] = kept
+	return nil
+}
+
+func (r *memoryRepository) DeleteBefore(ctx context.Context, t time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for wallet, snaps := range r.data {
+		i := 0
+		for i < len(snaps) && snaps[i].TakenAt.Before(t) {
+			i++
+		}
+		deleted += int64(i)
+		r.data[wallet] = snaps[i:]
+	}
+	return deleted, nil
+}
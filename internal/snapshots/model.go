@@ -0,0 +1,109 @@
+package snapshots
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+)
+
+var ErrInvalidResolution = errors.New("invalid resolution")
+
+// Snapshot is a portfolio valuation recorded at a point in time
+type Snapshot struct {
+	Wallet        string            `json:"wallet"`
+	TakenAt       time.Time         `json:"taken_at"`
+	TotalValueUSD float64           `json:"total_value_usd"`
+	Holdings      []SnapshotHolding `json:"holdings"`
+}
+
+type SnapshotHolding struct {
+	Chain           string  `json:"chain"`
+	ContractAddress string  `json:"contract_address"`
+	Amount          float64 `json:"amount"`
+	PriceUSD        float64 `json:"price_usd"`
+	ValueUSD        float64 `json:"value_usd"`
+}
+
+// FromView records the holdings, prices and total of a valued portfolio
+func FromView(view *portfolio.PortfolioView, takenAt time.Time) Snapshot {
+	holdings := make([]SnapshotHolding, 0, len(view.Holdings))
+	for _, h := range view.Holdings {
+		holdings = append(holdings, SnapshotHolding{
+			Chain:           h.Chain,
+			ContractAddress: h.ContractAddress,
+			Amount:          h.Amount,
+			PriceUSD:        h.PriceUSD,
+			ValueUSD:        h.ValueUSD,
+		})
+	}
+
+	return Snapshot{
+		Wallet:        view.Wallet,
+		TakenAt:       takenAt,
+		TotalValueUSD: view.TotalValueUSD,
+		Holdings:      holdings,
+	}
+}
+
+type Resolution string
+
+const (
+	ResolutionRaw    Resolution = "raw"
+	ResolutionHourly Resolution = "hourly"
+	ResolutionDaily  Resolution = "daily"
+)
+
+func ParseResolution(s string) (Resolution, error) {
+	switch r := Resolution(s); r {
+	case "":
+		return ResolutionRaw, nil
+	case ResolutionRaw, ResolutionHourly, ResolutionDaily:
+		return r, nil
+	default:
+		return "", ErrInvalidResolution
+	}
+}
+
+// bucket is the start of the period t falls in, or t itself for raw
+func (r Resolution) bucket(t time.Time) time.Time {
+	switch r {
+	case ResolutionHourly:
+		return t.UTC().Truncate(time.Hour)
+	case ResolutionDaily:
+		return t.UTC().Truncate(24 * time.Hour)
+	default:
+		return t
+	}
+}
+
+// Downsample keeps the last snapshot of every period. snaps must be ordered
+// by time, oldest first.
+func Downsample(snaps []Snapshot, r Resolution) []Snapshot {
+	if r == ResolutionRaw || len(snaps) == 0 {
+		return snaps
+	}
+
+	out := make([]Snapshot, 0)
+	for i, s := range snaps {
+		last := i == len(snaps)-1
+		if last || !r.bucket(snaps[i+1].TakenAt).Equal(r.bucket(s.TakenAt)) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+type Repository interface {
+	Save(ctx context.Context, s Snapshot) error
+
+	// List returns a wallet's snapshots taken in [from, to], oldest first
+	List(ctx context.Context, wallet string, from, to time.Time) ([]Snapshot, error)
+
+	// Delete removes the given snapshots of a wallet
+	Delete(ctx context.Context, wallet string, takenAt []time.Time) error
+
+	// DeleteBefore removes every snapshot taken before t
+	DeleteBefore(ctx context.Context, t time.Time) (int64, error)
+}
@@ -0,0 +1,86 @@
+package snapshots
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type postgresRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresRepository(pool *pgxpool.Pool) Repository {
+	return &postgresRepository{pool: pool}
+}
+
+func (r *postgresRepository) Save(ctx context.Context, s Snapshot) error {
+	holdings, err := json.Marshal(s.Holdings)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pool.Exec(ctx, `
+		INSERT INTO portfolio_snapshots (wallet, taken_at, total_value_usd, holdings)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (wallet, taken_at) DO UPDATE SET
+			total_value_usd = EXCLUDED.total_value_usd,
+			holdings        = EXCLUDED.holdings`,
+		s.Wallet,
+		s.TakenAt,
+		s.TotalValueUSD,
+		holdings,
+	)
+	return err
+}
+
+func (r *postgresRepository) List(ctx context.Context, wallet string, from, to time.Time) ([]Snapshot, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT wallet, taken_at, total_value_usd, holdings
+		FROM portfolio_snapshots
+		WHERE wallet = $1 AND taken_at BETWEEN $2 AND $3
+		ORDER BY taken_at`,
+		wallet,
+		from,
+		to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]Snapshot, 0)
+	for rows.Next() {
+		var (
+			s        Snapshot
+			holdings []byte
+		)
+		if err := rows.Scan(&s.Wallet, &s.TakenAt, &s.TotalValueUSD, &holdings); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(holdings, &s.Holdings); err != nil {
+			return nil, err
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+func (r *postgresRepository) Delete(ctx context.Context, wallet string, takenAt []time.Time) error {
+	_, err := r.pool.Exec(ctx, `
+		DELETE FROM portfolio_snapshots WHERE wallet = $1 AND taken_at = ANY($2)`,
+		wallet,
+		takenAt,
+	)
+	return err
+}
+
+func (r *postgresRepository) DeleteBefore(ctx context.Context, t time.Time) (int64, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM portfolio_snapshots WHERE taken_at < $1`, t)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,167 @@
+package snapshots
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+)
+
+type ServiceAPI interface {
+	History(
+		ctx context.Context,
+		wallet string,
+		from time.Time,
+		to time.Time,
+		resolution Resolution,
+	) ([]Snapshot, error)
+}
+
+// Retention controls how long snapshots are kept
+type Retention struct {
+	Raw time.Duration // older snapshots are compacted to one per day, 0 keeps them all
+	Max time.Duration // older snapshots are deleted, 0 keeps them forever
+}
+
+// Service snapshots every portfolio at a fixed interval and serves the
+// recorded history
+type Service struct {
+	repo      Repository
+	portfolio portfolio.Service
+	interval  time.Duration
+	retention Retention
+	logger    *zap.Logger
+}
+
+func NewService(
+	repo Repository,
+	portfolio portfolio.Service,
+	interval time.Duration,
+	retention Retention,
+	logger *zap.Logger,
+) *Service {
+	return &Service{
+		repo:      repo,
+		portfolio: portfolio,
+		interval:  interval,
+		retention: retention,
+		logger:    logger.With(zap.String("service", "snapshots")),
+	}
+}
+
+// Run snapshots every portfolio each interval. It returns at once when
+// the schedule is off.
+func (s *Service) Run(ctx context.Context) {
+	if s.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now().UTC().Truncate(time.Second)
+			s.TakeAll(ctx, now)
+			s.ApplyRetention(ctx, now)
+		}
+	}
+}
+
+// TakeAll values and records every portfolio at now. A wallet that fails to
+// value is skipped so one bad token does not stop the others being recorded.
+func (s *Service) TakeAll(ctx context.Context, now time.Time) int {
+	wallets, err := s.portfolio.Wallets(ctx)
+	if err != nil {
+		s.logger.Error("list-wallets-failed", zap.Error(err))
+		return 0
+	}
+
+	taken := 0
+	for _, wallet := range wallets {
+		view, err := s.portfolio.Get(ctx, wallet)
+		if err != nil {
+			s.logger.Warn("snapshot-valuation-failed", zap.String("wallet", wallet), zap.Error(err))
+			continue
+		}
+
+		if err := s.repo.Save(ctx, FromView(view, now)); err != nil {
+			s.logger.Error("save-snapshot-failed", zap.String("wallet", wallet), zap.Error(err))
+			continue
+		}
+		taken++
+	}
+
+	s.logger.Info("portfolio-snapshots-taken", zap.Int("wallets", taken))
+	return taken
+}
+
+// ApplyRetention deletes snapshots past the maximum age and compacts those
+// past the raw age to the last snapshot of each day
+func (s *Service) ApplyRetention(ctx context.Context, now time.Time) {
+	if s.retention.Max > 0 {
+		deleted, err := s.repo.DeleteBefore(ctx, now.Add(-s.retention.Max))
+		if err != nil {
+			s.logger.Error("snapshot-retention-failed", zap.Error(err))
+		} else if deleted > 0 {
+			s.logger.Info("snapshots-expired", zap.Int64("deleted", deleted))
+		}
+	}
+
+	if s.retention.Raw <= 0 {
+		return
+	}
+
+	wallets, err := s.portfolio.Wallets(ctx)
+	if err != nil {
+		s.logger.Error("list-wallets-failed", zap.Error(err))
+		return
+	}
+
+	cutoff := now.Add(-s.retention.Raw)
+	for _, wallet := range wallets {
+		old, err := s.repo.List(ctx, wallet, time.Time{}, cutoff)
+		if err != nil {
+			s.logger.Error("snapshot-compaction-failed", zap.String("wallet", wallet), zap.Error(err))
+			continue
+		}
+
+		kept := make(map[int64]struct{})
+		for _, snap := range Downsample(old, ResolutionDaily) {
+			kept[snap.TakenAt.UnixNano()] = struct{}{}
+		}
+
+		drop := make([]time.Time, 0)
+		for _, snap := range old {
+			if _, ok := kept[snap.TakenAt.UnixNano()]; !ok {
+				drop = append(drop, snap.TakenAt)
+			}
+		}
+		if len(drop) == 0 {
+			continue
+		}
+
+		if err := s.repo.Delete(ctx, wallet, drop); err != nil {
+			s.logger.Error("snapshot-compaction-failed", zap.String("wallet", wallet), zap.Error(err))
+		}
+	}
+}
+
+func (s *Service) History(
+	ctx context.Context,
+	wallet string,
+	from time.Time,
+	to time.Time,
+	resolution Resolution,
+) ([]Snapshot, error) {
+	snaps, err := s.repo.List(ctx, wallet, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return Downsample(snaps, resolution), nil
+}
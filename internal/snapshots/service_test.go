@@ -0,0 +1,99 @@
+package snapshots
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+)
+
+type fakePortfolio struct {
+	portfolio.Service
+	views map[string]*portfolio.PortfolioView
+}
+
+func (f *fakePortfolio) Wallets(ctx context.Context) ([]string, error) {
+	out := make([]string, 0, len(f.views))
+	for w := range f.views {
+		out = append(out, w)
+	}
+	return out, nil
+}
+
+func (f *fakePortfolio) Get(ctx context.Context, wallet string) (*portfolio.PortfolioView, error) {
+	return f.views[wallet], nil
+}
+
+var start = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newTestService(retention Retention) (*Service, Repository) {
+	repo := NewMemoryRepository()
+	pf := &fakePortfolio{views: map[string]*portfolio.PortfolioView{
+		"0xabc": {
+			Wallet:        "0xabc",
+			TotalValueUSD: 1500,
+			Holdings: []portfolio.HoldingView{
+				{Chain: "ethereum", ContractAddress: "0xusdc", Amount: 1500, PriceUSD: 1, ValueUSD: 1500},
+			},
+		},
+	}}
+	return NewService(repo, pf, time.Hour, retention, zap.NewNop()), repo
+}
+
+func TestService_TakeAllAndDownsample(t *testing.T) {
+	svc, _ := newTestService(Retention{})
+
+	// every 30 minutes over two days
+	for i := 0; i < 96; i++ {
+		require.Equal(t, 1, svc.TakeAll(context.Background(), start.Add(time.Duration(i)*30*time.Minute)))
+	}
+
+	raw, err := svc.History(context.Background(), "0xabc", start, start.Add(48*time.Hour), ResolutionRaw)
+	require.NoError(t, err)
+	require.Len(t, raw, 96)
+	require.Equal(t, 1500.0, raw[0].Holdings[0].ValueUSD)
+
+	hourly, err := svc.History(context.Background(), "0xabc", start, start.Add(48*time.Hour), ResolutionHourly)
+	require.NoError(t, err)
+	require.Len(t, hourly, 48)
+	require.Equal(t, start.Add(30*time.Minute), hourly[0].TakenAt)
+
+	daily, err := svc.History(context.Background(), "0xabc", start, start.Add(48*time.Hour), ResolutionDaily)
+	require.NoError(t, err)
+	require.Len(t, daily, 2)
+	require.Equal(t, start.Add(23*time.Hour+30*time.Minute), daily[0].TakenAt)
+}
+
+func TestService_ApplyRetention(t *testing.T) {
+	svc, repo := newTestService(Retention{Raw: 24 * time.Hour, Max: 72 * time.Hour})
+
+	// hourly over four days
+	for i := 0; i < 96; i++ {
+		svc.TakeAll(context.Background(), start.Add(time.Duration(i)*time.Hour))
+	}
+	now := start.Add(96 * time.Hour)
+
+	svc.ApplyRetention(context.Background(), now)
+
+	all, err := repo.List(context.Background(), "0xabc", time.Time{}, now)
+	require.NoError(t, err)
+
+	// day one is past the max age, days two and three are compacted to
+	// their last snapshot, day four is kept raw
+	require.Len(t, all, 2+24)
+	require.Equal(t, start.Add(47*time.Hour), all[0].TakenAt)
+	require.Equal(t, start.Add(71*time.Hour), all[1].TakenAt)
+}
+
+func TestParseResolution(t *testing.T) {
+	r, err := ParseResolution("")
+	require.NoError(t, err)
+	require.Equal(t, ResolutionRaw, r)
+
+	_, err = ParseResolution("weekly")
+	require.ErrorIs(t, err, ErrInvalidResolution)
+}
@@ -0,0 +1,49 @@
+package spam
+
+import (
+	"strings"
+	"unicode"
+)
+
+// knownRankCutoff is the market cap rank a token must be within to be
+// worth impersonating
+const knownRankCutoff = 500
+
+// homoglyphs maps characters scam tokens swap into well-known symbols to
+// the letters they pass for
+var homoglyphs = map[rune]rune{
+	'0': 'O', '1': 'I', '5': 'S',
+	// Cyrillic
+	'А': 'A', 'В': 'B', 'Е': 'E', 'К': 'K', 'М': 'M', 'Н': 'H', 'О': 'O',
+	'Р': 'P', 'С': 'C', 'Т': 'T', 'У': 'Y', 'Х': 'X', 'Ѕ': 'S', 'І': 'I',
+	// Greek
+	'Α': 'A', 'Β': 'B', 'Ε': 'E', 'Ζ': 'Z', 'Η': 'H', 'Ι': 'I', 'Κ': 'K',
+	'Μ': 'M', 'Ν': 'N', 'Ο': 'O', 'Ρ': 'P', 'Τ': 'T', 'Υ': 'Y', 'Χ': 'X',
+}
+
+// foldSymbol maps a symbol to the plain upper-case ASCII it reads as:
+// homoglyphs become the letters they imitate, full-width forms become
+// ASCII and spaces and invisible characters are dropped
+func foldSymbol(symbol string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(symbol) {
+		switch {
+		case unicode.IsSpace(r), unicode.Is(unicode.Cf, r):
+			continue
+		case r >= '！' && r <= '～':
+			r = unicode.ToUpper(r - 0xFEE0)
+		}
+		if g, ok := homoglyphs[r]; ok {
+			r = g
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// ZeroValueTransfer reports whether a transfer moved nothing, called no
+// function and was not sent by the wallet: the pattern address-poisoning
+// airdrops leave in a wallet's history
+func ZeroValueTransfer(wallet, from, method string, amount float64) bool {
+	return amount == 0 && method == "" && !strings.EqualFold(from, wallet)
+}
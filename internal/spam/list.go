@@ -0,0 +1,66 @@
+package spam
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+)
+
+var ErrInvalidList = errors.New("invalid spam list")
+
+// List names tokens always treated as spam and tokens never treated as
+// spam, whatever else is known about them
+type List struct {
+	Deny  []pricing.AssetRef `json:"deny"`
+	Allow []pricing.AssetRef `json:"allow"`
+}
+
+// LoadList reads and validates a spam list JSON file
+func LoadList(path string) (List, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return List{}, err
+	}
+
+	var l List
+	if err := json.Unmarshal(raw, &l); err != nil {
+		return List{}, fmt.Errorf("%w: %v", ErrInvalidList, err)
+	}
+	if err := l.validate(); err != nil {
+		return List{}, err
+	}
+	return l, nil
+}
+
+func (l *List) validate() error {
+	deny := make(map[pricing.AssetRef]bool, len(l.Deny))
+	for _, a := range l.Deny {
+		if a.Chain == "" || a.ContractAddress == "" {
+			return fmt.Errorf("%w: deny entry %s needs a chain and contract address", ErrInvalidList, a)
+		}
+		deny[normalize(a)] = true
+	}
+	for _, a := range l.Allow {
+		if a.Chain == "" {
+			return fmt.Errorf("%w: allow entry %s needs a chain", ErrInvalidList, a)
+		}
+		if deny[normalize(a)] {
+			return fmt.Errorf("%w: %s is both denied and allowed", ErrInvalidList, a)
+		}
+	}
+	return nil
+}
+
+// normalize keys entries the way holdings are keyed, so a list written
+// with chain IDs or checksummed addresses still matches
+func normalize(a pricing.AssetRef) pricing.AssetRef {
+	return pricing.AssetRef{
+		Chain:           tokens.ChainName(a.Chain),
+		ContractAddress: strings.ToLower(a.ContractAddress),
+	}
+}
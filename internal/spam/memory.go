@@ -0,0 +1,57 @@
+package spam
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+type memoryOverrideRepository struct {
+	mu   sync.RWMutex
+	data map[string]map[pricing.AssetRef]Override
+}
+
+func NewMemoryOverrideRepository() OverrideRepository {
+	return &memoryOverrideRepository{data: make(map[string]map[pricing.AssetRef]Override)}
+}
+
+func (r *memoryOverrideRepository) List(ctx context.Context, wallet string) ([]Override, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]Override, 0, len(r.data[wallet]))
+	for _, o := range r.data[wallet] {
+		out = append(out, o)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Asset.Chain != out[j].Asset.Chain {
+			return out[i].Asset.Chain < out[j].Asset.Chain
+		}
+		return out[i].Asset.ContractAddress < out[j].Asset.ContractAddress
+	})
+	return out, nil
+}
+
+func (r *memoryOverrideRepository) Save(ctx context.Context, o Override) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.data[o.Wallet] == nil {
+		r.data[o.Wallet] = make(map[pricing.AssetRef]Override)
+	}
+	r.data[o.Wallet][o.Asset] = o
+	return nil
+}
+
+func (r *memoryOverrideRepository) Delete(ctx context.Context, wallet string, asset pricing.AssetRef) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.data[wallet][asset]; !ok {
+		return ErrOverrideMissing
+	}
+	delete(r.data[wallet], asset)
+	return nil
+}
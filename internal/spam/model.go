@@ -0,0 +1,55 @@
+package spam
+
+import (
+	"errors"
+	"time"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+var (
+	ErrInvalidOverride = errors.New("invalid spam override")
+	ErrOverrideMissing = errors.New("spam override not found")
+)
+
+// Reason says why a token was or was not judged spam
+type Reason string
+
+const (
+	ReasonOverride  Reason = "override"   // the wallet's own call
+	ReasonAllowList Reason = "allow_list" // configured never-spam list
+	ReasonDenyList  Reason = "deny_list"  // configured always-spam list
+	ReasonKnown     Reason = "known_token"
+
+	// heuristic signals
+	ReasonLookalike Reason = "lookalike_symbol"    // poses as a well-known token
+	ReasonNoPrice   Reason = "no_price"            // no market prices it
+	ReasonZeroValue Reason = "zero_value_transfer" // moved nothing, address-poisoning style
+)
+
+// Token is what the classifier knows about a token. Transfers and
+// ZeroValueTransfers count the wallet's transfers of it when known.
+type Token struct {
+	Asset              pricing.AssetRef
+	Symbol             string
+	Name               string
+	Transfers          int
+	ZeroValueTransfers int
+}
+
+// Verdict is the classifier's judgement of one token. Signals lists every
+// heuristic that fired, even when they were not enough to call it spam.
+type Verdict struct {
+	Spam    bool     `json:"spam"`
+	Reason  Reason   `json:"reason,omitempty"`
+	Signals []Reason `json:"signals,omitempty"`
+}
+
+// Override is a wallet's own verdict on a token, taking precedence over
+// the lists and heuristics
+type Override struct {
+	Wallet    string           `json:"wallet"`
+	Asset     pricing.AssetRef `json:"asset"`
+	Spam      bool             `json:"spam"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
@@ -0,0 +1,79 @@
+package spam
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+type postgresOverrideRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresOverrideRepository(pool *pgxpool.Pool) OverrideRepository {
+	return &postgresOverrideRepository{pool: pool}
+}
+
+func (r *postgresOverrideRepository) List(ctx context.Context, wallet string) ([]Override, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT wallet, chain, contract_address, spam, updated_at
+		FROM spam_overrides
+		WHERE wallet = $1
+		ORDER BY chain, contract_address`,
+		wallet,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]Override, 0)
+	for rows.Next() {
+		var o Override
+		if err := rows.Scan(
+			&o.Wallet,
+			&o.Asset.Chain,
+			&o.Asset.ContractAddress,
+			&o.Spam,
+			&o.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, o)
+	}
+	return out, rows.Err()
+}
+
+func (r *postgresOverrideRepository) Save(ctx context.Context, o Override) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO spam_overrides (wallet, chain, contract_address, spam, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (wallet, chain, contract_address) DO UPDATE SET
+			spam       = EXCLUDED.spam,
+			updated_at = EXCLUDED.updated_at`,
+		o.Wallet,
+		o.Asset.Chain,
+		o.Asset.ContractAddress,
+		o.Spam,
+		o.UpdatedAt,
+	)
+	return err
+}
+
+func (r *postgresOverrideRepository) Delete(ctx context.Context, wallet string, asset pricing.AssetRef) error {
+	tag, err := r.pool.Exec(ctx, `
+		DELETE FROM spam_overrides WHERE wallet = $1 AND chain = $2 AND contract_address = $3`,
+		wallet,
+		asset.Chain,
+		asset.ContractAddress,
+	)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrOverrideMissing
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package spam
+
+import (
+	"context"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+// OverrideRepository stores the overrides wallets set through the API
+type OverrideRepository interface {
+	List(ctx context.Context, wallet string) ([]Override, error)
+	Save(ctx context.Context, o Override) error
+	Delete(ctx context.Context, wallet string, asset pricing.AssetRef) error
+}
@@ -0,0 +1,217 @@
+package spam
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+)
+
+type ServiceAPI interface {
+	// Classify judges each token for a wallet, keyed by the asset as given
+	Classify(ctx context.Context, wallet string, tokens []Token) map[pricing.AssetRef]Verdict
+	Overrides(ctx context.Context, wallet string) ([]Override, error)
+	SetOverride(ctx context.Context, wallet string, asset pricing.AssetRef, spam bool) (*Override, error)
+	DeleteOverride(ctx context.Context, wallet string, asset pricing.AssetRef) error
+}
+
+// Service judges tokens by, in order of precedence, the wallet's own
+// overrides, the configured allow and deny lists, and heuristics
+type Service struct {
+	deny      map[pricing.AssetRef]bool
+	allow     map[pricing.AssetRef]bool
+	overrides OverrideRepository
+	pricing   pricing.ServiceAPI
+	search    tokens.SearchAPI
+	logger    *zap.Logger
+}
+
+// NewService builds the classifier. Pricing and search are optional; the
+// heuristics that need them are skipped when they are nil.
+func NewService(
+	list List,
+	overrides OverrideRepository,
+	prices pricing.ServiceAPI,
+	search tokens.SearchAPI,
+	logger *zap.Logger,
+) *Service {
+	s := &Service{
+		deny:      make(map[pricing.AssetRef]bool, len(list.Deny)),
+		allow:     make(map[pricing.AssetRef]bool, len(list.Allow)),
+		overrides: overrides,
+		pricing:   prices,
+		search:    search,
+		logger:    logger.With(zap.String("service", "spam")),
+	}
+	for _, a := range list.Deny {
+		s.deny[normalize(a)] = true
+	}
+	for _, a := range list.Allow {
+		s.allow[normalize(a)] = true
+	}
+	return s
+}
+
+// Classify judges every token for the wallet. Native assets are never
+// spam. A token the lists and overrides say nothing about is spam when it
+// poses as a well-known token, or when at least two weaker signals fire.
+func (s *Service) Classify(ctx context.Context, wallet string, tokens []Token) map[pricing.AssetRef]Verdict {
+	out := make(map[pricing.AssetRef]Verdict, len(tokens))
+
+	overrides := make(map[pricing.AssetRef]bool)
+	list, err := s.overrides.List(ctx, strings.ToLower(wallet))
+	if err != nil {
+		s.logger.Warn("list-spam-overrides-failed", zap.String("wallet", wallet), zap.Error(err))
+	}
+	for _, o := range list {
+		overrides[o.Asset] = o.Spam
+	}
+
+	var pending []Token
+	for _, t := range tokens {
+		asset := normalize(t.Asset)
+		if asset.ContractAddress == "" {
+			out[t.Asset] = Verdict{}
+			continue
+		}
+		if spam, ok := overrides[asset]; ok {
+			out[t.Asset] = Verdict{Spam: spam, Reason: ReasonOverride}
+			continue
+		}
+		switch {
+		case s.allow[asset]:
+			out[t.Asset] = Verdict{Reason: ReasonAllowList}
+		case s.deny[asset]:
+			out[t.Asset] = Verdict{Spam: true, Reason: ReasonDenyList}
+		default:
+			pending = append(pending, t)
+		}
+	}
+	if len(pending) == 0 {
+		return out
+	}
+
+	priced := s.priced(ctx, pending)
+	for _, t := range pending {
+		out[t.Asset] = s.judge(ctx, t, priced)
+	}
+	return out
+}
+
+// judge applies the heuristics to a token no list or override covers
+func (s *Service) judge(ctx context.Context, t Token, priced map[pricing.AssetRef]bool) Verdict {
+	asset := normalize(t.Asset)
+
+	var signals []Reason
+	switch known, lookalike := s.matchKnown(ctx, asset, t.Symbol); {
+	case known:
+		return Verdict{Reason: ReasonKnown}
+	case lookalike:
+		signals = append(signals, ReasonLookalike)
+	}
+	if priced != nil && !priced[asset] {
+		signals = append(signals, ReasonNoPrice)
+	}
+	if t.Transfers > 0 && t.ZeroValueTransfers == t.Transfers {
+		signals = append(signals, ReasonZeroValue)
+	}
+
+	v := Verdict{Signals: signals}
+	if len(signals) > 0 && (signals[0] == ReasonLookalike || len(signals) >= 2) {
+		v.Spam = true
+		v.Reason = signals[0]
+	}
+	return v
+}
+
+// matchKnown looks the symbol up in the coin list. The token is known when
+// the list has it at this contract, and a lookalike when its folded symbol
+// belongs to a well-known coin deployed elsewhere on the chain.
+func (s *Service) matchKnown(ctx context.Context, asset pricing.AssetRef, symbol string) (bool, bool) {
+	folded := foldSymbol(symbol)
+	if s.search == nil || folded == "" {
+		return false, false
+	}
+
+	results, err := s.search.Search(ctx, folded, asset.Chain, 20)
+	if err != nil {
+		// the index may still be loading; judge on the other signals
+		return false, false
+	}
+
+	lookalike := false
+	for _, r := range results {
+		if r.ContractAddress == asset.ContractAddress {
+			return true, false
+		}
+		if strings.ToUpper(r.Symbol) == folded && r.MarketCapRank > 0 && r.MarketCapRank <= knownRankCutoff {
+			lookalike = true
+		}
+	}
+	return false, lookalike
+}
+
+// priced reports which tokens have a market price, or nil when prices
+// could not be fetched at all
+func (s *Service) priced(ctx context.Context, pending []Token) map[pricing.AssetRef]bool {
+	if s.pricing == nil {
+		return nil
+	}
+	refs := make([]pricing.AssetRef, len(pending))
+	for i, t := range pending {
+		refs[i] = normalize(t.Asset)
+	}
+
+	prices, err := s.pricing.GetPrices(ctx, refs)
+	if err != nil {
+		s.logger.Warn("spam-prices-failed", zap.Error(err))
+		return nil
+	}
+
+	out := make(map[pricing.AssetRef]bool, len(prices))
+	for a, p := range prices {
+		out[a] = p > 0
+	}
+	return out
+}
+
+// Overrides lists the wallet's own verdicts
+func (s *Service) Overrides(ctx context.Context, wallet string) ([]Override, error) {
+	return s.overrides.List(ctx, strings.ToLower(wallet))
+}
+
+// SetOverride records the wallet's verdict on a token, replacing any
+// earlier one
+func (s *Service) SetOverride(ctx context.Context, wallet string, asset pricing.AssetRef, spam bool) (*Override, error) {
+	asset = normalize(asset)
+	if wallet == "" || asset.Chain == "" || asset.ContractAddress == "" {
+		return nil, ErrInvalidOverride
+	}
+
+	o := Override{
+		Wallet:    strings.ToLower(wallet),
+		Asset:     asset,
+		Spam:      spam,
+		UpdatedAt: time.Now().UTC(),
+	}
+	if err := s.overrides.Save(ctx, o); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("spam-override-set",
+		zap.String("wallet", wallet),
+		zap.String("asset", asset.String()),
+		zap.Bool("spam", spam),
+	)
+	return &o, nil
+}
+
+// DeleteOverride removes the wallet's verdict so the token is judged by
+// the lists and heuristics again
+func (s *Service) DeleteOverride(ctx context.Context, wallet string, asset pricing.AssetRef) error {
+	return s.overrides.Delete(ctx, strings.ToLower(wallet), normalize(asset))
+}
@@ -0,0 +1,148 @@
+package spam
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+)
+
+const (
+	wallet = "0x00000000000000000000000000000000000000aa"
+	usdc   = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+)
+
+var (
+	denied   = pricing.AssetRef{Chain: "1", ContractAddress: "0x0000000000000000000000000000000000000BAD"}
+	allowed  = pricing.AssetRef{Chain: "ethereum", ContractAddress: usdc}
+	fakeUSDC = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xfake"}
+	link     = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0x514910771af9ca656af840dff83e8264ecf986ca"}
+	obscure  = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xobscure"}
+	dropped  = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xdropped"}
+)
+
+type fakePricing struct {
+	pricing.ServiceAPI
+	prices map[pricing.AssetRef]float64
+}
+
+func (f *fakePricing) GetPrices(ctx context.Context, assets []pricing.AssetRef) (map[pricing.AssetRef]float64, error) {
+	return f.prices, nil
+}
+
+type fakeSearch struct {
+	results []tokens.SearchResult
+}
+
+func (f *fakeSearch) Search(ctx context.Context, query string, chain string, limit int) ([]tokens.SearchResult, error) {
+	var out []tokens.SearchResult
+	for _, r := range f.results {
+		if r.Symbol == query && r.Chain == chain {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func exampleList(t *testing.T) List {
+	_, file, _, _ := runtime.Caller(0)
+	list, err := LoadList(filepath.Join(filepath.Dir(file), "../../config/spam_list.example.json"))
+	require.NoError(t, err)
+	return list
+}
+
+func newTestService(t *testing.T) *Service {
+	prices := &fakePricing{prices: map[pricing.AssetRef]float64{
+		allowed: 1,
+		link:    15,
+	}}
+	search := &fakeSearch{results: []tokens.SearchResult{
+		{Symbol: "USDC", Chain: "ethereum", ContractAddress: usdc, MarketCapRank: 7},
+		{Symbol: "LINK", Chain: "ethereum", ContractAddress: link.ContractAddress, MarketCapRank: 15},
+	}}
+	return NewService(exampleList(t), NewMemoryOverrideRepository(), prices, search, zap.NewNop())
+}
+
+func TestClassify(t *testing.T) {
+	svc := newTestService(t)
+
+	verdicts := svc.Classify(context.Background(), wallet, []Token{
+		{Asset: denied},
+		{Asset: allowed, Symbol: "USDC"},
+		{Asset: fakeUSDC, Symbol: "UЅDС"}, // Cyrillic S and C
+		{Asset: link, Symbol: "LINK"},
+		{Asset: obscure, Symbol: "OBS", Transfers: 3},
+		{Asset: dropped, Symbol: "DROP", Transfers: 2, ZeroValueTransfers: 2},
+		{Asset: pricing.AssetRef{Chain: "ethereum"}, Symbol: "ETH"},
+	})
+
+	require.Equal(t, Verdict{Spam: true, Reason: ReasonDenyList}, verdicts[denied])
+	require.Equal(t, Verdict{Reason: ReasonAllowList}, verdicts[allowed])
+	require.Equal(t, Verdict{Spam: true, Reason: ReasonLookalike, Signals: []Reason{ReasonLookalike, ReasonNoPrice}}, verdicts[fakeUSDC])
+	require.Equal(t, Verdict{Reason: ReasonKnown}, verdicts[link])
+
+	// one weak signal is not enough
+	require.Equal(t, Verdict{Signals: []Reason{ReasonNoPrice}}, verdicts[obscure])
+	require.Equal(t, Verdict{Spam: true, Reason: ReasonNoPrice, Signals: []Reason{ReasonNoPrice, ReasonZeroValue}}, verdicts[dropped])
+
+	require.False(t, verdicts[pricing.AssetRef{Chain: "ethereum"}].Spam)
+}
+
+func TestOverrides(t *testing.T) {
+	svc := newTestService(t)
+	ctx := context.Background()
+
+	_, err := svc.SetOverride(ctx, wallet, pricing.AssetRef{Chain: "ethereum"}, true)
+	require.ErrorIs(t, err, ErrInvalidOverride)
+
+	_, err = svc.SetOverride(ctx, "0x00000000000000000000000000000000000000AA", dropped, false)
+	require.NoError(t, err)
+	_, err = svc.SetOverride(ctx, wallet, pricing.AssetRef{Chain: "ethereum", ContractAddress: usdc}, true)
+	require.NoError(t, err)
+
+	overrides, err := svc.Overrides(ctx, wallet)
+	require.NoError(t, err)
+	require.Len(t, overrides, 2)
+
+	verdicts := svc.Classify(ctx, wallet, []Token{
+		{Asset: dropped, Transfers: 2, ZeroValueTransfers: 2},
+		{Asset: allowed},
+	})
+	require.Equal(t, Verdict{Reason: ReasonOverride}, verdicts[dropped])
+	require.Equal(t, Verdict{Spam: true, Reason: ReasonOverride}, verdicts[allowed])
+
+	// another wallet is unaffected
+	require.Equal(t, Verdict{Reason: ReasonAllowList}, svc.Classify(ctx, "0xother", []Token{{Asset: allowed}})[allowed])
+
+	require.NoError(t, svc.DeleteOverride(ctx, wallet, dropped))
+	require.ErrorIs(t, svc.DeleteOverride(ctx, wallet, dropped), ErrOverrideMissing)
+}
+
+func TestFoldSymbol(t *testing.T) {
+	require.Equal(t, "USDT", foldSymbol("usdt"))
+	require.Equal(t, "USDC", foldSymbol("U5DС"))
+	require.Equal(t, "ETH", foldSymbol("Ｅ Ｔ Ｈ"))
+	require.Equal(t, "WBTC", foldSymbol("W\u200bBTC"))
+}
+
+func TestZeroValueTransfer(t *testing.T) {
+	require.True(t, ZeroValueTransfer(wallet, "0xattacker", "", 0))
+	require.False(t, ZeroValueTransfer(wallet, wallet, "", 0))
+	require.False(t, ZeroValueTransfer(wallet, "0xowner", "execTransaction(address to)", 0))
+	require.False(t, ZeroValueTransfer(wallet, "0xfriend", "", 1))
+}
+
+func TestLoadList_RejectsConflicts(t *testing.T) {
+	a := pricing.AssetRef{Chain: "ethereum", ContractAddress: usdc}
+	list := List{Deny: []pricing.AssetRef{a}, Allow: []pricing.AssetRef{a}}
+	require.ErrorIs(t, list.validate(), ErrInvalidList)
+
+	list = List{Deny: []pricing.AssetRef{{Chain: "ethereum"}}}
+	require.ErrorIs(t, list.validate(), ErrInvalidList)
+}
@@ -0,0 +1,264 @@
+package streaming
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+// subscriberBuffer is how many updates may queue up for a slow client
+// before further updates are dropped
+const subscriberBuffer = 16
+
+var ErrInvalidMessage = errors.New("invalid subscription message")
+
+// Hub runs a single refresh loop for every connected client. Each interval it
+// prices the union of all subscribed assets once and fans the results out.
+type Hub struct {
+	pricing   pricing.ServiceAPI
+	portfolio portfolio.Service
+	interval  time.Duration
+	logger    *zap.Logger
+
+	mu          sync.RWMutex
+	subscribers map[*Subscriber]struct{}
+}
+
+func NewHub(
+	pricing pricing.ServiceAPI,
+	portfolio portfolio.Service,
+	interval time.Duration,
+	logger *zap.Logger,
+) *Hub {
+	return &Hub{
+		pricing:     pricing,
+		portfolio:   portfolio,
+		interval:    interval,
+		logger:      logger.With(zap.String("service", "streaming")),
+		subscribers: make(map[*Subscriber]struct{}),
+	}
+}
+
+// Subscriber is one connected client and what it is watching
+type Subscriber struct {
+	updates chan Update
+
+	mu      sync.Mutex
+	assets  map[pricing.AssetRef]struct{}
+	wallets map[string]struct{}
+}
+
+// Updates is closed when the subscriber is unregistered
+func (s *Subscriber) Updates() <-chan Update {
+	return s.updates
+}
+
+// Handle applies a client message to the subscription set
+func (s *Subscriber) Handle(msg ClientMessage) error {
+	if len(msg.Assets) == 0 && msg.Wallet == "" {
+		return ErrInvalidMessage
+	}
+	// an empty contract address is the chain's native asset
+	for _, a := range msg.Assets {
+		if a.Chain == "" {
+			return ErrInvalidMessage
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch msg.Action {
+	case ActionSubscribe:
+		for _, a := range msg.Assets {
+			s.assets[a.ToAssetRef()] = struct{}{}
+		}
+		if msg.Wallet != "" {
+			s.wallets[msg.Wallet] = struct{}{}
+		}
+	case ActionUnsubscribe:
+		for _, a := range msg.Assets {
+			delete(s.assets, a.ToAssetRef())
+		}
+		delete(s.wallets, msg.Wallet)
+	default:
+		return ErrInvalidMessage
+	}
+
+	return nil
+}
+
+func (s *Subscriber) snapshot() ([]pricing.AssetRef, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	assets := make([]pricing.AssetRef, 0, len(s.assets))
+	for a := range s.assets {
+		assets = append(assets, a)
+	}
+	wallets := make([]string, 0, len(s.wallets))
+	for w := range s.wallets {
+		wallets = append(wallets, w)
+	}
+	return assets, wallets
+}
+
+func (h *Hub) Register() *Subscriber {
+	s := &Subscriber{
+		updates: make(chan Update, subscriberBuffer),
+		assets:  make(map[pricing.AssetRef]struct{}),
+		wallets: make(map[string]struct{}),
+	}
+
+	h.mu.Lock()
+	h.subscribers[s] = struct{}{}
+	h.mu.Unlock()
+
+	h.logger.Info("subscriber-registered")
+	return s
+}
+
+func (h *Hub) Unregister(s *Subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subscribers[s]; !ok {
+		return
+	}
+	delete(h.subscribers, s)
+	close(s.updates)
+
+	h.logger.Info("subscriber-unregistered")
+}
+
+// Reject tells the subscriber its last message was not applied
+func (h *Hub) Reject(s *Subscriber, err error) {
+	h.push(s, Update{Type: UpdateError, Timestamp: time.Now().UTC(), Error: err.Error()})
+}
+
+// Run refreshes on every tick until ctx is cancelled. It returns at once
+// when the interval is not positive, which turns live updates off.
+func (h *Hub) Run(ctx context.Context) {
+	if h.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			h.Refresh(ctx)
+		}
+	}
+}
+
+type subscription struct {
+	subscriber *Subscriber
+	assets     []pricing.AssetRef
+	wallets    []string
+}
+
+// Refresh prices every subscribed asset once and pushes updates to all subscribers
+func (h *Hub) Refresh(ctx context.Context) {
+	h.mu.RLock()
+	subs := make([]subscription, 0, len(h.subscribers))
+	for s := range h.subscribers {
+		assets, wallets := s.snapshot()
+		subs = append(subs, subscription{subscriber: s, assets: assets, wallets: wallets})
+	}
+	h.mu.RUnlock()
+
+	if len(subs) == 0 {
+		return
+	}
+
+	// resolve each wallet's holdings once, however many clients watch it
+	holdings := make(map[string][]portfolio.Holding)
+	unique := make(map[pricing.AssetRef]struct{})
+
+	for _, sub := range subs {
+		for _, a := range sub.assets {
+			unique[a] = struct{}{}
+		}
+		for _, w := range sub.wallets {
+			if _, ok := holdings[w]; ok {
+				continue
+			}
+			hs, err := h.portfolio.Holdings(ctx, w)
+			if err != nil {
+				h.logger.Warn("stream-holdings-failed", zap.String("wallet", w), zap.Error(err))
+				hs = nil
+			}
+			holdings[w] = hs
+			for _, hd := range hs {
+				unique[hd.AssetRef()] = struct{}{}
+			}
+		}
+	}
+
+	if len(unique) == 0 {
+		return
+	}
+
+	assets := make([]pricing.AssetRef, 0, len(unique))
+	for a := range unique {
+		assets = append(assets, a)
+	}
+
+	now := time.Now().UTC()
+
+	market, err := h.pricing.GetMarketData(ctx, assets)
+	if err != nil {
+		h.logger.Error("stream-refresh-failed", zap.Error(err))
+		for _, sub := range subs {
+			h.push(sub.subscriber, Update{Type: UpdateError, Timestamp: now, Error: "failed to refresh prices"})
+		}
+		return
+	}
+
+	for _, sub := range subs {
+		if len(sub.assets) > 0 {
+			prices := make(map[string]pricing.MarketData, len(sub.assets))
+			for _, a := range sub.assets {
+				if md, ok := market[a]; ok {
+					prices[a.Chain+":"+a.ContractAddress] = md
+				}
+			}
+			h.push(sub.subscriber, Update{Type: UpdatePrices, Timestamp: now, Prices: prices})
+		}
+
+		for _, w := range sub.wallets {
+			h.push(sub.subscriber, Update{
+				Type:      UpdatePortfolio,
+				Timestamp: now,
+				Portfolio: portfolio.NewView(w, holdings[w], market),
+			})
+		}
+	}
+}
+
+// push never blocks the refresh loop; a client that falls behind misses updates
+func (h *Hub) push(s *Subscriber, u Update) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if _, ok := h.subscribers[s]; !ok {
+		return
+	}
+
+	select {
+	case s.updates <- u:
+	default:
+		h.logger.Warn("subscriber-too-slow-dropping-update")
+	}
+}
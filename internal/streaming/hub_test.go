@@ -0,0 +1,153 @@
+package streaming
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+type fakePricing struct {
+	pricing.ServiceAPI
+	market map[pricing.AssetRef]pricing.MarketData
+	calls  int
+	asked  []pricing.AssetRef
+}
+
+func (f *fakePricing) GetMarketData(ctx context.Context, assets []pricing.AssetRef) (map[pricing.AssetRef]pricing.MarketData, error) {
+	f.calls++
+	f.asked = assets
+	return f.market, nil
+}
+
+type fakePortfolio struct {
+	portfolio.Service
+	holdings map[string][]portfolio.Holding
+}
+
+func (f *fakePortfolio) Holdings(ctx context.Context, wallet string) ([]portfolio.Holding, error) {
+	return f.holdings[wallet], nil
+}
+
+var (
+	eth  = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xeth"}
+	usdc = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xusdc"}
+)
+
+func setupHub() (*Hub, *fakePricing) {
+	prices := &fakePricing{
+		market: map[pricing.AssetRef]pricing.MarketData{
+			eth:  {PriceUSD: 2000},
+			usdc: {PriceUSD: 1},
+		},
+	}
+	pf := &fakePortfolio{
+		holdings: map[string][]portfolio.Holding{
+			"wallet1": {
+				{Chain: "ethereum", ContractAddress: "0xeth", Amount: 2},
+				{Chain: "ethereum", ContractAddress: "0xusdc", Amount: 100},
+			},
+		},
+	}
+	return NewHub(prices, pf, time.Second, zap.NewNop()), prices
+}
+
+func TestHub_RefreshFansOutSingleFetch(t *testing.T) {
+	hub, prices := setupHub()
+
+	a := hub.Register()
+	b := hub.Register()
+
+	require.NoError(t, a.Handle(ClientMessage{
+		Action: ActionSubscribe,
+		Assets: []AssetMessage{{Chain: "ethereum", ContractAddress: "0xeth"}},
+	}))
+	require.NoError(t, b.Handle(ClientMessage{Action: ActionSubscribe, Wallet: "wallet1"}))
+
+	hub.Refresh(context.Background())
+
+	// one pricing call covering the union of both subscriptions
+	require.Equal(t, 1, prices.calls)
+	require.ElementsMatch(t, []pricing.AssetRef{eth, usdc}, prices.asked)
+
+	update := <-a.Updates()
+	require.Equal(t, UpdatePrices, update.Type)
+	require.Equal(t, 2000.0, update.Prices["ethereum:0xeth"].PriceUSD)
+	require.Len(t, update.Prices, 1)
+
+	update = <-b.Updates()
+	require.Equal(t, UpdatePortfolio, update.Type)
+	require.Equal(t, 4100.0, update.Portfolio.TotalValueUSD)
+}
+
+func TestHub_Unsubscribe(t *testing.T) {
+	hub, prices := setupHub()
+
+	s := hub.Register()
+	require.NoError(t, s.Handle(ClientMessage{Action: ActionSubscribe, Wallet: "wallet1"}))
+	require.NoError(t, s.Handle(ClientMessage{Action: ActionUnsubscribe, Wallet: "wallet1"}))
+
+	hub.Refresh(context.Background())
+
+	require.Equal(t, 0, prices.calls)
+	require.Len(t, s.Updates(), 0)
+}
+
+func TestHub_UnregisterClosesUpdates(t *testing.T) {
+	hub, _ := setupHub()
+
+	s := hub.Register()
+	hub.Unregister(s)
+	hub.Unregister(s)
+
+	_, ok := <-s.Updates()
+	require.False(t, ok)
+}
+
+func TestSubscriber_HandleRejectsInvalid(t *testing.T) {
+	hub, _ := setupHub()
+	s := hub.Register()
+
+	require.ErrorIs(t, s.Handle(ClientMessage{Action: ActionSubscribe}), ErrInvalidMessage)
+	require.ErrorIs(t, s.Handle(ClientMessage{Action: "bogus", Wallet: "w"}), ErrInvalidMessage)
+	require.ErrorIs(t, s.Handle(ClientMessage{
+		Action: ActionSubscribe,
+		Assets: []AssetMessage{{ContractAddress: "0xeth"}},
+	}), ErrInvalidMessage)
+}
+
+func TestSubscriber_HandleNativeAndMixedCase(t *testing.T) {
+	hub, _ := setupHub()
+	s := hub.Register()
+
+	require.NoError(t, s.Handle(ClientMessage{
+		Action: ActionSubscribe,
+		Assets: []AssetMessage{{Chain: "ethereum"}, {Chain: "ethereum", ContractAddress: "0xUSDC"}},
+	}))
+
+	assets, _ := s.snapshot()
+	require.ElementsMatch(t, []pricing.AssetRef{{Chain: "ethereum"}, usdc}, assets)
+}
+
+func TestHub_RejectSendsError(t *testing.T) {
+	hub, _ := setupHub()
+	s := hub.Register()
+
+	hub.Reject(s, ErrInvalidMessage)
+
+	update := <-s.Updates()
+	require.Equal(t, UpdateError, update.Type)
+	require.Equal(t, ErrInvalidMessage.Error(), update.Error)
+}
+
+func TestHub_RunOffWithoutInterval(t *testing.T) {
+	hub := NewHub(&fakePricing{}, &fakePortfolio{}, 0, zap.NewNop())
+
+	// returns at once instead of panicking in time.NewTicker
+	hub.Run(context.Background())
+}
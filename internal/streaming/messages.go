@@ -0,0 +1,55 @@
+package streaming
+
+import (
+	"strings"
+	"time"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/portfolio"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+type Action string
+
+const (
+	ActionSubscribe   Action = "subscribe"
+	ActionUnsubscribe Action = "unsubscribe"
+)
+
+// ClientMessage is sent by clients to change what they are subscribed to.
+// A message may carry assets, a wallet, or both.
+type ClientMessage struct {
+	Action Action         `json:"action"`
+	Assets []AssetMessage `json:"assets,omitempty"`
+	Wallet string         `json:"wallet,omitempty"`
+}
+
+type AssetMessage struct {
+	Chain           string `json:"chain"`
+	ContractAddress string `json:"contract_address"`
+}
+
+// ToAssetRef lowercases the contract address so every spelling of an
+// address shares one subscription and one price
+func (a AssetMessage) ToAssetRef() pricing.AssetRef {
+	return pricing.AssetRef{
+		Chain:           a.Chain,
+		ContractAddress: strings.ToLower(a.ContractAddress),
+	}
+}
+
+type UpdateType string
+
+const (
+	UpdatePrices    UpdateType = "prices"
+	UpdatePortfolio UpdateType = "portfolio"
+	UpdateError     UpdateType = "error"
+)
+
+// Update is pushed to subscribers after every refresh
+type Update struct {
+	Type      UpdateType                    `json:"type"`
+	Timestamp time.Time                     `json:"timestamp"`
+	Prices    map[string]pricing.MarketData `json:"prices,omitempty"`
+	Portfolio *portfolio.PortfolioView      `json:"portfolio,omitempty"`
+	Error     string                        `json:"error,omitempty"`
+}
@@ -0,0 +1,61 @@
+package tax
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+// form8949Date is the MM/DD/YYYY layout Form 8949 uses
+const form8949Date = "01/02/2006"
+
+var csvHeader = []string{
+	"Description of property",
+	"Date acquired",
+	"Date sold or disposed of",
+	"Proceeds",
+	"Cost or other basis",
+	"Gain or (loss)",
+	"Term",
+	"Chain",
+	"Contract address",
+	"Transaction hash",
+}
+
+// WriteCSV writes rows in the column order of Form 8949. Rows with no
+// acquisition on record have an acquired date of UNKNOWN.
+func WriteCSV(w io.Writer, rows []Row) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, r := range rows {
+		acquired := r.AcquiredAt.UTC().Format(form8949Date)
+		if r.Unmatched {
+			acquired = "UNKNOWN"
+		}
+		record := []string{
+			r.Description,
+			acquired,
+			r.DisposedAt.UTC().Format(form8949Date),
+			usd(r.ProceedsUSD),
+			usd(r.CostBasisUSD),
+			usd(r.GainUSD),
+			string(r.Term),
+			r.Asset.Chain,
+			r.Asset.ContractAddress,
+			r.DisposeHash,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func usd(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
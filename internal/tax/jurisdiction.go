@@ -0,0 +1,96 @@
+package tax
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnknownJurisdiction = errors.New("unknown jurisdiction")
+	ErrInvalidYear         = errors.New("invalid tax year")
+)
+
+// Term is the holding-period class of a disposal
+type Term string
+
+const (
+	TermShort Term = "short"
+	TermLong  Term = "long"
+)
+
+// Jurisdiction decides when a holding becomes long-term. The holding
+// period is measured as calendar years, months and days added to the
+// acquisition date.
+type Jurisdiction struct {
+	Code   string `json:"code"`
+	Name   string `json:"name"`
+	Years  int    `json:"years"`
+	Months int    `json:"months"`
+	Days   int    `json:"days"`
+	// Inclusive makes a disposal on the anniversary long-term; otherwise
+	// the asset must be held for more than the period
+	Inclusive bool   `json:"inclusive"`
+	Note      string `json:"note"`
+}
+
+var jurisdictions = map[string]Jurisdiction{
+	"us": {
+		Code: "us", Name: "United States", Years: 1,
+		Note: "long-term when held more than one year (Form 8949 Part II)",
+	},
+	"de": {
+		Code: "de", Name: "Germany", Years: 1,
+		Note: "private sales held more than one year are tax-free (§ 23 EStG)",
+	},
+	"au": {
+		Code: "au", Name: "Australia", Months: 12, Inclusive: true,
+		Note: "held at least 12 months qualifies for the CGT discount",
+	},
+	"pt": {
+		Code: "pt", Name: "Portugal", Days: 365, Inclusive: true,
+		Note: "held 365 days or more is exempt",
+	},
+}
+
+// DefaultJurisdiction is used when none is requested or configured
+const DefaultJurisdiction = "us"
+
+func LookupJurisdiction(code string) (Jurisdiction, error) {
+	if code == "" {
+		code = DefaultJurisdiction
+	}
+	j, ok := jurisdictions[strings.ToLower(code)]
+	if !ok {
+		return Jurisdiction{}, ErrUnknownJurisdiction
+	}
+	return j, nil
+}
+
+// Jurisdictions lists the supported rules by code
+func Jurisdictions() []Jurisdiction {
+	out := make([]Jurisdiction, 0, len(jurisdictions))
+	for _, j := range jurisdictions {
+		out = append(out, j)
+	}
+	sort.Slice(out, func(i, k int) bool { return out[i].Code < out[k].Code })
+	return out
+}
+
+// Classify returns the term of an asset held from acquired to disposed.
+// Holding periods count whole days, so times of day are ignored.
+func (j Jurisdiction) Classify(acquired, disposed time.Time) Term {
+	threshold := dateOf(acquired).AddDate(j.Years, j.Months, j.Days)
+	disposed = dateOf(disposed)
+
+	if disposed.After(threshold) || (j.Inclusive && disposed.Equal(threshold)) {
+		return TermLong
+	}
+	return TermShort
+}
+
+func dateOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,52 @@
+package tax
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func date(y int, m time.Month, d, h int) time.Time {
+	return time.Date(y, m, d, h, 0, 0, 0, time.UTC)
+}
+
+func TestJurisdiction_Classify(t *testing.T) {
+	acquired := date(2024, time.March, 15, 18)
+
+	cases := []struct {
+		code     string
+		disposed time.Time
+		term     Term
+	}{
+		// more than one year: the anniversary itself is still short-term
+		{"us", date(2025, time.March, 15, 23), TermShort},
+		{"us", date(2025, time.March, 16, 1), TermLong},
+		{"de", date(2025, time.March, 15, 12), TermShort},
+		{"de", date(2025, time.March, 16, 0), TermLong},
+		// at least 12 months: the anniversary counts
+		{"au", date(2025, time.March, 15, 0), TermLong},
+		{"au", date(2025, time.March, 14, 23), TermShort},
+		{"pt", date(2025, time.March, 15, 0), TermLong},
+		{"pt", date(2025, time.March, 14, 0), TermShort},
+	}
+
+	for _, tc := range cases {
+		j, err := LookupJurisdiction(tc.code)
+		require.NoError(t, err)
+		require.Equal(t, tc.term, j.Classify(acquired, tc.disposed), "%s disposed %s", tc.code, tc.disposed)
+	}
+}
+
+func TestLookupJurisdiction(t *testing.T) {
+	j, err := LookupJurisdiction("")
+	require.NoError(t, err)
+	require.Equal(t, "us", j.Code)
+
+	j, err = LookupJurisdiction("DE")
+	require.NoError(t, err)
+	require.Equal(t, "de", j.Code)
+
+	_, err = LookupJurisdiction("xx")
+	require.ErrorIs(t, err, ErrUnknownJurisdiction)
+}
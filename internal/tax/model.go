@@ -0,0 +1,53 @@
+package tax
+
+import (
+	"time"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pnl"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+// Query selects the wallet's chain, lot matching method, jurisdiction and
+// tax year
+type Query struct {
+	Chain        string
+	Method       pnl.Method
+	Jurisdiction string
+	Year         int
+}
+
+// Row is one disposal line, matched against a single acquisition lot
+type Row struct {
+	Description  string           `json:"description"`
+	Asset        pricing.AssetRef `json:"asset"`
+	Quantity     float64          `json:"quantity"`
+	AcquiredAt   time.Time        `json:"acquired_at"`
+	DisposedAt   time.Time        `json:"disposed_at"`
+	ProceedsUSD  float64          `json:"proceeds_usd"`
+	CostBasisUSD float64          `json:"cost_basis_usd"`
+	GainUSD      float64          `json:"gain_usd"`
+	Term         Term             `json:"term"`
+	Unmatched    bool             `json:"unmatched,omitempty"` // no acquisition on record; zero basis
+	DisposeHash  string           `json:"dispose_hash"`
+}
+
+// Summary adds up a tax year's disposals by term
+type Summary struct {
+	Wallet       string       `json:"wallet"`
+	Chain        string       `json:"chain"`
+	Year         int          `json:"year"`
+	Method       pnl.Method   `json:"method"`
+	Jurisdiction Jurisdiction `json:"jurisdiction"`
+	ShortTerm    pnl.Totals   `json:"short_term"`
+	LongTerm     pnl.Totals   `json:"long_term"`
+	Total        pnl.Totals   `json:"total"`
+	Unmatched    int          `json:"unmatched"`          // rows with no acquisition on record
+	Unpriced     []string     `json:"unpriced,omitempty"` // transfers valued at zero
+	Truncated    bool         `json:"truncated,omitempty"`
+}
+
+// Report is a tax year's disposals and their summary
+type Report struct {
+	Summary Summary `json:"summary"`
+	Rows    []Row   `json:"rows"`
+}
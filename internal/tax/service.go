@@ -0,0 +1,154 @@
+package tax
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pnl"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+)
+
+type ServiceAPI interface {
+	Report(ctx context.Context, wallet string, q Query) (*Report, error)
+}
+
+// Service turns a tax year's realized gains into disposal rows classified
+// by holding period
+type Service struct {
+	pnl                 pnl.ServiceAPI
+	tokens              tokens.ServiceAPI
+	defaultJurisdiction string
+	logger              *zap.Logger
+}
+
+// Option configures optional collaborators on the tax service
+type Option func(*Service)
+
+// WithTokens names assets by symbol in row descriptions
+func WithTokens(t tokens.ServiceAPI) Option {
+	return func(s *Service) {
+		s.tokens = t
+	}
+}
+
+func NewService(gains pnl.ServiceAPI, defaultJurisdiction string, logger *zap.Logger, opts ...Option) *Service {
+	s := &Service{
+		pnl:                 gains,
+		defaultJurisdiction: defaultJurisdiction,
+		logger:              logger.With(zap.String("service", "tax")),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Report lists every disposal made during the tax year (UTC calendar year)
+// with its acquisition lot and term under the jurisdiction's rules
+func (s *Service) Report(ctx context.Context, wallet string, q Query) (*Report, error) {
+	if q.Jurisdiction == "" {
+		q.Jurisdiction = s.defaultJurisdiction
+	}
+	jurisdiction, err := LookupJurisdiction(q.Jurisdiction)
+	if err != nil {
+		return nil, err
+	}
+	if q.Year < 2009 || q.Year > time.Now().UTC().Year() {
+		return nil, ErrInvalidYear
+	}
+
+	s.logger.Info("tax-report",
+		zap.String("wallet", wallet),
+		zap.Int("year", q.Year),
+		zap.String("jurisdiction", jurisdiction.Code),
+	)
+
+	from := time.Date(q.Year, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(1, 0, 0).Add(-time.Nanosecond)
+
+	realized, err := s.pnl.Realized(ctx, wallet, pnl.Query{
+		Chain:   q.Chain,
+		Method:  q.Method,
+		GroupBy: pnl.GroupYear,
+		From:    &from,
+		To:      &to,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := s.symbols(ctx, realized.Realizations)
+
+	report := &Report{
+		Summary: Summary{
+			Wallet:       wallet,
+			Chain:        realized.Chain,
+			Year:         q.Year,
+			Method:       realized.Method,
+			Jurisdiction: jurisdiction,
+			Unpriced:     realized.Unpriced,
+			Truncated:    realized.Truncated,
+		},
+		Rows: make([]Row, 0, len(realized.Realizations)),
+	}
+
+	for _, r := range realized.Realizations {
+		row := Row{
+			Description:  describe(r, symbols),
+			Asset:        r.Asset,
+			Quantity:     r.Quantity,
+			AcquiredAt:   r.AcquiredAt,
+			DisposedAt:   r.DisposedAt,
+			ProceedsUSD:  r.ProceedsUSD,
+			CostBasisUSD: r.CostBasisUSD,
+			GainUSD:      r.GainUSD,
+			Term:         jurisdiction.Classify(r.AcquiredAt, r.DisposedAt),
+			Unmatched:    r.Unmatched,
+			DisposeHash:  r.DisposeHash,
+		}
+		report.Rows = append(report.Rows, row)
+
+		if row.Term == TermLong {
+			add(&report.Summary.LongTerm, row)
+		} else {
+			add(&report.Summary.ShortTerm, row)
+		}
+		add(&report.Summary.Total, row)
+		if row.Unmatched {
+			report.Summary.Unmatched++
+		}
+	}
+
+	return report, nil
+}
+
+func add(t *pnl.Totals, r Row) {
+	t.ProceedsUSD += r.ProceedsUSD
+	t.CostBasisUSD += r.CostBasisUSD
+	t.GainUSD += r.GainUSD
+	t.Disposals++
+}
+
+func (s *Service) symbols(ctx context.Context, realized []pnl.Realization) map[pricing.AssetRef]tokens.Metadata {
+	if s.tokens == nil || len(realized) == 0 {
+		return nil
+	}
+	refs := make([]pricing.AssetRef, 0, len(realized))
+	for _, r := range realized {
+		refs = append(refs, r.Asset)
+	}
+	return s.tokens.Lookup(ctx, refs)
+}
+
+// describe names the property sold the way Form 8949 expects, e.g. 1.5 ETH
+func describe(r pnl.Realization, symbols map[pricing.AssetRef]tokens.Metadata) string {
+	name := r.Asset.String()
+	if m, ok := symbols[r.Asset]; ok && m.Symbol != "" {
+		name = m.Symbol
+	}
+	return strconv.FormatFloat(r.Quantity, 'f', -1, 64) + " " + name
+}
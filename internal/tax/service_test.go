@@ -0,0 +1,177 @@
+package tax
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pnl"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
+)
+
+var eth = pricing.AssetRef{Chain: "ethereum"}
+
+type fakePnL struct {
+	query   pnl.Query
+	realize []pnl.Realization
+}
+
+func (f *fakePnL) Realized(ctx context.Context, wallet string, q pnl.Query) (*pnl.Report, error) {
+	f.query = q
+	return &pnl.Report{
+		Wallet:       wallet,
+		Chain:        q.Chain,
+		Method:       q.Method,
+		Realizations: f.realize,
+		Unpriced:     []string{"0xdead"},
+	}, nil
+}
+
+type fakeTokens struct {
+	tokens.ServiceAPI
+}
+
+func (fakeTokens) Lookup(ctx context.Context, assets []pricing.AssetRef) map[pricing.AssetRef]tokens.Metadata {
+	return map[pricing.AssetRef]tokens.Metadata{eth: {Symbol: "ETH"}}
+}
+
+func testRealizations() []pnl.Realization {
+	return []pnl.Realization{
+		{
+			Asset: eth, Quantity: 1.5,
+			AcquiredAt: date(2023, time.June, 1, 0), DisposedAt: date(2025, time.February, 1, 0),
+			ProceedsUSD: 4500, CostBasisUSD: 3000, GainUSD: 1500, DisposeHash: "0x1",
+		},
+		{
+			Asset: eth, Quantity: 0.5,
+			AcquiredAt: date(2024, time.December, 1, 0), DisposedAt: date(2025, time.March, 1, 0),
+			ProceedsUSD: 1000, CostBasisUSD: 1800, GainUSD: -800, DisposeHash: "0x2",
+		},
+		{
+			Asset: eth, Quantity: 0.25,
+			AcquiredAt: date(2025, time.April, 1, 0), DisposedAt: date(2025, time.April, 1, 0),
+			ProceedsUSD: 500, GainUSD: 500, DisposeHash: "0x3", Unmatched: true,
+		},
+	}
+}
+
+func TestService_Report(t *testing.T) {
+	gains := &fakePnL{realize: testRealizations()}
+	svc := NewService(gains, "us", zap.NewNop(), WithTokens(fakeTokens{}))
+
+	report, err := svc.Report(context.Background(), "0xabc", Query{Chain: "ethereum", Method: pnl.MethodHIFO, Year: 2025})
+	require.NoError(t, err)
+
+	require.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), *gains.query.From)
+	require.Equal(t, 2025, gains.query.To.Year())
+	require.Equal(t, 2026, gains.query.To.Add(time.Nanosecond).Year())
+
+	require.Len(t, report.Rows, 3)
+	require.Equal(t, "1.5 ETH", report.Rows[0].Description)
+	require.Equal(t, TermLong, report.Rows[0].Term)
+	require.Equal(t, TermShort, report.Rows[1].Term)
+	require.Equal(t, TermShort, report.Rows[2].Term)
+
+	s := report.Summary
+	require.Equal(t, "us", s.Jurisdiction.Code)
+	require.Equal(t, pnl.MethodHIFO, s.Method)
+	require.InDelta(t, 1500, s.LongTerm.GainUSD, 1e-9)
+	require.InDelta(t, -300, s.ShortTerm.GainUSD, 1e-9)
+	require.Equal(t, 2, s.ShortTerm.Disposals)
+	require.InDelta(t, 6000, s.Total.ProceedsUSD, 1e-9)
+	require.Equal(t, 1, s.Unmatched)
+	require.Equal(t, []string{"0xdead"}, s.Unpriced)
+}
+
+func TestService_ReportValidates(t *testing.T) {
+	svc := NewService(&fakePnL{}, "us", zap.NewNop())
+
+	_, err := svc.Report(context.Background(), "0xabc", Query{Year: 2025, Jurisdiction: "xx"})
+	require.ErrorIs(t, err, ErrUnknownJurisdiction)
+
+	_, err = svc.Report(context.Background(), "0xabc", Query{Year: time.Now().Year() + 1})
+	require.ErrorIs(t, err, ErrInvalidYear)
+}
+
+func TestWriteCSV(t *testing.T) {
+	svc := NewService(&fakePnL{realize: testRealizations()}, "us", zap.NewNop())
+	report, err := svc.Report(context.Background(), "0xabc", Query{Chain: "ethereum", Year: 2025})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, report.Rows))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	require.Equal(t, "Description of property", records[0][0])
+	require.Equal(t, []string{
+		"1.5 ethereum:", "06/01/2023", "02/01/2025", "4500.00", "3000.00", "1500.00", "long", "ethereum", "", "0x1",
+	}, records[1])
+	require.Equal(t, "-800.00", records[2][5])
+	require.Equal(t, "UNKNOWN", records[3][1])
+}
+
+type fakeTransfers struct {
+	txs       []transactions.Transaction
+	transfers []transactions.Transaction
+}
+
+func (f *fakeTransfers) List(ctx context.Context, chain, wallet string, page, limit int, filters transactions.Filters) ([]transactions.Transaction, error) {
+	if page > 1 {
+		return nil, nil
+	}
+	return f.txs, nil
+}
+
+func (f *fakeTransfers) TokenTransfers(ctx context.Context, chain, wallet string, page, limit int) ([]transactions.Transaction, error) {
+	if page > 1 {
+		return nil, nil
+	}
+	return f.transfers, nil
+}
+
+type fakeHistorical struct {
+	pricing.ServiceAPI
+}
+
+func (fakeHistorical) GetHistoricalPrices(ctx context.Context, assets []pricing.AssetRef, at time.Time) (map[pricing.AssetRef]pricing.HistoricalPrice, error) {
+	out := make(map[pricing.AssetRef]pricing.HistoricalPrice)
+	for _, a := range assets {
+		out[a] = pricing.HistoricalPrice{PriceUSD: float64(at.Month()), Time: at}
+	}
+	return out, nil
+}
+
+func TestService_ReportTokenDisposals(t *testing.T) {
+	transfer := func(hash string, at time.Time, from, to string, amount float64, dir transactions.Direction) transactions.Transaction {
+		return transactions.Transaction{
+			Hash: hash, Chain: "1", From: from, To: to, TokenAddr: "0x1f98", Amount: amount,
+			Type: transactions.TypeSend, Status: transactions.StatusSuccess, Direction: dir, Timestamp: at,
+		}
+	}
+	history := &fakeTransfers{transfers: []transactions.Transaction{
+		transfer("0x2", date(2025, time.May, 2, 0), "0xabc", "0xbob", 4, transactions.DirectionOut),
+		transfer("0x1", date(2025, time.February, 2, 0), "0xbob", "0xabc", 10, transactions.DirectionIn),
+	}}
+	gains := pnl.NewService(history, history, fakeHistorical{}, zap.NewNop())
+	svc := NewService(gains, "us", zap.NewNop())
+
+	report, err := svc.Report(context.Background(), "0xabc", Query{Chain: "ethereum", Method: pnl.MethodFIFO, Year: 2025})
+	require.NoError(t, err)
+
+	// 4 tokens bought at 2 and sold at 5
+	require.Len(t, report.Rows, 1)
+	require.Equal(t, pricing.AssetRef{Chain: "ethereum", ContractAddress: "0x1f98"}, report.Rows[0].Asset)
+	require.InDelta(t, 20, report.Rows[0].ProceedsUSD, 1e-9)
+	require.InDelta(t, 8, report.Rows[0].CostBasisUSD, 1e-9)
+	require.Equal(t, TermShort, report.Rows[0].Term)
+	require.Empty(t, report.Summary.Unpriced)
+}
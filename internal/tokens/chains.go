@@ -0,0 +1,86 @@
+package tokens
+
+import "strconv"
+
+// chain IDs as used by token lists, mapped to the coingecko platform names
+// used as chain identifiers everywhere else
+var chainNames = map[int]string{
+	1:     "ethereum",
+	10:    "optimistic-ethereum",
+	56:    "binance-smart-chain",
+	137:   "polygon-pos",
+	8453:  "base",
+	42161: "arbitrum-one",
+	43114: "avalanche",
+}
+
+// chainIDs and platformIDs are built from chainNames: chain ID (as a
+// string) to platform name and back
+var (
+	chainIDs    = make(map[string]string, len(chainNames))
+	platformIDs = make(map[string]string, len(chainNames))
+)
+
+func init() {
+	for id, name := range chainNames {
+		chainIDs[strconv.Itoa(id)] = name
+		platformIDs[name] = strconv.Itoa(id)
+	}
+}
+
+var natives = map[string]Metadata{
+	"ethereum":            {Symbol: "ETH", Name: "Ether", Decimals: 18},
+	"optimistic-ethereum": {Symbol: "ETH", Name: "Ether", Decimals: 18},
+	"base":                {Symbol: "ETH", Name: "Ether", Decimals: 18},
+	"arbitrum-one":        {Symbol: "ETH", Name: "Ether", Decimals: 18},
+	"binance-smart-chain": {Symbol: "BNB", Name: "BNB", Decimals: 18},
+	"polygon-pos":         {Symbol: "POL", Name: "Polygon Ecosystem Token", Decimals: 18},
+	"avalanche":           {Symbol: "AVAX", Name: "Avalanche", Decimals: 18},
+}
+
+// nativeCoins maps coingecko coin IDs to the chains they are the gas token of
+var nativeCoins = map[string][]string{
+	"ethereum":                {"ethereum", "optimistic-ethereum", "base", "arbitrum-one"},
+	"binancecoin":             {"binance-smart-chain"},
+	"polygon-ecosystem-token": {"polygon-pos"},
+	"avalanche-2":             {"avalanche"},
+}
+
+// ChainName resolves a numeric chain ID (as taken by etherscan) to its
+// platform name; anything else is returned unchanged
+func ChainName(chain string) string {
+	if name, ok := chainIDs[chain]; ok {
+		return name
+	}
+	return chain
+}
+
+// NativeMetadata describes the gas token of a chain
+func NativeMetadata(chain string) (Metadata, bool) {
+	m, ok := natives[ChainName(chain)]
+	if !ok {
+		return Metadata{}, false
+	}
+	m.Chain = chain
+	return m, true
+}
+
+// ChainID is the reverse of ChainName: the numeric chain ID etherscan takes
+// for a platform name
+func ChainID(chain string) (string, bool) {
+	id, ok := platformIDs[chain]
+	return id, ok
+}
+
+// NativeCoinID returns the coingecko coin ID of a chain's gas token
+func NativeCoinID(chain string) (string, bool) {
+	chain = ChainName(chain)
+	for id, chains := range nativeCoins {
+		for _, c := range chains {
+			if c == chain {
+				return id, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,32 @@
+package tokens
+
+import (
+	"strings"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+// Metadata describes a token so clients don't have to resolve raw addresses
+type Metadata struct {
+	Chain           string `json:"chain"`
+	ContractAddress string `json:"contract_address"` // empty for the native asset
+	Symbol          string `json:"symbol"`
+	Name            string `json:"name"`
+	Decimals        int    `json:"decimals"`
+	LogoURL         string `json:"logo_url,omitempty"`
+}
+
+func (m Metadata) AssetRef() pricing.AssetRef {
+	return pricing.AssetRef{
+		Chain:           m.Chain,
+		ContractAddress: m.ContractAddress,
+	}
+}
+
+// normalize maps chain IDs to platform names and lowercases the address so
+// registry, cache and provider lookups agree
+func normalize(asset pricing.AssetRef) pricing.AssetRef {
+	asset.Chain = ChainName(asset.Chain)
+	asset.ContractAddress = strings.ToLower(asset.ContractAddress)
+	return asset
+}
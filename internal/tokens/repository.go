@@ -0,0 +1,16 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+var ErrTokenNotFound = errors.New("token not found")
+
+// MetadataProvider resolves metadata from an external source
+type MetadataProvider interface {
+	GetMetadata(ctx context.Context, asset pricing.AssetRef) (*Metadata, error)
+	Name() string
+}
@@ -0,0 +1,278 @@
+package tokens
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/cache"
+)
+
+const coinsCacheKey = "tokens:coins"
+
+var (
+	ErrSearchNotReady = errors.New("token search index is not loaded yet")
+	ErrInvalidQuery   = errors.New("search query is required")
+)
+
+// Coin is one entry of the searchable coin list
+type Coin struct {
+	ID            string            `json:"id"`
+	Symbol        string            `json:"symbol"`
+	Name          string            `json:"name"`
+	MarketCapRank int               `json:"market_cap_rank,omitempty"` // 0 when unranked
+	Platforms     map[string]string `json:"platforms"`                 // chain -> contract address
+}
+
+// CoinListProvider returns the full list of known coins
+type CoinListProvider interface {
+	GetCoins(ctx context.Context) ([]Coin, error)
+	Name() string
+}
+
+// SearchResult is a token candidate on a single chain
+type SearchResult struct {
+	CoinID          string `json:"coin_id"`
+	Symbol          string `json:"symbol"`
+	Name            string `json:"name"`
+	Chain           string `json:"chain"`
+	ContractAddress string `json:"contract_address"` // empty for the native asset
+	MarketCapRank   int    `json:"market_cap_rank,omitempty"`
+}
+
+type SearchAPI interface {
+	Search(ctx context.Context, query string, chain string, limit int) ([]SearchResult, error)
+}
+
+// SearchIndex serves token search from a local copy of the coin list. The
+// copy is kept in the cache too, so a restart does not wait on a refresh.
+type SearchIndex struct {
+	cache    cache.CacheManager
+	provider CoinListProvider
+	interval time.Duration
+	logger   *zap.Logger
+
+	mu    sync.RWMutex
+	coins []Coin
+}
+
+func NewSearchIndex(
+	cache cache.CacheManager,
+	provider CoinListProvider,
+	interval time.Duration,
+	logger *zap.Logger,
+) *SearchIndex {
+	return &SearchIndex{
+		cache:    cache,
+		provider: provider,
+		interval: interval,
+		logger:   logger.With(zap.String("service", "token-search")),
+	}
+}
+
+// Run loads the cached coin list, then refreshes it every interval. With
+// no interval the list is refreshed once and kept.
+func (s *SearchIndex) Run(ctx context.Context) {
+	s.loadCached(ctx)
+	s.refresh(ctx)
+
+	if s.interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.refresh(ctx)
+		}
+	}
+}
+
+// Load fills the index once, from the cache when it has a copy and from
+// the provider otherwise; for one-off commands that do not Run the index
+func (s *SearchIndex) Load(ctx context.Context) error {
+	s.loadCached(ctx)
+	if s.size() > 0 {
+		return nil
+	}
+	return s.Refresh(ctx)
+}
+
+// Refresh replaces the index with a fresh copy of the provider's coin list
+func (s *SearchIndex) Refresh(ctx context.Context) error {
+	coins, err := s.provider.GetCoins(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.set(coins)
+
+	if encoded, err := json.Marshal(coins); err == nil {
+		// no expiry, a stale list is better than none
+		_ = s.cache.Set(ctx, coinsCacheKey, string(encoded), 0)
+	}
+	return nil
+}
+
+func (s *SearchIndex) Search(ctx context.Context, query string, chain string, limit int) ([]SearchResult, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return nil, ErrInvalidQuery
+	}
+	if chain != "" {
+		chain = ChainName(chain)
+	}
+
+	s.mu.RLock()
+	coins := s.coins
+	s.mu.RUnlock()
+
+	if coins == nil {
+		return nil, ErrSearchNotReady
+	}
+
+	type match struct {
+		coin  Coin
+		score int
+	}
+
+	matches := make([]match, 0)
+	for _, c := range coins {
+		if score, ok := matchScore(c, query); ok {
+			matches = append(matches, match{coin: c, score: score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score < matches[j].score
+		}
+		return rankLess(matches[i].coin.MarketCapRank, matches[j].coin.MarketCapRank)
+	})
+
+	out := make([]SearchResult, 0, limit)
+	for _, m := range matches {
+		for _, r := range candidates(m.coin, chain) {
+			if len(out) == limit {
+				return out, nil
+			}
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (s *SearchIndex) refresh(ctx context.Context) {
+	if err := s.Refresh(ctx); err != nil {
+		s.logger.Warn("coin-list-refresh-failed",
+			zap.String("provider", s.provider.Name()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	s.logger.Info("coin-list-refreshed", zap.Int("coins", s.size()))
+}
+
+func (s *SearchIndex) loadCached(ctx context.Context) {
+	cached, err := s.cache.Get(ctx, coinsCacheKey)
+	if err != nil {
+		return
+	}
+
+	var coins []Coin
+	if err := json.Unmarshal([]byte(cached), &coins); err != nil {
+		return
+	}
+	s.set(coins)
+}
+
+func (s *SearchIndex) set(coins []Coin) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.coins = coins
+}
+
+func (s *SearchIndex) size() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.coins)
+}
+
+// matchScore ranks exact symbol or name matches first, then prefixes, then
+// names containing the query
+func matchScore(c Coin, query string) (int, bool) {
+	symbol := strings.ToLower(c.Symbol)
+	name := strings.ToLower(c.Name)
+
+	switch {
+	case symbol == query || name == query:
+		return 0, true
+	case strings.HasPrefix(symbol, query) || strings.HasPrefix(name, query):
+		return 1, true
+	case strings.Contains(name, query):
+		return 2, true
+	default:
+		return 0, false
+	}
+}
+
+// rankLess orders by market cap rank with unranked coins last
+func rankLess(a, b int) bool {
+	if a == 0 {
+		return false
+	}
+	if b == 0 {
+		return true
+	}
+	return a < b
+}
+
+// candidates expands a coin into one result per chain it is deployed on,
+// including the chains it is the native asset of
+func candidates(c Coin, chain string) []SearchResult {
+	out := make([]SearchResult, 0)
+
+	add := func(ch, contract string) {
+		if chain != "" && ch != chain {
+			return
+		}
+		out = append(out, SearchResult{
+			CoinID:          c.ID,
+			Symbol:          strings.ToUpper(c.Symbol),
+			Name:            c.Name,
+			Chain:           ch,
+			ContractAddress: strings.ToLower(contract),
+			MarketCapRank:   c.MarketCapRank,
+		})
+	}
+
+	for _, ch := range nativeCoins[c.ID] {
+		add(ch, "")
+	}
+
+	platforms := make([]string, 0, len(c.Platforms))
+	for ch, contract := range c.Platforms {
+		if ch != "" && contract != "" {
+			platforms = append(platforms, ch)
+		}
+	}
+	sort.Strings(platforms)
+
+	for _, ch := range platforms {
+		add(ch, c.Platforms[ch])
+	}
+
+	return out
+}
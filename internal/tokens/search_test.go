@@ -0,0 +1,80 @@
+package tokens
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeCoinList struct {
+	coins []Coin
+}
+
+func (f *fakeCoinList) Name() string {
+	return "fake"
+}
+
+func (f *fakeCoinList) GetCoins(ctx context.Context) ([]Coin, error) {
+	return f.coins, nil
+}
+
+func newTestIndex(t *testing.T, c *fakeCache) *SearchIndex {
+	provider := &fakeCoinList{coins: []Coin{
+		{ID: "usd-coin", Symbol: "usdc", Name: "USDC", MarketCapRank: 7, Platforms: map[string]string{
+			"ethereum":     "0xA0B8",
+			"arbitrum-one": "0xaf88",
+		}},
+		{ID: "usdc-fake", Symbol: "usdc", Name: "USDC Fake", Platforms: map[string]string{"ethereum": "0xbad"}},
+		{ID: "usd-coin-bridged", Symbol: "usdc.e", Name: "Bridged USDC", MarketCapRank: 90, Platforms: map[string]string{"polygon-pos": "0x2791"}},
+		{ID: "ethereum", Symbol: "eth", Name: "Ethereum", MarketCapRank: 2, Platforms: map[string]string{"": ""}},
+	}}
+
+	idx := NewSearchIndex(c, provider, 0, zap.NewNop())
+	require.NoError(t, idx.Refresh(context.Background()))
+	return idx
+}
+
+func TestSearchIndex_RanksExactMatchesByMarketCap(t *testing.T) {
+	idx := newTestIndex(t, newFakeCache())
+
+	out, err := idx.Search(context.Background(), "USDC", "", 10)
+	require.NoError(t, err)
+	require.Len(t, out, 4)
+
+	require.Equal(t, "usd-coin", out[0].CoinID)
+	require.Equal(t, "arbitrum-one", out[0].Chain)
+	require.Equal(t, "ethereum", out[1].Chain)
+	require.Equal(t, "0xa0b8", out[1].ContractAddress)
+	require.Equal(t, "usdc-fake", out[2].CoinID)
+	require.Equal(t, "usd-coin-bridged", out[3].CoinID)
+}
+
+func TestSearchIndex_FiltersByChainAndIncludesNative(t *testing.T) {
+	idx := newTestIndex(t, newFakeCache())
+
+	out, err := idx.Search(context.Background(), "eth", "8453", 10)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Equal(t, "base", out[0].Chain)
+	require.Equal(t, "", out[0].ContractAddress)
+	require.Equal(t, "ETH", out[0].Symbol)
+}
+
+func TestSearchIndex_LoadsCachedCopy(t *testing.T) {
+	c := newFakeCache()
+	newTestIndex(t, c)
+
+	idx := NewSearchIndex(c, &fakeCoinList{}, 0, zap.NewNop())
+
+	_, err := idx.Search(context.Background(), "usdc", "", 10)
+	require.ErrorIs(t, err, ErrSearchNotReady)
+
+	idx.loadCached(context.Background())
+
+	out, err := idx.Search(context.Background(), "usdc", "ethereum", 1)
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Equal(t, "usd-coin", out[0].CoinID)
+}
@@ -0,0 +1,190 @@
+package tokens
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/cache"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+const (
+	metadataTTL = 7 * 24 * time.Hour
+	// unknown tokens are remembered for a while so they are not re-queried on every request
+	notFoundTTL = time.Hour
+	// pendingBuffer bounds how many misses can wait for the background resolver
+	pendingBuffer = 256
+)
+
+type ServiceAPI interface {
+	// Lookup returns the metadata already known for assets without calling
+	// out; misses are queued and resolved in the background
+	Lookup(ctx context.Context, assets []pricing.AssetRef) map[pricing.AssetRef]Metadata
+
+	// Get resolves a single asset, calling the provider if needed
+	Get(ctx context.Context, asset pricing.AssetRef) (*Metadata, error)
+
+	// Import adds a token list to the registry and returns the number of tokens added
+	Import(ctx context.Context, list *TokenList) (int, error)
+}
+
+type Service struct {
+	cache    cache.CacheManager
+	provider MetadataProvider
+	logger   *zap.Logger
+
+	mu       sync.RWMutex
+	registry map[pricing.AssetRef]Metadata // imported token lists
+
+	pending  chan pricing.AssetRef
+	inflight sync.Map
+}
+
+func NewService(cache cache.CacheManager, provider MetadataProvider, logger *zap.Logger) *Service {
+	return &Service{
+		cache:    cache,
+		provider: provider,
+		logger:   logger.With(zap.String("service", "tokens")),
+		registry: make(map[pricing.AssetRef]Metadata),
+		pending:  make(chan pricing.AssetRef, pendingBuffer),
+	}
+}
+
+func (s *Service) Lookup(ctx context.Context, assets []pricing.AssetRef) map[pricing.AssetRef]Metadata {
+	out := make(map[pricing.AssetRef]Metadata, len(assets))
+
+	for _, a := range assets {
+		m, found, known := s.local(ctx, normalize(a))
+		if found {
+			m.Chain, m.ContractAddress = a.Chain, a.ContractAddress
+			out[a] = m
+			continue
+		}
+		if !known {
+			s.enqueue(normalize(a))
+		}
+	}
+
+	return out
+}
+
+func (s *Service) Get(ctx context.Context, asset pricing.AssetRef) (*Metadata, error) {
+	asset = normalize(asset)
+
+	m, found, known := s.local(ctx, asset)
+	if found {
+		return &m, nil
+	}
+	if known {
+		return nil, ErrTokenNotFound
+	}
+
+	return s.resolve(ctx, asset)
+}
+
+func (s *Service) Import(ctx context.Context, list *TokenList) (int, error) {
+	items := list.Metadata()
+
+	s.mu.Lock()
+	for _, m := range items {
+		s.registry[m.AssetRef()] = m
+	}
+	s.mu.Unlock()
+
+	s.logger.Info("token-list-imported",
+		zap.String("list", list.Name),
+		zap.Int("tokens", len(items)),
+	)
+
+	return len(items), nil
+}
+
+// Run resolves queued misses one at a time, so metadata lookups never
+// compete with request-path pricing for the provider's rate limit in bursts
+func (s *Service) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case asset := <-s.pending:
+			if _, err := s.resolve(ctx, asset); err != nil && !errors.Is(err, ErrTokenNotFound) {
+				s.logger.Warn("token-metadata-resolve-failed",
+					zap.String("asset", asset.String()),
+					zap.Error(err),
+				)
+			}
+			s.inflight.Delete(asset)
+		}
+	}
+}
+
+// local checks the native table, imported lists and the cache. known is true
+// when the token was previously found not to exist.
+func (s *Service) local(ctx context.Context, asset pricing.AssetRef) (m Metadata, found bool, known bool) {
+	if asset.ContractAddress == "" {
+		m, ok := NativeMetadata(asset.Chain)
+		return m, ok, !ok
+	}
+
+	s.mu.RLock()
+	m, ok := s.registry[asset]
+	s.mu.RUnlock()
+	if ok {
+		return m, true, true
+	}
+
+	cached, err := s.cache.Get(ctx, cacheKey(asset))
+	if err != nil {
+		return Metadata{}, false, false
+	}
+	if cached == "" {
+		return Metadata{}, false, true
+	}
+	if err := json.Unmarshal([]byte(cached), &m); err != nil {
+		return Metadata{}, false, false
+	}
+	return m, true, true
+}
+
+func (s *Service) resolve(ctx context.Context, asset pricing.AssetRef) (*Metadata, error) {
+	if s.provider == nil {
+		return nil, ErrTokenNotFound
+	}
+
+	m, err := s.provider.GetMetadata(ctx, asset)
+	if errors.Is(err, ErrTokenNotFound) {
+		_ = s.cache.Set(ctx, cacheKey(asset), "", notFoundTTL)
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s metadata: %w", s.provider.Name(), err)
+	}
+
+	if encoded, err := json.Marshal(m); err == nil {
+		_ = s.cache.Set(ctx, cacheKey(asset), string(encoded), metadataTTL)
+	}
+	return m, nil
+}
+
+func (s *Service) enqueue(asset pricing.AssetRef) {
+	if _, loaded := s.inflight.LoadOrStore(asset, struct{}{}); loaded {
+		return
+	}
+
+	select {
+	case s.pending <- asset:
+	default:
+		// queue full, the asset will be retried on a later lookup
+		s.inflight.Delete(asset)
+	}
+}
+
+func cacheKey(a pricing.AssetRef) string {
+	return fmt.Sprintf("token:%s:%s", a.Chain, a.ContractAddress)
+}
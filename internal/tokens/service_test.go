@@ -0,0 +1,148 @@
+package tokens
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+)
+
+type fakeCache struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{data: make(map[string]string)}
+}
+
+func (f *fakeCache) Get(ctx context.Context, key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	v, ok := f.data[key]
+	if !ok {
+		return "", fmt.Errorf("cache key not found")
+	}
+	return v, nil
+}
+
+func (f *fakeCache) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data[key] = value
+	return nil
+}
+
+func (f *fakeCache) Del(ctx context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.data, key)
+	return nil
+}
+
+type fakeProvider struct {
+	mu    sync.Mutex
+	known map[pricing.AssetRef]Metadata
+	calls int
+}
+
+func (f *fakeProvider) Name() string {
+	return "fake"
+}
+
+func (f *fakeProvider) GetMetadata(ctx context.Context, asset pricing.AssetRef) (*Metadata, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	m, ok := f.known[asset]
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+	return &m, nil
+}
+
+var usdc = pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xa0b8"}
+
+func TestService_GetCachesProviderResult(t *testing.T) {
+	provider := &fakeProvider{known: map[pricing.AssetRef]Metadata{
+		usdc: {Chain: "ethereum", ContractAddress: "0xa0b8", Symbol: "USDC", Decimals: 6},
+	}}
+	svc := NewService(newFakeCache(), provider, zap.NewNop())
+
+	m, err := svc.Get(context.Background(), pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xA0B8"})
+	require.NoError(t, err)
+	require.Equal(t, "USDC", m.Symbol)
+	require.Equal(t, 6, m.Decimals)
+
+	_, err = svc.Get(context.Background(), usdc)
+	require.NoError(t, err)
+	require.Equal(t, 1, provider.calls)
+}
+
+func TestService_GetRemembersUnknownTokens(t *testing.T) {
+	provider := &fakeProvider{}
+	svc := NewService(newFakeCache(), provider, zap.NewNop())
+
+	unknown := pricing.AssetRef{Chain: "ethereum", ContractAddress: "0xdead"}
+
+	_, err := svc.Get(context.Background(), unknown)
+	require.ErrorIs(t, err, ErrTokenNotFound)
+
+	_, err = svc.Get(context.Background(), unknown)
+	require.ErrorIs(t, err, ErrTokenNotFound)
+	require.Equal(t, 1, provider.calls)
+}
+
+func TestService_LookupQueuesMisses(t *testing.T) {
+	provider := &fakeProvider{known: map[pricing.AssetRef]Metadata{
+		usdc: {Chain: "ethereum", ContractAddress: "0xa0b8", Symbol: "USDC", Decimals: 6},
+	}}
+	svc := NewService(newFakeCache(), provider, zap.NewNop())
+
+	native := pricing.AssetRef{Chain: "1"}
+
+	out := svc.Lookup(context.Background(), []pricing.AssetRef{usdc, native})
+	require.Len(t, out, 1)
+	require.Equal(t, "ETH", out[native].Symbol)
+	require.Equal(t, "1", out[native].Chain)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go svc.Run(ctx)
+
+	require.Eventually(t, func() bool {
+		out := svc.Lookup(context.Background(), []pricing.AssetRef{usdc})
+		return out[usdc].Symbol == "USDC"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestService_ImportedListWins(t *testing.T) {
+	provider := &fakeProvider{}
+	svc := NewService(newFakeCache(), provider, zap.NewNop())
+
+	list, err := ParseTokenList(strings.NewReader(`{
+		"name": "Test List",
+		"tokens": [
+			{"chainId": 1, "address": "0xA0B8", "symbol": "USDC", "name": "USD Coin", "decimals": 6, "logoURI": "https://example.com/usdc.png"},
+			{"chainId": 999999, "address": "0x1", "symbol": "NOPE", "name": "Unknown chain", "decimals": 18}
+		]
+	}`))
+	require.NoError(t, err)
+
+	n, err := svc.Import(context.Background(), list)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	m, err := svc.Get(context.Background(), usdc)
+	require.NoError(t, err)
+	require.Equal(t, "USD Coin", m.Name)
+	require.Equal(t, "https://example.com/usdc.png", m.LogoURL)
+	require.Equal(t, 0, provider.calls)
+}
@@ -0,0 +1,62 @@
+package tokens
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// TokenList is the standard token list format (https://tokenlists.org)
+type TokenList struct {
+	Name   string          `json:"name"`
+	Tokens []TokenListItem `json:"tokens"`
+}
+
+type TokenListItem struct {
+	ChainID  int    `json:"chainId"`
+	Address  string `json:"address"`
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Decimals int    `json:"decimals"`
+	LogoURI  string `json:"logoURI"`
+}
+
+func ParseTokenList(r io.Reader) (*TokenList, error) {
+	var list TokenList
+	if err := json.NewDecoder(r).Decode(&list); err != nil {
+		return nil, fmt.Errorf("parse token list: %w", err)
+	}
+	return &list, nil
+}
+
+func LoadTokenList(path string) (*TokenList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseTokenList(f)
+}
+
+// Metadata converts the list entries, skipping tokens on unknown chains
+func (l *TokenList) Metadata() []Metadata {
+	out := make([]Metadata, 0, len(l.Tokens))
+	for _, t := range l.Tokens {
+		chain, ok := chainNames[t.ChainID]
+		if !ok || t.Address == "" {
+			continue
+		}
+		out = append(out, Metadata{
+			Chain:           chain,
+			ContractAddress: strings.ToLower(t.Address),
+			Symbol:          t.Symbol,
+			Name:            t.Name,
+			Decimals:        t.Decimals,
+			LogoURL:         t.LogoURI,
+		})
+	}
+	return out
+}
@@ -22,6 +22,33 @@ func weiToEther(value string) float64 {
 	return f
 }
 
+// scaleUnits converts an integer token amount with the given decimals
+func scaleUnits(value string, decimals int) float64 {
+	v, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return 0
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	f, _ := new(big.Rat).SetFrac(v, scale).Float64()
+	return f
+}
+
+// gasFee is the gas paid by the sender, in the native asset
+func gasFee(item txListItem) float64 {
+	used, ok := new(big.Int).SetString(item.GasUsed, 10)
+	if !ok {
+		return 0
+	}
+	price, ok := new(big.Int).SetString(item.GasPrice, 10)
+	if !ok {
+		return 0
+	}
+	return weiToEther(new(big.Int).Mul(used, price).String())
+}
+
+// classifyType guesses a type from the function called. Swaps are only
+// told apart by name here; the transactions service decodes their legs
+// from the receipt when an RPC endpoint can serve it.
 func classifyType(item txListItem) transactions.TransactionType {
 	fn := strings.ToLower(item.FunctionName)
 
@@ -19,6 +19,36 @@ type ClientAPI interface {
 		page int,
 		offset int,
 	) (*txListResponse, error)
+	FetchTokenTx(
+		ctx context.Context,
+		chainID string,
+		wallet string,
+		page int,
+		offset int,
+	) (*tokenTxResponse, error)
+	FetchInternalTxList(
+		ctx context.Context,
+		chainID string,
+		wallet string,
+		startBlock int64,
+		endBlock int64,
+		page int,
+		offset int,
+	) (*internalTxResponse, error)
+	FetchNFTTx(
+		ctx context.Context,
+		chainID string,
+		wallet string,
+		page int,
+		offset int,
+	) (*nftTxResponse, error)
+	FetchERC1155Tx(
+		ctx context.Context,
+		chainID string,
+		wallet string,
+		page int,
+		offset int,
+	) (*nftTxResponse, error)
 }
 
 type Client struct {
@@ -38,6 +68,8 @@ func NewClient(apiKey string, baseURL string) *Client {
 	}
 }
 
+// FetchTxList lists a wallet's transactions, newest first. A page past the
+// end gets an empty result rather than an error.
 func (c *Client) FetchTxList(
 	ctx context.Context,
 	chainID string,
@@ -46,6 +78,151 @@ func (c *Client) FetchTxList(
 	offset int,
 ) (*txListResponse, error) {
 
+	body, err := c.get(ctx, "txlist", chainID, wallet, 0, latestBlock, page, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded txListResponse
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return nil, err
+	}
+
+	if decoded.Status != "1" && decoded.Message != noTransactionsFound {
+		return nil, fmt.Errorf("etherscan error: %s", decoded.Message)
+	}
+
+	return &decoded, nil
+}
+
+// FetchTokenTx lists the ERC-20 transfers in and out of a wallet, newest
+// first. A wallet without any gets an empty result rather than an error.
+func (c *Client) FetchTokenTx(
+	ctx context.Context,
+	chainID string,
+	wallet string,
+	page int,
+	offset int,
+) (*tokenTxResponse, error) {
+
+	body, err := c.get(ctx, "tokentx", chainID, wallet, 0, latestBlock, page, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded tokenTxResponse
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return nil, err
+	}
+
+	if decoded.Status != "1" && decoded.Message != noTransactionsFound {
+		return nil, fmt.Errorf("etherscan error: %s", decoded.Message)
+	}
+
+	return &decoded, nil
+}
+
+// FetchInternalTxList lists the value moved to or from a wallet by contract
+// calls in blocks startBlock to endBlock, newest first. A wallet without
+// any gets an empty result rather than an error.
+func (c *Client) FetchInternalTxList(
+	ctx context.Context,
+	chainID string,
+	wallet string,
+	startBlock int64,
+	endBlock int64,
+	page int,
+	offset int,
+) (*internalTxResponse, error) {
+
+	body, err := c.get(ctx, "txlistinternal", chainID, wallet, startBlock, endBlock, page, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded internalTxResponse
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return nil, err
+	}
+
+	if decoded.Status != "1" && decoded.Message != noTransactionsFound {
+		return nil, fmt.Errorf("etherscan error: %s", decoded.Message)
+	}
+
+	return &decoded, nil
+}
+
+// FetchNFTTx lists the ERC-721 transfers in and out of a wallet, newest
+// first. A wallet without any gets an empty result rather than an error.
+func (c *Client) FetchNFTTx(
+	ctx context.Context,
+	chainID string,
+	wallet string,
+	page int,
+	offset int,
+) (*nftTxResponse, error) {
+	return c.fetchNFTs(ctx, "tokennfttx", chainID, wallet, page, offset)
+}
+
+// FetchERC1155Tx lists the ERC-1155 transfers in and out of a wallet,
+// newest first. A wallet without any gets an empty result rather than an
+// error.
+func (c *Client) FetchERC1155Tx(
+	ctx context.Context,
+	chainID string,
+	wallet string,
+	page int,
+	offset int,
+) (*nftTxResponse, error) {
+	return c.fetchNFTs(ctx, "token1155tx", chainID, wallet, page, offset)
+}
+
+func (c *Client) fetchNFTs(
+	ctx context.Context,
+	action string,
+	chainID string,
+	wallet string,
+	page int,
+	offset int,
+) (*nftTxResponse, error) {
+
+	body, err := c.get(ctx, action, chainID, wallet, 0, latestBlock, page, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded nftTxResponse
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return nil, err
+	}
+
+	if decoded.Status != "1" && decoded.Message != noTransactionsFound {
+		return nil, fmt.Errorf("etherscan error: %s", decoded.Message)
+	}
+
+	return &decoded, nil
+}
+
+const (
+	// noTransactionsFound is the message etherscan answers an empty list with
+	noTransactionsFound = "No transactions found"
+
+	// latestBlock is the end block that reaches the chain head
+	latestBlock int64 = 99999999
+)
+
+// get calls an account action and returns the raw response body
+func (c *Client) get(
+	ctx context.Context,
+	action string,
+	chainID string,
+	wallet string,
+	startBlock int64,
+	endBlock int64,
+	page int,
+	offset int,
+) ([]byte, error) {
+
 	if c.limiter != nil {
 		if err := c.limiter.Wait(ctx); err != nil {
 			return nil, err
@@ -56,10 +233,13 @@ func (c *Client) FetchTxList(
 		ctx,
 		http.MethodGet,
 		fmt.Sprintf(
-			"%s?module=account&chainid=%s&action=txlist&address=%s&startblock=0&endblock=99999999&page=%d&offset=%d&sort=desc&apikey=%s",
+			"%s?module=account&chainid=%s&action=%s&address=%s&startblock=%d&endblock=%d&page=%d&offset=%d&sort=desc&apikey=%s",
 			c.baseURL,
 			chainID,
+			action,
 			wallet,
+			startBlock,
+			endBlock,
 			page,
 			offset,
 			c.apiKey,
@@ -76,19 +256,5 @@ func (c *Client) FetchTxList(
 	}
 	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	var decoded txListResponse
-	if err := json.Unmarshal(body, &decoded); err != nil {
-		return nil, err
-	}
-
-	if decoded.Status != "1" {
-		return nil, fmt.Errorf("etherscan error: %s", decoded.Message)
-	}
-
-	return &decoded, nil
+	return io.ReadAll(resp.Body)
 }
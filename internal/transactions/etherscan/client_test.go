@@ -0,0 +1,31 @@
+package etherscan
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_FetchTxList_NoTransactions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"0","message":"No transactions found","result":[]}`))
+	}))
+	defer server.Close()
+
+	resp, err := NewClient("key", server.URL).FetchTxList(context.Background(), "1", "0xwallet", 3, 2)
+	require.NoError(t, err)
+	require.Empty(t, resp.Result)
+}
+
+func TestClient_FetchTxList_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"status":"0","message":"NOTOK","result":[]}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient("key", server.URL).FetchTxList(context.Background(), "1", "0xwallet", 1, 2)
+	require.EqualError(t, err, "etherscan error: NOTOK")
+}
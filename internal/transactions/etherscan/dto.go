@@ -19,6 +19,85 @@ type txListItem struct {
 	MethodID     string `json:"methodId"`
 	FunctionName string `json:"functionName"`
 
+	GasUsed  string `json:"gasUsed"`
+	GasPrice string `json:"gasPrice"`
+
 	TxReceiptStatus string `json:"txreceipt_status"`
 	IsError         string `json:"isError"`
 }
+
+type tokenTxResponse struct {
+	Status  string        `json:"status"`
+	Message string        `json:"message"`
+	Result  []tokenTxItem `json:"result"`
+}
+
+// tokenTxItem is one ERC-20 transfer; a transaction moving several tokens
+// has an item for each
+type tokenTxItem struct {
+	BlockNumber string `json:"blockNumber"`
+	TimeStamp   string `json:"timeStamp"`
+	Hash        string `json:"hash"`
+
+	From  string `json:"from"`
+	To    string `json:"to"`
+	Value string `json:"value"`
+
+	ContractAddress string `json:"contractAddress"`
+	TokenName       string `json:"tokenName"`
+	TokenSymbol     string `json:"tokenSymbol"`
+	TokenDecimal    string `json:"tokenDecimal"`
+
+	GasUsed  string `json:"gasUsed"`
+	GasPrice string `json:"gasPrice"`
+}
+
+type internalTxResponse struct {
+	Status  string           `json:"status"`
+	Message string           `json:"message"`
+	Result  []internalTxItem `json:"result"`
+}
+
+// internalTxItem is value moved by a contract call inside a transaction;
+// Hash is the parent transaction's and TraceID tells its calls apart
+type internalTxItem struct {
+	BlockNumber string `json:"blockNumber"`
+	TimeStamp   string `json:"timeStamp"`
+	Hash        string `json:"hash"`
+
+	From            string `json:"from"`
+	To              string `json:"to"`
+	Value           string `json:"value"`
+	ContractAddress string `json:"contractAddress"` // set when the call created a contract
+
+	Type    string `json:"type"`
+	TraceID string `json:"traceId"`
+	IsError string `json:"isError"`
+}
+
+type nftTxResponse struct {
+	Status  string      `json:"status"`
+	Message string      `json:"message"`
+	Result  []nftTxItem `json:"result"`
+}
+
+// nftTxItem is one ERC-721 or ERC-1155 transfer. TokenValue, the number of
+// tokens moved, is only reported for ERC-1155; an ERC-721 transfer always
+// moves one.
+type nftTxItem struct {
+	BlockNumber string `json:"blockNumber"`
+	TimeStamp   string `json:"timeStamp"`
+	Hash        string `json:"hash"`
+
+	From string `json:"from"`
+	To   string `json:"to"`
+
+	ContractAddress string `json:"contractAddress"`
+	TokenID         string `json:"tokenID"`
+	TokenValue      string `json:"tokenValue"`
+	TokenName       string `json:"tokenName"`
+	TokenSymbol     string `json:"tokenSymbol"`
+
+	GasUsed  string `json:"gasUsed"`
+	GasPrice string `json:"gasPrice"`
+}
@@ -2,14 +2,26 @@ package etherscan
 
 import (
 	"context"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/transactions"
 	"github.com/markdave123-py/crypto-portfolio-tracker/internal/utils"
 )
 
+// maxResultWindow is the most transactions etherscan returns for an
+// address, however they are paged
+const maxResultWindow = transactions.MaxResults
+
+var retryConfig = utils.RetryConfig{
+	MaxRetries: 3,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   4 * time.Second,
+}
+
 type Provider struct {
 	client ClientAPI
 }
@@ -18,6 +30,9 @@ func NewProvider(client ClientAPI) *Provider {
 	return &Provider{client: client}
 }
 
+// GetTransactions lists a page of the wallet's transactions, newest first,
+// with the internal transactions that fall between this page and the next
+// merged in. Reading every page sees each internal transaction once.
 func (p *Provider) GetTransactions(
 	ctx context.Context,
 	chain string,
@@ -26,13 +41,156 @@ func (p *Provider) GetTransactions(
 	limit int,
 ) ([]transactions.Transaction, error) {
 
+	resp, err := p.fetchTxList(ctx, chain, wallet, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]transactions.Transaction, 0, len(resp.Result))
+
+	for _, item := range resp.Result {
+		txType := classifyType(item)
+		status := classifyStatus(item)
+
+		ts, _ := strconv.ParseInt(item.TimeStamp, 10, 64)
+
+		amount := weiToEther(item.Value)
+
+		tx := transactions.Transaction{
+			ID:        item.Hash,
+			Chain:     chain,
+			Hash:      item.Hash,
+			From:      strings.ToLower(item.From),
+			To:        strings.ToLower(item.To),
+			Amount:    amount,
+			Fee:       gasFee(item),
+			Type:      txType,
+			Status:    status,
+			Method:    item.FunctionName,
+			Timestamp: time.Unix(ts, 0),
+		}
+
+		txs = append(txs, tx)
+	}
+
+	internal, err := p.internalTransactions(ctx, chain, wallet, page, limit, resp.Result)
+	if err != nil {
+		return nil, err
+	}
+	if len(internal) == 0 {
+		return txs, nil
+	}
+
+	// a parent and its internal transactions share a timestamp; the stable
+	// sort keeps the parent first
+	txs = append(txs, internal...)
+	sort.SliceStable(txs, func(i, j int) bool { return txs[i].Timestamp.After(txs[j].Timestamp) })
+	return txs, nil
+}
+
+// internalTransactions lists the internal transactions of the block window
+// a txlist page owns. The first page owns everything from its next page's
+// newest block up to the chain head; later pages own from there up to their
+// own newest block, and the last page owns everything older.
+func (p *Provider) internalTransactions(
+	ctx context.Context,
+	chain string,
+	wallet string,
+	page int,
+	limit int,
+	items []txListItem,
+) ([]transactions.Transaction, error) {
+
+	endBlock := latestBlock
+	if page > 1 {
+		if len(items) == 0 {
+			// past the last page, which took everything older
+			return nil, nil
+		}
+		endBlock = blockNumber(items[0])
+	}
+
+	var startBlock int64
+	// etherscan refuses to page past maxResultWindow, so the last readable
+	// page takes everything older
+	if len(items) == limit && page*limit < maxResultWindow {
+		next, err := p.fetchTxList(ctx, chain, wallet, page*limit+1, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(next.Result) > 0 {
+			startBlock = blockNumber(next.Result[0]) + 1
+		}
+	}
+	if startBlock > endBlock {
+		return nil, nil
+	}
+
+	var resp *internalTxResponse
+	err := utils.Retry(ctx, retryConfig, func() error {
+		r, err := p.client.FetchInternalTxList(ctx, chain, wallet, startBlock, endBlock, 1, maxResultWindow)
+		if err != nil {
+			return err
+		}
+
+		resp = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, nil
+	}
+
+	wallet = strings.ToLower(wallet)
+	txs := make([]transactions.Transaction, 0, len(resp.Result))
+
+	for i, item := range resp.Result {
+		ts, _ := strconv.ParseInt(item.TimeStamp, 10, 64)
+
+		to := strings.ToLower(item.To)
+		if to == "" {
+			to = strings.ToLower(item.ContractAddress)
+		}
+
+		txType := transactions.TypeReceive
+		if strings.ToLower(item.From) == wallet {
+			txType = transactions.TypeSend
+		}
+
+		status := transactions.StatusSuccess
+		if item.IsError == "1" {
+			status = transactions.StatusFailed
+		}
+
+		trace := item.TraceID
+		if trace == "" {
+			trace = strconv.Itoa(i)
+		}
+
+		txs = append(txs, transactions.Transaction{
+			ID:         item.Hash + ":" + trace,
+			Chain:      chain,
+			Hash:       item.Hash,
+			ParentHash: item.Hash,
+			From:       strings.ToLower(item.From),
+			To:         to,
+			Amount:     weiToEther(item.Value),
+			// gas is paid once, by the parent transaction
+			Type:      txType,
+			Status:    status,
+			Timestamp: time.Unix(ts, 0),
+		})
+	}
+
+	return txs, nil
+}
+
+func (p *Provider) fetchTxList(ctx context.Context, chain, wallet string, page, limit int) (*txListResponse, error) {
 	var resp *txListResponse
 
-	err := utils.Retry(ctx, utils.RetryConfig{
-		MaxRetries: 3,
-		BaseDelay:  500 * time.Millisecond,
-		MaxDelay:   4 * time.Second,
-	}, func() error {
+	err := utils.Retry(ctx, retryConfig, func() error {
 
 		r, err := p.client.FetchTxList(ctx, chain, wallet, page, limit)
 		if err != nil {
@@ -46,31 +204,179 @@ func (p *Provider) GetTransactions(
 	if err != nil {
 		return nil, err
 	}
+	return resp, nil
+}
+
+func blockNumber(item txListItem) int64 {
+	n, _ := strconv.ParseInt(item.BlockNumber, 10, 64)
+	return n
+}
+
+// GetTokenTransfers lists ERC-20 transfers in and out of the wallet, newest
+// first. Fees are left to the transaction's own txlist entry.
+func (p *Provider) GetTokenTransfers(
+	ctx context.Context,
+	chain string,
+	wallet string,
+	page int,
+	limit int,
+) ([]transactions.Transaction, error) {
+
+	var resp *tokenTxResponse
+
+	err := utils.Retry(ctx, retryConfig, func() error {
+
+		r, err := p.client.FetchTokenTx(ctx, chain, wallet, page, limit)
+		if err != nil {
+			return err
+		}
 
+		resp = r
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	wallet = strings.ToLower(wallet)
 	txs := make([]transactions.Transaction, 0, len(resp.Result))
 
 	for _, item := range resp.Result {
-		txType := classifyType(item)
-		status := classifyStatus(item)
-
 		ts, _ := strconv.ParseInt(item.TimeStamp, 10, 64)
+		decimals, _ := strconv.Atoi(item.TokenDecimal)
 
-		amount := weiToEther(item.Value)
+		txType := transactions.TypeReceive
+		if strings.ToLower(item.From) == wallet {
+			txType = transactions.TypeSend
+		}
 
-		tx := transactions.Transaction{
-			ID:        item.Hash,
+		txs = append(txs, transactions.Transaction{
+			ID:        item.Hash + ":" + strings.ToLower(item.ContractAddress),
 			Chain:     chain,
 			Hash:      item.Hash,
 			From:      strings.ToLower(item.From),
 			To:        strings.ToLower(item.To),
-			Amount:    amount,
-			Type:      txType,
-			Status:    status,
+			Token:     item.TokenSymbol,
+			TokenAddr: strings.ToLower(item.ContractAddress),
+			// as the contract reports it; the registry's entry replaces it
+			TokenMeta: &tokens.Metadata{
+				Chain:           tokens.ChainName(chain),
+				ContractAddress: strings.ToLower(item.ContractAddress),
+				Symbol:          item.TokenSymbol,
+				Name:            item.TokenName,
+				Decimals:        decimals,
+			},
+			Amount: scaleUnits(item.Value, decimals),
+			Type:   txType,
+			// tokentx only lists transfers that happened
+			Status:    transactions.StatusSuccess,
 			Timestamp: time.Unix(ts, 0),
+		})
+	}
+
+	return txs, nil
+}
+
+// GetNFTTransfers lists the ERC-721 and ERC-1155 transfers in and out of
+// the wallet, newest first, at most limit to a page. Etherscan lists the
+// two standards apart, so both are read from the start up to the end of
+// the page and merged; later pages read more. Fees are left to the
+// transaction's own txlist entry.
+func (p *Provider) GetNFTTransfers(
+	ctx context.Context,
+	chain string,
+	wallet string,
+	page int,
+	limit int,
+) ([]transactions.Transaction, error) {
+
+	// the newest page*limit of the merged list are among the newest
+	// page*limit of each standard
+	window := min(page*limit, maxResultWindow)
+	start := (page - 1) * limit
+	if start >= window {
+		return []transactions.Transaction{}, nil
+	}
+
+	var erc721, erc1155 *nftTxResponse
+
+	err := utils.Retry(ctx, retryConfig, func() error {
+
+		r, err := p.client.FetchNFTTx(ctx, chain, wallet, 1, window)
+		if err != nil {
+			return err
 		}
 
-		txs = append(txs, tx)
+		erc721 = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return txs, nil
+	err = utils.Retry(ctx, retryConfig, func() error {
+
+		r, err := p.client.FetchERC1155Tx(ctx, chain, wallet, 1, window)
+		if err != nil {
+			return err
+		}
+
+		erc1155 = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	wallet = strings.ToLower(wallet)
+	txs := make([]transactions.Transaction, 0, len(erc721.Result)+len(erc1155.Result))
+
+	for _, list := range []struct {
+		standard transactions.TokenStandard
+		items    []nftTxItem
+	}{
+		{transactions.StandardERC721, erc721.Result},
+		{transactions.StandardERC1155, erc1155.Result},
+	} {
+		for _, item := range list.items {
+			ts, _ := strconv.ParseInt(item.TimeStamp, 10, 64)
+
+			amount := 1.0
+			if list.standard == transactions.StandardERC1155 {
+				amount, _ = strconv.ParseFloat(item.TokenValue, 64)
+			}
+
+			contract := strings.ToLower(item.ContractAddress)
+
+			txs = append(txs, transactions.Transaction{
+				ID:        item.Hash + ":" + contract + ":" + item.TokenID,
+				Chain:     chain,
+				Hash:      item.Hash,
+				From:      strings.ToLower(item.From),
+				To:        strings.ToLower(item.To),
+				Token:     item.TokenSymbol,
+				TokenAddr: contract,
+				TokenMeta: &tokens.Metadata{
+					Chain:           tokens.ChainName(chain),
+					ContractAddress: contract,
+					Symbol:          item.TokenSymbol,
+					Name:            item.TokenName,
+				},
+				TokenID:  item.TokenID,
+				Standard: list.standard,
+				Amount:   amount,
+				Type:     transactions.TypeNFT,
+				// the NFT lists only hold transfers that happened
+				Status:    transactions.StatusSuccess,
+				Timestamp: time.Unix(ts, 0),
+			})
+		}
+	}
+
+	sort.SliceStable(txs, func(i, j int) bool { return txs[i].Timestamp.After(txs[j].Timestamp) })
+	if start >= len(txs) {
+		return []transactions.Transaction{}, nil
+	}
+	return txs[start:min(window, len(txs))], nil
 }
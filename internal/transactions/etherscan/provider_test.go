@@ -2,6 +2,7 @@ package etherscan
 
 import (
 	"context"
+	"strconv"
 	"testing"
 	"time"
 
@@ -12,8 +13,18 @@ import (
 )
 
 type mockClient struct {
-	resp *txListResponse
-	err  error
+	resp     *txListResponse
+	tokens   *tokenTxResponse
+	internal *internalTxResponse
+	nfts     *nftTxResponse
+	erc1155  *nftTxResponse
+	err      error
+
+	// list, when set, is paged like etherscan pages txlist
+	list []txListItem
+
+	// block windows internal transactions were asked for
+	windows [][2]int64
 }
 
 func (m *mockClient) FetchTxList(
@@ -23,9 +34,75 @@ func (m *mockClient) FetchTxList(
 	page int,
 	limit int,
 ) (*txListResponse, error) {
+	if m.list != nil {
+		start := min((page-1)*limit, len(m.list))
+		end := min(start+limit, len(m.list))
+		if start == end {
+			return &txListResponse{Status: "0", Message: noTransactionsFound}, m.err
+		}
+		return &txListResponse{Status: "1", Result: m.list[start:end]}, m.err
+	}
 	return m.resp, m.err
 }
 
+func (m *mockClient) FetchTokenTx(
+	ctx context.Context,
+	chain string,
+	wallet string,
+	page int,
+	limit int,
+) (*tokenTxResponse, error) {
+	return m.tokens, m.err
+}
+
+func (m *mockClient) FetchInternalTxList(
+	ctx context.Context,
+	chain string,
+	wallet string,
+	startBlock int64,
+	endBlock int64,
+	page int,
+	offset int,
+) (*internalTxResponse, error) {
+	m.windows = append(m.windows, [2]int64{startBlock, endBlock})
+	if m.internal == nil {
+		return &internalTxResponse{}, m.err
+	}
+	return m.internal, m.err
+}
+
+func (m *mockClient) FetchNFTTx(
+	ctx context.Context,
+	chain string,
+	wallet string,
+	page int,
+	limit int,
+) (*nftTxResponse, error) {
+	return pageOf(m.nfts, page, limit), m.err
+}
+
+func (m *mockClient) FetchERC1155Tx(
+	ctx context.Context,
+	chain string,
+	wallet string,
+	page int,
+	limit int,
+) (*nftTxResponse, error) {
+	return pageOf(m.erc1155, page, limit), m.err
+}
+
+// pageOf serves a page of r's results, as etherscan does
+func pageOf(r *nftTxResponse, page, limit int) *nftTxResponse {
+	if r == nil {
+		return &nftTxResponse{}
+	}
+	start := (page - 1) * limit
+	if start >= len(r.Result) {
+		return &nftTxResponse{}
+	}
+	return &nftTxResponse{Result: r.Result[start:min(start+limit, len(r.Result))]}
+}
+
 func TestProvider_GetTransactions_Success(t *testing.T) {
 	mockResp := &txListResponse{
 		Result: []txListItem{
@@ -36,6 +113,8 @@ func TestProvider_GetTransactions_Success(t *testing.T) {
 				Value:           "1000000000000000000", // 1 ETH
 				TimeStamp:       "1700000000",
 				FunctionName:    "transfer(address,uint256)",
+				GasUsed:         "21000",
+				GasPrice:        "20000000000", // 20 gwei
 				IsError:         "0",
 				TxReceiptStatus: "1",
 			},
@@ -61,6 +140,7 @@ func TestProvider_GetTransactions_Success(t *testing.T) {
 	require.Equal(t, transactions.TypeSend, tx.Type)
 	require.Equal(t, transactions.StatusSuccess, tx.Status)
 	require.Equal(t, 1.0, tx.Amount)
+	require.InDelta(t, 0.00042, tx.Fee, 1e-12)
 	require.Equal(t, time.Unix(1700000000, 0), tx.Timestamp)
 }
 
@@ -82,3 +162,237 @@ func TestProvider_GetTransactions_ClientError(t *testing.T) {
 
 	require.Error(t, err)
 }
+
+func TestProvider_GetTokenTransfers(t *testing.T) {
+	client := &mockClient{tokens: &tokenTxResponse{
+		Result: []tokenTxItem{
+			{
+				Hash:            "0xtx1",
+				From:            "0xWallet",
+				To:              "0xto",
+				Value:           "2500000",
+				ContractAddress: "0xUSDC",
+				TokenSymbol:     "USDC",
+				TokenDecimal:    "6",
+				TimeStamp:       "1700000000",
+			},
+			{
+				Hash:            "0xtx2",
+				From:            "0xfrom",
+				To:              "0xwallet",
+				Value:           "1000000000000000000",
+				ContractAddress: "0xdai",
+				TokenSymbol:     "DAI",
+				TokenDecimal:    "18",
+				TimeStamp:       "1700000100",
+			},
+		},
+	}}
+
+	txs, err := NewProvider(client).GetTokenTransfers(context.Background(), "1", "0xwallet", 1, 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+
+	require.Equal(t, "0xusdc", txs[0].TokenAddr)
+	require.Equal(t, "USDC", txs[0].Token)
+	require.Equal(t, 6, txs[0].TokenMeta.Decimals)
+	require.Equal(t, 2.5, txs[0].Amount)
+	require.Equal(t, transactions.TypeSend, txs[0].Type)
+	require.Equal(t, transactions.TypeReceive, txs[1].Type)
+	require.Equal(t, 1.0, txs[1].Amount)
+}
+
+func TestProvider_GetTransactions_Internal(t *testing.T) {
+	client := &mockClient{
+		resp: &txListResponse{Result: []txListItem{
+			{
+				BlockNumber:     "200",
+				Hash:            "0xswap",
+				From:            "0xwallet",
+				To:              "0xrouter",
+				Value:           "0",
+				TimeStamp:       "1700000200",
+				GasUsed:         "21000",
+				GasPrice:        "20000000000",
+				IsError:         "0",
+				TxReceiptStatus: "1",
+			},
+			{
+				BlockNumber:     "100",
+				Hash:            "0xold",
+				From:            "0xfrom",
+				To:              "0xwallet",
+				Value:           "1000000000000000000",
+				TimeStamp:       "1700000100",
+				IsError:         "0",
+				TxReceiptStatus: "1",
+			},
+		}},
+		internal: &internalTxResponse{Result: []internalTxItem{
+			{
+				BlockNumber: "300",
+				Hash:        "0xbridge",
+				From:        "0xBridge",
+				To:          "0xWallet",
+				Value:       "2000000000000000000",
+				TimeStamp:   "1700000300",
+				TraceID:     "0_1",
+				IsError:     "0",
+			},
+			{
+				BlockNumber: "200",
+				Hash:        "0xswap",
+				From:        "0xrouter",
+				To:          "0xwallet",
+				Value:       "500000000000000000",
+				TimeStamp:   "1700000200",
+				TraceID:     "0_2",
+				IsError:     "0",
+			},
+			{
+				BlockNumber: "150",
+				Hash:        "0xvault",
+				From:        "0xwallet",
+				To:          "0xvault",
+				Value:       "100000000000000000",
+				TimeStamp:   "1700000150",
+				IsError:     "1",
+			},
+		}},
+	}
+
+	txs, err := NewProvider(client).GetTransactions(context.Background(), "1", "0xWallet", 1, 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 5)
+
+	// a short first page owns every block
+	require.Equal(t, [][2]int64{{0, latestBlock}}, client.windows)
+
+	require.Equal(t, "0xbridge:0_1", txs[0].ID)
+	require.Equal(t, "0xbridge", txs[0].ParentHash)
+	require.Equal(t, "0xbridge", txs[0].Hash)
+	require.Equal(t, "0xbridge", txs[0].From)
+	require.Equal(t, "0xwallet", txs[0].To)
+	require.Equal(t, transactions.TypeReceive, txs[0].Type)
+	require.Equal(t, 2.0, txs[0].Amount)
+	require.Zero(t, txs[0].Fee)
+
+	// the parent stays ahead of its internal payout
+	require.Equal(t, "0xswap", txs[1].ID)
+	require.Empty(t, txs[1].ParentHash)
+	require.Equal(t, "0xswap:0_2", txs[2].ID)
+	require.Equal(t, "0xswap", txs[2].ParentHash)
+	require.Equal(t, 0.5, txs[2].Amount)
+
+	require.Equal(t, "0xvault:2", txs[3].ID)
+	require.Equal(t, transactions.TypeSend, txs[3].Type)
+	require.Equal(t, transactions.StatusFailed, txs[3].Status)
+
+	require.Equal(t, "0xold", txs[4].ID)
+}
+
+func TestProvider_GetTransactions_InternalWindow(t *testing.T) {
+	client := &mockClient{list: []txListItem{
+		{BlockNumber: "500", Hash: "0xa", TimeStamp: "1700000500"},
+		{BlockNumber: "400", Hash: "0xb", TimeStamp: "1700000400"},
+		{BlockNumber: "300", Hash: "0xc", TimeStamp: "1700000300"},
+		{BlockNumber: "200", Hash: "0xd", TimeStamp: "1700000200"},
+		{BlockNumber: "100", Hash: "0xe", TimeStamp: "1700000100"},
+	}}
+	provider := NewProvider(client)
+
+	for page := 1; page <= 4; page++ {
+		_, err := provider.GetTransactions(context.Background(), "1", "0xwallet", page, 2)
+		require.NoError(t, err)
+	}
+
+	// each page owns the blocks down to the next page's newest, the last
+	// page everything older, and pages past the end nothing
+	require.Equal(t, [][2]int64{
+		{301, latestBlock},
+		{101, 300},
+		{0, 100},
+	}, client.windows)
+}
+
+func TestProvider_GetTransactions_LastFullPage(t *testing.T) {
+	// the probe for the next page's newest block finds nothing
+	client := &mockClient{list: []txListItem{
+		{BlockNumber: "200", Hash: "0xa", TimeStamp: "1700000200"},
+		{BlockNumber: "100", Hash: "0xb", TimeStamp: "1700000100"},
+	}}
+	provider := NewProvider(client)
+
+	txs, err := provider.GetTransactions(context.Background(), "1", "0xwallet", 1, 2)
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+	require.Equal(t, [][2]int64{{0, latestBlock}}, client.windows)
+}
+
+func TestProvider_GetNFTTransfers(t *testing.T) {
+	client := &mockClient{
+		nfts: &nftTxResponse{Result: []nftTxItem{{
+			Hash:            "0xmint",
+			From:            "0x0000000000000000000000000000000000000000",
+			To:              "0xWallet",
+			ContractAddress: "0xPunks",
+			TokenID:         "42",
+			TokenName:       "Punks",
+			TokenSymbol:     "PUNK",
+			TimeStamp:       "1700000100",
+		}}},
+		erc1155: &nftTxResponse{Result: []nftTxItem{{
+			Hash:            "0xgift",
+			From:            "0xwallet",
+			To:              "0xfriend",
+			ContractAddress: "0xitems",
+			TokenID:         "7",
+			TokenValue:      "3",
+			TokenName:       "Items",
+			TokenSymbol:     "ITEM",
+			TimeStamp:       "1700000200",
+		}}},
+	}
+
+	txs, err := NewProvider(client).GetNFTTransfers(context.Background(), "1", "0xwallet", 1, 10)
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+
+	// newest first across both standards
+	require.Equal(t, "0xgift:0xitems:7", txs[0].ID)
+	require.Equal(t, transactions.StandardERC1155, txs[0].Standard)
+	require.Equal(t, 3.0, txs[0].Amount)
+
+	require.Equal(t, "0xpunks", txs[1].TokenAddr)
+	require.Equal(t, "42", txs[1].TokenID)
+	require.Equal(t, "PUNK", txs[1].Token)
+	require.Equal(t, "Punks", txs[1].TokenMeta.Name)
+	require.Equal(t, transactions.StandardERC721, txs[1].Standard)
+	require.Equal(t, transactions.TypeNFT, txs[1].Type)
+	require.Equal(t, "0xwallet", txs[1].To)
+	require.Equal(t, 1.0, txs[1].Amount)
+}
+
+func TestProvider_GetNFTTransfers_Pages(t *testing.T) {
+	item := func(hash string, ts int) nftTxItem {
+		return nftTxItem{Hash: hash, ContractAddress: "0xc", TokenID: hash, TimeStamp: strconv.Itoa(ts)}
+	}
+	client := &mockClient{
+		nfts:    &nftTxResponse{Result: []nftTxItem{item("0xa", 900), item("0xb", 800), item("0xc", 700)}},
+		erc1155: &nftTxResponse{Result: []nftTxItem{item("0xx", 850), item("0xy", 100), item("0xz", 50)}},
+	}
+	provider := NewProvider(client)
+
+	// the two standards are merged before paging, so a page never holds
+	// more than limit transfers
+	var hashes []string
+	for page := 1; page <= 4; page++ {
+		txs, err := provider.GetNFTTransfers(context.Background(), "1", "0xwallet", page, 2)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(txs), 2)
+		for _, tx := range txs {
+			hashes = append(hashes, tx.Hash)
+		}
+	}
+	require.Equal(t, []string{"0xa", "0xx", "0xb", "0xc", "0xy", "0xz"}, hashes)
+}
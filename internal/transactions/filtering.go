@@ -9,6 +9,8 @@ type Filters struct {
 
 	StartDate *time.Time
 	EndDate   *time.Time
+
+	HideSpam bool
 }
 
 // This filters the transaction by Type, Status, Time
@@ -28,5 +30,8 @@ func applyFilters(tx Transaction, f Filters) bool {
 	if f.EndDate != nil && tx.Timestamp.After(*f.EndDate) {
 		return false
 	}
+	if f.HideSpam && tx.Spam != nil && tx.Spam.Spam {
+		return false
+	}
 	return true
 }
@@ -1,24 +1,58 @@
 package transactions
 
-import "time"
+import (
+	"time"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/spam"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+)
 
 type Transaction struct {
 	ID    string // internal ID (can be tx hash)
 	Chain string // ethereum, polygon, etc
 	Hash  string // on-chain tx hash
 
+	// ParentHash is set on internal transactions, value moved by a
+	// contract call, to the transaction that made the call
+	ParentHash string
+
 	From string
 	To   string
 
 	Token     string // ETH, USDC, WBTC, etc
 	TokenAddr string // empty for native ETH
+	TokenMeta *tokens.Metadata
+
+	// set on NFT transfers, where TokenAddr is the collection and Amount
+	// the number of tokens moved
+	TokenID  string
+	Standard TokenStandard
 
 	Amount float64
+	Fee    float64 // gas paid by From, in the chain's native asset
 
 	Type   TransactionType
 	Status TransactionStatus
 
 	Direction Direction
 
+	Method string        // contract function called, empty for plain transfers
+	Spam   *spam.Verdict // set when a spam classifier has judged it
+
+	Legs []SwapLeg // set on swaps decoded from their receipt logs
+
 	Timestamp time.Time
 }
+
+// SwapLeg is what a swap exchanged: TokenIn, given by the wallet, for
+// TokenOut, received by it. Token addresses are empty for the native asset.
+type SwapLeg struct {
+	TokenIn   string
+	SymbolIn  string
+	AmountIn  float64
+	TokenOut  string
+	SymbolOut string
+	AmountOut float64
+	DEX       string // protocols whose swap events the logs hold, e.g. uniswap-v2+uniswap-v3
+	Router    string // the contract the wallet called
+}
@@ -0,0 +1,47 @@
+package transactions
+
+import "time"
+
+const (
+	// MaxResults is the most records etherscan returns for an address and
+	// list, however they are paged
+	MaxResults = 10000
+
+	// HistoryPageSize and HistoryMaxPages read all MaxResults of a list
+	HistoryPageSize = 1000
+	HistoryMaxPages = MaxResults / HistoryPageSize
+)
+
+// ReadPages calls list for pages 1 to maxPages of pageSize records and
+// returns them in order, stopping at the first short page. A list can cut a
+// page short to stop early. It reports true when maxPages full pages were
+// read, so more may be left unread.
+func ReadPages(
+	list func(page int, limit int) ([]Transaction, error),
+	pageSize int,
+	maxPages int,
+) ([]Transaction, bool, error) {
+	var out []Transaction
+	for page := 1; page <= maxPages; page++ {
+		txs, err := list(page, pageSize)
+		if err != nil {
+			return nil, false, err
+		}
+		out = append(out, txs...)
+		if len(txs) < pageSize {
+			return out, false, nil
+		}
+	}
+	return out, true, nil
+}
+
+// NewerThan cuts a newest-first page at its first transaction made at or
+// before t, which ends a ReadPages read early
+func NewerThan(txs []Transaction, t time.Time) []Transaction {
+	for i, tx := range txs {
+		if !tx.Timestamp.After(t) {
+			return txs[:i]
+		}
+	}
+	return txs
+}
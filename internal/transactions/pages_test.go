@@ -0,0 +1,40 @@
+package transactions
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func pagesOf(n int) func(page, limit int) ([]Transaction, error) {
+	return func(page, limit int) ([]Transaction, error) {
+		var out []Transaction
+		for i := (page - 1) * limit; i < min(page*limit, n); i++ {
+			out = append(out, Transaction{Amount: float64(i)})
+		}
+		return out, nil
+	}
+}
+
+func TestReadPages_StopsAtShortPage(t *testing.T) {
+	txs, truncated, err := ReadPages(pagesOf(5), 2, 10)
+	require.NoError(t, err)
+	require.False(t, truncated)
+	require.Len(t, txs, 5)
+	require.Equal(t, 4.0, txs[4].Amount)
+}
+
+func TestReadPages_Truncated(t *testing.T) {
+	txs, truncated, err := ReadPages(pagesOf(7), 2, 3)
+	require.NoError(t, err)
+	require.True(t, truncated)
+	require.Len(t, txs, 6)
+}
+
+func TestReadPages_Error(t *testing.T) {
+	_, _, err := ReadPages(func(page, limit int) ([]Transaction, error) {
+		return nil, errors.New("boom")
+	}, 2, 3)
+	require.EqualError(t, err, "boom")
+}
@@ -12,4 +12,18 @@ type Repository interface {
 		page int,
 		limit int,
 	) ([]Transaction, error)
+	GetTokenTransfers(
+		ctx context.Context,
+		chain string,
+		wallet string,
+		page int,
+		limit int,
+	) ([]Transaction, error)
+	GetNFTTransfers(
+		ctx context.Context,
+		chain string,
+		wallet string,
+		page int,
+		limit int,
+	) ([]Transaction, error)
 }
@@ -2,9 +2,15 @@ package transactions
 
 import (
 	"context"
+	"slices"
 	"strings"
 
 	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/cache"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/spam"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
 )
 
 type ServiceAPI interface {
@@ -18,13 +24,71 @@ type ServiceAPI interface {
 	) ([]Transaction, error)
 }
 
+// TokenTransfersAPI lists the ERC-20 transfers in and out of a wallet
+type TokenTransfersAPI interface {
+	TokenTransfers(
+		ctx context.Context,
+		chain string,
+		wallet string,
+		page int,
+		limit int,
+	) ([]Transaction, error)
+}
+
+// NFTTransfersAPI lists the ERC-721 and ERC-1155 transfers in and out of a
+// wallet
+type NFTTransfersAPI interface {
+	NFTTransfers(
+		ctx context.Context,
+		chain string,
+		wallet string,
+		page int,
+		limit int,
+	) ([]Transaction, error)
+}
+
 type Service struct {
 	repo   Repository
+	tokens tokens.ServiceAPI
+	spam   spam.ServiceAPI
 	logger *zap.Logger
+
+	receipts ReceiptReader
+	cache    cache.CacheManager
 }
 
-func NewService(repo Repository, logger *zap.Logger) *Service {
-	return &Service{repo: repo, logger: logger}
+// Option configures optional collaborators on the transactions service
+type Option func(*Service)
+
+// WithTokens resolves token symbols and metadata on listed transactions
+func WithTokens(t tokens.ServiceAPI) Option {
+	return func(s *Service) {
+		s.tokens = t
+	}
+}
+
+// WithSpam judges listed transactions so spam can be flagged and hidden
+func WithSpam(c spam.ServiceAPI) Option {
+	return func(s *Service) {
+		s.spam = c
+	}
+}
+
+// WithSwaps decodes the legs of swaps from their receipt logs, caching
+// them when a cache is given
+func WithSwaps(receipts ReceiptReader, c cache.CacheManager) Option {
+	return func(s *Service) {
+		s.receipts = receipts
+		s.cache = c
+	}
+}
+
+func NewService(repo Repository, logger *zap.Logger, opts ...Option) *Service {
+	s := &Service{repo: repo, logger: logger}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *Service) List(
@@ -38,13 +102,27 @@ func (s *Service) List(
 	s.logger.Info("list-transactions",
 		zap.String("wallet", wallet),
 	)
-	txs, err := s.repo.GetTransactions(ctx, chain, wallet, page, limit)
+
+	// NFT transfers are listed apart from the native feed
+	var txs []Transaction
+	var err error
+	if filters.Type != nil && *filters.Type == TypeNFT {
+		txs, err = s.repo.GetNFTTransfers(ctx, chain, wallet, page, limit)
+	} else {
+		txs, err = s.repo.GetTransactions(ctx, chain, wallet, page, limit)
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	wallet = strings.ToLower(wallet)
 
+	// resolved before filtering so the token filter can match on symbol
+	s.attachTokens(ctx, txs)
+	// before filtering, as decoding can turn a call into a swap
+	s.decodeSwaps(ctx, wallet, txs)
+	s.markSpam(ctx, wallet, txs)
+
 	out := make([]Transaction, 0)
 
 	for _, tx := range txs {
@@ -57,3 +135,139 @@ func (s *Service) List(
 
 	return out, nil
 }
+
+// TokenTransfers lists a page of the wallet's ERC-20 transfers, newest
+// first, with direction, token metadata and spam verdicts set
+func (s *Service) TokenTransfers(
+	ctx context.Context,
+	chain string,
+	wallet string,
+	page int,
+	limit int,
+) ([]Transaction, error) {
+	s.logger.Info("list-token-transfers",
+		zap.String("wallet", wallet),
+	)
+	txs, err := s.repo.GetTokenTransfers(ctx, chain, wallet, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	s.attachTokens(ctx, txs)
+
+	wallet = strings.ToLower(wallet)
+	s.markSpam(ctx, wallet, txs)
+	for i := range txs {
+		txs[i] = detectDirection(txs[i], wallet)
+	}
+	return txs, nil
+}
+
+// NFTTransfers lists a page of the wallet's ERC-721 and ERC-1155
+// transfers, newest first, with direction set
+func (s *Service) NFTTransfers(
+	ctx context.Context,
+	chain string,
+	wallet string,
+	page int,
+	limit int,
+) ([]Transaction, error) {
+	s.logger.Info("list-nft-transfers",
+		zap.String("wallet", wallet),
+	)
+	txs, err := s.repo.GetNFTTransfers(ctx, chain, wallet, page, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	wallet = strings.ToLower(wallet)
+	for i := range txs {
+		txs[i] = detectDirection(txs[i], wallet)
+	}
+	return txs, nil
+}
+
+// attachTokens resolves fungible tokens only; NFT collections keep the
+// name and symbol their transfer reported
+func (s *Service) attachTokens(ctx context.Context, txs []Transaction) {
+	if s.tokens == nil || len(txs) == 0 {
+		return
+	}
+
+	refs := make([]pricing.AssetRef, 0, len(txs))
+	for _, tx := range txs {
+		if tx.Type == TypeNFT {
+			continue
+		}
+		refs = append(refs, pricing.AssetRef{Chain: tx.Chain, ContractAddress: tx.TokenAddr})
+	}
+	if len(refs) == 0 {
+		return
+	}
+
+	meta := s.tokens.Lookup(ctx, refs)
+
+	for i, tx := range txs {
+		if tx.Type == TypeNFT {
+			continue
+		}
+		m, ok := meta[pricing.AssetRef{Chain: tx.Chain, ContractAddress: tx.TokenAddr}]
+		if !ok {
+			continue
+		}
+		txs[i].TokenMeta = &m
+		if txs[i].Token == "" {
+			txs[i].Token = m.Symbol
+		}
+	}
+}
+
+// markSpam sets each transaction's verdict: its token's, or spam when it
+// is a zero-value transfer the wallet did not send
+func (s *Service) markSpam(ctx context.Context, wallet string, txs []Transaction) {
+	if s.spam == nil || len(txs) == 0 {
+		return
+	}
+
+	seen := make(map[pricing.AssetRef]int)
+	var judged []spam.Token
+	for _, tx := range txs {
+		if tx.TokenAddr == "" {
+			continue
+		}
+		ref := pricing.AssetRef{Chain: tx.Chain, ContractAddress: tx.TokenAddr}
+		i, ok := seen[ref]
+		if !ok {
+			i = len(judged)
+			seen[ref] = i
+			judged = append(judged, spam.Token{Asset: ref, Symbol: tx.Token})
+			if tx.TokenMeta != nil {
+				judged[i].Name = tx.TokenMeta.Name
+			}
+		}
+		judged[i].Transfers++
+		if tx.Amount == 0 {
+			judged[i].ZeroValueTransfers++
+		}
+	}
+
+	var verdicts map[pricing.AssetRef]spam.Verdict
+	if len(judged) > 0 {
+		verdicts = s.spam.Classify(ctx, wallet, judged)
+	}
+
+	for i, tx := range txs {
+		var v spam.Verdict
+		if tx.TokenAddr != "" {
+			v = verdicts[pricing.AssetRef{Chain: tx.Chain, ContractAddress: tx.TokenAddr}]
+		}
+		if !v.Spam && spam.ZeroValueTransfer(wallet, tx.From, tx.Method, tx.Amount) {
+			signals := append([]spam.Reason{}, v.Signals...)
+			if !slices.Contains(signals, spam.ReasonZeroValue) {
+				signals = append(signals, spam.ReasonZeroValue)
+			}
+			v = spam.Verdict{Spam: true, Reason: spam.ReasonZeroValue, Signals: signals}
+		}
+		txs[i].Spam = &v
+	}
+}
@@ -6,10 +6,15 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/spam"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
 )
 
 type mockRepository struct {
-	txs []Transaction
+	txs  []Transaction
+	nfts []Transaction
 }
 
 func (m *mockRepository) GetTransactions(
@@ -22,6 +27,26 @@ func (m *mockRepository) GetTransactions(
 	return m.txs, nil
 }
 
+func (m *mockRepository) GetTokenTransfers(
+	ctx context.Context,
+	chain string,
+	wallet string,
+	page int,
+	limit int,
+) ([]Transaction, error) {
+	return m.txs, nil
+}
+
+func (m *mockRepository) GetNFTTransfers(
+	ctx context.Context,
+	chain string,
+	wallet string,
+	page int,
+	limit int,
+) ([]Transaction, error) {
+	return m.nfts, nil
+}
+
 func TestService_List_WithFiltering(t *testing.T) {
 	repo := &mockRepository{
 		txs: []Transaction{
@@ -58,3 +83,108 @@ func TestService_List_WithFiltering(t *testing.T) {
 	require.Equal(t, "tx1", out[0].Hash)
 	require.Equal(t, DirectionOut, out[0].Direction)
 }
+
+type mockTokens struct{}
+
+func (mockTokens) Lookup(ctx context.Context, assets []pricing.AssetRef) map[pricing.AssetRef]tokens.Metadata {
+	return map[pricing.AssetRef]tokens.Metadata{
+		{Chain: "1"}: {Chain: "1", Symbol: "ETH", Decimals: 18},
+	}
+}
+
+func (mockTokens) Get(ctx context.Context, asset pricing.AssetRef) (*tokens.Metadata, error) {
+	return nil, tokens.ErrTokenNotFound
+}
+
+func (mockTokens) Import(ctx context.Context, list *tokens.TokenList) (int, error) {
+	return 0, nil
+}
+
+func TestService_List_ResolvesTokenBeforeFiltering(t *testing.T) {
+	repo := &mockRepository{
+		txs: []Transaction{
+			{Hash: "tx1", Chain: "1", From: "0xabc"},
+		},
+	}
+
+	svc := NewService(repo, zap.NewNop(), WithTokens(mockTokens{}))
+
+	symbol := "ETH"
+	out, err := svc.List(context.Background(), "1", "0xabc", 1, 10, Filters{Token: &symbol})
+
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Equal(t, "ETH", out[0].Token)
+	require.NotNil(t, out[0].TokenMeta)
+	require.Equal(t, 18, out[0].TokenMeta.Decimals)
+}
+
+type mockSpam struct {
+	spam.ServiceAPI
+	verdicts map[pricing.AssetRef]spam.Verdict
+}
+
+func (m mockSpam) Classify(ctx context.Context, wallet string, tokens []spam.Token) map[pricing.AssetRef]spam.Verdict {
+	return m.verdicts
+}
+
+func TestService_List_HidesSpam(t *testing.T) {
+	repo := &mockRepository{
+		txs: []Transaction{
+			{Hash: "tx1", Chain: "1", From: "0xfriend", To: "0xabc", Amount: 1},
+			{Hash: "tx2", Chain: "1", From: "0xab0", To: "0xabc"},
+			{Hash: "tx3", Chain: "1", From: "0xowner", To: "0xabc", Method: "execTransaction()"},
+			{Hash: "tx4", Chain: "1", From: "0xscam", To: "0xabc", Token: "U5DC", TokenAddr: "0xscam", Amount: 5000},
+		},
+	}
+	verdict := spam.Verdict{Spam: true, Reason: spam.ReasonLookalike}
+	svc := NewService(repo, zap.NewNop(), WithSpam(mockSpam{verdicts: map[pricing.AssetRef]spam.Verdict{
+		{Chain: "1", ContractAddress: "0xscam"}: verdict,
+	}}))
+
+	all, err := svc.List(context.Background(), "1", "0xabc", 1, 10, Filters{})
+	require.NoError(t, err)
+	require.Len(t, all, 4)
+	require.False(t, all[0].Spam.Spam)
+	require.Equal(t, spam.ReasonZeroValue, all[1].Spam.Reason)
+	require.False(t, all[2].Spam.Spam)
+	require.Equal(t, verdict, *all[3].Spam)
+
+	visible, err := svc.List(context.Background(), "1", "0xabc", 1, 10, Filters{HideSpam: true})
+	require.NoError(t, err)
+	require.Len(t, visible, 2)
+	require.Equal(t, "tx1", visible[0].Hash)
+	require.Equal(t, "tx3", visible[1].Hash)
+
+	// token transfers carry the same verdicts
+	transfers, err := svc.TokenTransfers(context.Background(), "1", "0xabc", 1, 10)
+	require.NoError(t, err)
+	require.Equal(t, verdict, *transfers[3].Spam)
+}
+
+func TestService_List_NFTs(t *testing.T) {
+	repo := &mockRepository{
+		txs: []Transaction{
+			{Hash: "tx1", Chain: "1", From: "0xabc", Type: TypeSend},
+		},
+		nfts: []Transaction{
+			{Hash: "tx2", Chain: "1", From: "0xmint", To: "0xabc", Token: "PUNK", TokenAddr: "0xpunks", TokenID: "42", Amount: 1, Type: TypeNFT},
+		},
+	}
+	svc := NewService(repo, zap.NewNop(), WithTokens(mockTokens{}))
+
+	nft := TypeNFT
+	out, err := svc.List(context.Background(), "1", "0xabc", 1, 10, Filters{Type: &nft})
+	require.NoError(t, err)
+	require.Len(t, out, 1)
+	require.Equal(t, "42", out[0].TokenID)
+	require.Equal(t, DirectionIn, out[0].Direction)
+	// collections keep the name their transfer reported
+	require.Equal(t, "PUNK", out[0].Token)
+	require.Nil(t, out[0].TokenMeta)
+
+	all, err := svc.List(context.Background(), "1", "0xabc", 1, 10, Filters{})
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	require.Equal(t, "tx1", all[0].Hash)
+}
@@ -0,0 +1,333 @@
+package transactions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/evm"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/pricing"
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/tokens"
+)
+
+// topicTransfer is the signature hash of the ERC-20
+// Transfer(address,address,uint256) event
+const topicTransfer = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+
+// swapEvents names DEX protocols by the signature hash of their swap event
+var swapEvents = map[string]string{
+	// Swap(address,uint256,uint256,uint256,uint256,address), also emitted by its forks
+	"0xd78ad95fa46c994b6551d0da85fc275fe613ce37657fb8d5e3d130840159d822": "uniswap-v2",
+	// Swap(address,address,int256,int256,uint160,uint128,int24)
+	"0xc42079f94a6350d7e6235f29174924f928cc2ac818eb64fed8004e115fbcca67": "uniswap-v3",
+	// TokenExchange(address,int128,uint256,int128,uint256)
+	"0x8b3e96f2b889fa771c53c981b40daf005f63f637f1869f707052d15a3dd97140": "curve",
+	// Swap(bytes32,address,address,uint256,uint256)
+	"0x2170c741c41531aec20e7c107c24eecfdd15e69c9bb0a8dd37b1840b9e0b207b": "balancer-v2",
+}
+
+// nonSwapMethods are contract calls never worth reading a receipt for
+var nonSwapMethods = []string{"approve(", "transfer(", "transferfrom(", "setapprovalforall("}
+
+const (
+	// swapLegsTTL is how long decoded legs are cached; a mined receipt never
+	// changes, so this only bounds the cache's size
+	swapLegsTTL = 30 * 24 * time.Hour
+	// swapFailureTTL keeps a receipt that could not be read from being
+	// retried on every listing, while letting a node that was down recover
+	swapFailureTTL = 10 * time.Minute
+	// decodeConcurrency bounds the receipts read at once for one page
+	decodeConcurrency = 8
+)
+
+// swapDecodeFailed is cached in place of legs when decoding failed
+const swapDecodeFailed = "failed"
+
+var errSwapDecodeFailed = errors.New("swap decoding failed recently")
+
+// flowDust is the net amount below which a token counts as not moved
+const flowDust = 1e-12
+
+// ReceiptReader reads transaction receipts and token decimals over
+// JSON-RPC; evm.Client implements it
+type ReceiptReader interface {
+	Chains() []string
+	Receipt(ctx context.Context, chain, hash string) (*evm.Receipt, error)
+	Decimals(ctx context.Context, chain, contract string) (int, error)
+}
+
+// decodeSwaps reads the receipt of every contract call the wallet made on
+// the page, a few at a time, and where its logs show one token given for
+// another, sets the legs and marks it a swap. Transactions whose receipt
+// cannot be read are left as they are.
+func (s *Service) decodeSwaps(ctx context.Context, wallet string, txs []Transaction) {
+	if s.receipts == nil || len(txs) == 0 {
+		return
+	}
+	chains := s.receipts.Chains()
+
+	// native value paid back to the wallet by each transaction's calls
+	payouts := make(map[string]float64)
+	for _, tx := range txs {
+		if tx.ParentHash != "" && tx.Status == StatusSuccess && strings.ToLower(tx.To) == wallet {
+			payouts[tx.ParentHash] += tx.Amount
+		}
+	}
+
+	// each worker writes only its own transaction's slot
+	legs := make([][]SwapLeg, len(txs))
+	sem := make(chan struct{}, decodeConcurrency)
+	var wg sync.WaitGroup
+	for i, tx := range txs {
+		if !swapCandidate(tx, wallet) || !slices.Contains(chains, tokens.ChainName(tx.Chain)) {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, tx Transaction) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			decoded, err := s.swapLegs(ctx, wallet, tx, payouts[tx.Hash])
+			if err != nil {
+				if !errors.Is(err, errSwapDecodeFailed) {
+					s.logger.Warn("decode-swap-failed",
+						zap.String("hash", tx.Hash),
+						zap.Error(err),
+					)
+				}
+				return
+			}
+			legs[i] = decoded
+		}(i, tx)
+	}
+	wg.Wait()
+
+	for i := range txs {
+		if len(legs[i]) > 0 {
+			txs[i].Type = TypeSwap
+			txs[i].Legs = legs[i]
+		}
+	}
+}
+
+// swapCandidate reports whether a transaction is a successful contract
+// call sent by the wallet, the only kind that can swap its tokens
+func swapCandidate(tx Transaction, wallet string) bool {
+	if tx.Status != StatusSuccess || tx.ParentHash != "" || tx.TokenAddr != "" || tx.Method == "" {
+		return false
+	}
+	if tx.Type == TypeStake || strings.ToLower(tx.From) != wallet {
+		return false
+	}
+	method := strings.ToLower(tx.Method)
+	for _, m := range nonSwapMethods {
+		if strings.HasPrefix(method, m) {
+			return false
+		}
+	}
+	return true
+}
+
+// swapLegs decodes a transaction's legs from its receipt, or from the
+// cache when it was decoded before. No legs and no error means it is not a
+// swap.
+func (s *Service) swapLegs(ctx context.Context, wallet string, tx Transaction, payout float64) ([]SwapLeg, error) {
+	chain := tokens.ChainName(tx.Chain)
+	key := "swap-legs:" + chain + ":" + strings.ToLower(tx.Hash)
+
+	if s.cache != nil {
+		if cached, err := s.cache.Get(ctx, key); err == nil && cached != "" {
+			if cached == swapDecodeFailed {
+				return nil, errSwapDecodeFailed
+			}
+			var legs []SwapLeg
+			if json.Unmarshal([]byte(cached), &legs) == nil {
+				return legs, nil
+			}
+		}
+	}
+
+	legs, err := s.decodeLegs(ctx, wallet, tx, payout)
+	if err != nil {
+		if s.cache != nil && ctx.Err() == nil {
+			_ = s.cache.Set(ctx, key, swapDecodeFailed, swapFailureTTL)
+		}
+		return nil, err
+	}
+
+	if s.cache != nil {
+		if encoded, err := json.Marshal(legs); err == nil {
+			_ = s.cache.Set(ctx, key, string(encoded), swapLegsTTL)
+		}
+	}
+	return legs, nil
+}
+
+// decodeLegs reads the receipt and nets its token transfers
+func (s *Service) decodeLegs(ctx context.Context, wallet string, tx Transaction, payout float64) ([]SwapLeg, error) {
+	receipt, err := s.receipts.Receipt(ctx, tokens.ChainName(tx.Chain), tx.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := tokenFlows(wallet, receipt.Logs)
+	flows := make(map[string]float64, len(raw)+1)
+	for contract, amount := range raw {
+		decimals, err := s.decimals(ctx, tx.Chain, contract)
+		if err != nil {
+			return nil, err
+		}
+		flows[contract] = evm.ScaleDecimals(amount, decimals)
+	}
+	if native := payout - tx.Amount; native != 0 {
+		flows[""] = native
+	}
+
+	legs := []SwapLeg{}
+	if leg, ok := s.swapLeg(ctx, tx, flows, dexes(receipt.Logs)); ok {
+		legs = append(legs, leg)
+	}
+	return legs, nil
+}
+
+// swapLeg pairs the one token the wallet gave with the one it got. Other
+// shapes, such as adding liquidity, are not swaps. Without a known DEX's
+// swap event, only a call named as a swap counts.
+func (s *Service) swapLeg(ctx context.Context, tx Transaction, flows map[string]float64, dex []string) (SwapLeg, bool) {
+	if len(dex) == 0 && !strings.Contains(strings.ToLower(tx.Method), "swap") {
+		return SwapLeg{}, false
+	}
+
+	var given, got []string
+	for contract, amount := range flows {
+		switch {
+		case amount < -flowDust:
+			given = append(given, contract)
+		case amount > flowDust:
+			got = append(got, contract)
+		}
+	}
+	if len(given) != 1 || len(got) != 1 {
+		return SwapLeg{}, false
+	}
+
+	symbols := s.symbols(ctx, tx.Chain, given[0], got[0])
+	return SwapLeg{
+		TokenIn:   given[0],
+		SymbolIn:  symbols[given[0]],
+		AmountIn:  -flows[given[0]],
+		TokenOut:  got[0],
+		SymbolOut: symbols[got[0]],
+		AmountOut: flows[got[0]],
+		DEX:       strings.Join(dex, "+"),
+		Router:    strings.ToLower(tx.To),
+	}, true
+}
+
+// decimals takes a token's decimals from its metadata, reading them from
+// the contract when it has not been resolved yet
+func (s *Service) decimals(ctx context.Context, chain, contract string) (int, error) {
+	ref := pricing.AssetRef{Chain: chain, ContractAddress: contract}
+	if s.tokens != nil {
+		if m, ok := s.tokens.Lookup(ctx, []pricing.AssetRef{ref})[ref]; ok && m.Decimals > 0 {
+			return m.Decimals, nil
+		}
+	}
+	return s.receipts.Decimals(ctx, tokens.ChainName(chain), contract)
+}
+
+// symbols looks up the symbols of a swap's tokens; unresolved ones are
+// left empty
+func (s *Service) symbols(ctx context.Context, chain string, contracts ...string) map[string]string {
+	out := make(map[string]string, len(contracts))
+	refs := make([]pricing.AssetRef, 0, len(contracts))
+	for _, c := range contracts {
+		if c == "" {
+			if m, ok := tokens.NativeMetadata(chain); ok {
+				out[c] = m.Symbol
+			}
+			continue
+		}
+		refs = append(refs, pricing.AssetRef{Chain: chain, ContractAddress: c})
+	}
+	if s.tokens == nil || len(refs) == 0 {
+		return out
+	}
+	for ref, m := range s.tokens.Lookup(ctx, refs) {
+		out[ref.ContractAddress] = m.Symbol
+	}
+	return out
+}
+
+// tokenFlows nets the ERC-20 Transfer logs of a receipt per token from the
+// wallet's side: negative for tokens it gave, positive for tokens it got.
+// ERC-721 transfers, which index the token ID as a fourth topic, are left
+// out.
+func tokenFlows(wallet string, logs []evm.Log) map[string]*big.Int {
+	out := make(map[string]*big.Int)
+	for _, l := range logs {
+		if len(l.Topics) != 3 || strings.ToLower(l.Topics[0]) != topicTransfer {
+			continue
+		}
+		from := topicAddress(l.Topics[1])
+		to := topicAddress(l.Topics[2])
+		if from == to || (from != wallet && to != wallet) {
+			continue
+		}
+
+		amount, err := evm.DecodeUint256(l.Data)
+		if err != nil {
+			continue
+		}
+
+		contract := strings.ToLower(l.Address)
+		net, ok := out[contract]
+		if !ok {
+			net = new(big.Int)
+			out[contract] = net
+		}
+		if from == wallet {
+			net.Sub(net, amount)
+		} else {
+			net.Add(net, amount)
+		}
+	}
+	return out
+}
+
+// dexes lists, in log order and once each, the protocols whose swap events
+// a receipt has
+func dexes(logs []evm.Log) []string {
+	var out []string
+	for _, l := range logs {
+		if len(l.Topics) == 0 {
+			continue
+		}
+		name, ok := swapEvents[strings.ToLower(l.Topics[0])]
+		if ok && !slices.Contains(out, name) {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// topicAddress reads the address held in the last 20 bytes of an indexed
+// topic
+func topicAddress(topic string) string {
+	hex := strings.ToLower(strings.TrimPrefix(topic, "0x"))
+	if len(hex) < 40 {
+		return ""
+	}
+	return "0x" + hex[len(hex)-40:]
+}
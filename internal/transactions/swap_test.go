@@ -0,0 +1,176 @@
+package transactions
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/markdave123-py/crypto-portfolio-tracker/internal/evm"
+)
+
+const (
+	swapWallet = "0x00000000000000000000000000000000000000aa"
+	router     = "0x00000000000000000000000000000000000000bb"
+	pool       = "0x00000000000000000000000000000000000000cc"
+	usdc       = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	weth       = "0xc02aaa39b223fe8d0a0e5c4f27ead083c756cc2"
+	ausdc      = "0x98c23e9d8f34fefb1b7bd6a91b7ff122f4e16f5c"
+
+	topicSwapV2 = "0xd78ad95fa46c994b6551d0da85fc275fe613ce37657fb8d5e3d130840159d822"
+	topicSwapV3 = "0xc42079f94a6350d7e6235f29174924f928cc2ac818eb64fed8004e115fbcca67"
+)
+
+// fakeReceipts and fakeCache are read by several decoders at once
+type fakeReceipts struct {
+	mu       sync.Mutex
+	receipts map[string]*evm.Receipt
+	reads    int
+}
+
+func (f *fakeReceipts) Chains() []string { return []string{"ethereum"} }
+
+func (f *fakeReceipts) Receipt(ctx context.Context, chain, hash string) (*evm.Receipt, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.reads++
+	r, ok := f.receipts[hash]
+	if !ok {
+		return nil, evm.ErrReceiptNotFound
+	}
+	return r, nil
+}
+
+func (f *fakeReceipts) Decimals(ctx context.Context, chain, contract string) (int, error) {
+	if contract == weth {
+		return 18, nil
+	}
+	return 6, nil
+}
+
+type fakeCache struct {
+	mu   sync.Mutex
+	data map[string]string
+}
+
+func (f *fakeCache) Get(ctx context.Context, key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	v, ok := f.data[key]
+	if !ok {
+		return "", fmt.Errorf("cache key not found")
+	}
+	return v, nil
+}
+
+func (f *fakeCache) Set(ctx context.Context, key string, value string, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data[key] = value
+	return nil
+}
+
+func (f *fakeCache) Del(ctx context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.data, key)
+	return nil
+}
+
+func word(address string) string {
+	return "0x" + strings.Repeat("0", 24) + strings.TrimPrefix(address, "0x")
+}
+
+func transferLog(token, from, to string, amount int64) evm.Log {
+	return evm.Log{
+		Address: token,
+		Topics:  []string{topicTransfer, word(from), word(to)},
+		Data:    fmt.Sprintf("0x%064x", big.NewInt(amount)),
+	}
+}
+
+func TestService_List_DecodesSwaps(t *testing.T) {
+	repo := &mockRepository{txs: []Transaction{
+		// 1 ETH for 2,000 USDC through a v3 pool
+		{Hash: "0xbuy", Chain: "1", From: swapWallet, To: router, Amount: 1, Method: "exactInputSingle(tuple params)", Type: TypeSend, Status: StatusSuccess},
+		// 1,000 USDC for ETH, paid out as an internal transaction
+		{Hash: "0xsell", Chain: "1", From: swapWallet, To: router, Method: "swapExactTokensForETH(uint256 amountIn)", Type: TypeSwap, Status: StatusSuccess},
+		{Hash: "0xsell", ParentHash: "0xsell", Chain: "1", From: router, To: swapWallet, Amount: 0.5, Type: TypeReceive, Status: StatusSuccess},
+		// lending USDC gives aUSDC back, but is no swap
+		{Hash: "0xsupply", Chain: "1", From: swapWallet, To: pool, Method: "supply(address asset)", Type: TypeReceive, Status: StatusSuccess},
+		{Hash: "0xapprove", Chain: "1", From: swapWallet, To: usdc, Method: "approve(address spender, uint256 amount)", Type: TypeReceive, Status: StatusSuccess},
+	}}
+	receipts := &fakeReceipts{receipts: map[string]*evm.Receipt{
+		"0xbuy": {Status: "0x1", Logs: []evm.Log{
+			transferLog(weth, router, pool, 1e18),
+			transferLog(usdc, pool, swapWallet, 2_000e6),
+			{Address: pool, Topics: []string{topicSwapV3, word(router), word(swapWallet)}},
+		}},
+		"0xsell": {Status: "0x1", Logs: []evm.Log{
+			transferLog(usdc, swapWallet, pool, 1_000e6),
+			transferLog(weth, pool, router, 5e17),
+			{Address: pool, Topics: []string{topicSwapV2, word(router), word(router)}},
+		}},
+		"0xsupply": {Status: "0x1", Logs: []evm.Log{
+			transferLog(usdc, swapWallet, pool, 100e6),
+			transferLog(ausdc, "0x0000000000000000000000000000000000000000", swapWallet, 100e6),
+		}},
+	}}
+	c := &fakeCache{data: make(map[string]string)}
+	svc := NewService(repo, zap.NewNop(), WithSwaps(receipts, c))
+
+	swap := TypeSwap
+	out, err := svc.List(context.Background(), "1", swapWallet, 1, 10, Filters{Type: &swap})
+	require.NoError(t, err)
+	require.Len(t, out, 2)
+	require.Equal(t, 3, receipts.reads)
+
+	require.Equal(t, []SwapLeg{{
+		TokenIn:   "",
+		SymbolIn:  "ETH",
+		AmountIn:  1,
+		TokenOut:  usdc,
+		AmountOut: 2000,
+		DEX:       "uniswap-v3",
+		Router:    router,
+	}}, out[0].Legs)
+
+	require.Len(t, out[1].Legs, 1)
+	require.Equal(t, usdc, out[1].Legs[0].TokenIn)
+	require.Equal(t, 1000.0, out[1].Legs[0].AmountIn)
+	require.Equal(t, "", out[1].Legs[0].TokenOut)
+	require.Equal(t, 0.5, out[1].Legs[0].AmountOut)
+	require.Equal(t, "uniswap-v2", out[1].Legs[0].DEX)
+
+	// decoded receipts, swaps or not, are read from the cache
+	all, err := svc.List(context.Background(), "1", swapWallet, 1, 10, Filters{})
+	require.NoError(t, err)
+	require.Equal(t, 3, receipts.reads)
+	require.Equal(t, TypeReceive, all[3].Type)
+	require.Empty(t, all[3].Legs)
+	require.Equal(t, TypeSwap, all[0].Type)
+}
+
+func TestService_List_CachesDecodeFailures(t *testing.T) {
+	repo := &mockRepository{txs: []Transaction{
+		{Hash: "0xpending", Chain: "1", From: swapWallet, To: router, Amount: 1, Method: "swapExactETHForTokens(uint256 amountOutMin)", Type: TypeSwap, Status: StatusSuccess},
+	}}
+	receipts := &fakeReceipts{receipts: map[string]*evm.Receipt{}}
+	c := &fakeCache{data: make(map[string]string)}
+	svc := NewService(repo, zap.NewNop(), WithSwaps(receipts, c))
+
+	for range 2 {
+		out, err := svc.List(context.Background(), "1", swapWallet, 1, 10, Filters{})
+		require.NoError(t, err)
+		require.Empty(t, out[0].Legs)
+	}
+	// the missing receipt is not asked for again until the failure expires
+	require.Equal(t, 1, receipts.reads)
+	require.Equal(t, swapDecodeFailed, c.data["swap-legs:ethereum:0xpending"])
+}
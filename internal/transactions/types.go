@@ -7,6 +7,7 @@ const (
 	TypeReceive TransactionType = "receive"
 	TypeSwap    TransactionType = "swap"
 	TypeStake   TransactionType = "stake"
+	TypeNFT     TransactionType = "nft" // an ERC-721 or ERC-1155 transfer
 )
 
 type TransactionStatus string
@@ -22,3 +23,11 @@ const (
 	DirectionIn  Direction = "in"
 	DirectionOut Direction = "out"
 )
+
+// TokenStandard is the standard an NFT transfer's contract implements
+type TokenStandard string
+
+const (
+	StandardERC721  TokenStandard = "erc721"
+	StandardERC1155 TokenStandard = "erc1155"
+)